@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+// attachDaemonLog routes this daemon's stdout/stderr through a timestamping,
+// rotating writer when the spawner handed us the log path. The inherited log
+// handle can neither stamp lines nor follow a rename, so output is piped and
+// re-opened on rotation instead. On unix fds 1 and 2 are pointed at the pipe
+// too (redirectStdFDs). The returned func flushes the pipe and must run before
+// exit.
+func attachDaemonLog() func() {
+	logFile := strings.TrimSpace(os.Getenv(ralph.DaemonLogFileEnv))
+	maxBytes, backups := ralph.ParseDaemonLogRotateEnv(os.Getenv(ralph.DaemonLogRotateEnv))
+	// Supervisor workers share our stdout pipe, so they must not rotate the file too.
+	_ = os.Unsetenv(ralph.DaemonLogFileEnv)
+	_ = os.Unsetenv(ralph.DaemonLogRotateEnv)
+	if logFile == "" {
+		return func() {}
+	}
+
+	// maxBytes <= 0 keeps a single file; the writer then never rotates.
+	w, err := ralph.NewRotatingLogWriter(logFile, maxBytes, backups)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ralph] warning: log timestamps/rotation disabled: %v\n", err)
+		return func() {}
+	}
+	r, pw, err := os.Pipe()
+	if err != nil {
+		_ = w.Close()
+		fmt.Fprintf(os.Stderr, "[ralph] warning: log timestamps/rotation disabled: %v\n", err)
+		return func() {}
+	}
+	restoreFDs, err := redirectStdFDs(pw)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ralph] warning: raw stdout/stderr writes bypass log timestamps: %v\n", err)
+		restoreFDs = func() {}
+	}
+	origStdout, origStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = pw, pw
+	stamped := ralph.NewTimestampLogWriter(w)
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(stamped, r)
+		_ = stamped.Flush()
+		close(done)
+	}()
+	return func() {
+		os.Stdout, os.Stderr = origStdout, origStderr
+		restoreFDs()
+		_ = pw.Close()
+		// A stray grandchild holding the pipe must not keep us from exiting.
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+		}
+		_ = r.Close()
+		_ = w.Close()
+	}
+}
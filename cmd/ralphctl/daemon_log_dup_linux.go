@@ -0,0 +1,8 @@
+package main
+
+import "syscall"
+
+// dupFD is dup2; linux/arm64 and newer ports only have dup3.
+func dupFD(oldfd, newfd int) error {
+	return syscall.Dup3(oldfd, newfd, 0)
+}
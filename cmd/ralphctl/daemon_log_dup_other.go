@@ -0,0 +1,9 @@
+//go:build !windows && !linux
+
+package main
+
+import "syscall"
+
+func dupFD(oldfd, newfd int) error {
+	return syscall.Dup2(oldfd, newfd)
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// redirectStdFDs points fds 1 and 2 at w, so output that bypasses os.Stdout and
+// os.Stderr (runtime panics, children handed the raw fds) is stamped too. The
+// returned func points them back at the original files.
+func redirectStdFDs(w *os.File) (func(), error) {
+	savedOut, err := syscall.Dup(1)
+	if err != nil {
+		return nil, err
+	}
+	savedErr, err := syscall.Dup(2)
+	if err != nil {
+		_ = syscall.Close(savedOut)
+		return nil, err
+	}
+	restore := func() {
+		_ = dupFD(savedOut, 1)
+		_ = dupFD(savedErr, 2)
+		_ = syscall.Close(savedOut)
+		_ = syscall.Close(savedErr)
+	}
+	if err := dupFD(int(w.Fd()), 1); err != nil {
+		restore()
+		return nil, err
+	}
+	if err := dupFD(int(w.Fd()), 2); err != nil {
+		restore()
+		return nil, err
+	}
+	return restore, nil
+}
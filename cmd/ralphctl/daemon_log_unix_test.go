@@ -0,0 +1,46 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"codex-ralph/internal/ralph"
+)
+
+func TestAttachDaemonLogStampsRawFDWrites(t *testing.T) {
+	if os.Getenv("RALPH_TEST_ATTACH_DAEMON_LOG") == "1" {
+		closeLog := attachDaemonLog()
+		_, _ = syscall.Write(1, []byte("raw stdout line\n"))
+		_, _ = syscall.Write(2, []byte("raw stderr line\n"))
+		closeLog()
+		os.Exit(0)
+	}
+
+	logFile := filepath.Join(t.TempDir(), "daemon.out")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestAttachDaemonLogStampsRawFDWrites$")
+	cmd.Env = append(os.Environ(), "RALPH_TEST_ATTACH_DAEMON_LOG=1", ralph.DaemonLogFileEnv+"="+logFile)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("helper process failed: %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("read log: %v", err)
+	}
+	for _, want := range []string{"raw stdout line", "raw stderr line"} {
+		found := false
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasSuffix(line, want) && !strings.HasPrefix(line, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("%q should be written to the log with a timestamp:\n%s", want, data)
+		}
+	}
+}
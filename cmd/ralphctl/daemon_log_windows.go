@@ -0,0 +1,13 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// redirectStdFDs is a no-op on Windows: only os.Stdout and os.Stderr are
+// swapped, so a runtime panic or a child given the inherited std handles still
+// writes to the log file directly, without timestamps.
+func redirectStdFDs(w *os.File) (func(), error) {
+	_ = w
+	return func() {}, nil
+}
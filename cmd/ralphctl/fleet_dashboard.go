@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"codex-ralph/internal/ralph"
+)
+
+func renderFleetMetrics(controlDir, projectID string, all bool, out io.Writer) error {
+	projects, err := ralph.ResolveFleetProjects(controlDir, projectID, all)
+	if err != nil {
+		return err
+	}
+	samples := make([]ralph.StatusMetricsSample, 0, len(projects))
+	for _, p := range projects {
+		paths, err := ralph.NewPaths(controlDir, p.ProjectDir)
+		if err != nil {
+			return err
+		}
+		st, err := ralph.GetStatus(paths)
+		if err != nil {
+			return err
+		}
+		samples = append(samples, ralph.StatusMetricsSample{ProjectID: p.ID, Status: st})
+	}
+	ralph.WriteStatusMetrics(out, samples)
+	return nil
+}
+
+type fleetDashboardEntry struct {
+	fleetStatusEntry
+	ControlPlane *fleetDashboardControlPlane `json:"control_plane,omitempty"`
+}
+
+type fleetDashboardControlPlane struct {
+	Mode       string         `json:"mode"`
+	Canary     bool           `json:"canary"`
+	TasksTotal int            `json:"tasks_total,omitempty"`
+	TaskStates map[string]int `json:"task_states,omitempty"`
+}
+
+// fleetDashboardFrame is one --watch refresh in --json mode.
+type fleetDashboardFrame struct {
+	UpdatedUTC string                `json:"updated_utc"`
+	ControlDir string                `json:"control_dir"`
+	Projects   []fleetDashboardEntry `json:"projects"`
+}
+
+func collectFleetDashboard(controlDir, projectID string, all bool) ([]fleetDashboardEntry, error) {
+	projects, err := ralph.ResolveFleetProjects(controlDir, projectID, all)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]fleetDashboardEntry, 0, len(projects))
+	for _, p := range projects {
+		paths, err := ralph.NewPaths(controlDir, p.ProjectDir)
+		if err != nil {
+			return nil, err
+		}
+		st, err := ralph.GetStatus(paths)
+		if err != nil {
+			return nil, err
+		}
+		_, rolePIDs := ralph.RunningRoleDaemons(paths)
+		entry := fleetDashboardEntry{fleetStatusEntry: fleetStatusEntry{
+			ID:            p.ID,
+			ProjectDir:    p.ProjectDir,
+			Plugin:        p.Plugin,
+			AssignedRoles: p.AssignedRoles,
+			Workers:       rolePIDs,
+			Status:        st,
+		}}
+		if cpState, cpErr := ralph.ControlPlaneGetCutoverState(paths.ProjectDir); cpErr == nil {
+			entry.ControlPlane = &fleetDashboardControlPlane{Mode: cpState.Mode, Canary: cpState.Canary}
+			if cpState.Mode == "v2" {
+				if cpStatus, cpStatusErr := ralph.ControlPlaneStatusReport(paths.ProjectDir); cpStatusErr == nil {
+					entry.ControlPlane.TasksTotal = cpStatus.TasksTotal
+					entry.ControlPlane.TaskStates = cpStatus.StateCounts
+				}
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// renderFleetDashboardJSON writes an indented array, or a single-line frame object
+// when frame is set so --watch output stays one JSON document per line.
+func renderFleetDashboardJSON(controlDir, projectID string, all bool, frame bool, out io.Writer) error {
+	entries, err := collectFleetDashboard(controlDir, projectID, all)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if frame {
+		data, err = json.Marshal(fleetDashboardFrame{
+			UpdatedUTC: time.Now().UTC().Format(time.RFC3339),
+			ControlDir: controlDir,
+			Projects:   entries,
+		})
+	} else {
+		data, err = json.MarshalIndent(entries, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(data, '\n'))
+	return err
+}
+
+func renderFleetDashboard(controlDir, projectID string, all bool, out io.Writer) error {
+	entries, err := collectFleetDashboard(controlDir, projectID, all)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "## Fleet Dashboard")
+	fmt.Fprintf(out, "- updated_utc: %s\n", time.Now().UTC().Format(time.RFC3339))
+	fmt.Fprintf(out, "- control_dir: %s\n", controlDir)
+	fmt.Fprintf(out, "- projects: %d\n", len(entries))
+	for _, e := range entries {
+		st := e.Status
+		fmt.Fprintf(
+			out,
+			"- project=%s plugin=%s daemon=%s state=%s circuit=%s ready=%d in_progress=%d done=%d blocked=%d\n",
+			e.ID,
+			e.Plugin,
+			st.Daemon,
+			st.QueueState,
+			st.CodexCircuitState,
+			st.QueueReady,
+			st.InProgress,
+			st.Done,
+			st.Blocked,
+		)
+		if cp := e.ControlPlane; cp != nil {
+			fmt.Fprintf(out, "  control_plane_mode=%s | canary=%t\n", cp.Mode, cp.Canary)
+			if cp.TaskStates != nil {
+				fmt.Fprintf(
+					out,
+					"  cp_tasks total=%d ready=%d running=%d verifying=%d done=%d blocked=%d\n",
+					cp.TasksTotal,
+					cp.TaskStates[ralph.ControlPlaneTaskStateReady],
+					cp.TaskStates[ralph.ControlPlaneTaskStateRunning],
+					cp.TaskStates[ralph.ControlPlaneTaskStateVerifying],
+					cp.TaskStates[ralph.ControlPlaneTaskStateDone],
+					cp.TaskStates[ralph.ControlPlaneTaskStateBlocked],
+				)
+			}
+		}
+		if len(e.Workers) > 0 {
+			roleLine := []string{}
+			for _, role := range ralph.RequiredAgentRoles {
+				pid, ok := e.Workers[role]
+				if !ok {
+					continue
+				}
+				roleLine = append(roleLine, fmt.Sprintf("%s:%d", role, pid))
+			}
+			if len(roleLine) > 0 {
+				fmt.Fprintf(out, "  workers=%s\n", strings.Join(roleLine, ","))
+			}
+		}
+		if st.LastProfileReloadAt != "" || st.ProfileReloadCount > 0 {
+			fmt.Fprintf(
+				out,
+				"  profile_reload_at=%s | profile_reload_count=%d\n",
+				valueOrDash(st.LastProfileReloadAt),
+				st.ProfileReloadCount,
+			)
+		}
+		if st.LastFailureCause != "" || st.LastCodexRetryCount > 0 || st.LastPermissionStreak > 0 {
+			fmt.Fprintf(
+				out,
+				"  last_failure=%s | category=%s | codex_retries=%d | perm_streak=%d\n",
+				compactSingleLine(st.LastFailureCause, 120),
+				valueOrDash(st.LastFailureCategory),
+				st.LastCodexRetryCount,
+				st.LastPermissionStreak,
+			)
+		}
+	}
+	return nil
+}
+
+const (
+	ansiReset = "\033[0m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+)
+
+// terminalColorEnabled reports whether f is an interactive terminal that should get ANSI color.
+func terminalColorEnabled(f *os.File) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// renderFleetDashboardTable writes one aligned row per project. With color, rows with
+// blocked issues or an open codex circuit are red and the rest green.
+func renderFleetDashboardTable(controlDir, projectID string, all, color bool, out io.Writer) error {
+	entries, err := collectFleetDashboard(controlDir, projectID, all)
+	if err != nil {
+		return err
+	}
+	header := []string{"PROJECT", "PLUGIN", "DAEMON", "READY", "IN_PROG", "DONE", "BLOCKED", "WORKERS"}
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		st := e.Status
+		workers := []string{}
+		for _, role := range ralph.RequiredAgentRoles {
+			if _, ok := e.Workers[role]; ok {
+				workers = append(workers, role)
+			}
+		}
+		rows = append(rows, []string{
+			e.ID,
+			e.Plugin,
+			st.Daemon,
+			strconv.Itoa(st.QueueReady),
+			strconv.Itoa(st.InProgress),
+			strconv.Itoa(st.Done),
+			strconv.Itoa(st.Blocked),
+			valueOrDash(strings.Join(workers, ",")),
+		})
+	}
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	formatRow := func(row []string) string {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		}
+		return strings.TrimRight(strings.Join(cells, "  "), " ")
+	}
+
+	fmt.Fprintf(out, "Fleet Dashboard  updated=%s  projects=%d\n", time.Now().UTC().Format(time.RFC3339), len(entries))
+	fmt.Fprintln(out, formatRow(header))
+	for i, row := range rows {
+		line := formatRow(row)
+		if color {
+			st := entries[i].Status
+			if st.Blocked > 0 || st.CodexCircuitState == "open" {
+				line = ansiRed + line + ansiReset
+			} else {
+				line = ansiGreen + line + ansiReset
+			}
+		}
+		fmt.Fprintln(out, line)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"codex-ralph/internal/ralph"
+)
+
+func setupFleetDashboardProject(t *testing.T) (string, ralph.Paths) {
+	t.Helper()
+	controlDir := filepath.Join(t.TempDir(), "control")
+	projectDir := filepath.Join(t.TempDir(), "wallet")
+	if err := ralph.EnsureDefaultControlAssets(controlDir); err != nil {
+		t.Fatalf("ensure control assets: %v", err)
+	}
+	if _, err := ralph.RegisterFleetProject(controlDir, "wallet", projectDir, "universal-default", ""); err != nil {
+		t.Fatalf("register fleet project: %v", err)
+	}
+	paths, err := ralph.NewPaths(controlDir, projectDir)
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	if err := ralph.EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	if _, _, err := ralph.CreateIssue(paths, "developer", "queued work"); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	return controlDir, paths
+}
+
+func TestRenderFleetDashboardJSON(t *testing.T) {
+	controlDir, _ := setupFleetDashboardProject(t)
+
+	var arr bytes.Buffer
+	if err := renderFleetDashboardJSON(controlDir, "", true, false, &arr); err != nil {
+		t.Fatalf("render json: %v", err)
+	}
+	var entries []map[string]any
+	if err := json.Unmarshal(arr.Bytes(), &entries); err != nil {
+		t.Fatalf("decode array: %v\n%s", err, arr.String())
+	}
+	if len(entries) != 1 || entries[0]["id"] != "wallet" {
+		t.Fatalf("unexpected entries: %v", entries)
+	}
+	status, _ := entries[0]["status"].(map[string]any)
+	if status["queue_ready"] != float64(1) {
+		t.Fatalf("status should be nested with queue_ready=1: %v", entries[0])
+	}
+
+	var frame bytes.Buffer
+	if err := renderFleetDashboardJSON(controlDir, "", true, true, &frame); err != nil {
+		t.Fatalf("render frame: %v", err)
+	}
+	if strings.Count(frame.String(), "\n") != 1 {
+		t.Fatalf("frame must be a single line: %q", frame.String())
+	}
+	var decoded fleetDashboardFrame
+	if err := json.Unmarshal(frame.Bytes(), &decoded); err != nil {
+		t.Fatalf("decode frame: %v", err)
+	}
+	if decoded.UpdatedUTC == "" || len(decoded.Projects) != 1 || decoded.Projects[0].ID != "wallet" {
+		t.Fatalf("unexpected frame: %+v", decoded)
+	}
+}
+
+func TestRenderFleetDashboardTableAlignsAndColors(t *testing.T) {
+	controlDir, paths := setupFleetDashboardProject(t)
+
+	var plain bytes.Buffer
+	if err := renderFleetDashboardTable(controlDir, "", true, false, &plain); err != nil {
+		t.Fatalf("render table: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(plain.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected title, header and one row: %q", plain.String())
+	}
+	if strings.Contains(plain.String(), "\033[") {
+		t.Fatalf("color disabled output must not contain ANSI codes: %q", plain.String())
+	}
+	if strings.Index(lines[1], "READY") != strings.Index(lines[2], "1 ") {
+		t.Fatalf("READY column misaligned:\n%s\n%s", lines[1], lines[2])
+	}
+
+	var healthy bytes.Buffer
+	if err := renderFleetDashboardTable(controlDir, "", true, true, &healthy); err != nil {
+		t.Fatalf("render color table: %v", err)
+	}
+	if !strings.Contains(healthy.String(), ansiGreen+"wallet") {
+		t.Fatalf("healthy row should be green: %q", healthy.String())
+	}
+
+	blockedPath, _, err := ralph.CreateIssue(paths, "qa", "broken work")
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if err := os.Rename(blockedPath, filepath.Join(paths.BlockedDir, filepath.Base(blockedPath))); err != nil {
+		t.Fatalf("move to blocked: %v", err)
+	}
+	var blocked bytes.Buffer
+	if err := renderFleetDashboardTable(controlDir, "", true, true, &blocked); err != nil {
+		t.Fatalf("render color table: %v", err)
+	}
+	if !strings.Contains(blocked.String(), ansiRed+"wallet") {
+		t.Fatalf("blocked row should be red: %q", blocked.String())
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"codex-ralph/internal/ralph"
+)
+
+// runFleetDoctor checks the fleet config and then every resolved project with
+// runFleetProjectDoctor, printing the same per-project summary line as
+// Telegram /doctor followed by the project's non-passing checks.
+func runFleetDoctor(controlDir string, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("fleet doctor", flag.ContinueOnError)
+	id := fs.String("id", "", "fleet project id")
+	all := fs.Bool("all", false, "check all projects (default when --id is empty)")
+	strict := fs.Bool("strict", false, "exit with error when any project has failing checks")
+	repair := fs.Bool("repair", false, "run safe repair actions before checks")
+	concurrencyRaw := fs.Int("concurrency", 1, "number of projects to check in parallel")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	concurrency, err := parseFleetConcurrency(*concurrencyRaw)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(*id) == "" {
+		*all = true
+	}
+	cfg, err := ralph.LoadFleetConfigUnvalidated(controlDir)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	fmt.Fprintln(w, "## Fleet Doctor")
+	fmt.Fprintln(w, "### fleet config")
+	for _, check := range append(ralph.FleetConfigChecks(cfg), ralph.FleetRoleChecks(cfg)...) {
+		if check.Status == "fail" {
+			failed++
+		}
+		fmt.Fprintf(w, "- [%s] %s: %s\n", check.Status, check.Name, check.Detail)
+	}
+	projects, err := ralph.ResolveFleetProjects(controlDir, *id, *all)
+	if err != nil {
+		if failed == 0 {
+			return err
+		}
+		fmt.Fprintf(w, "- project checks skipped: %v\n", err)
+		projects = nil
+	}
+	projectFailed := make([]bool, len(projects))
+	results := runFleetProjects(projects, concurrency, false, func(i int, p ralph.FleetProject, out io.Writer) error {
+		fmt.Fprintf(out, "### project=%s\n", p.ID)
+		paths, err := ralph.NewPaths(controlDir, p.ProjectDir)
+		if err != nil {
+			return err
+		}
+		if *repair {
+			actions, err := ralph.RepairProject(paths)
+			for _, action := range actions {
+				fmt.Fprintf(out, "- repair [%s] %s: %s\n", action.Status, action.Name, action.Detail)
+			}
+			if err != nil {
+				projectFailed[i] = true
+				fmt.Fprintf(out, "- repair error: %v\n", err)
+				return nil
+			}
+		}
+		report, err := runFleetProjectDoctor(paths)
+		if err != nil {
+			projectFailed[i] = true
+			fmt.Fprintf(out, "- project=%s status=fail detail=%s\n", p.ID, compactSingleLine(err.Error(), 160))
+			return nil
+		}
+		fmt.Fprintln(out, formatFleetDoctorSummary(p.ID, report))
+		for _, check := range report.Checks {
+			if check.Status != "pass" {
+				fmt.Fprintf(out, "- [%s] %s: %s\n", check.Status, check.Name, check.Detail)
+			}
+		}
+		projectFailed[i] = report.HasFailures()
+		return nil
+	})
+	if err := flushFleetProjectResults(w, results); err != nil {
+		return err
+	}
+	for _, f := range projectFailed {
+		if f {
+			failed++
+		}
+	}
+	if *strict && failed > 0 {
+		return fmt.Errorf("fleet doctor reported failing checks")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"codex-ralph/internal/ralph"
+)
+
+func TestRunFleetDoctorSummarizesEachProject(t *testing.T) {
+	t.Setenv("RALPH_REQUIRE_CODEX", "false")
+	controlDir := t.TempDir()
+	projects := []ralph.FleetProject{
+		{ID: "good", ProjectDir: t.TempDir(), Plugin: "universal-default"},
+		{ID: "bad", ProjectDir: t.TempDir(), Plugin: "universal-default"},
+	}
+	if err := ralph.SaveFleetConfig(controlDir, ralph.FleetConfig{Projects: projects}); err != nil {
+		t.Fatalf("save fleet config: %v", err)
+	}
+	badPaths, err := ralph.NewPaths(controlDir, projects[1].ProjectDir)
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	if err := ralph.EnsureLayout(badPaths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	if err := os.WriteFile(badPaths.ProfileYAMLFile, []byte("plugin: [unterminated\n"), 0o644); err != nil {
+		t.Fatalf("write profile: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := runFleetDoctor(controlDir, []string{"--all", "--strict"}, &out); err == nil {
+		t.Fatalf("--strict should fail when a project has failing checks:\n%s", out.String())
+	}
+	text := out.String()
+	if !strings.Contains(text, "- project=bad pass=3 warn=0 fail=1\n") {
+		t.Fatalf("missing failing project summary:\n%s", text)
+	}
+	if !strings.Contains(text, "- [fail] profile: ") {
+		t.Fatalf("failing check should be listed:\n%s", text)
+	}
+	if !strings.Contains(text, "- project=good pass=") || !strings.Contains(text, "fail=0\n") {
+		t.Fatalf("missing healthy project summary:\n%s", text)
+	}
+
+	out.Reset()
+	if err := runFleetDoctor(controlDir, []string{"--id", "good", "--strict"}, &out); err != nil {
+		t.Fatalf("--strict should pass for a healthy project: %v\n%s", err, out.String())
+	}
+	if err := runFleetDoctor(controlDir, []string{"--all"}, &out); err != nil {
+		t.Fatalf("without --strict failing checks should not be an error: %v", err)
+	}
+
+	telegram, err := runFleetDoctorReports(controlDir, telegramTargetSpec{All: true})
+	if err != nil {
+		t.Fatalf("telegram fleet doctor: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(telegram), "\n") {
+		if strings.HasPrefix(line, "- project=") && !strings.Contains(text, line+"\n") {
+			t.Fatalf("telegram summary %q should match fleet doctor output:\n%s", line, text)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"codex-ralph/internal/ralph"
+)
+
+type fleetProjectResult struct {
+	output bytes.Buffer
+	err    error
+}
+
+func parseFleetConcurrency(n int) (int, error) {
+	if n <= 0 {
+		return 0, fmt.Errorf("--concurrency must be > 0")
+	}
+	return n, nil
+}
+
+// runFleetProjects runs fn for each project on up to concurrency workers. Each
+// project writes into its own buffer, so the output can be replayed in project
+// order once every worker is done. fn must only touch per-project state; shared
+// control dir writes such as fleet/projects.json stay with the caller, outside the pool.
+// With concurrency 1 the first failure stops the remaining projects, as the
+// serial loop did, unless continueOnError is set; their results stay nil.
+func runFleetProjects(projects []ralph.FleetProject, concurrency int, continueOnError bool, fn func(i int, p ralph.FleetProject, out io.Writer) error) []*fleetProjectResult {
+	results := make([]*fleetProjectResult, len(projects))
+	if concurrency <= 1 {
+		for i, p := range projects {
+			results[i] = &fleetProjectResult{}
+			results[i].err = fn(i, p, &results[i].output)
+			if results[i].err != nil && !continueOnError {
+				break
+			}
+		}
+		return results
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(projects); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := &fleetProjectResult{}
+				result.err = fn(i, projects[i], &result.output)
+				results[i] = result
+			}
+		}()
+	}
+	for i := range projects {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// flushFleetProjectResults prints every project's output in project order and
+// joins the per-project errors in the same order.
+func flushFleetProjectResults(w io.Writer, results []*fleetProjectResult) error {
+	var errs []error
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		_, _ = w.Write(result.output.Bytes())
+		if result.err != nil {
+			errs = append(errs, result.err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+func TestRunFleetProjectsKeepsProjectOrderAndBound(t *testing.T) {
+	projects := []ralph.FleetProject{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}, {ID: "e"}}
+	var running, peak atomic.Int32
+	results := runFleetProjects(projects, 2, false, func(i int, p ralph.FleetProject, out io.Writer) error {
+		n := running.Add(1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		// later projects finish first so output order cannot follow completion order
+		time.Sleep(time.Duration(len(projects)-i) * 5 * time.Millisecond)
+		running.Add(-1)
+		fmt.Fprintf(out, "project=%s\n", p.ID)
+		if p.ID == "b" || p.ID == "d" {
+			return fmt.Errorf("project=%s: boom", p.ID)
+		}
+		return nil
+	})
+	if peak.Load() > 2 {
+		t.Fatalf("expected at most 2 concurrent projects, got %d", peak.Load())
+	}
+
+	var buf bytes.Buffer
+	err := flushFleetProjectResults(&buf, results)
+	if got, want := buf.String(), "project=a\nproject=b\nproject=c\nproject=d\nproject=e\n"; got != want {
+		t.Fatalf("unexpected output order\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	if err == nil || err.Error() != "project=b: boom\nproject=d: boom" {
+		t.Fatalf("expected joined errors in project order, got %v", err)
+	}
+}
+
+func TestRunFleetProjectsSerialStopsAtFirstError(t *testing.T) {
+	projects := []ralph.FleetProject{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	var ran []string
+	results := runFleetProjects(projects, 1, false, func(_ int, p ralph.FleetProject, out io.Writer) error {
+		ran = append(ran, p.ID)
+		if p.ID == "b" {
+			return fmt.Errorf("project=%s: boom", p.ID)
+		}
+		return nil
+	})
+	if strings.Join(ran, ",") != "a,b" {
+		t.Fatalf("expected serial run to stop after b, ran=%v", ran)
+	}
+	if err := flushFleetProjectResults(io.Discard, results); err == nil || !strings.Contains(err.Error(), "project=b") {
+		t.Fatalf("expected project=b error, got %v", err)
+	}
+}
+
+func TestParseFleetConcurrencyRejectsNonPositive(t *testing.T) {
+	if _, err := parseFleetConcurrency(0); err == nil {
+		t.Fatalf("expected --concurrency 0 to be rejected")
+	}
+	if n, err := parseFleetConcurrency(4); err != nil || n != 4 {
+		t.Fatalf("expected 4, got %d err=%v", n, err)
+	}
+}
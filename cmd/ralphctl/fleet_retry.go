@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+// resolveFleetRunProjects picks the targets of a recordable fleet command:
+// --id/--all normally, or the last run's failed and pending projects with
+// --retry-failed.
+func resolveFleetRunProjects(controlDir, command, id string, all, retryFailed bool) ([]ralph.FleetProject, error) {
+	if !retryFailed {
+		return ralph.ResolveFleetProjects(controlDir, id, all)
+	}
+	run, ok, err := ralph.LoadFleetLastRun(controlDir)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no fleet run recorded; nothing to retry")
+	}
+	if run.Command != command {
+		return nil, fmt.Errorf("last fleet run was %s, not %s", run.Command, command)
+	}
+	return ralph.ResolveFleetRetryProjects(controlDir, run)
+}
+
+// fleetRunArgs drops --retry-failed so retry-last can append it again.
+func fleetRunArgs(args []string) []string {
+	out := make([]string, 0, len(args))
+	for _, arg := range args {
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if strings.HasPrefix(arg, "-") && name == "retry-failed" {
+			continue
+		}
+		out = append(out, arg)
+	}
+	return out
+}
+
+// finishFleetRun prints the per-project results and records which projects
+// succeeded, failed or were never reached for `fleet retry-last`.
+func finishFleetRun(controlDir, command string, args []string, projects []ralph.FleetProject, results []*fleetProjectResult) error {
+	runErr := flushFleetProjectResults(os.Stdout, results)
+	run := ralph.FleetLastRun{
+		Command:      command,
+		Args:         fleetRunArgs(args),
+		Succeeded:    []string{},
+		Failed:       []string{},
+		Pending:      []string{},
+		UpdatedAtUTC: time.Now().UTC().Format(time.RFC3339),
+	}
+	for i, p := range projects {
+		switch {
+		case results[i] == nil:
+			run.Pending = append(run.Pending, p.ID)
+		case results[i].err != nil:
+			run.Failed = append(run.Failed, p.ID)
+		default:
+			run.Succeeded = append(run.Succeeded, p.ID)
+		}
+	}
+	if err := ralph.SaveFleetLastRun(controlDir, run); err != nil {
+		fmt.Fprintf(os.Stderr, "[fleet] warning: %v\n", err)
+	} else if retry := run.RetryIDs(); len(retry) > 0 {
+		fmt.Printf("[fleet] %s incomplete: failed=%d pending=%d (retry: ralphctl fleet retry-last)\n", command, len(run.Failed), len(run.Pending))
+	}
+	return runErr
+}
+
+func runFleetRetryLast(controlDir string, args []string) error {
+	fs := flag.NewFlagSet("fleet retry-last", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	run, ok, err := ralph.LoadFleetLastRun(controlDir)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no fleet run recorded; nothing to retry")
+	}
+	retry := run.RetryIDs()
+	if len(retry) == 0 {
+		fmt.Printf("[fleet] last %s succeeded for all %d project(s); nothing to retry\n", run.Command, len(run.Succeeded))
+		return nil
+	}
+	fmt.Printf("[fleet] retry-last command=%s projects=%s\n", run.Command, strings.Join(retry, ","))
+	retryArgs := append([]string{run.Command}, run.Args...)
+	return runFleetCommand(controlDir, append(retryArgs, "--retry-failed"))
+}
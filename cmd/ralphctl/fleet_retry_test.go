@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"codex-ralph/internal/ralph"
+)
+
+func TestFinishFleetRunRecordsRetryTargets(t *testing.T) {
+	controlDir := t.TempDir()
+	projects := []ralph.FleetProject{
+		{ID: "a", ProjectDir: t.TempDir(), Plugin: "universal-default"},
+		{ID: "b", ProjectDir: t.TempDir(), Plugin: "universal-default"},
+		{ID: "c", ProjectDir: t.TempDir(), Plugin: "universal-default"},
+	}
+	if err := ralph.SaveFleetConfig(controlDir, ralph.FleetConfig{Projects: projects}); err != nil {
+		t.Fatalf("save fleet config: %v", err)
+	}
+
+	results := runFleetProjects(projects, 1, false, func(_ int, p ralph.FleetProject, _ io.Writer) error {
+		if p.ID == "b" {
+			return fmt.Errorf("project=%s: transient", p.ID)
+		}
+		return nil
+	})
+	args := []string{"--all", "--retry-failed", "--concurrency", "2"}
+	if err := finishFleetRun(controlDir, "start", args, projects, results); err == nil {
+		t.Fatalf("expected project b failure to be returned")
+	}
+
+	run, ok, err := ralph.LoadFleetLastRun(controlDir)
+	if err != nil || !ok {
+		t.Fatalf("load last run: ok=%t err=%v", ok, err)
+	}
+	if !reflect.DeepEqual(run.Succeeded, []string{"a"}) || !reflect.DeepEqual(run.Failed, []string{"b"}) || !reflect.DeepEqual(run.Pending, []string{"c"}) {
+		t.Fatalf("unexpected last run: %+v", run)
+	}
+	if !reflect.DeepEqual(run.Args, []string{"--all", "--concurrency", "2"}) {
+		t.Fatalf("expected --retry-failed stripped from args, got %v", run.Args)
+	}
+
+	retry, err := resolveFleetRunProjects(controlDir, "start", "", false, true)
+	if err != nil {
+		t.Fatalf("resolve retry projects: %v", err)
+	}
+	if len(retry) != 2 || retry[0].ID != "b" || retry[1].ID != "c" {
+		t.Fatalf("expected retry of b,c, got %+v", retry)
+	}
+	if _, err := resolveFleetRunProjects(controlDir, "apply-plugin", "", false, true); err == nil {
+		t.Fatalf("expected retry of a different command to be rejected")
+	}
+}
+
+func TestRunFleetRetryLastNothingToRetry(t *testing.T) {
+	controlDir := t.TempDir()
+	if err := runFleetRetryLast(controlDir, nil); err == nil {
+		t.Fatalf("expected error without a recorded run")
+	}
+	if err := ralph.SaveFleetLastRun(controlDir, ralph.FleetLastRun{Command: "start", Succeeded: []string{"a"}}); err != nil {
+		t.Fatalf("save last run: %v", err)
+	}
+	if err := runFleetRetryLast(controlDir, nil); err != nil {
+		t.Fatalf("expected no-op retry, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+// fleetStatusFilters pick the projects `fleet status --filter` keeps.
+var fleetStatusFilters = map[string]func(ralph.Status) bool{
+	"blocked": func(st ralph.Status) bool { return st.Blocked > 0 },
+	"stopped": func(st ralph.Status) bool { return st.Daemon == "stopped" },
+	// Idle with nothing queued: the project waits for a human to add work.
+	"input-required": ralph.IsInputRequiredStatus,
+	"failing":        func(st ralph.Status) bool { return isFailingFleetStatus(st, time.Now().UTC()) },
+}
+
+// fleetFailingWindow is how long a blocked failure keeps a project "failing".
+// LastFailureCause alone sticks to the newest blocked issue forever, so an old
+// failure would otherwise flag a project that has run cleanly since.
+const fleetFailingWindow = time.Hour
+
+func isFailingFleetStatus(st ralph.Status, now time.Time) bool {
+	if st.CodexCircuitState == "open" || st.LastPermissionStreak > 0 {
+		return true
+	}
+	failedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(st.LastFailureUpdatedAt))
+	if err != nil {
+		return false
+	}
+	return now.Sub(failedAt) < fleetFailingWindow
+}
+
+func parseFleetStatusFilter(raw string) (func(ralph.Status) bool, error) {
+	name := strings.ToLower(strings.TrimSpace(raw))
+	if name == "" {
+		return nil, nil
+	}
+	match, ok := fleetStatusFilters[name]
+	if !ok {
+		names := make([]string, 0, len(fleetStatusFilters))
+		for k := range fleetStatusFilters {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("invalid --filter: %s (expected %s)", raw, strings.Join(names, "|"))
+	}
+	return match, nil
+}
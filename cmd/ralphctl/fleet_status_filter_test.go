@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+func TestParseFleetStatusFilter(t *testing.T) {
+	t.Parallel()
+
+	healthy := ralph.Status{Daemon: "running(general_pid=1)", CodexCircuitState: "closed", QueueReady: 2}
+	cases := []struct {
+		filter string
+		match  ralph.Status
+	}{
+		{"blocked", ralph.Status{Daemon: healthy.Daemon, Blocked: 1}},
+		{"stopped", ralph.Status{Daemon: "stopped", QueueReady: 2}},
+		{"input-required", ralph.Status{Daemon: healthy.Daemon}},
+		{"FAILING", ralph.Status{Daemon: healthy.Daemon, QueueReady: 1, CodexCircuitState: "open"}},
+	}
+	for _, tc := range cases {
+		match, err := parseFleetStatusFilter(tc.filter)
+		if err != nil {
+			t.Fatalf("parse %q: %v", tc.filter, err)
+		}
+		if !match(tc.match) {
+			t.Fatalf("%s should match %+v", tc.filter, tc.match)
+		}
+		if match(healthy) {
+			t.Fatalf("%s should not match a healthy project", tc.filter)
+		}
+	}
+
+	if match, err := parseFleetStatusFilter(""); err != nil || match != nil {
+		t.Fatalf("empty filter should keep every project: match=%v err=%v", match != nil, err)
+	}
+	if _, err := parseFleetStatusFilter("broken"); err == nil {
+		t.Fatalf("expected error for unknown filter")
+	}
+}
+
+func TestIsFailingFleetStatusUsesCurrentSignals(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	stale := ralph.Status{
+		CodexCircuitState:    "closed",
+		LastFailureCause:     "codex exit 1",
+		LastFailureUpdatedAt: now.Add(-3 * time.Hour).Format(time.RFC3339),
+	}
+	if isFailingFleetStatus(stale, now) {
+		t.Fatalf("an old blocked failure should not keep the project failing")
+	}
+	recent := stale
+	recent.LastFailureUpdatedAt = now.Add(-10 * time.Minute).Format(time.RFC3339)
+	if !isFailingFleetStatus(recent, now) {
+		t.Fatalf("a failure inside the window should mark the project failing")
+	}
+	permission := stale
+	permission.LastPermissionStreak = 2
+	if !isFailingFleetStatus(permission, now) {
+		t.Fatalf("an active permission streak should mark the project failing")
+	}
+	open := stale
+	open.CodexCircuitState = "open"
+	if !isFailingFleetStatus(open, now) {
+		t.Fatalf("an open circuit should mark the project failing")
+	}
+}
@@ -22,8 +22,13 @@ import (
 )
 
 func main() {
-	if err := run(); err != nil {
+	closeLog := attachDaemonLog()
+	err := run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+	}
+	closeLog()
+	if err != nil {
 		os.Exit(1)
 	}
 }
@@ -39,10 +44,13 @@ func run() error {
 	global.SetOutput(os.Stderr)
 	controlDir := global.String("control-dir", defaultControl, "directory that stores shared plugins and fleet config")
 	projectDir := global.String("project-dir", cwd, "target project directory (.ralph lives here)")
+	outputDir := global.String("output-dir", "", "relocate reports, logs and pid files outside the project (same as RALPH_OUTPUT_DIR / profile output_dir)")
+	pluginDir := global.String("plugin-dir", "", "load plugins and registry.json from this dir instead of <control-dir>/plugins (same as RALPH_PLUGIN_DIR)")
+	codexDryRun := global.Bool("codex-dry-run", false, "log codex prompts under .ralph/reports/codex-dry-run instead of running codex (same as RALPH_CODEX_DRY_RUN=true)")
 
 	global.Usage = func() {
-		fmt.Fprintln(os.Stderr, "Usage: ralphctl [--control-dir DIR] [--project-dir DIR] <command> [args]")
-		fmt.Fprintln(os.Stderr, "Commands: list-plugins, install, apply-plugin, registry, setup, reload, init, on, off, new, intake, import-prd, recover, retry-blocked, doctor, run, supervise, start, stop, restart, status, tail, service, fleet, telegram, cp")
+		fmt.Fprintln(os.Stderr, "Usage: ralphctl [--control-dir DIR] [--project-dir DIR] [--output-dir DIR] [--plugin-dir DIR] [--codex-dry-run] <command> [args]")
+		fmt.Fprintln(os.Stderr, "Commands: list-plugins, install, apply-plugin, registry, setup, reload, init, on, off, new, issue, profile, intake, import-prd, export-prd, recover, retry-blocked, doctor, migrate, run, supervise, start, stop, restart, status, history, tail, logs, service, fleet, telegram, notify, cp")
 	}
 
 	if err := global.Parse(os.Args[1:]); err != nil {
@@ -57,6 +65,31 @@ func run() error {
 		global.Usage()
 		return fmt.Errorf("command is required")
 	}
+	if strings.TrimSpace(*outputDir) != "" {
+		absOutput, err := filepath.Abs(strings.TrimSpace(*outputDir))
+		if err != nil {
+			return fmt.Errorf("resolve output-dir: %w", err)
+		}
+		if err := os.Setenv("RALPH_OUTPUT_DIR", absOutput); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(*pluginDir) != "" {
+		absPluginDir, err := filepath.Abs(strings.TrimSpace(*pluginDir))
+		if err != nil {
+			return fmt.Errorf("resolve plugin-dir: %w", err)
+		}
+		// Exported so spawned daemons and workers read the same plugins.
+		if err := os.Setenv(ralph.PluginDirEnv, absPluginDir); err != nil {
+			return err
+		}
+	}
+	if *codexDryRun {
+		// Env overrides every profile layer and is inherited by spawned workers.
+		if err := os.Setenv("RALPH_CODEX_DRY_RUN", "true"); err != nil {
+			return err
+		}
+	}
 
 	cmd := args[0]
 	cmdArgs := args[1:]
@@ -87,6 +120,13 @@ func run() error {
 		}
 		return runTelegramCommand(*controlDir, paths, cmdArgs)
 	}
+	if cmd == "notify" {
+		paths, err := ralph.NewPaths(*controlDir, *projectDir)
+		if err != nil {
+			return err
+		}
+		return runNotifyCommand(*controlDir, paths, cmdArgs)
+	}
 	if cmd == "cp" {
 		return runControlPlaneCommand(*controlDir, *projectDir, cmdArgs)
 	}
@@ -110,6 +150,7 @@ func run() error {
 	case "install":
 		fs := flag.NewFlagSet("install", flag.ContinueOnError)
 		plugin := fs.String("plugin", "universal-default", "plugin name")
+		skipVerify := fs.Bool("skip-verify", false, "skip plugin registry checksum verification")
 		if err := fs.Parse(cmdArgs); err != nil {
 			return err
 		}
@@ -117,7 +158,8 @@ func run() error {
 		if err != nil {
 			return err
 		}
-		if err := ralph.Install(paths, *plugin, exe); err != nil {
+		warnSkipVerify(os.Stderr, *skipVerify, *plugin)
+		if err := ralph.InstallWithOptions(paths, *plugin, exe, ralph.ApplyPluginOptions{SkipVerify: *skipVerify}); err != nil {
 			return err
 		}
 		fmt.Println("Ralph Runtime Installed")
@@ -132,13 +174,24 @@ func run() error {
 	case "apply-plugin":
 		fs := flag.NewFlagSet("apply-plugin", flag.ContinueOnError)
 		plugin := fs.String("plugin", "", "plugin name")
+		showDiff := fs.Bool("diff", false, "print the profile.yaml diff and ask before writing")
+		yes := fs.Bool("yes", false, "with --diff: apply without prompting")
+		skipVerify := fs.Bool("skip-verify", false, "skip plugin registry checksum verification")
 		if err := fs.Parse(cmdArgs); err != nil {
 			return err
 		}
 		if strings.TrimSpace(*plugin) == "" {
 			return fmt.Errorf("--plugin is required")
 		}
-		if err := ralph.ApplyPlugin(paths, *plugin); err != nil {
+		opts := ralph.ApplyPluginOptions{SkipVerify: *skipVerify}
+		warnSkipVerify(os.Stderr, *skipVerify, *plugin)
+		if *showDiff {
+			proceed, err := previewApplyPlugin(paths, *plugin, opts, *yes, os.Stdin, os.Stdout)
+			if err != nil || !proceed {
+				return err
+			}
+		}
+		if err := ralph.ApplyPluginWithOptions(paths, *plugin, opts); err != nil {
 			return err
 		}
 		fmt.Println("Plugin Applied")
@@ -158,9 +211,25 @@ func run() error {
 		fleetRegister := fs.Bool("fleet-register", true, "register this project to fleet list (enabled by default)")
 		fleetID := fs.String("fleet-id", "", "register this project into fleet with the given id")
 		fleetPRD := fs.String("fleet-prd", "PRD.md", "fleet PRD path used for setup registration")
+		profileFrom := fs.String("profile-from", "", "seed setup choices from another managed project dir (same control dir)")
+		roleModelFlags := map[string]*string{}
+		for _, role := range ralph.RequiredAgentRoles {
+			roleModelFlags[role] = fs.String("model-"+role, "", "codex model for "+role+" role (inherit=use codex_model)")
+		}
 		if err := fs.Parse(cmdArgs); err != nil {
 			return err
 		}
+		roleModels := map[string]string{}
+		for role, value := range roleModelFlags {
+			model := strings.TrimSpace(*value)
+			if model == "" {
+				continue
+			}
+			if strings.EqualFold(model, "inherit") {
+				model = ""
+			}
+			roleModels[role] = model
+		}
 		exe, err := executablePath()
 		if err != nil {
 			return err
@@ -180,12 +249,44 @@ func run() error {
 			*advanced = false
 		}
 
+		if *advanced && len(roleModels) > 0 {
+			return fmt.Errorf("--model-<role> flags apply to non-interactive setup only (the wizard prompts for role models)")
+		}
+
+		var seed *ralph.SetupSelections
+		if from := strings.TrimSpace(*profileFrom); from != "" {
+			seeded, err := ralph.SetupSelectionsFromProject(*controlDir, from)
+			if err != nil {
+				return fmt.Errorf("--profile-from: %w", err)
+			}
+			seed = &seeded
+			fmt.Printf("Profile From: %s\n", from)
+		}
+
 		if *advanced {
-			if err := ralph.RunSetupWizard(paths, exe, *plugin, os.Stdin, os.Stdout); err != nil {
+			opts := ralph.SetupWizardOptions{Seed: seed, Telegram: telegramSetupWizardStep(paths)}
+			if err := ralph.RunSetupWizardWithOptions(paths, exe, *plugin, opts, os.Stdin, os.Stdout); err != nil {
 				return err
 			}
 		} else {
 			selection := ralph.DefaultSetupSelections(strings.TrimSpace(*plugin))
+			if seed != nil {
+				selection = *seed
+				if p := strings.TrimSpace(*plugin); p != "" {
+					selection.Plugin = p
+				}
+			}
+			for role, model := range roleModels {
+				if selection.RoleModels == nil {
+					selection.RoleModels = map[string]string{}
+				}
+				selection.RoleModels[role] = model
+			}
+			if seed != nil && !*nonInteractive {
+				if err := ralph.ConfirmSetupSelections(selection, os.Stdin, os.Stdout); err != nil {
+					return err
+				}
+			}
 			if err := ralph.ApplySetupSelections(paths, exe, selection); err != nil {
 				return err
 			}
@@ -239,6 +340,7 @@ func run() error {
 		restartRunning := fs.Bool("restart-running", true, "restart loop/telegram daemons that were running before reload")
 		telegram := fs.Bool("telegram", true, "reload telegram daemon when it is running")
 		currentOnly := fs.Bool("current-only", false, "reload only current project")
+		check := fs.Bool("check", false, "dry run: report what would be restarted/rewritten without changing anything")
 		if err := fs.Parse(cmdArgs); err != nil {
 			return err
 		}
@@ -250,6 +352,7 @@ func run() error {
 			RestartRunning: *restartRunning,
 			ReloadTelegram: *telegram,
 			CurrentOnly:    *currentOnly,
+			Check:          *check,
 		})
 		if err != nil {
 			return err
@@ -280,20 +383,54 @@ func run() error {
 
 	case "new":
 		fs := flag.NewFlagSet("new", flag.ContinueOnError)
-		priority := fs.Int("priority", 0, "optional priority (lower value runs first)")
+		priority := fs.Int("priority", 0, "optional priority (lower value runs first, negative allowed, 0=default)")
+		top := fs.Bool("top", false, "run before every ready issue (priority one below the current lowest)")
 		storyID := fs.String("story-id", "", "optional external story id")
+		assignee := fs.String("assignee", "", "optional owner for human-in-the-loop issues")
+		template := fs.String("template", "", "issue body template: name under <control-dir>/issue-templates or file path (default: <role>.md when present)")
+		batch := fs.String("batch", "", "create one issue per line of FILE (- = stdin): role<TAB>title or just title")
+		defaultRole := fs.String("default-role", "developer", "role for --batch lines without a role")
+		dedupeDefault := false
+		if profile, err := ralph.LoadProfile(paths); err == nil {
+			dedupeDefault = profile.IssueDedupe
+		}
+		dedupe := fs.Bool("dedupe", dedupeDefault, "refuse to create an issue when an open issue has the same role and title (default: profile issue_dedupe)")
 		if err := fs.Parse(cmdArgs); err != nil {
 			return err
 		}
 		args := fs.Args()
+		if *top && *priority != 0 {
+			return fmt.Errorf("--top and --priority are mutually exclusive")
+		}
+		if strings.TrimSpace(*batch) != "" {
+			if *top {
+				return fmt.Errorf("--top cannot be used with --batch")
+			}
+			if len(args) > 0 {
+				return fmt.Errorf("--batch does not take role/title arguments")
+			}
+			if strings.TrimSpace(*storyID) != "" {
+				return fmt.Errorf("--story-id cannot be shared across --batch issues")
+			}
+			return runNewBatch(paths, strings.TrimSpace(*batch), strings.TrimSpace(*defaultRole), ralph.IssueCreateOptions{
+				Priority: *priority,
+				Assignee: *assignee,
+				Template: *template,
+				Dedupe:   *dedupe,
+			}, os.Stdin, os.Stdout)
+		}
 		if len(args) < 2 {
-			return fmt.Errorf("usage: new [--priority N] [--story-id ID] <manager|planner|developer|qa> <title>")
+			return fmt.Errorf("usage: new [--priority N|--top] [--story-id ID] [--assignee NAME] [--template NAME|FILE] <manager|planner|developer|qa> <title> | new --batch FILE|- [--default-role ROLE] [--dedupe]")
 		}
 		role := args[0]
 		title := strings.Join(args[1:], " ")
 		path, _, err := ralph.CreateIssueWithOptions(paths, role, title, ralph.IssueCreateOptions{
 			Priority: *priority,
+			Top:      *top,
 			StoryID:  *storyID,
+			Assignee: *assignee,
+			Template: *template,
+			Dedupe:   *dedupe,
 		})
 		if err != nil {
 			return err
@@ -315,35 +452,118 @@ func run() error {
 
 	case "import-prd":
 		fs := flag.NewFlagSet("import-prd", flag.ContinueOnError)
-		file := fs.String("file", "prd.json", "path to prd json file")
+		files := stringListFlag{}
+		fs.Var(&files, "file", "path or glob to prd json/yaml file (repeatable, default prd.json)")
+		format := fs.String("format", "auto", "prd file format: auto|json|yaml (auto uses the file extension)")
 		defaultRole := fs.String("default-role", "developer", "fallback role for stories with missing/invalid role")
 		dryRun := fs.Bool("dry-run", false, "preview without creating issues")
+		merge := fs.Bool("merge", false, "update title/description/priority of existing non-done issues with the same story id")
 		if err := fs.Parse(cmdArgs); err != nil {
 			return err
 		}
-		result, err := ralph.ImportPRDStories(paths, *file, *defaultRole, *dryRun)
+		if len(files) == 0 {
+			files = append(files, "prd.json")
+		}
+		result, err := ralph.ImportPRDFilesWithOptions(paths, files, ralph.PRDImportOptions{
+			Format:      *format,
+			DefaultRole: *defaultRole,
+			DryRun:      *dryRun,
+			Merge:       *merge,
+		})
 		if err != nil {
 			return err
 		}
 		fmt.Println("prd import summary")
-		fmt.Printf("- source: %s\n", result.SourcePath)
+		for _, sourcePath := range result.SourcePaths {
+			fmt.Printf("- source: %s\n", sourcePath)
+		}
 		fmt.Printf("- dry_run: %t\n", result.DryRun)
 		fmt.Printf("- stories_total: %d\n", result.StoriesTotal)
 		fmt.Printf("- imported: %d\n", result.Imported)
+		if result.Merge {
+			fmt.Printf("- updated: %d\n", result.Updated)
+		}
 		fmt.Printf("- skipped_passed: %d\n", result.SkippedPassed)
 		fmt.Printf("- skipped_existing: %d\n", result.SkippedExisting)
 		fmt.Printf("- skipped_invalid: %d\n", result.SkippedInvalid)
 		for _, createdPath := range result.CreatedPaths {
 			fmt.Printf("- created: %s\n", createdPath)
 		}
+		for _, update := range result.Updates {
+			fmt.Printf("- merged: %s %s (%s)\n", update.StoryID, update.Path, strings.Join(update.Changed, ","))
+		}
+		return nil
+
+	case "export-prd":
+		fs := flag.NewFlagSet("export-prd", flag.ContinueOnError)
+		file := fs.String("file", "prd-export.json", "path to write prd json file")
+		includeDone := fs.Bool("include-done", false, "include done issues (exported with passes=true)")
+		statusCSV := fs.String("status", "", "comma-separated status filter (ready,in-progress,blocked,done)")
+		if err := fs.Parse(cmdArgs); err != nil {
+			return err
+		}
+		statuses := []string{}
+		for _, raw := range strings.Split(*statusCSV, ",") {
+			if strings.TrimSpace(raw) != "" {
+				statuses = append(statuses, raw)
+			}
+		}
+		result, err := ralph.ExportPRDStories(paths, *file, ralph.PRDExportOptions{
+			IncludeDone: *includeDone,
+			Statuses:    statuses,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println("prd export summary")
+		fmt.Printf("- output: %s\n", result.OutputPath)
+		fmt.Printf("- exported: %d\n", result.Exported)
+		fmt.Printf("- skipped_filtered: %d\n", result.SkippedFiltered)
+		fmt.Printf("- skipped_duplicates: %d\n", result.SkippedDuplicates)
 		return nil
 
+	case "issue":
+		return runIssueCommand(paths, cmdArgs)
+
+	case "profile":
+		return runProfileCommand(paths, cmdArgs)
+
 	case "recover":
-		recovered, err := ralph.RecoverInProgressWithCount(paths)
+		fs := flag.NewFlagSet("recover", flag.ContinueOnError)
+		dryRun := fs.Bool("dry-run", false, "list in-progress issues, their age and owning worker without moving anything")
+		force := fs.Bool("force", false, "also reset issues whose owning worker is still alive")
+		staleAfter := fs.Duration("stale-after", 0, "only reset issues in-progress at least this long, e.g. 30m (0=any age)")
+		if err := fs.Parse(cmdArgs); err != nil {
+			return err
+		}
+		if *staleAfter < 0 {
+			return fmt.Errorf("--stale-after must be >= 0")
+		}
+		opts := ralph.RecoverInProgressOptions{Force: *force, StaleAfter: *staleAfter}
+		if *dryRun {
+			issues, err := ralph.ListInProgressIssues(paths)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("in-progress issues: %d (dry-run)\n", len(issues))
+			fmt.Printf("- stale_after: %s\n", recoverStaleAfterLabel(*staleAfter))
+			for _, issue := range issues {
+				fmt.Printf("- %s role=%s age=%s worker=%s action=%s\n", issue.Meta.ID, issue.Meta.Role, issue.Age.Round(time.Second), recoverWorkerLabel(issue), opts.RecoverAction(issue))
+			}
+			return nil
+		}
+		res, err := ralph.RecoverInProgressWithOptions(paths, opts)
 		if err != nil {
 			return err
 		}
-		fmt.Printf("recovered in-progress issues: %d\n", recovered)
+		fmt.Printf("recovered in-progress issues: %d\n", res.Recovered)
+		fmt.Printf("- stale_after: %s\n", recoverStaleAfterLabel(*staleAfter))
+		if res.SkippedLive > 0 {
+			fmt.Printf("- skipped_live_worker: %d (use --force to reset)\n", res.SkippedLive)
+		}
+		if res.SkippedFresh > 0 {
+			fmt.Printf("- skipped_not_stale: %d\n", res.SkippedFresh)
+		}
 		return nil
 
 	case "retry-blocked":
@@ -373,6 +593,7 @@ func run() error {
 		fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
 		strict := fs.Bool("strict", false, "exit with error when failing checks are found")
 		repair := fs.Bool("repair", false, "run safe repair actions before checks")
+		asJSON := fs.Bool("json", false, "print report as JSON (repair actions go to stderr)")
 		if err := fs.Parse(cmdArgs); err != nil {
 			return err
 		}
@@ -381,76 +602,169 @@ func run() error {
 			if err != nil {
 				return err
 			}
-			fmt.Println("## Ralph Doctor Repair")
+			repairOut := io.Writer(os.Stdout)
+			if *asJSON {
+				repairOut = os.Stderr
+			}
+			fmt.Fprintln(repairOut, "## Ralph Doctor Repair")
 			for _, action := range actions {
-				fmt.Printf("- [%s] %s: %s\n", action.Status, action.Name, action.Detail)
+				fmt.Fprintf(repairOut, "- [%s] %s: %s\n", action.Status, action.Name, action.Detail)
 			}
 		}
 		report, err := ralph.RunDoctor(paths)
 		if err != nil {
 			return err
 		}
-		report.Print(os.Stdout)
+		report.Checks = append(report.Checks, telegramConfigDoctorChecks(paths)...)
+		if *asJSON {
+			if err := printJSON(report); err != nil {
+				return err
+			}
+		} else {
+			report.Print(os.Stdout)
+		}
 		if *strict && report.HasFailures() {
 			return fmt.Errorf("doctor reported failing checks")
 		}
 		return nil
 
+	case "migrate":
+		fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+		asJSON := fs.Bool("json", false, "print migration result as JSON")
+		if err := fs.Parse(cmdArgs); err != nil {
+			return err
+		}
+		res, err := ralph.MigrateState(paths)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(res)
+		}
+		if !res.Migrated {
+			fmt.Printf("state schema up to date: v%d\n", res.ToVersion)
+			return nil
+		}
+		fmt.Printf("state schema migrated: v%d -> v%d\n", res.FromVersion, res.ToVersion)
+		for _, file := range res.Files {
+			fmt.Printf("- rewritten: %s\n", file)
+		}
+		return nil
+
 	case "run":
 		fs := flag.NewFlagSet("run", flag.ContinueOnError)
 		maxLoops := fs.Int("max-loops", 1, "0 means infinite")
-		rolesRaw := fs.String("roles", "", "comma-separated role scope (manager,planner,developer,qa)")
+		rolesRaw := fs.String("roles", "", "comma-separated role scope (manager,planner,developer,qa; aliases: all, dev=developer,qa)")
 		engine := fs.String("engine", "auto", "execution engine: auto|v1|v2")
 		executeWithCodex := fs.Bool("execute-with-codex", false, "when engine=v2, run codex execution step before verify")
+		logFormat := fs.String("log-format", "text", "loop output format: text|json (json emits one object per line)")
+		maxRuntime := fs.Duration("max-runtime", 0, "stop cleanly after this wall-clock duration, e.g. 2h (0=unlimited)")
+		httpAddr := fs.String("http-addr", "", "serve /status, /healthz, /metrics on this address (\":9090\" binds 127.0.0.1)")
+		codexModel := fs.String("codex-model", "", "use this codex model for every role for this run only (not saved to the profile)")
+		codexSandbox := fs.String("codex-sandbox", "", "override codex_sandbox for this run only: read-only|workspace-write|danger-full-access (not saved to the profile)")
+		codexApproval := fs.String("codex-approval", "", "override codex_approval for this run only: untrusted|on-failure|on-request|never (not saved to the profile)")
 		if err := fs.Parse(cmdArgs); err != nil {
 			return err
 		}
+		if *maxRuntime < 0 {
+			return fmt.Errorf("--max-runtime must be >= 0")
+		}
 		allowedRoles, err := ralph.ParseRolesCSV(*rolesRaw)
 		if err != nil {
 			return err
 		}
+		applyCodexModelOverride(*codexModel, os.Stdout)
+		if err := applyCodexPolicyOverride(*codexSandbox, *codexApproval, os.Stdout); err != nil {
+			return err
+		}
+		resolvedLogFormat, err := ralph.NormalizeLoopLogFormat(*logFormat)
+		if err != nil {
+			return err
+		}
 		profile, err := ralph.LoadProfile(paths)
 		if err != nil {
 			return err
 		}
-		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-		defer stop()
 		resolvedEngine, cutoverState, err := resolveRunEngine(paths.ProjectDir, *engine)
 		if err != nil {
 			return err
 		}
 		if resolvedEngine == "v2" {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
 			if len(allowedRoles) > 0 {
 				return fmt.Errorf("roles are not supported with engine=v2 yet; use --engine v1 for role-scoped workers")
 			}
 			fmt.Fprintf(os.Stdout, "[ralph-run] engine=v2 (cutover_mode=%s canary=%t)\n", cutoverState.Mode, cutoverState.Canary)
-			return runControlPlaneLoop(ctx, paths, profile, *maxLoops, *controlDir, *executeWithCodex, os.Stdout)
+			if err := startStatusHTTP(ctx, paths, *httpAddr, os.Stdout); err != nil {
+				return err
+			}
+			runCtx := ctx
+			if *maxRuntime > 0 {
+				var cancelRun context.CancelFunc
+				runCtx, cancelRun = context.WithTimeout(ctx, *maxRuntime)
+				defer cancelRun()
+			}
+			if err := runControlPlaneLoop(runCtx, paths, profile, *maxLoops, *controlDir, *executeWithCodex, os.Stdout); err != nil {
+				return err
+			}
+			if ctx.Err() == nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+				fmt.Fprintf(os.Stdout, "[ralph-run] max runtime reached (%s); stopped cleanly\n", *maxRuntime)
+			}
+			return nil
+		}
+		// Keep stdout pure JSON lines in json mode; run notes go to stderr.
+		noteOut := io.Writer(os.Stdout)
+		if resolvedLogFormat == ralph.LoopLogFormatJSON {
+			noteOut = os.Stderr
 		}
 		if *executeWithCodex {
-			fmt.Fprintln(os.Stdout, "[ralph-run] note: --execute-with-codex is ignored when engine=v1")
+			fmt.Fprintln(noteOut, "[ralph-run] note: --execute-with-codex is ignored when engine=v1")
+		}
+		fmt.Fprintf(noteOut, "[ralph-run] engine=v1 (cutover_mode=%s canary=%t)\n", cutoverState.Mode, cutoverState.Canary)
+		ctx, drain, stop := drainSignalContext(noteOut)
+		defer stop()
+		if err := startStatusHTTP(ctx, paths, *httpAddr, noteOut); err != nil {
+			return err
 		}
-		fmt.Fprintf(os.Stdout, "[ralph-run] engine=v1 (cutover_mode=%s canary=%t)\n", cutoverState.Mode, cutoverState.Canary)
-		return ralph.RunLoop(ctx, paths, profile, ralph.RunOptions{MaxLoops: *maxLoops, Stdout: os.Stdout, AllowedRoles: allowedRoles})
+		return ralph.RunLoop(ctx, paths, profile, ralph.RunOptions{MaxLoops: *maxLoops, Stdout: os.Stdout, AllowedRoles: allowedRoles, LogFormat: resolvedLogFormat, MaxRuntime: *maxRuntime, Drain: drain})
 
 	case "supervise":
 		fs := flag.NewFlagSet("supervise", flag.ContinueOnError)
-		rolesRaw := fs.String("roles", "", "comma-separated role scope (manager,planner,developer,qa)")
+		rolesRaw := fs.String("roles", "", "comma-separated role scope (manager,planner,developer,qa; aliases: all, dev=developer,qa)")
 		engine := fs.String("engine", "auto", "execution engine: auto|v1|v2")
 		executeWithCodex := fs.Bool("execute-with-codex", false, "when engine=v2, run codex execution step before verify")
+		maxRuntime := fs.Duration("max-runtime", 0, "stop cleanly after this wall-clock duration, e.g. 2h (0=unlimited)")
+		httpAddr := fs.String("http-addr", "", "serve /status, /healthz, /metrics on this address (\":9090\" binds 127.0.0.1)")
+		codexModel := fs.String("codex-model", "", "use this codex model for every role in the supervised workers (not saved to the profile)")
+		windowsService := fs.String("windows-service", "", "run under the windows service control manager as this service (set by service install)")
 		if err := fs.Parse(cmdArgs); err != nil {
 			return err
 		}
+		if *maxRuntime < 0 {
+			return fmt.Errorf("--max-runtime must be >= 0")
+		}
 		allowedRoles, err := ralph.ParseRolesCSV(*rolesRaw)
 		if err != nil {
 			return err
 		}
+		applyCodexModelOverride(*codexModel, os.Stdout)
 		profile, err := ralph.LoadProfile(paths)
 		if err != nil {
 			return err
 		}
+		supervise := func(ctx context.Context, out io.Writer) error {
+			if err := startStatusHTTP(ctx, paths, *httpAddr, out); err != nil {
+				return err
+			}
+			return ralph.RunSupervisor(ctx, paths, profile, allowedRoles, *engine, *executeWithCodex, *maxRuntime, out)
+		}
+		if name := strings.TrimSpace(*windowsService); name != "" {
+			return runSupervisorWindowsService(paths, profile, name, supervise)
+		}
 		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer stop()
-		return ralph.RunSupervisor(ctx, paths, profile, allowedRoles, *engine, *executeWithCodex, os.Stdout)
+		return supervise(ctx, os.Stdout)
 
 	case "start":
 		fs := flag.NewFlagSet("start", flag.ContinueOnError)
@@ -473,7 +787,14 @@ func run() error {
 		return nil
 
 	case "stop":
-		if err := ralph.StopDaemon(paths); err != nil {
+		fs := flag.NewFlagSet("stop", flag.ContinueOnError)
+		drainTimeout := fs.Duration("drain-timeout", 0, "max wait for in-flight codex work after SIGTERM before SIGKILL (0=codex exec timeout + grace)")
+		if err := fs.Parse(cmdArgs); err != nil {
+			return err
+		}
+		if err := withProjectControlLock(paths, "stop", func() error {
+			return ralph.StopDaemonWithDrain(paths, *drainTimeout)
+		}); err != nil {
 			return err
 		}
 		fmt.Println("Ralph Loop")
@@ -482,11 +803,20 @@ func run() error {
 		return nil
 
 	case "restart":
-		if err := ralph.StopDaemon(paths); err != nil {
+		fs := flag.NewFlagSet("restart", flag.ContinueOnError)
+		drainTimeout := fs.Duration("drain-timeout", 0, "max wait for in-flight codex work after SIGTERM before SIGKILL (0=codex exec timeout + grace)")
+		if err := fs.Parse(cmdArgs); err != nil {
 			return err
 		}
-		pid, _, err := ralph.StartDaemon(paths)
-		if err != nil {
+		var pid int
+		if err := withProjectControlLock(paths, "restart", func() error {
+			if err := ralph.StopDaemonWithDrain(paths, *drainTimeout); err != nil {
+				return err
+			}
+			var err error
+			pid, _, err = ralph.StartDaemon(paths)
+			return err
+		}); err != nil {
 			return err
 		}
 		fmt.Println("Ralph Loop")
@@ -495,38 +825,103 @@ func run() error {
 		return nil
 
 	case "status":
-		st, err := ralph.GetStatus(paths)
-		if err != nil {
-			return err
-		}
-		st.Print(os.Stdout)
-		cutoverState, cutoverErr := ralph.ControlPlaneGetCutoverState(paths.ProjectDir)
-		if cutoverErr == nil {
-			fmt.Fprintln(os.Stdout)
-			fmt.Fprintln(os.Stdout, "[Control Plane]")
-			fmt.Fprintf(os.Stdout, "Mode:   %s\n", cutoverState.Mode)
-			fmt.Fprintf(os.Stdout, "Canary: %t\n", cutoverState.Canary)
-			if cutoverState.UpdatedAtUTC != "" {
-				fmt.Fprintf(os.Stdout, "Updated: %s\n", cutoverState.UpdatedAtUTC)
-			}
-			if cutoverState.Mode == "v2" {
-				cpStatus, cpErr := ralph.ControlPlaneStatusReport(paths.ProjectDir)
-				if cpErr == nil {
-					fmt.Fprintf(os.Stdout, "Tasks:  total=%d ready=%d running=%d verifying=%d done=%d blocked=%d\n",
-						cpStatus.TasksTotal,
-						cpStatus.StateCounts[ralph.ControlPlaneTaskStateReady],
-						cpStatus.StateCounts[ralph.ControlPlaneTaskStateRunning],
-						cpStatus.StateCounts[ralph.ControlPlaneTaskStateVerifying],
-						cpStatus.StateCounts[ralph.ControlPlaneTaskStateDone],
-						cpStatus.StateCounts[ralph.ControlPlaneTaskStateBlocked],
-					)
-					fmt.Fprintf(os.Stdout, "KPI:    blocked_rate=%.4f recovery_success_rate=%.4f mttr_seconds=%.2f\n",
-						cpStatus.Metrics.BlockedRate,
-						cpStatus.Metrics.RecoverySuccessRate,
-						cpStatus.Metrics.MeanTimeToRecovery,
-					)
+		fs := flag.NewFlagSet("status", flag.ContinueOnError)
+		asJSON := fs.Bool("json", false, "print status as JSON")
+		since := fs.Duration("since", ralph.DefaultStatusThroughputWindow, "window for throughput (issues completed per hour)")
+		notifyDefaults := defaultTelegramCLIConfig()
+		followJSON := fs.Bool("follow-json", false, "stream a JSON line per status change until interrupted")
+		watch := fs.Bool("watch", false, "clear the screen and re-render status until interrupted")
+		intervalSec := fs.Int("interval-sec", 5, "status poll interval for --watch/--follow-json")
+		retryThreshold := fs.Int("retry-threshold", notifyDefaults.NotifyRetryThreshold, "codex retry alert threshold for --follow-json")
+		permStreakThreshold := fs.Int("perm-streak-threshold", notifyDefaults.NotifyPermStreakThreshold, "permission streak alert threshold for --follow-json")
+		if err := fs.Parse(cmdArgs); err != nil {
+			return err
+		}
+		if *since <= 0 {
+			return fmt.Errorf("--since must be > 0")
+		}
+		if *followJSON && *watch {
+			return fmt.Errorf("--follow-json cannot be combined with --watch")
+		}
+		if *followJSON {
+			if *intervalSec <= 0 {
+				return fmt.Errorf("--interval-sec must be > 0")
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return runStatusFollowJSON(ctx, paths, statusFollowOptions{
+				Since:          *since,
+				Interval:       time.Duration(*intervalSec) * time.Second,
+				RetryThreshold: *retryThreshold,
+				PermThreshold:  *permStreakThreshold,
+			}, os.Stdout)
+		}
+		if *watch {
+			if *asJSON {
+				return fmt.Errorf("--watch cannot be combined with --json")
+			}
+			if *intervalSec <= 0 {
+				return fmt.Errorf("--interval-sec must be > 0")
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return runStatusWatch(ctx, paths, *since, time.Duration(*intervalSec)*time.Second, os.Stdout)
+		}
+		if *asJSON {
+			st, err := ralph.GetStatusSince(paths, *since)
+			if err != nil {
+				return err
+			}
+			return printJSON(st)
+		}
+		return renderStatusText(paths, *since, os.Stdout)
+
+	case "history":
+		fs := flag.NewFlagSet("history", flag.ContinueOnError)
+		limit := fs.Int("limit", ralph.CompletedHistoryLimit, "max number of completed issues to show")
+		rolesRaw := fs.String("role", "", "comma-separated role filter (manager,planner,developer,qa)")
+		asJSON := fs.Bool("json", false, "print completed issues as JSON")
+		since := fs.Duration("since", 0, "only issues finished within this window, e.g. 1h (0=all)")
+		if err := fs.Parse(cmdArgs); err != nil {
+			return err
+		}
+		if *limit <= 0 {
+			return fmt.Errorf("--limit must be > 0")
+		}
+		if *since < 0 {
+			return fmt.Errorf("--since must be >= 0")
+		}
+		roles, err := ralph.ParseRolesCSV(*rolesRaw)
+		if err != nil {
+			return err
+		}
+		entries, err := ralph.LoadCompletedHistory(paths)
+		if err != nil {
+			return err
+		}
+		if *since > 0 {
+			entries = ralph.CompletedSince(entries, time.Now().UTC().Add(-*since))
+		}
+		filtered := []ralph.CompletedIssue{}
+		for _, entry := range entries {
+			if len(roles) > 0 {
+				if _, ok := roles[entry.Role]; !ok {
+					continue
 				}
 			}
+			filtered = append(filtered, entry)
+			if len(filtered) >= *limit {
+				break
+			}
+		}
+		if *asJSON {
+			return printJSON(filtered)
+		}
+		fmt.Println("## Completed Issues")
+		fmt.Printf("- project: %s\n", paths.ProjectDir)
+		fmt.Printf("- count: %d\n", len(filtered))
+		for _, entry := range filtered {
+			fmt.Printf("- %s\n", ralph.FormatCompletedIssue(entry))
 		}
 		return nil
 
@@ -539,6 +934,20 @@ func run() error {
 		}
 		return ralph.TailRunner(paths, *lines, *follow)
 
+	case "logs":
+		fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+		lines := fs.Int("lines", 120, "number of merged lines")
+		follow := fs.Bool("follow", false, "follow appended lines across all logs")
+		if err := fs.Parse(cmdArgs); err != nil {
+			return err
+		}
+		if *lines <= 0 {
+			return fmt.Errorf("--lines must be > 0")
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return ralph.TailMergedLogs(ctx, paths, os.Stdout, *lines, *follow)
+
 	default:
 		global.Usage()
 		return fmt.Errorf("unknown command: %s", cmd)
@@ -548,7 +957,7 @@ func run() error {
 func runRegistryCommand(controlDir string, args []string) error {
 	usage := func() {
 		fmt.Fprintln(os.Stderr, "Usage: ralphctl --control-dir DIR registry <subcommand>")
-		fmt.Fprintln(os.Stderr, "Subcommands: generate, list, verify")
+		fmt.Fprintln(os.Stderr, "Subcommands: generate, list, verify, sign, verify-signature")
 	}
 	if len(args) == 0 {
 		usage()
@@ -583,6 +992,7 @@ func runRegistryCommand(controlDir string, args []string) error {
 		fmt.Println("## Plugin Registry")
 		fmt.Printf("- path: %s\n", ralph.PluginRegistryPath(controlDir))
 		fmt.Printf("- generated_at_utc: %s\n", reg.GeneratedAtUTC)
+		fmt.Printf("- signed: %t\n", strings.TrimSpace(reg.Signature) != "")
 		for _, entry := range reg.Plugins {
 			fmt.Printf("- name=%s file=%s sha256=%s\n", entry.Name, entry.File, entry.SHA256)
 		}
@@ -608,6 +1018,50 @@ func runRegistryCommand(controlDir string, args []string) error {
 		fmt.Println("plugin registry verification passed")
 		return nil
 
+	case "sign":
+		fs := flag.NewFlagSet("registry sign", flag.ContinueOnError)
+		keyFile := fs.String("key", "", "ed25519 private key (PKCS#8 PEM)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if strings.TrimSpace(*keyFile) == "" {
+			return fmt.Errorf("--key is required")
+		}
+		reg, err := ralph.SignPluginRegistry(controlDir, *keyFile)
+		if err != nil {
+			return err
+		}
+		fmt.Println("plugin registry signed")
+		fmt.Printf("- path: %s\n", ralph.PluginRegistryPath(controlDir))
+		fmt.Printf("- plugins: %d\n", len(reg.Plugins))
+		fmt.Printf("- signature: %s\n", reg.Signature)
+		return nil
+
+	case "verify-signature":
+		fs := flag.NewFlagSet("registry verify-signature", flag.ContinueOnError)
+		pubFile := fs.String("pub", ralph.RegistryPublicKeyPath(), "ed25519 public key (PEM); default $"+ralph.RegistryPublicKeyEnv)
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if strings.TrimSpace(*pubFile) == "" {
+			return fmt.Errorf("--pub or %s is required", ralph.RegistryPublicKeyEnv)
+		}
+		pub, err := ralph.LoadRegistryPublicKey(*pubFile)
+		if err != nil {
+			return err
+		}
+		reg, err := ralph.LoadPluginRegistry(controlDir)
+		if err != nil {
+			return err
+		}
+		if err := ralph.VerifyPluginRegistrySignature(reg, pub); err != nil {
+			return fmt.Errorf("plugin registry signature verification failed: %w", err)
+		}
+		fmt.Println("plugin registry signature verified")
+		fmt.Printf("- path: %s\n", ralph.PluginRegistryPath(controlDir))
+		fmt.Printf("- public_key: %s\n", *pubFile)
+		return nil
+
 	default:
 		usage()
 		return fmt.Errorf("unknown registry subcommand: %s", args[0])
@@ -624,6 +1078,7 @@ type reloadOptions struct {
 	RestartRunning bool
 	ReloadTelegram bool
 	CurrentOnly    bool
+	Check          bool
 }
 
 type reloadTarget struct {
@@ -637,6 +1092,8 @@ type reloadProjectResult struct {
 	ID                 string
 	ProjectDir         string
 	Source             string
+	DryRun             bool
+	WrapperChanged     bool
 	WrapperUpdated     bool
 	PrimaryWasRunning  bool
 	PrimaryPID         int
@@ -649,6 +1106,16 @@ type reloadProjectResult struct {
 }
 
 func startProjectDaemon(paths ralph.Paths, opts startOptions) (string, error) {
+	var result string
+	err := withProjectControlLock(paths, "start", func() error {
+		var err error
+		result, err = startProjectDaemonLocked(paths, opts)
+		return err
+	})
+	return result, err
+}
+
+func startProjectDaemonLocked(paths ralph.Paths, opts startOptions) (string, error) {
 	out := opts.Out
 	if out == nil {
 		out = os.Stdout
@@ -801,8 +1268,10 @@ func projectLooksManaged(paths ralph.Paths) bool {
 
 func reloadSingleProject(target reloadTarget, executable string, opts reloadOptions) (reloadProjectResult, error) {
 	paths := target.Paths
-	if err := ralph.EnsureLayout(paths); err != nil {
-		return reloadProjectResult{}, err
+	if !opts.Check {
+		if err := ralph.EnsureLayout(paths); err != nil {
+			return reloadProjectResult{}, err
+		}
 	}
 
 	primaryPID, primaryRunning, _ := telegramPIDState(paths.PIDFile)
@@ -829,6 +1298,18 @@ func reloadSingleProject(target reloadTarget, executable string, opts reloadOpti
 		TelegramPID:        telegramPID,
 		TelegramOrphanPIDs: append([]int(nil), telegramOrphanPIDs...),
 	}
+	wrapperChanged, err := ralph.ProjectWrapperNeedsUpdate(paths, executable)
+	if err != nil {
+		return res, err
+	}
+	res.WrapperChanged = wrapperChanged
+
+	if opts.Check {
+		res.DryRun = true
+		res.PrimaryRestarted = opts.RestartRunning && (primaryRunning || len(roleWorkers) > 0)
+		res.TelegramRestarted = opts.RestartRunning && opts.ReloadTelegram && telegramRunning
+		return res, nil
+	}
 
 	if opts.RestartRunning {
 		for _, role := range roleWorkers {
@@ -879,7 +1360,7 @@ func reloadSingleProject(target reloadTarget, executable string, opts reloadOpti
 		res.PrimaryRestarted = true
 	}
 	if opts.ReloadTelegram && telegramRunning {
-		runArgs := ensureTelegramForegroundArg([]string{"--config-file", telegramConfigFileFromArgs(paths.ControlDir, nil)})
+		runArgs := ensureTelegramForegroundArg([]string{"--config-file", telegramConfigFileFromArgs(paths, nil)})
 		if _, err := startTelegramDaemon(paths, runArgs); err != nil {
 			return res, err
 		}
@@ -889,22 +1370,29 @@ func reloadSingleProject(target reloadTarget, executable string, opts reloadOpti
 }
 
 func printReloadSummary(out io.Writer, executable, controlDir string, results []reloadProjectResult) {
-	fmt.Fprintln(out, "Ralph Reload")
-	fmt.Fprintln(out, "============")
+	dryRun := len(results) > 0 && results[0].DryRun
+	if dryRun {
+		fmt.Fprintln(out, "Ralph Reload (dry run)")
+		fmt.Fprintln(out, "======================")
+		fmt.Fprintln(out, "- mode: check (no daemons stopped/started, no wrappers written)")
+	} else {
+		fmt.Fprintln(out, "Ralph Reload")
+		fmt.Fprintln(out, "============")
+	}
 	fmt.Fprintf(out, "- control_dir: %s\n", controlDir)
 	fmt.Fprintf(out, "- binary: %s\n", executable)
 	fmt.Fprintf(out, "- projects: %d\n", len(results))
 	for _, res := range results {
 		fmt.Fprintf(out, "\n[%s] %s\n", res.ID, res.ProjectDir)
 		fmt.Fprintf(out, "- source: %s\n", res.Source)
-		fmt.Fprintf(out, "- wrapper: updated\n")
-		fmt.Fprintf(out, "- daemon_primary: %s\n", reloadRunStateLabel(res.PrimaryWasRunning, res.PrimaryRestarted, res.PrimaryPID))
+		fmt.Fprintf(out, "- wrapper: %s\n", reloadWrapperLabel(res))
+		fmt.Fprintf(out, "- daemon_primary: %s\n", reloadRunStateLabel(res.PrimaryWasRunning, res.PrimaryRestarted, res.DryRun, res.PrimaryPID))
 		if len(res.RoleWorkers) == 0 {
 			fmt.Fprintf(out, "- daemon_roles: none\n")
 		} else {
 			fmt.Fprintf(out, "- daemon_roles: %s\n", strings.Join(res.RoleWorkers, ","))
 		}
-		fmt.Fprintf(out, "- telegram: %s\n", reloadRunStateLabel(res.TelegramWasRunning, res.TelegramRestarted, res.TelegramPID))
+		fmt.Fprintf(out, "- telegram: %s\n", reloadRunStateLabel(res.TelegramWasRunning, res.TelegramRestarted, res.DryRun, res.TelegramPID))
 		if len(res.TelegramOrphanPIDs) > 0 {
 			parts := make([]string, 0, len(res.TelegramOrphanPIDs))
 			for _, pid := range res.TelegramOrphanPIDs {
@@ -915,73 +1403,380 @@ func printReloadSummary(out io.Writer, executable, controlDir string, results []
 	}
 }
 
-func reloadRunStateLabel(wasRunning, restarted bool, pid int) string {
+func reloadWrapperLabel(res reloadProjectResult) string {
+	switch {
+	case !res.DryRun:
+		return "updated"
+	case res.WrapperChanged:
+		return "would-update"
+	default:
+		return "unchanged"
+	}
+}
+
+func reloadRunStateLabel(wasRunning, restarted, dryRun bool, pid int) string {
 	if !wasRunning {
 		return "not-running"
 	}
+	if restarted && dryRun {
+		return fmt.Sprintf("would-restart(pid=%d)", pid)
+	}
 	if restarted {
 		return fmt.Sprintf("restarted(previous_pid=%d)", pid)
 	}
 	return fmt.Sprintf("running(previous_pid=%d)", pid)
 }
 
-func runServiceCommand(paths ralph.Paths, args []string) error {
+func runIssueCommand(paths ralph.Paths, args []string) error {
 	usage := func() {
-		fmt.Fprintln(os.Stderr, "Usage: ralphctl --control-dir DIR --project-dir DIR service <subcommand> [args]")
-		fmt.Fprintln(os.Stderr, "Subcommands: install, uninstall, status")
+		fmt.Fprintln(os.Stderr, "Usage: ralphctl --project-dir DIR issue <subcommand>")
+		fmt.Fprintln(os.Stderr, "Subcommands: list, show, rm, priority, deadletter list, requeue")
 	}
 	if len(args) == 0 {
 		usage()
-		return fmt.Errorf("service subcommand is required")
+		return fmt.Errorf("issue subcommand is required")
 	}
 
-	sub := args[0]
-	subArgs := args[1:]
-
-	switch sub {
-	case "install":
-		fs := flag.NewFlagSet("service install", flag.ContinueOnError)
-		name := fs.String("name", "", "service name (default: ralph-<project-dir>)")
-		startNow := fs.Bool("start", true, "enable/start service immediately")
-		if err := fs.Parse(subArgs); err != nil {
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("issue list", flag.ContinueOnError)
+		statusCSV := fs.String("status", "", "comma-separated status filter (ready,in-progress,blocked,done)")
+		rolesRaw := fs.String("role", "", "comma-separated role filter")
+		assignee := fs.String("assignee", "", "only issues assigned to this owner")
+		sortBy := fs.String("sort", "created", "sort order: priority|created")
+		limit := fs.Int("limit", 0, "show at most N issues (0=all)")
+		if err := fs.Parse(args[1:]); err != nil {
 			return err
 		}
-		exe, err := executablePath()
+		if *limit < 0 {
+			return fmt.Errorf("--limit must be >= 0")
+		}
+		roleFilter, err := ralph.ParseRolesCSV(*rolesRaw)
 		if err != nil {
 			return err
 		}
-		result, err := ralph.InstallService(paths, exe, *name, *startNow)
+		entries, err := ralph.ListIssues(paths, ralph.IssueListOptions{
+			Statuses: strings.Split(*statusCSV, ","),
+			Roles:    roleFilter,
+			Assignee: *assignee,
+			Sort:     *sortBy,
+		})
 		if err != nil {
 			return err
 		}
-		fmt.Println("service installed")
-		fmt.Printf("- platform: %s\n", result.Platform)
-		fmt.Printf("- service: %s\n", result.ServiceName)
-		fmt.Printf("- unit_path: %s\n", result.UnitPath)
-		fmt.Printf("- activated: %t\n", result.Activated)
-		hint := ralph.ServiceInstallHint(result.Platform)
-		if hint != "" {
-			fmt.Printf("- hint: %s\n", hint)
+		if len(entries) == 0 {
+			fmt.Println("no issues found")
+			return nil
 		}
-		for _, warn := range result.Warnings {
-			fmt.Printf("- warning: %s\n", warn)
+		if *limit > 0 && len(entries) > *limit {
+			entries = entries[:*limit]
+		}
+		fmt.Println("## Issues")
+		for _, entry := range entries {
+			priority := "-"
+			if entry.Meta.Priority != 0 {
+				priority = strconv.Itoa(entry.Meta.Priority)
+			}
+			owner := ""
+			if entry.Meta.Assignee != "" {
+				owner = " assignee=" + entry.Meta.Assignee
+			}
+			fmt.Printf("- id=%s status=%s role=%s priority=%s%s title=%s\n", entry.Meta.ID, entry.Status, entry.Meta.Role, priority, owner, entry.Meta.Title)
 		}
 		return nil
 
-	case "uninstall":
-		fs := flag.NewFlagSet("service uninstall", flag.ContinueOnError)
-		name := fs.String("name", "", "service name (default: ralph-<project-dir>)")
-		if err := fs.Parse(subArgs); err != nil {
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: issue show <id>")
+		}
+		entry, err := ralph.FindIssue(paths, args[1])
+		if err != nil {
 			return err
 		}
-		result, err := ralph.UninstallService(paths, *name)
+		data, err := os.ReadFile(entry.Path)
 		if err != nil {
 			return err
 		}
-		fmt.Println("service uninstalled")
+		fmt.Println("## Issue")
+		fmt.Printf("- id: %s\n", entry.Meta.ID)
+		fmt.Printf("- status: %s\n", entry.Status)
+		fmt.Printf("- role: %s\n", entry.Meta.Role)
+		fmt.Printf("- path: %s\n", entry.Path)
+		fmt.Println()
+		fmt.Print(string(data))
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			fmt.Println()
+		}
+		return nil
+
+	case "rm":
+		fs := flag.NewFlagSet("issue rm", flag.ContinueOnError)
+		force := fs.Bool("force", false, "remove even if the issue is in-progress")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 1 {
+			return fmt.Errorf("usage: issue rm [--force] <id>")
+		}
+		entry, err := ralph.RemoveIssue(paths, fs.Arg(0), *force)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("removed: %s\n", entry.Path)
+		fmt.Printf("- id: %s\n", entry.Meta.ID)
+		fmt.Printf("- status: %s\n", entry.Status)
+		fmt.Printf("- title: %s\n", entry.Meta.Title)
+		return nil
+
+	case "priority":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: issue priority <id> <value|top>")
+		}
+		raw := strings.TrimSpace(args[2])
+		value, err := strconv.Atoi(raw)
+		if strings.EqualFold(raw, "top") {
+			value, err = ralph.TopIssuePriority(paths)
+			if err != nil {
+				return err
+			}
+		} else if err != nil || value == 0 {
+			return fmt.Errorf("priority must be a non-zero integer or top: %s", args[2])
+		}
+		entry, oldPriority, err := ralph.SetIssuePriority(paths, args[1], value)
+		if err != nil {
+			return err
+		}
+		oldLabel := "default"
+		if oldPriority != 0 {
+			oldLabel = strconv.Itoa(oldPriority)
+		}
+		fmt.Printf("issue priority updated: %s\n", entry.Meta.ID)
+		fmt.Printf("- old: %s\n", oldLabel)
+		fmt.Printf("- new: %d\n", value)
+		fmt.Printf("- status: %s\n", entry.Status)
+		if entry.Status == "in-progress" {
+			fmt.Println("- note: issue is in-progress; new priority applies when it re-enters the ready queue")
+		}
+		return nil
+
+	case "deadletter":
+		if len(args) != 2 || args[1] != "list" {
+			return fmt.Errorf("usage: issue deadletter list")
+		}
+		entries, err := ralph.ListDeadLetterIssues(paths)
+		if err != nil {
+			return err
+		}
+		fmt.Println("## Dead-letter Issues")
+		fmt.Printf("- count: %d\n", len(entries))
+		for _, entry := range entries {
+			fmt.Printf("- id=%s role=%s title=%s cause=%s\n", entry.Meta.ID, entry.Meta.Role, entry.Meta.Title, valueOrDash(compactSingleLine(entry.Cause, 200)))
+		}
+		return nil
+
+	case "requeue":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: issue requeue <id>")
+		}
+		entry, err := ralph.RequeueDeadLetterIssue(paths, args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("requeued: %s\n", entry.Path)
+		fmt.Printf("- id: %s\n", entry.Meta.ID)
+		fmt.Printf("- title: %s\n", entry.Meta.Title)
+		return nil
+
+	default:
+		usage()
+		return fmt.Errorf("unknown issue subcommand: %s", args[0])
+	}
+}
+
+// drainSignalContext maps the first SIGTERM to a drain request so the loop
+// finishes its current issue; SIGINT or a second SIGTERM cancels immediately.
+func drainSignalContext(out io.Writer) (context.Context, <-chan struct{}, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	drain := make(chan struct{})
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		drained := false
+		for {
+			select {
+			case sig := <-sigCh:
+				if sig == syscall.SIGTERM && !drained {
+					drained = true
+					fmt.Fprintln(out, "[ralph-run] SIGTERM received; draining current issue (send again to abort)")
+					close(drain)
+					continue
+				}
+				cancel()
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ctx, drain, func() {
+		signal.Stop(sigCh)
+		cancel()
+	}
+}
+
+func runProfileCommand(paths ralph.Paths, args []string) error {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "Usage: ralphctl --project-dir DIR profile <subcommand>")
+		fmt.Fprintln(os.Stderr, "Subcommands: show, set")
+		fmt.Fprintln(os.Stderr, "  set <key=value> [key=value...]  write validated overrides to profile.local.yaml")
+	}
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("profile subcommand is required")
+	}
+
+	switch args[0] {
+	case "show":
+		fs := flag.NewFlagSet("profile show", flag.ContinueOnError)
+		asJSON := fs.Bool("json", false, "print effective profile and sources as JSON")
+		env := fs.String("env", ralph.ProfileEnvironment(), "preview with profile.<env>.yaml overlay (default $"+ralph.ProfileEnvVar+")")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		*env = strings.TrimSpace(*env)
+		profile, sources, err := ralph.LoadProfileWithSourcesForEnv(paths, *env)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(struct {
+				Env     string            `json:"env,omitempty"`
+				Profile ralph.Profile     `json:"profile"`
+				Sources map[string]string `json:"sources"`
+			}{Env: *env, Profile: profile, Sources: sources})
+		}
+		values := ralph.ProfileToYAMLMap(profile)
+		for _, role := range ralph.RequiredAgentRoles {
+			key := "codex_model_" + role
+			if _, ok := values[key]; !ok {
+				values[key] = "(inherit codex_model)"
+				sources[key] = "default"
+			}
+		}
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Println("## Ralph Profile")
+		fmt.Printf("- project: %s\n", paths.ProjectDir)
+		if *env != "" {
+			overlay := ralph.ProfileEnvYAMLFile(paths, *env)
+			if _, statErr := os.Stat(overlay); os.IsNotExist(statErr) {
+				overlay += " (missing)"
+			}
+			fmt.Printf("- env: %s (%s)\n", *env, overlay)
+		}
+		for _, key := range keys {
+			source := sources[key]
+			if source == "" {
+				source = "default"
+			}
+			fmt.Printf("- %s: %s (%s)\n", key, valueOrDash(values[key]), source)
+		}
+		return nil
+
+	case "set":
+		if len(args) < 2 {
+			usage()
+			return fmt.Errorf("profile set requires at least one key=value pair")
+		}
+		values := map[string]string{}
+		for _, pair := range args[1:] {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(key) == "" {
+				return fmt.Errorf("invalid pair %q (expected key=value)", pair)
+			}
+			values[key] = value
+		}
+		applied, err := ralph.SetProfileValues(paths, values)
+		if err != nil {
+			return err
+		}
+		keys := make([]string, 0, len(applied))
+		for key := range applied {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Printf("profile updated: %s\n", paths.ProfileLocalYAMLFile)
+		for _, key := range keys {
+			fmt.Printf("- %s: %s\n", key, valueOrDash(applied[key]))
+		}
+		return nil
+
+	default:
+		usage()
+		return fmt.Errorf("unknown profile subcommand: %s", args[0])
+	}
+}
+
+func runServiceCommand(paths ralph.Paths, args []string) error {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "Usage: ralphctl --control-dir DIR --project-dir DIR service <subcommand> [args]")
+		fmt.Fprintln(os.Stderr, "Subcommands: install, uninstall, status, list")
+	}
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("service subcommand is required")
+	}
+
+	sub := args[0]
+	subArgs := args[1:]
+
+	switch sub {
+	case "install":
+		fs := flag.NewFlagSet("service install", flag.ContinueOnError)
+		name := fs.String("name", "", "service name (default: ralph-<project-dir>)")
+		startNow := fs.Bool("start", true, "enable/start service immediately")
+		if err := fs.Parse(subArgs); err != nil {
+			return err
+		}
+		exe, err := executablePath()
+		if err != nil {
+			return err
+		}
+		result, err := ralph.InstallService(paths, exe, *name, *startNow)
+		if err != nil {
+			return err
+		}
+		fmt.Println("service installed")
 		fmt.Printf("- platform: %s\n", result.Platform)
 		fmt.Printf("- service: %s\n", result.ServiceName)
-		fmt.Printf("- unit_path: %s\n", result.UnitPath)
+		fmt.Printf("- unit_path: %s\n", valueOrDash(result.UnitPath))
+		fmt.Printf("- activated: %t\n", result.Activated)
+		hint := ralph.ServiceInstallHint(result.Platform)
+		if hint != "" {
+			fmt.Printf("- hint: %s\n", hint)
+		}
+		for _, warn := range result.Warnings {
+			fmt.Printf("- warning: %s\n", warn)
+		}
+		return nil
+
+	case "uninstall":
+		fs := flag.NewFlagSet("service uninstall", flag.ContinueOnError)
+		name := fs.String("name", "", "service name (default: ralph-<project-dir>)")
+		if err := fs.Parse(subArgs); err != nil {
+			return err
+		}
+		result, err := ralph.UninstallService(paths, *name)
+		if err != nil {
+			return err
+		}
+		fmt.Println("service uninstalled")
+		fmt.Printf("- platform: %s\n", result.Platform)
+		fmt.Printf("- service: %s\n", result.ServiceName)
+		fmt.Printf("- unit_path: %s\n", valueOrDash(result.UnitPath))
 		for _, warn := range result.Warnings {
 			fmt.Printf("- warning: %s\n", warn)
 		}
@@ -990,9 +1785,16 @@ func runServiceCommand(paths ralph.Paths, args []string) error {
 	case "status":
 		fs := flag.NewFlagSet("service status", flag.ContinueOnError)
 		name := fs.String("name", "", "service name (default: ralph-<project-dir>)")
+		all := fs.Bool("all", false, "report every installed ralph service")
 		if err := fs.Parse(subArgs); err != nil {
 			return err
 		}
+		if *all {
+			if strings.TrimSpace(*name) != "" {
+				return fmt.Errorf("--name and --all cannot be used together")
+			}
+			return printServiceList(os.Stdout)
+		}
 		status, err := ralph.GetServiceStatus(paths, *name)
 		if err != nil {
 			return err
@@ -1007,91 +1809,33 @@ func runServiceCommand(paths ralph.Paths, args []string) error {
 		}
 		return nil
 
+	case "list":
+		fs := flag.NewFlagSet("service list", flag.ContinueOnError)
+		if err := fs.Parse(subArgs); err != nil {
+			return err
+		}
+		return printServiceList(os.Stdout)
+
 	default:
 		usage()
 		return fmt.Errorf("unknown service subcommand: %s", sub)
 	}
 }
 
-func renderFleetDashboard(controlDir, projectID string, all bool, out io.Writer) error {
-	projects, err := ralph.ResolveFleetProjects(controlDir, projectID, all)
+func printServiceList(out io.Writer) error {
+	platform, err := ralph.DetectServicePlatform()
 	if err != nil {
 		return err
 	}
-	fmt.Fprintln(out, "## Fleet Dashboard")
-	fmt.Fprintf(out, "- updated_utc: %s\n", time.Now().UTC().Format(time.RFC3339))
-	fmt.Fprintf(out, "- control_dir: %s\n", controlDir)
-	fmt.Fprintf(out, "- projects: %d\n", len(projects))
-	for _, p := range projects {
-		paths, err := ralph.NewPaths(controlDir, p.ProjectDir)
-		if err != nil {
-			return err
-		}
-		st, err := ralph.GetStatus(paths)
-		if err != nil {
-			return err
-		}
-		roles, rolePIDs := ralph.RunningRoleDaemons(paths)
-		fmt.Fprintf(
-			out,
-			"- project=%s plugin=%s daemon=%s state=%s circuit=%s ready=%d in_progress=%d done=%d blocked=%d\n",
-			p.ID,
-			p.Plugin,
-			st.Daemon,
-			st.QueueState,
-			st.CodexCircuitState,
-			st.QueueReady,
-			st.InProgress,
-			st.Done,
-			st.Blocked,
-		)
-		if cpState, cpErr := ralph.ControlPlaneGetCutoverState(paths.ProjectDir); cpErr == nil {
-			fmt.Fprintf(out, "  control_plane_mode=%s | canary=%t\n", cpState.Mode, cpState.Canary)
-			if cpState.Mode == "v2" {
-				if cpStatus, cpStatusErr := ralph.ControlPlaneStatusReport(paths.ProjectDir); cpStatusErr == nil {
-					fmt.Fprintf(
-						out,
-						"  cp_tasks total=%d ready=%d running=%d verifying=%d done=%d blocked=%d\n",
-						cpStatus.TasksTotal,
-						cpStatus.StateCounts[ralph.ControlPlaneTaskStateReady],
-						cpStatus.StateCounts[ralph.ControlPlaneTaskStateRunning],
-						cpStatus.StateCounts[ralph.ControlPlaneTaskStateVerifying],
-						cpStatus.StateCounts[ralph.ControlPlaneTaskStateDone],
-						cpStatus.StateCounts[ralph.ControlPlaneTaskStateBlocked],
-					)
-				}
-			}
-		}
-		if len(roles) > 0 {
-			roleLine := []string{}
-			for _, role := range ralph.RequiredAgentRoles {
-				pid, ok := rolePIDs[role]
-				if !ok {
-					continue
-				}
-				roleLine = append(roleLine, fmt.Sprintf("%s:%d", role, pid))
-			}
-			if len(roleLine) > 0 {
-				fmt.Fprintf(out, "  workers=%s\n", strings.Join(roleLine, ","))
-			}
-		}
-		if st.LastProfileReloadAt != "" || st.ProfileReloadCount > 0 {
-			fmt.Fprintf(
-				out,
-				"  profile_reload_at=%s | profile_reload_count=%d\n",
-				valueOrDash(st.LastProfileReloadAt),
-				st.ProfileReloadCount,
-			)
-		}
-		if st.LastFailureCause != "" || st.LastCodexRetryCount > 0 || st.LastPermissionStreak > 0 {
-			fmt.Fprintf(
-				out,
-				"  last_failure=%s | codex_retries=%d | perm_streak=%d\n",
-				compactSingleLine(st.LastFailureCause, 120),
-				st.LastCodexRetryCount,
-				st.LastPermissionStreak,
-			)
-		}
+	services, err := ralph.ListServiceStatuses()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "## Services")
+	fmt.Fprintf(out, "- platform: %s\n", platform)
+	fmt.Fprintf(out, "- services: %d\n", len(services))
+	for _, st := range services {
+		fmt.Fprintf(out, "- service=%s installed=%t active=%t detail=%s unit_path=%s\n", st.ServiceName, st.Installed, st.Active, valueOrDash(st.Detail), valueOrDash(st.UnitPath))
 	}
 	return nil
 }
@@ -1186,7 +1930,7 @@ func runControlPlaneLoop(ctx context.Context, paths ralph.Paths, profile ralph.P
 func runFleetCommand(controlDir string, args []string) error {
 	usage := func() {
 		fmt.Fprintln(os.Stderr, "Usage: ralphctl --control-dir DIR fleet <subcommand> [args]")
-		fmt.Fprintln(os.Stderr, "Subcommands: interactive, register, unregister, list, start, stop, status, dashboard, apply-plugin, bootstrap")
+		fmt.Fprintln(os.Stderr, "Subcommands: interactive, register, unregister, rename, move, set-notify, list, start, stop, status, dashboard, doctor, logs, apply-plugin, retry-last, bootstrap")
 	}
 	if len(args) == 0 {
 		return runFleetInteractive(controlDir)
@@ -1205,13 +1949,24 @@ func runFleetCommand(controlDir string, args []string) error {
 		projectDir := fs.String("project-dir", "", "project directory")
 		plugin := fs.String("plugin", "universal-default", "plugin name")
 		prdPath := fs.String("prd", "PRD.md", "project PRD path")
+		notifyRetry := fs.Int("notify-retry-threshold", 0, "telegram retry alert threshold for this project (0=telegram global)")
+		notifyPerm := fs.Int("notify-perm-streak-threshold", 0, "telegram permission streak alert threshold for this project (0=telegram global)")
 		if err := fs.Parse(subArgs); err != nil {
 			return err
 		}
+		if *notifyRetry < 0 || *notifyPerm < 0 {
+			return fmt.Errorf("notify thresholds must be >= 0")
+		}
 		fp, err := ralph.RegisterFleetProject(controlDir, *id, *projectDir, *plugin, *prdPath)
 		if err != nil {
 			return err
 		}
+		if *notifyRetry > 0 || *notifyPerm > 0 {
+			fp, err = ralph.SetFleetProjectNotifyThresholds(controlDir, fp.ID, notifyRetry, notifyPerm)
+			if err != nil {
+				return err
+			}
+		}
 
 		paths, err := ralph.NewPaths(controlDir, fp.ProjectDir)
 		if err != nil {
@@ -1239,6 +1994,118 @@ func runFleetCommand(controlDir string, args []string) error {
 		fmt.Printf("- bootstrap_created: %d\n", len(created))
 		return nil
 
+	case "rename":
+		fs := flag.NewFlagSet("fleet rename", flag.ContinueOnError)
+		if err := fs.Parse(subArgs); err != nil {
+			return err
+		}
+		if fs.NArg() != 2 {
+			return fmt.Errorf("usage: ralphctl fleet rename <old-id> <new-id>")
+		}
+		oldID := strings.TrimSpace(fs.Arg(0))
+		fp, err := ralph.RenameFleetProject(controlDir, oldID, fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		fmt.Println("fleet project renamed")
+		fmt.Printf("- old_id: %s\n", oldID)
+		fmt.Printf("- id: %s\n", fp.ID)
+		fmt.Printf("- project_dir: %s\n", fp.ProjectDir)
+		return nil
+
+	case "move":
+		fs := flag.NewFlagSet("fleet move", flag.ContinueOnError)
+		projectDir := fs.String("project-dir", "", "new project directory (must exist)")
+		migrateRalph := fs.Bool("migrate-ralph", false, "move the old .ralph dir (queue, state, logs) to the new project-dir")
+		// Accept the id before the flags too, as in `fleet move app --project-dir ...`.
+		id := ""
+		if len(subArgs) > 0 && !strings.HasPrefix(subArgs[0], "-") {
+			id, subArgs = subArgs[0], subArgs[1:]
+		}
+		if err := fs.Parse(subArgs); err != nil {
+			return err
+		}
+		if id == "" && fs.NArg() == 1 {
+			id = fs.Arg(0)
+		} else if id == "" || fs.NArg() != 0 {
+			return fmt.Errorf("usage: ralphctl fleet move <id> --project-dir <new-dir> [--migrate-ralph]")
+		}
+		exe, err := executablePath()
+		if err != nil {
+			return err
+		}
+		res, err := ralph.MoveFleetProject(controlDir, strings.TrimSpace(id), *projectDir, *migrateRalph, exe)
+		if err != nil {
+			return err
+		}
+		rebound, err := moveTelegramTokenBindings(controlDir, res.OldProjectDir, res.Project.ProjectDir)
+		if err != nil {
+			return err
+		}
+		paths, err := ralph.NewPaths(controlDir, res.Project.ProjectDir)
+		if err != nil {
+			return err
+		}
+		if err := ralph.EnsureFleetProjectInstalled(paths, res.Project.Plugin, exe); err != nil {
+			return err
+		}
+		if err := ralph.WriteProjectWrapper(paths, exe); err != nil {
+			return err
+		}
+		if err := ralph.EnsureFleetAgentSetFile(paths, res.Project); err != nil {
+			return err
+		}
+		fmt.Println("fleet project moved")
+		fmt.Printf("- id: %s\n", res.Project.ID)
+		fmt.Printf("- old_project_dir: %s\n", res.OldProjectDir)
+		fmt.Printf("- project_dir: %s\n", res.Project.ProjectDir)
+		fmt.Printf("- ralph_migrated: %t\n", res.RalphMigrated)
+		if res.Service != nil {
+			fmt.Printf("- service: %s reinstalled (%s, activated=%t)\n", res.Service.ServiceName, res.Service.UnitPath, res.Service.Activated)
+			for _, warning := range res.Service.Warnings {
+				fmt.Printf("  - warning: %s\n", warning)
+			}
+		}
+		if rebound > 0 {
+			fmt.Printf("- telegram_token_bindings: %d moved\n", rebound)
+		}
+		fmt.Printf("- daemons: stopped (restart: ralphctl fleet start --id %s)\n", res.Project.ID)
+		return nil
+
+	case "set-notify":
+		fs := flag.NewFlagSet("fleet set-notify", flag.ContinueOnError)
+		id := fs.String("id", "", "fleet project id")
+		retry := fs.Int("retry-threshold", 0, "telegram retry alert threshold (0=telegram global)")
+		perm := fs.Int("perm-streak-threshold", 0, "telegram permission streak alert threshold (0=telegram global)")
+		if err := fs.Parse(subArgs); err != nil {
+			return err
+		}
+		if strings.TrimSpace(*id) == "" {
+			return fmt.Errorf("--id is required")
+		}
+		// Only thresholds given on the command line change; the other keeps its value.
+		var retrySet, permSet *int
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "retry-threshold":
+				retrySet = retry
+			case "perm-streak-threshold":
+				permSet = perm
+			}
+		})
+		if retrySet == nil && permSet == nil {
+			return fmt.Errorf("--retry-threshold or --perm-streak-threshold is required")
+		}
+		fp, err := ralph.SetFleetProjectNotifyThresholds(controlDir, strings.TrimSpace(*id), retrySet, permSet)
+		if err != nil {
+			return err
+		}
+		fmt.Println("fleet project notify thresholds updated")
+		fmt.Printf("- id: %s\n", fp.ID)
+		fmt.Printf("- notify_retry_threshold: %s\n", fleetNotifyThresholdLabel(fp.NotifyRetryThreshold))
+		fmt.Printf("- notify_perm_streak_threshold: %s\n", fleetNotifyThresholdLabel(fp.NotifyPermStreakThreshold))
+		return nil
+
 	case "unregister":
 		fs := flag.NewFlagSet("fleet unregister", flag.ContinueOnError)
 		id := fs.String("id", "", "fleet project id")
@@ -1291,10 +2158,23 @@ func runFleetCommand(controlDir string, args []string) error {
 		id := fs.String("id", "", "fleet project id")
 		all := fs.Bool("all", false, "start all projects")
 		bootstrap := fs.Bool("bootstrap", true, "ensure bootstrap issues for role set")
+		rolesRaw := fs.String("roles", "", "comma-separated role scope intersected with each project's assigned roles")
+		dryRun := fs.Bool("dry-run", false, "preview role daemons and bootstrap issues without side effects")
+		concurrencyRaw := fs.Int("concurrency", 1, "number of projects to start in parallel")
+		continueOnError := fs.Bool("continue-on-error", false, "keep starting the remaining projects after a failure")
+		retryFailed := fs.Bool("retry-failed", false, "only start the projects that failed or were skipped in the last fleet start")
 		if err := fs.Parse(subArgs); err != nil {
 			return err
 		}
-		projects, err := ralph.ResolveFleetProjects(controlDir, *id, *all)
+		concurrency, err := parseFleetConcurrency(*concurrencyRaw)
+		if err != nil {
+			return err
+		}
+		roleFilter, err := ralph.ParseRolesCSV(*rolesRaw)
+		if err != nil {
+			return err
+		}
+		projects, err := resolveFleetRunProjects(controlDir, "start", *id, *all, *retryFailed)
 		if err != nil {
 			return err
 		}
@@ -1302,47 +2182,65 @@ func runFleetCommand(controlDir string, args []string) error {
 		if err != nil {
 			return err
 		}
-		for _, p := range projects {
+		results := runFleetProjects(projects, concurrency, *continueOnError, func(_ int, p ralph.FleetProject, out io.Writer) error {
+			roles := fleetRolesInScope(p.AssignedRoles, roleFilter)
+			if len(roles) == 0 {
+				fmt.Fprintf(out, "[fleet] project=%s skipped: no assigned roles match --roles=%s\n", p.ID, ralph.RoleSetCSV(roleFilter))
+				return nil
+			}
 			paths, err := ralph.NewPaths(controlDir, p.ProjectDir)
 			if err != nil {
 				return err
 			}
-			if err := ralph.EnsureFleetProjectInstalled(paths, p.Plugin, exe); err != nil {
-				return err
-			}
-			if err := ralph.EnsureFleetAgentSetFile(paths, p); err != nil {
-				return err
+			if *dryRun {
+				return previewFleetStart(out, paths, p, roles, *bootstrap)
 			}
-			if *bootstrap {
-				if _, err := ralph.EnsureRoleBootstrapIssues(paths, p.PRDPath); err != nil {
+			if err := withProjectControlLock(paths, "fleet start", func() error {
+				if err := ralph.EnsureFleetProjectInstalled(paths, p.Plugin, exe); err != nil {
 					return err
 				}
-			}
-			if err := ralph.StopPrimaryDaemon(paths); err != nil {
-				return err
-			}
-			if err := ralph.SetEnabled(paths, true); err != nil {
-				return err
-			}
-			fmt.Printf("[fleet] project=%s\n", p.ID)
-			for _, role := range p.AssignedRoles {
-				pid, already, err := ralph.StartRoleDaemon(paths, role)
-				if err != nil {
+				if err := ralph.EnsureFleetAgentSetFile(paths, p); err != nil {
+					return err
+				}
+				if *bootstrap {
+					if _, err := ralph.EnsureRoleBootstrapIssues(paths, p.PRDPath); err != nil {
+						return err
+					}
+				}
+				if err := ralph.StopPrimaryDaemon(paths); err != nil {
 					return err
 				}
-				if already {
-					fmt.Printf("  - %s: already running (pid=%d)\n", role, pid)
-				} else {
-					fmt.Printf("  - %s: started (pid=%d)\n", role, pid)
+				if err := ralph.SetEnabled(paths, true); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "[fleet] project=%s\n", p.ID)
+				for _, role := range roles {
+					pid, already, err := ralph.StartRoleDaemon(paths, role)
+					if err != nil {
+						return err
+					}
+					if already {
+						fmt.Fprintf(out, "  - %s: already running (pid=%d)\n", role, pid)
+					} else {
+						fmt.Fprintf(out, "  - %s: started (pid=%d)\n", role, pid)
+					}
 				}
+				return nil
+			}); err != nil {
+				return fmt.Errorf("project=%s: %w", p.ID, err)
 			}
+			return nil
+		})
+		if *dryRun {
+			return flushFleetProjectResults(os.Stdout, results)
 		}
-		return nil
+		return finishFleetRun(controlDir, "start", subArgs, projects, results)
 
 	case "stop":
 		fs := flag.NewFlagSet("fleet stop", flag.ContinueOnError)
 		id := fs.String("id", "", "fleet project id")
 		all := fs.Bool("all", false, "stop all projects")
+		dryRun := fs.Bool("dry-run", false, "preview role daemons to stop without side effects")
 		if err := fs.Parse(subArgs); err != nil {
 			return err
 		}
@@ -1355,19 +2253,23 @@ func runFleetCommand(controlDir string, args []string) error {
 			if err != nil {
 				return err
 			}
-			if err := ralph.SetEnabled(paths, false); err != nil {
-				return err
-			}
-			if err := ralph.StopPrimaryDaemon(paths); err != nil {
-				return err
-			}
-			for _, role := range p.AssignedRoles {
-				if err := ralph.StopRoleDaemon(paths, role); err != nil {
-					return err
+			steps := fleetStopSteps(paths, p)
+			if *dryRun {
+				if err := previewFleetStop(steps); err != nil {
+					return fmt.Errorf("project=%s: %w", p.ID, err)
 				}
+				fmt.Printf("[fleet] stopped project=%s (dry-run)\n", p.ID)
+				continue
 			}
-			if err := ralph.RecoverInProgress(paths); err != nil {
-				return err
+			if err := withProjectControlLock(paths, "fleet stop", func() error {
+				for _, step := range steps {
+					if err := step.run(); err != nil {
+						return err
+					}
+				}
+				return nil
+			}); err != nil {
+				return fmt.Errorf("project=%s: %w", p.ID, err)
 			}
 			fmt.Printf("[fleet] stopped project=%s\n", p.ID)
 		}
@@ -1377,14 +2279,47 @@ func runFleetCommand(controlDir string, args []string) error {
 		fs := flag.NewFlagSet("fleet status", flag.ContinueOnError)
 		id := fs.String("id", "", "fleet project id")
 		all := fs.Bool("all", false, "show all projects")
+		asJSON := fs.Bool("json", false, "print per-project status as JSON")
+		filter := fs.String("filter", "", "only show projects that are blocked|stopped|input-required|failing")
 		if err := fs.Parse(subArgs); err != nil {
 			return err
 		}
+		match, err := parseFleetStatusFilter(*filter)
+		if err != nil {
+			return err
+		}
 		projects, err := ralph.ResolveFleetProjects(controlDir, *id, *all)
 		if err != nil {
 			return err
 		}
+		if *asJSON {
+			entries := []fleetStatusEntry{}
+			for _, p := range projects {
+				paths, err := ralph.NewPaths(controlDir, p.ProjectDir)
+				if err != nil {
+					return err
+				}
+				st, err := ralph.GetStatus(paths)
+				if err != nil {
+					return err
+				}
+				if match != nil && !match(st) {
+					continue
+				}
+				_, rolePIDs := ralph.RunningRoleDaemons(paths)
+				entries = append(entries, fleetStatusEntry{
+					ID:            p.ID,
+					ProjectDir:    p.ProjectDir,
+					Plugin:        p.Plugin,
+					AssignedRoles: p.AssignedRoles,
+					Workers:       rolePIDs,
+					Status:        st,
+				})
+			}
+			return printJSON(entries)
+		}
 		fmt.Println("## Fleet Status")
+		shown := 0
 		for _, p := range projects {
 			paths, err := ralph.NewPaths(controlDir, p.ProjectDir)
 			if err != nil {
@@ -1394,13 +2329,20 @@ func runFleetCommand(controlDir string, args []string) error {
 			if err != nil {
 				return err
 			}
+			if match != nil && !match(st) {
+				continue
+			}
+			shown++
 			roles, rolePIDs := ralph.RunningRoleDaemons(paths)
 			fmt.Printf("- project=%s dir=%s plugin=%s roles=%s daemon=%s state=%s circuit=%s ready=%d in_progress=%d done=%d blocked=%d\n", p.ID, p.ProjectDir, p.Plugin, strings.Join(p.AssignedRoles, ","), st.Daemon, st.QueueState, st.CodexCircuitState, st.QueueReady, st.InProgress, st.Done, st.Blocked)
 			if len(roles) > 0 {
 				for _, role := range roles {
-					fmt.Printf("  - worker[%s]=running pid=%d\n", role, rolePIDs[role])
+					fmt.Printf("  - worker[%s]=running pid=%d restarts=%d\n", role, rolePIDs[role], st.RoleRestartCounts[role])
 				}
 			}
+			if restarts := ralph.FormatRoleRestartCounts(st.RoleRestartCounts); restarts != "" {
+				fmt.Printf("  - supervisor_restarts: %s\n", restarts)
+			}
 			if st.LastSelfHealAt != "" {
 				fmt.Printf("  - busywait_last_detected=%s self_heal_attempts=%d\n", st.LastBusyWaitDetectedAt, st.SelfHealAttempts)
 			}
@@ -1413,39 +2355,98 @@ func runFleetCommand(controlDir string, args []string) error {
 			}
 			if st.LastFailureCause != "" || st.LastCodexRetryCount > 0 || st.LastPermissionStreak > 0 {
 				fmt.Printf(
-					"  - last_failure=%s codex_retries=%d perm_streak=%d\n",
+					"  - last_failure=%s category=%s codex_retries=%d perm_streak=%d\n",
 					compactSingleLine(st.LastFailureCause, 120),
+					valueOrDash(st.LastFailureCategory),
 					st.LastCodexRetryCount,
 					st.LastPermissionStreak,
 				)
 			}
 		}
+		if match != nil {
+			fmt.Printf("- filter=%s matched=%d/%d\n", strings.ToLower(strings.TrimSpace(*filter)), shown, len(projects))
+		}
 		return nil
 
+	case "doctor":
+		return runFleetDoctor(controlDir, subArgs, os.Stdout)
+
+	case "logs":
+		fs := flag.NewFlagSet("fleet logs", flag.ContinueOnError)
+		id := fs.String("id", "", "fleet project id")
+		all := fs.Bool("all", false, "tail all projects")
+		lines := fs.Int("lines", 120, "number of merged lines")
+		follow := fs.Bool("follow", false, "follow appended lines across projects")
+		if err := fs.Parse(subArgs); err != nil {
+			return err
+		}
+		if *lines <= 0 {
+			return fmt.Errorf("--lines must be > 0")
+		}
+		projects, err := ralph.ResolveFleetProjects(controlDir, *id, *all)
+		if err != nil {
+			return err
+		}
+		sources, err := ralph.FleetLogSources(controlDir, projects)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return ralph.TailLogSources(ctx, sources, os.Stdout, *lines, *follow)
+
 	case "dashboard":
 		fs := flag.NewFlagSet("fleet dashboard", flag.ContinueOnError)
 		id := fs.String("id", "", "fleet project id")
 		all := fs.Bool("all", true, "show all projects")
 		watch := fs.Bool("watch", false, "refresh continuously")
 		intervalSec := fs.Int("interval-sec", 5, "refresh interval seconds when --watch is enabled")
+		metrics := fs.Bool("metrics", false, "print aggregated Prometheus metrics (one project label per fleet id)")
+		asJSON := fs.Bool("json", false, "print per-project dashboard data as JSON (with --watch: one JSON object per refresh)")
+		once := fs.Bool("once", false, "render a single refresh and exit, even with --watch (with --json: one frame object)")
+		table := fs.Bool("table", false, "render aligned columns; ANSI color when stdout is a terminal")
 		if err := fs.Parse(subArgs); err != nil {
 			return err
 		}
+		if *table && *asJSON {
+			return fmt.Errorf("--table cannot be combined with --json")
+		}
 		if *intervalSec <= 0 {
 			return fmt.Errorf("--interval-sec must be > 0")
 		}
-		if *watch {
+		if *metrics {
+			if *watch || *asJSON || *table {
+				return fmt.Errorf("--metrics cannot be combined with --watch, --json or --table")
+			}
+			return renderFleetMetrics(controlDir, *id, *all, os.Stdout)
+		}
+		render := func() error {
+			if *asJSON {
+				return renderFleetDashboardJSON(controlDir, *id, *all, *watch || *once, os.Stdout)
+			}
+			if *table {
+				return renderFleetDashboardTable(controlDir, *id, *all, terminalColorEnabled(os.Stdout), os.Stdout)
+			}
+			return renderFleetDashboard(controlDir, *id, *all, os.Stdout)
+		}
+		if *watch && !*once {
 			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 			defer stop()
 			for {
 				select {
 				case <-ctx.Done():
-					fmt.Println("[fleet-dashboard] interrupted")
+					if *asJSON {
+						fmt.Fprintln(os.Stderr, "[fleet-dashboard] interrupted")
+					} else {
+						fmt.Println("[fleet-dashboard] interrupted")
+					}
 					return nil
 				default:
 				}
-				fmt.Print("\033[H\033[2J")
-				if err := renderFleetDashboard(controlDir, *id, *all, os.Stdout); err != nil {
+				if !*asJSON {
+					fmt.Print("\033[H\033[2J")
+				}
+				if err := render(); err != nil {
 					return err
 				}
 				if err := sleepOrInterrupt(ctx, time.Duration(*intervalSec)*time.Second); err != nil {
@@ -1453,35 +2454,52 @@ func runFleetCommand(controlDir string, args []string) error {
 				}
 			}
 		}
-		return renderFleetDashboard(controlDir, *id, *all, os.Stdout)
+		return render()
 
 	case "apply-plugin":
 		fs := flag.NewFlagSet("fleet apply-plugin", flag.ContinueOnError)
 		id := fs.String("id", "", "fleet project id")
 		all := fs.Bool("all", false, "apply to all projects")
 		plugin := fs.String("plugin", "", "plugin name (optional: use registered plugin)")
+		skipVerify := fs.Bool("skip-verify", false, "skip plugin registry checksum verification")
+		concurrencyRaw := fs.Int("concurrency", 1, "number of projects to apply in parallel")
+		continueOnError := fs.Bool("continue-on-error", false, "keep applying to the remaining projects after a failure")
+		retryFailed := fs.Bool("retry-failed", false, "only apply to the projects that failed or were skipped in the last fleet apply-plugin")
 		if err := fs.Parse(subArgs); err != nil {
 			return err
 		}
-		projects, err := ralph.ResolveFleetProjects(controlDir, *id, *all)
+		concurrency, err := parseFleetConcurrency(*concurrencyRaw)
+		if err != nil {
+			return err
+		}
+		projects, err := resolveFleetRunProjects(controlDir, "apply-plugin", *id, *all, *retryFailed)
 		if err != nil {
 			return err
 		}
+		targetPlugin := func(p ralph.FleetProject) string {
+			if strings.TrimSpace(*plugin) != "" {
+				return *plugin
+			}
+			return p.Plugin
+		}
 		for _, p := range projects {
+			warnSkipVerify(os.Stderr, *skipVerify, targetPlugin(p))
+		}
+		results := runFleetProjects(projects, concurrency, *continueOnError, func(_ int, p ralph.FleetProject, out io.Writer) error {
 			paths, err := ralph.NewPaths(controlDir, p.ProjectDir)
 			if err != nil {
 				return err
 			}
-			targetPlugin := p.Plugin
-			if strings.TrimSpace(*plugin) != "" {
-				targetPlugin = *plugin
+			if err := ralph.ApplyPluginWithOptions(paths, targetPlugin(p), ralph.ApplyPluginOptions{SkipVerify: *skipVerify}); err != nil {
+				return fmt.Errorf("project=%s: %w", p.ID, err)
 			}
-			if err := ralph.ApplyPlugin(paths, targetPlugin); err != nil {
-				return err
-			}
-			fmt.Printf("[fleet] applied plugin project=%s plugin=%s\n", p.ID, targetPlugin)
-		}
-		return nil
+			fmt.Fprintf(out, "[fleet] applied plugin project=%s plugin=%s\n", p.ID, targetPlugin(p))
+			return nil
+		})
+		return finishFleetRun(controlDir, "apply-plugin", subArgs, projects, results)
+
+	case "retry-last":
+		return runFleetRetryLast(controlDir, subArgs)
 
 	case "bootstrap":
 		fs := flag.NewFlagSet("fleet bootstrap", flag.ContinueOnError)
@@ -1685,6 +2703,117 @@ func runFleetInteractiveStop(controlDir string, reader *bufio.Reader) error {
 	return runFleetCommand(controlDir, []string{"stop", "--id", id})
 }
 
+// startStatusHTTP runs the status endpoint for the lifetime of ctx; an empty addr disables it.
+func startStatusHTTP(ctx context.Context, paths ralph.Paths, addr string, out io.Writer) error {
+	if strings.TrimSpace(addr) == "" {
+		return nil
+	}
+	bound, err := ralph.StartStatusHTTPServer(ctx, paths, addr, out)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "[ralph-http] serving http://%s (/status, /healthz, /metrics)\n", bound)
+	return nil
+}
+
+// runSupervisorWindowsService runs supervise under the service control manager.
+// A service has no console, so its output goes to the rotated runner log.
+func runSupervisorWindowsService(paths ralph.Paths, profile ralph.Profile, name string, supervise func(ctx context.Context, out io.Writer) error) error {
+	if err := ralph.EnsureLayout(paths); err != nil {
+		return err
+	}
+	maxBytes, backups := ralph.LogRotationLimits(profile)
+	logWriter, err := ralph.NewRotatingLogWriter(paths.RunnerLogFile, maxBytes, backups)
+	if err != nil {
+		return err
+	}
+	defer logWriter.Close()
+	stamped := ralph.NewTimestampLogWriter(logWriter)
+	defer stamped.Flush()
+	return ralph.RunWindowsService(name, func(ctx context.Context) error {
+		return supervise(ctx, stamped)
+	})
+}
+
+// applyCodexModelOverride exports --codex-model so every profile reload in this
+// process, and the workers a supervisor spawns, pick it up.
+func applyCodexModelOverride(model string, out io.Writer) {
+	model = strings.TrimSpace(model)
+	if model == "" {
+		return
+	}
+	_ = os.Setenv(ralph.CodexModelOverrideEnv, model)
+	fmt.Fprintf(out, "[ralph] codex_model override for all roles: %s (not persisted)\n", model)
+}
+
+// applyCodexPolicyOverride validates and exports --codex-sandbox/--codex-approval
+// for this run's profile loads only; the profile file is left untouched.
+func applyCodexPolicyOverride(sandbox, approval string, out io.Writer) error {
+	if strings.TrimSpace(sandbox) != "" {
+		v, err := ralph.NormalizeCodexSandbox(sandbox)
+		if err != nil {
+			return fmt.Errorf("--codex-sandbox: %w", err)
+		}
+		sandbox = v
+	}
+	if strings.TrimSpace(approval) != "" {
+		v, err := ralph.NormalizeCodexApproval(approval)
+		if err != nil {
+			return fmt.Errorf("--codex-approval: %w", err)
+		}
+		approval = v
+	}
+	if sandbox != "" {
+		_ = os.Setenv(ralph.CodexSandboxOverrideEnv, sandbox)
+		fmt.Fprintf(out, "[ralph] codex_sandbox override: %s (not persisted)\n", sandbox)
+	}
+	if approval != "" {
+		_ = os.Setenv(ralph.CodexApprovalOverrideEnv, approval)
+		fmt.Fprintf(out, "[ralph] codex_approval override: %s (not persisted)\n", approval)
+	}
+	return nil
+}
+
+func warnSkipVerify(out io.Writer, skip bool, plugin string) {
+	if skip {
+		fmt.Fprintf(out, "[ralph] warning: --skip-verify set; plugin %s applied without registry checksum check\n", plugin)
+	}
+}
+
+// previewApplyPlugin prints the pending profile.yaml change and reports whether to go ahead with the write.
+func previewApplyPlugin(paths ralph.Paths, plugin string, opts ralph.ApplyPluginOptions, yes bool, in io.Reader, out io.Writer) (bool, error) {
+	preview, err := ralph.PreviewApplyPlugin(paths, plugin, opts)
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprintf(out, "## Plugin Diff (%s)\n", plugin)
+	if !preview.Changed() {
+		fmt.Fprintf(out, "no changes: %s already matches plugin %s\n", preview.ProfileYAMLFile, plugin)
+		return false, nil
+	}
+	if preview.Diff != "" {
+		fmt.Fprint(out, preview.Diff)
+	}
+	if preview.RemovesLegacyEnv {
+		fmt.Fprintf(out, "note: legacy %s will be moved to %s.bak\n", paths.ProfileFile, paths.ProfileFile)
+	}
+	if yes {
+		return true, nil
+	}
+	fmt.Fprint(out, "Apply these changes? (y/N): ")
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		fmt.Fprintln(out, "aborted: profile unchanged")
+		return false, nil
+	}
+}
+
 func promptFleetInput(reader *bufio.Reader, label, defaultValue string) (string, error) {
 	if strings.TrimSpace(defaultValue) == "" {
 		fmt.Printf("%s: ", label)
@@ -1808,6 +2937,124 @@ func defaultControlDir(cwd string) string {
 	return filepath.Join(home, ".ralph-control")
 }
 
+func previewFleetStart(out io.Writer, paths ralph.Paths, p ralph.FleetProject, roles []string, bootstrap bool) error {
+	fmt.Fprintf(out, "[fleet] project=%s (dry-run)\n", p.ID)
+	if bootstrap {
+		missing, err := ralph.MissingBootstrapRoles(paths)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			fmt.Fprintf(out, "  - bootstrap: would create issues for roles=%s\n", strings.Join(missing, ","))
+		} else {
+			fmt.Fprintln(out, "  - bootstrap: no issues needed")
+		}
+	}
+	_, rolePIDs := ralph.RunningRoleDaemons(paths)
+	for _, role := range roles {
+		if pid, ok := rolePIDs[role]; ok {
+			fmt.Fprintf(out, "  - %s: already running (pid=%d)\n", role, pid)
+		} else {
+			fmt.Fprintf(out, "  - %s: would start\n", role)
+		}
+	}
+	return nil
+}
+
+// fleetStopStep is one action of `fleet stop`. --dry-run prints preview for the
+// same steps the real stop runs, so the two cannot drift apart.
+type fleetStopStep struct {
+	preview func() (string, error)
+	run     func() error
+}
+
+func fleetStopSteps(paths ralph.Paths, p ralph.FleetProject) []fleetStopStep {
+	steps := []fleetStopStep{
+		{
+			preview: func() (string, error) { return "enabled: would set false", nil },
+			run:     func() error { return ralph.SetEnabled(paths, false) },
+		},
+		{
+			preview: func() (string, error) {
+				if pid, ok := ralph.PrimaryDaemonPID(paths); ok {
+					return fmt.Sprintf("primary: would stop (pid=%d)", pid), nil
+				}
+				return "primary: not running", nil
+			},
+			run: func() error { return ralph.StopPrimaryDaemon(paths) },
+		},
+	}
+	for _, role := range p.AssignedRoles {
+		role := role
+		steps = append(steps, fleetStopStep{
+			preview: func() (string, error) {
+				_, rolePIDs := ralph.RunningRoleDaemons(paths)
+				if pid, ok := rolePIDs[role]; ok {
+					return fmt.Sprintf("%s: would stop (pid=%d)", role, pid), nil
+				}
+				return role + ": not running", nil
+			},
+			run: func() error { return ralph.StopRoleDaemon(paths, role) },
+		})
+	}
+	return append(steps, fleetStopStep{
+		preview: func() (string, error) {
+			inProgress, err := ralph.CountIssueFiles(paths.InProgressDir)
+			if err != nil || inProgress == 0 {
+				return "", err
+			}
+			return fmt.Sprintf("in_progress: would recover %d issue(s)", inProgress), nil
+		},
+		run: func() error { return ralph.RecoverInProgress(paths) },
+	})
+}
+
+func previewFleetStop(steps []fleetStopStep) error {
+	for _, step := range steps {
+		line, err := step.preview()
+		if err != nil {
+			return err
+		}
+		if line != "" {
+			fmt.Printf("  - %s\n", line)
+		}
+	}
+	return nil
+}
+
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+type fleetStatusEntry struct {
+	ID            string         `json:"id"`
+	ProjectDir    string         `json:"project_dir"`
+	Plugin        string         `json:"plugin"`
+	AssignedRoles []string       `json:"assigned_roles"`
+	Workers       map[string]int `json:"workers"`
+	Status        ralph.Status   `json:"status"`
+}
+
+func fleetRolesInScope(assigned []string, filter map[string]struct{}) []string {
+	if len(filter) == 0 {
+		return assigned
+	}
+	out := []string{}
+	for _, role := range assigned {
+		if _, ok := filter[role]; ok {
+			out = append(out, role)
+		}
+	}
+	return out
+}
+
 func commandNeedsControlAssets(cmd string) bool {
 	switch cmd {
 	case "list-plugins", "install", "apply-plugin", "setup", "reload", "fleet", "registry", "service", "telegram":
@@ -1818,9 +3065,30 @@ func commandNeedsControlAssets(cmd string) bool {
 }
 
 func executablePath() (string, error) {
-	exe, err := os.Executable()
-	if err != nil {
-		return "", err
+	return ralph.CurrentExecutablePath()
+}
+
+func recoverWorkerLabel(issue ralph.InProgressIssue) string {
+	switch {
+	case issue.ClaimedByPID <= 0:
+		return "unknown"
+	case issue.WorkerAlive:
+		return fmt.Sprintf("%d(alive)", issue.ClaimedByPID)
+	default:
+		return fmt.Sprintf("%d(dead)", issue.ClaimedByPID)
+	}
+}
+
+func fleetNotifyThresholdLabel(v int) string {
+	if v <= 0 {
+		return "global"
+	}
+	return strconv.Itoa(v)
+}
+
+func recoverStaleAfterLabel(d time.Duration) string {
+	if d <= 0 {
+		return "any"
 	}
-	return filepath.Abs(exe)
+	return d.String()
 }
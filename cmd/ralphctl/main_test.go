@@ -1,8 +1,10 @@
 package main
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"unicode/utf8"
 
@@ -45,6 +47,22 @@ func TestCommandNeedsControlAssets(t *testing.T) {
 	}
 }
 
+func TestFleetRolesInScope(t *testing.T) {
+	t.Parallel()
+
+	assigned := []string{"manager", "developer", "qa"}
+	if got := fleetRolesInScope(assigned, nil); len(got) != 3 {
+		t.Fatalf("nil filter should keep all assigned roles: %v", got)
+	}
+	got := fleetRolesInScope(assigned, map[string]struct{}{"qa": {}, "planner": {}})
+	if len(got) != 1 || got[0] != "qa" {
+		t.Fatalf("fleetRolesInScope mismatch: got=%v want=[qa]", got)
+	}
+	if got := fleetRolesInScope(assigned, map[string]struct{}{"planner": {}}); len(got) != 0 {
+		t.Fatalf("disjoint filter should be empty: %v", got)
+	}
+}
+
 func TestCompactSingleLine(t *testing.T) {
 	t.Parallel()
 
@@ -129,6 +147,49 @@ func TestResolveReloadTargetsFleetOnlyWhenCurrentUnmanaged(t *testing.T) {
 	}
 }
 
+func TestReloadCheckReportsWithoutWritingWrapper(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	controlDir := filepath.Join(root, "control")
+	projectDir := filepath.Join(root, "project")
+	paths, err := ralph.NewPaths(controlDir, projectDir)
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	if err := ralph.WriteProjectWrapper(paths, "/old/bin/ralphctl"); err != nil {
+		t.Fatalf("write wrapper: %v", err)
+	}
+	before, err := os.ReadFile(ralph.ProjectWrapperPath(paths))
+	if err != nil {
+		t.Fatalf("read wrapper: %v", err)
+	}
+
+	target := reloadTarget{ID: "current", Paths: paths, Source: "current", IsCurrent: true}
+	res, err := reloadSingleProject(target, "/new/bin/ralphctl", reloadOptions{RestartRunning: true, ReloadTelegram: true, Check: true})
+	if err != nil {
+		t.Fatalf("reload check: %v", err)
+	}
+	if !res.DryRun || !res.WrapperChanged || res.WrapperUpdated {
+		t.Fatalf("dry-run result mismatch: %+v", res)
+	}
+	after, err := os.ReadFile(ralph.ProjectWrapperPath(paths))
+	if err != nil {
+		t.Fatalf("read wrapper: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Fatalf("check must not rewrite the wrapper")
+	}
+
+	var out bytes.Buffer
+	printReloadSummary(&out, "/new/bin/ralphctl", controlDir, []reloadProjectResult{res})
+	for _, want := range []string{"dry run", "- wrapper: would-update", "- daemon_primary: not-running"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("summary missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
 func TestResolveReloadTargetsCurrentOnlyRequiresManagedProject(t *testing.T) {
 	t.Parallel()
 
@@ -189,3 +250,54 @@ func TestResolveRunEngineAutoFromCutover(t *testing.T) {
 		t.Fatalf("cutover mode mismatch after v2: got=%s want=v2", state.Mode)
 	}
 }
+
+func TestFleetStopPreviewCoversEveryStep(t *testing.T) {
+	t.Parallel()
+
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	issuePath, id, err := ralph.CreateIssue(paths, "developer", "orphaned work")
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	if err := os.Rename(issuePath, filepath.Join(paths.InProgressDir, id+".md")); err != nil {
+		t.Fatalf("move issue in-progress: %v", err)
+	}
+
+	steps := fleetStopSteps(paths, ralph.FleetProject{ID: "wallet", AssignedRoles: []string{"developer", "qa"}})
+	previews := []string{}
+	for _, step := range steps {
+		line, err := step.preview()
+		if err != nil {
+			t.Fatalf("preview: %v", err)
+		}
+		previews = append(previews, line)
+	}
+	want := []string{
+		"enabled: would set false",
+		"primary: not running",
+		"developer: not running",
+		"qa: not running",
+		"in_progress: would recover 1 issue(s)",
+	}
+	if strings.Join(previews, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("preview mismatch:\n%s", strings.Join(previews, "\n"))
+	}
+	if enabled, _ := ralph.IsEnabled(paths); !enabled {
+		t.Fatalf("preview must not disable the project")
+	}
+
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	}
+	if enabled, _ := ralph.IsEnabled(paths); enabled {
+		t.Fatalf("stop should disable the project")
+	}
+	if n, err := ralph.CountIssueFiles(paths.InProgressDir); err != nil || n != 0 {
+		t.Fatalf("stop should recover in-progress issues: n=%d err=%v", n, err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"codex-ralph/internal/ralph"
+)
+
+type issueBatchEntry struct {
+	Line  int
+	Role  string
+	Title string
+}
+
+// parseIssueBatch reads `role<TAB>title` lines, or bare titles that get
+// defaultRole. Blank lines and # comments are skipped. Every line is validated
+// before anything is created so a typo does not leave a half-seeded backlog.
+func parseIssueBatch(r io.Reader, defaultRole string) ([]issueBatchEntry, error) {
+	entries := []issueBatchEntry{}
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		role, title := defaultRole, line
+		if before, after, ok := strings.Cut(line, "\t"); ok {
+			role, title = strings.TrimSpace(before), strings.TrimSpace(after)
+		}
+		if !ralph.IsSupportedRole(role) {
+			return nil, fmt.Errorf("batch line %d: invalid role: %s", lineNo, role)
+		}
+		if title == "" {
+			return nil, fmt.Errorf("batch line %d: title is required", lineNo)
+		}
+		entries = append(entries, issueBatchEntry{Line: lineNo, Role: role, Title: title})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read batch: %w", err)
+	}
+	return entries, nil
+}
+
+// runNewBatch creates one issue per batch line; source "-" reads stdin.
+func runNewBatch(paths ralph.Paths, source, defaultRole string, opts ralph.IssueCreateOptions, stdin io.Reader, w io.Writer) error {
+	in := stdin
+	if source != "-" {
+		f, err := os.Open(source)
+		if err != nil {
+			return fmt.Errorf("open batch file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	entries, err := parseIssueBatch(in, defaultRole)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("batch has no titles: %s", source)
+	}
+	created, duplicates := 0, 0
+	for _, entry := range entries {
+		path, _, err := ralph.CreateIssueWithOptions(paths, entry.Role, entry.Title, opts)
+		if errors.Is(err, ralph.ErrDuplicateIssue) {
+			// With --dedupe a re-run of the same checklist only adds new lines.
+			duplicates++
+			fmt.Fprintf(w, "skipped: line %d %v\n", entry.Line, err)
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(w, "new batch summary\n- created: %d/%d\n", created, len(entries))
+			return fmt.Errorf("batch line %d: %w", entry.Line, err)
+		}
+		created++
+		fmt.Fprintf(w, "created: %s\n", path)
+	}
+	fmt.Fprintf(w, "new batch summary\n- source: %s\n- created: %d\n", source, created)
+	if opts.Dedupe {
+		fmt.Fprintf(w, "- skipped_duplicate: %d\n", duplicates)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"codex-ralph/internal/ralph"
+)
+
+func TestRunNewBatchCreatesIssuePerLine(t *testing.T) {
+	t.Parallel()
+
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	batch := "# seed backlog\nqa\tverify checkout flow\n\nadd health endpoint\n"
+	var out bytes.Buffer
+	if err := runNewBatch(paths, "-", "developer", ralph.IssueCreateOptions{Priority: 5}, strings.NewReader(batch), &out); err != nil {
+		t.Fatalf("run batch failed: %v", err)
+	}
+	if got := strings.Count(out.String(), "created: "+paths.IssuesDir); got != 2 {
+		t.Fatalf("expected 2 created paths, got=%d output=%q", got, out.String())
+	}
+	if !strings.Contains(out.String(), "- created: 2") {
+		t.Fatalf("expected count summary: %q", out.String())
+	}
+	entries, err := ralph.ListIssues(paths, ralph.IssueListOptions{Statuses: []string{"ready"}})
+	if err != nil {
+		t.Fatalf("list issues failed: %v", err)
+	}
+	roles := map[string]string{}
+	for _, entry := range entries {
+		roles[entry.Meta.Title] = entry.Meta.Role
+		if entry.Meta.Priority != 5 {
+			t.Fatalf("batch priority not applied: %+v", entry.Meta)
+		}
+	}
+	if roles["verify checkout flow"] != "qa" || roles["add health endpoint"] != "developer" {
+		t.Fatalf("role mismatch: %v", roles)
+	}
+}
+
+func TestRunNewBatchRejectsInvalidLineBeforeCreating(t *testing.T) {
+	t.Parallel()
+
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	file := filepath.Join(t.TempDir(), "batch.txt")
+	if err := os.WriteFile(file, []byte("first title\nceo\tsecond title\n"), 0o644); err != nil {
+		t.Fatalf("write batch failed: %v", err)
+	}
+	err = runNewBatch(paths, file, "developer", ralph.IssueCreateOptions{}, nil, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "batch line 2: invalid role") {
+		t.Fatalf("expected line 2 role error, got %v", err)
+	}
+	if entries, _ := filepath.Glob(filepath.Join(paths.IssuesDir, "*.md")); len(entries) != 0 {
+		t.Fatalf("no issue should be created on a bad batch: %v", entries)
+	}
+}
+
+func TestRunNewBatchDedupeSkipsOpenDuplicates(t *testing.T) {
+	t.Parallel()
+
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	if _, _, err := ralph.CreateIssue(paths, "developer", "Add health endpoint"); err != nil {
+		t.Fatalf("seed issue failed: %v", err)
+	}
+	var out bytes.Buffer
+	batch := "add health endpoint\nqa\tverify checkout\nqa\tVerify  checkout\n"
+	if err := runNewBatch(paths, "-", "developer", ralph.IssueCreateOptions{Dedupe: true}, strings.NewReader(batch), &out); err != nil {
+		t.Fatalf("run batch failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "- created: 1\n") || !strings.Contains(out.String(), "- skipped_duplicate: 2") {
+		t.Fatalf("expected 1 created and 2 duplicates: %q", out.String())
+	}
+
+	reply, err := telegramNewIssueCommand(paths, "qa verify checkout")
+	if err != nil {
+		t.Fatalf("telegram new failed: %v", err)
+	}
+	if !strings.Contains(reply, "issue created") {
+		t.Fatalf("telegram /new should follow the profile default (dedupe off): %q", reply)
+	}
+
+	if err := os.WriteFile(paths.ProfileYAMLFile, []byte("issue_dedupe: true\n"), 0o644); err != nil {
+		t.Fatalf("write profile failed: %v", err)
+	}
+	reply, err = telegramNewIssueCommand(paths, "qa verify checkout")
+	if err != nil {
+		t.Fatalf("telegram new failed: %v", err)
+	}
+	if !strings.Contains(reply, "issue not created") || !strings.Contains(reply, "duplicate issue") {
+		t.Fatalf("profile issue_dedupe should refuse the duplicate: %q", reply)
+	}
+}
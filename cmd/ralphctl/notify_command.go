@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+func runNotifyCommand(controlDir string, paths ralph.Paths, args []string) error {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "Usage: ralphctl --control-dir DIR --project-dir DIR notify run --webhook-url URL [flags]")
+		fmt.Fprintln(os.Stderr, "Env: RALPH_NOTIFY_WEBHOOK_URL, RALPH_NOTIFY_WEBHOOK_SECRET")
+	}
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("notify subcommand is required")
+	}
+
+	switch args[0] {
+	case "run":
+		return runNotifyRunCommand(controlDir, paths, args[1:])
+	default:
+		usage()
+		return fmt.Errorf("unknown notify subcommand: %s", args[0])
+	}
+}
+
+func runNotifyRunCommand(controlDir string, paths ralph.Paths, args []string) error {
+	defaults := defaultTelegramCLIConfig()
+	fs := flag.NewFlagSet("notify run", flag.ContinueOnError)
+	webhookURL := fs.String("webhook-url", strings.TrimSpace(os.Getenv("RALPH_NOTIFY_WEBHOOK_URL")), "webhook URL that receives alerts as JSON POSTs")
+	webhookSecret := fs.String("webhook-secret", os.Getenv("RALPH_NOTIFY_WEBHOOK_SECRET"), "shared secret for the X-Ralph-Signature HMAC-SHA256 header")
+	scope := fs.String("scope", defaults.NotifyScope, "notify scope: project|fleet|auto")
+	intervalSec := fs.Int("interval-sec", defaults.NotifyIntervalSec, "status poll interval")
+	retryThreshold := fs.Int("retry-threshold", defaults.NotifyRetryThreshold, "codex retry alert threshold")
+	permStreakThreshold := fs.Int("perm-streak-threshold", defaults.NotifyPermStreakThreshold, "permission streak alert threshold")
+	minSeverity := fs.String("min-severity", defaults.NotifyMinSeverity, "minimum alert severity to send: info|warn|critical")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*webhookURL) == "" {
+		return fmt.Errorf("--webhook-url is required (or set RALPH_NOTIFY_WEBHOOK_URL)")
+	}
+	if *intervalSec <= 0 {
+		return fmt.Errorf("--interval-sec must be > 0")
+	}
+	resolvedScope, err := normalizeNotifyScope(*scope)
+	if err != nil {
+		return fmt.Errorf("invalid --scope: %w", err)
+	}
+	resolvedSeverity, err := normalizeNotifySeverity(*minSeverity)
+	if err != nil {
+		return fmt.Errorf("invalid --min-severity: %w", err)
+	}
+
+	fmt.Println("Ralph Notify")
+	fmt.Println("============")
+	fmt.Printf("Project Dir: %s\n", paths.ProjectDir)
+	fmt.Printf("Webhook:     %s\n", strings.TrimSpace(*webhookURL))
+	fmt.Printf("Signed:      %t\n", *webhookSecret != "")
+	fmt.Printf("Scope:       %s\n", resolvedScope)
+	fmt.Printf("Level:       %s+\n", resolvedSeverity)
+	fmt.Printf("Every:       %ds\n", *intervalSec)
+	if *webhookSecret == "" {
+		fmt.Println("warning: no --webhook-secret; receivers cannot verify alert authenticity")
+	}
+
+	handler := newScopedStatusNotifyHandler(controlDir, paths, resolvedScope, *retryThreshold, *permStreakThreshold)
+	handler = withNotifySeverityFilter(handler, resolvedSeverity)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return runWebhookNotifyLoop(ctx, handler, webhookNotifyOptions{
+		URL:        strings.TrimSpace(*webhookURL),
+		Secret:     *webhookSecret,
+		ProjectDir: paths.ProjectDir,
+		Interval:   time.Duration(*intervalSec) * time.Second,
+		Out:        os.Stdout,
+	})
+}
+
+type webhookNotifyOptions struct {
+	URL        string
+	Secret     string
+	ProjectDir string
+	Interval   time.Duration
+	Out        io.Writer
+}
+
+func runWebhookNotifyLoop(ctx context.Context, handler ralph.TelegramNotifyHandler, opts webhookNotifyOptions) error {
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		sendWebhookAlerts(ctx, handler, opts)
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(opts.Out, "[notify] interrupted; stopping")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func sendWebhookAlerts(ctx context.Context, handler ralph.TelegramNotifyHandler, opts webhookNotifyOptions) {
+	alerts, err := handler(ctx)
+	if err != nil {
+		fmt.Fprintf(opts.Out, "[notify] warning: status poll failed: %v\n", err)
+		return
+	}
+	for _, alert := range alerts {
+		alert = strings.TrimSpace(alert)
+		if alert == "" {
+			continue
+		}
+		payload := webhookAlertFromText(alert, opts.ProjectDir)
+		if err := ralph.PostWebhookAlert(ctx, nil, opts.URL, opts.Secret, payload); err != nil {
+			fmt.Fprintf(opts.Out, "[notify] warning: webhook send failed kind=%s: %v\n", payload.Kind, err)
+			continue
+		}
+		fmt.Fprintf(opts.Out, "[notify] sent kind=%s severity=%s project=%s\n", payload.Kind, payload.Severity, payload.ProjectDir)
+	}
+}
+
+func webhookAlertFromText(alert, defaultProjectDir string) ralph.WebhookAlert {
+	kind := telegramAlertKind(alert)
+	if kind == "" {
+		kind = "status"
+	}
+	projectDir := defaultProjectDir
+	for _, line := range strings.Split(alert, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "- project: "); ok && strings.TrimSpace(v) != "" {
+			projectDir = strings.TrimSpace(v)
+			break
+		}
+	}
+	return ralph.WebhookAlert{
+		TS:         time.Now().UTC().Format(time.RFC3339),
+		ProjectDir: projectDir,
+		Kind:       kind,
+		Severity:   telegramAlertSeverity(alert),
+		Message:    alert,
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+func TestWebhookAlertFromText(t *testing.T) {
+	t.Parallel()
+
+	alert := webhookAlertFromText("[ralph alert][blocked]\n- project: /work/app\n- blocked: 2 (+1)", "/fallback")
+	if alert.Kind != "blocked" || alert.Severity != telegramAlertSeverityCritical || alert.ProjectDir != "/work/app" {
+		t.Fatalf("alert mismatch: %+v", alert)
+	}
+	plain := webhookAlertFromText("something happened", "/fallback")
+	if plain.Kind != "status" || plain.ProjectDir != "/fallback" {
+		t.Fatalf("plain alert mismatch: %+v", plain)
+	}
+}
+
+func TestSendWebhookAlertsPostsSignedJSON(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan ralph.WebhookAlert, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !ralph.VerifyWebhookSignature("secret", body, r.Header.Get(ralph.WebhookSignatureHeader)) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		var alert ralph.WebhookAlert
+		_ = json.Unmarshal(body, &alert)
+		received <- alert
+	}))
+	defer srv.Close()
+
+	handler := func(ctx context.Context) ([]string, error) {
+		return []string{"[ralph alert][retry]\n- project: /work/app\n- codex_retries: 3 (threshold=2)"}, nil
+	}
+	var out strings.Builder
+	sendWebhookAlerts(context.Background(), handler, webhookNotifyOptions{
+		URL:        srv.URL,
+		Secret:     "secret",
+		ProjectDir: "/fallback",
+		Interval:   time.Second,
+		Out:        &out,
+	})
+	select {
+	case alert := <-received:
+		if alert.Kind != "retry" || alert.Severity != telegramAlertSeverityWarn {
+			t.Fatalf("alert mismatch: %+v", alert)
+		}
+	default:
+		t.Fatalf("webhook not called; output=%q", out.String())
+	}
+	if !strings.Contains(out.String(), "sent kind=retry") {
+		t.Fatalf("missing send log: %q", out.String())
+	}
+}
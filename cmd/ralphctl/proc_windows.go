@@ -0,0 +1,27 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+const windowsDetachedProcess = 0x00000008
+
+func detachedSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | windowsDetachedProcess}
+}
+
+// FindProcess opens a handle on Windows, so it fails once the process is gone.
+func telegramPRDProcessAlive(pid int) (bool, error) {
+	if pid <= 0 {
+		return false, nil
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false, nil
+	}
+	_ = proc.Release()
+	return true, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"codex-ralph/internal/ralph"
+)
+
+var errProjectControlInProgress = errors.New("control operation already in progress")
+
+func projectControlLockFile(paths ralph.Paths) string {
+	return filepath.Join(paths.RalphDir, "control.lock")
+}
+
+// withProjectControlLock serializes start/stop operations on one project.
+// A concurrent caller fails fast with errProjectControlInProgress instead of waiting.
+// Locks left by dead owners are reclaimed by ralph.TryCreateOwnedLock.
+func withProjectControlLock(paths ralph.Paths, op string, fn func() error) error {
+	lockPath := projectControlLockFile(paths)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		return fmt.Errorf("create control lock dir: %w", err)
+	}
+	ok, err := ralph.TryCreateOwnedLock(lockPath, op)
+	if err != nil {
+		return fmt.Errorf("acquire control lock: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s requested while %s", errProjectControlInProgress, op, projectControlLockHolder(lockPath))
+	}
+	defer func() {
+		_ = os.Remove(lockPath)
+	}()
+	return fn()
+}
+
+// projectControlLockHolder describes the operation holding lockPath ("pid\ntime\nop\n").
+func projectControlLockHolder(lockPath string) string {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return "another operation is running"
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return "another operation is running"
+	}
+	return fmt.Sprintf("%s is running (pid=%s since %s)", fields[2], fields[0], fields[1])
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+func TestProjectControlLockRejectsConcurrentOperation(t *testing.T) {
+	t.Parallel()
+
+	controlDir := filepath.Join(t.TempDir(), "control")
+	projectDir := filepath.Join(t.TempDir(), "project")
+	paths, err := ralph.NewPaths(controlDir, projectDir)
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+
+	var nestedErr error
+	if err := withProjectControlLock(paths, "start", func() error {
+		nestedErr = withProjectControlLock(paths, "stop", func() error {
+			t.Fatalf("second operation must not run while the lock is held")
+			return nil
+		})
+		return nil
+	}); err != nil {
+		t.Fatalf("first lock failed: %v", err)
+	}
+	if !errors.Is(nestedErr, errProjectControlInProgress) {
+		t.Fatalf("expected in-progress error, got %v", nestedErr)
+	}
+	if !strings.Contains(nestedErr.Error(), "start is running") {
+		t.Fatalf("error should name the running operation: %v", nestedErr)
+	}
+	reply, err := telegramControlBusyReply(nestedErr)
+	if err != nil || !strings.Contains(reply, "already in progress") {
+		t.Fatalf("telegram reply mismatch: reply=%q err=%v", reply, err)
+	}
+
+	if _, err := os.Stat(projectControlLockFile(paths)); !os.IsNotExist(err) {
+		t.Fatalf("lock should be released: %v", err)
+	}
+	ran := false
+	if err := withProjectControlLock(paths, "stop", func() error {
+		ran = true
+		return nil
+	}); err != nil || !ran {
+		t.Fatalf("lock should be reusable after release: ran=%t err=%v", ran, err)
+	}
+}
+
+func TestProjectControlLockBreaksDeadOwner(t *testing.T) {
+	t.Parallel()
+
+	controlDir := filepath.Join(t.TempDir(), "control")
+	projectDir := filepath.Join(t.TempDir(), "project")
+	paths, err := ralph.NewPaths(controlDir, projectDir)
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	lockPath := projectControlLockFile(paths)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		t.Fatalf("mkdir lock dir: %v", err)
+	}
+	// pid_max on Linux is at most 2^22, so this pid cannot be alive.
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n2026-01-01T00:00:00Z\nstart\n", 1<<23)), 0o600); err != nil {
+		t.Fatalf("write lock file: %v", err)
+	}
+
+	ran := false
+	if err := withProjectControlLock(paths, "stop", func() error {
+		ran = true
+		return nil
+	}); err != nil || !ran {
+		t.Fatalf("dead owner lock should be broken: ran=%t err=%v", ran, err)
+	}
+}
+
+func TestProjectControlLockDeadOwnerReclaimedByOneContender(t *testing.T) {
+	t.Parallel()
+
+	controlDir := filepath.Join(t.TempDir(), "control")
+	projectDir := filepath.Join(t.TempDir(), "project")
+	paths, err := ralph.NewPaths(controlDir, projectDir)
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	lockPath := projectControlLockFile(paths)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		t.Fatalf("mkdir lock dir: %v", err)
+	}
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n2026-01-01T00:00:00Z\nstart\n", 1<<23)), 0o600); err != nil {
+		t.Fatalf("write lock file: %v", err)
+	}
+
+	var active, maxActive, ran int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_ = withProjectControlLock(paths, "stop", func() error {
+				n := atomic.AddInt32(&active, 1)
+				for {
+					m := atomic.LoadInt32(&maxActive)
+					if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+						break
+					}
+				}
+				atomic.AddInt32(&ran, 1)
+				time.Sleep(50 * time.Millisecond)
+				atomic.AddInt32(&active, -1)
+				return nil
+			})
+		}()
+	}
+	close(start)
+	wg.Wait()
+	if ran == 0 || maxActive != 1 {
+		t.Fatalf("exactly one contender should hold the lock at a time: ran=%d maxActive=%d", ran, maxActive)
+	}
+}
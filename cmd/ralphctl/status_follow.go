@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+type statusFieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// statusFollowEvent is one line of `status --follow-json`: a full snapshot first,
+// then a change event whenever a tracked field moves or buildStatusAlerts fires.
+type statusFollowEvent struct {
+	Event      string               `json:"event"`
+	TS         string               `json:"ts"`
+	ProjectDir string               `json:"project_dir"`
+	Changes    []statusFieldChange  `json:"changes,omitempty"`
+	Alerts     []ralph.WebhookAlert `json:"alerts,omitempty"`
+	Status     *ralph.Status        `json:"status,omitempty"`
+	Error      string               `json:"error,omitempty"`
+}
+
+type statusFollowField struct {
+	name  string
+	value func(ralph.Status) any
+}
+
+var statusFollowFields = []statusFollowField{
+	{"enabled", func(s ralph.Status) any { return s.Enabled }},
+	{"daemon", func(s ralph.Status) any { return s.Daemon }},
+	{"queue_state", func(s ralph.Status) any { return s.QueueState }},
+	{"codex_circuit_state", func(s ralph.Status) any { return s.CodexCircuitState }},
+	{"queue_ready", func(s ralph.Status) any { return s.QueueReady }},
+	{"in_progress", func(s ralph.Status) any { return s.InProgress }},
+	{"done", func(s ralph.Status) any { return s.Done }},
+	{"blocked", func(s ralph.Status) any { return s.Blocked }},
+	{"dead_letter", func(s ralph.Status) any { return s.DeadLetter }},
+	{"next_ready", func(s ralph.Status) any { return s.NextReady }},
+	{"last_failure_cause", func(s ralph.Status) any { return s.LastFailureCause }},
+	{"last_failure_category", func(s ralph.Status) any { return s.LastFailureCategory }},
+	{"last_failure_updated_at", func(s ralph.Status) any { return s.LastFailureUpdatedAt }},
+	{"last_codex_retry_count", func(s ralph.Status) any { return s.LastCodexRetryCount }},
+	{"last_permission_streak", func(s ralph.Status) any { return s.LastPermissionStreak }},
+	{"last_busywait_detected_at", func(s ralph.Status) any { return s.LastBusyWaitDetectedAt }},
+	{"last_crash_loop_at", func(s ralph.Status) any { return s.LastCrashLoopAt }},
+}
+
+func diffStatusFields(prev, current ralph.Status) []statusFieldChange {
+	changes := []statusFieldChange{}
+	for _, field := range statusFollowFields {
+		from, to := field.value(prev), field.value(current)
+		if from != to {
+			changes = append(changes, statusFieldChange{Field: field.name, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+func buildStatusFollowEvent(prev, current ralph.Status, retryThreshold, permThreshold int) (statusFollowEvent, bool) {
+	event := statusFollowEvent{
+		Event:      "change",
+		TS:         time.Now().UTC().Format(time.RFC3339),
+		ProjectDir: current.ProjectDir,
+		Changes:    diffStatusFields(prev, current),
+	}
+	for _, alert := range buildStatusAlerts(prev, current, retryThreshold, permThreshold) {
+		event.Alerts = append(event.Alerts, webhookAlertFromText(alert, current.ProjectDir))
+	}
+	return event, len(event.Changes) > 0 || len(event.Alerts) > 0
+}
+
+type statusFollowOptions struct {
+	Since          time.Duration
+	Interval       time.Duration
+	RetryThreshold int
+	PermThreshold  int
+}
+
+// runStatusFollowJSON polls status and streams JSON lines to out until ctx is done.
+func runStatusFollowJSON(ctx context.Context, paths ralph.Paths, opts statusFollowOptions, out io.Writer) error {
+	enc := json.NewEncoder(out)
+	var prev ralph.Status
+	initialized := false
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		current, err := ralph.GetStatusSince(paths, opts.Since)
+		switch {
+		case err != nil:
+			if encErr := enc.Encode(statusFollowEvent{Event: "error", TS: time.Now().UTC().Format(time.RFC3339), ProjectDir: paths.ProjectDir, Error: err.Error()}); encErr != nil {
+				return fmt.Errorf("write status event: %w", encErr)
+			}
+		case !initialized:
+			initialized = true
+			snapshot := current
+			if encErr := enc.Encode(statusFollowEvent{Event: "snapshot", TS: time.Now().UTC().Format(time.RFC3339), ProjectDir: current.ProjectDir, Status: &snapshot}); encErr != nil {
+				return fmt.Errorf("write status event: %w", encErr)
+			}
+			prev = current
+		default:
+			if event, changed := buildStatusFollowEvent(prev, current, opts.RetryThreshold, opts.PermThreshold); changed {
+				if encErr := enc.Encode(event); encErr != nil {
+					return fmt.Errorf("write status event: %w", encErr)
+				}
+			}
+			prev = current
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
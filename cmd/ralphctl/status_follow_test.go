@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+type statusFollowTestWriter func(p []byte) (int, error)
+
+func (f statusFollowTestWriter) Write(p []byte) (int, error) { return f(p) }
+
+func TestBuildStatusFollowEventReportsChangedFieldsAndAlerts(t *testing.T) {
+	t.Parallel()
+
+	prev := ralph.Status{ProjectDir: "/tmp/p", Daemon: "running", QueueReady: 2, Blocked: 0}
+	if _, changed := buildStatusFollowEvent(prev, prev, 2, 3); changed {
+		t.Fatalf("identical status should not emit an event")
+	}
+
+	curr := prev
+	curr.QueueReady = 1
+	curr.Blocked = 1
+	curr.LastFailureCause = "codex_failed_after_3_attempts"
+	curr.LastFailureUpdatedAt = "2026-02-20T08:10:00Z"
+	event, changed := buildStatusFollowEvent(prev, curr, 2, 3)
+	if !changed || event.Event != "change" {
+		t.Fatalf("expected change event: %+v", event)
+	}
+	fields := map[string]statusFieldChange{}
+	for _, change := range event.Changes {
+		fields[change.Field] = change
+	}
+	if got := fields["queue_ready"]; got.From != 2 || got.To != 1 {
+		t.Fatalf("queue_ready change mismatch: %+v", event.Changes)
+	}
+	if _, ok := fields["blocked"]; !ok || len(fields) != 4 {
+		t.Fatalf("unexpected changes: %+v", event.Changes)
+	}
+	if len(event.Alerts) != 1 || event.Alerts[0].Kind != "blocked" {
+		t.Fatalf("expected blocked alert: %+v", event.Alerts)
+	}
+}
+
+func TestRunStatusFollowJSONStreamsSnapshotThenChange(t *testing.T) {
+	controlDir := filepath.Join(t.TempDir(), "control")
+	projectDir := filepath.Join(t.TempDir(), "project")
+	paths, err := ralph.NewPaths(controlDir, projectDir)
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	if err := ralph.EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	events := []statusFollowEvent{}
+	out := statusFollowTestWriter(func(p []byte) (int, error) {
+		var event statusFollowEvent
+		if err := json.Unmarshal(p, &event); err != nil {
+			t.Errorf("event line is not json: %q", string(p))
+		}
+		events = append(events, event)
+		if len(events) == 1 {
+			if _, _, err := ralph.CreateIssue(paths, "developer", "queued work"); err != nil {
+				t.Errorf("create issue: %v", err)
+			}
+		} else {
+			cancel()
+		}
+		return len(p), nil
+	})
+
+	opts := statusFollowOptions{Since: time.Hour, Interval: 20 * time.Millisecond, RetryThreshold: 2, PermThreshold: 3}
+	if err := runStatusFollowJSON(ctx, paths, opts, out); err != nil {
+		t.Fatalf("follow status: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected snapshot and change, got %+v", events)
+	}
+	if events[0].Event != "snapshot" || events[0].Status == nil || events[0].Status.QueueReady != 0 {
+		t.Fatalf("snapshot mismatch: %+v", events[0])
+	}
+	found := false
+	for _, change := range events[1].Changes {
+		if change.Field == "queue_ready" && change.To == float64(1) {
+			found = true
+		}
+	}
+	if events[1].Event != "change" || !found {
+		t.Fatalf("change event mismatch: %+v", events[1])
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+// renderStatusText is the plain `status` output, shared with --watch refreshes.
+func renderStatusText(paths ralph.Paths, since time.Duration, w io.Writer) error {
+	st, err := ralph.GetStatusSince(paths, since)
+	if err != nil {
+		return err
+	}
+	st.Print(w)
+	cutoverState, cutoverErr := ralph.ControlPlaneGetCutoverState(paths.ProjectDir)
+	if cutoverErr == nil {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "[Control Plane]")
+		fmt.Fprintf(w, "Mode:   %s\n", cutoverState.Mode)
+		fmt.Fprintf(w, "Canary: %t\n", cutoverState.Canary)
+		if cutoverState.UpdatedAtUTC != "" {
+			fmt.Fprintf(w, "Updated: %s\n", cutoverState.UpdatedAtUTC)
+		}
+		if cutoverState.Mode == "v2" {
+			cpStatus, cpErr := ralph.ControlPlaneStatusReport(paths.ProjectDir)
+			if cpErr == nil {
+				fmt.Fprintf(w, "Tasks:  total=%d ready=%d running=%d verifying=%d done=%d blocked=%d\n",
+					cpStatus.TasksTotal,
+					cpStatus.StateCounts[ralph.ControlPlaneTaskStateReady],
+					cpStatus.StateCounts[ralph.ControlPlaneTaskStateRunning],
+					cpStatus.StateCounts[ralph.ControlPlaneTaskStateVerifying],
+					cpStatus.StateCounts[ralph.ControlPlaneTaskStateDone],
+					cpStatus.StateCounts[ralph.ControlPlaneTaskStateBlocked],
+				)
+				fmt.Fprintf(w, "KPI:    blocked_rate=%.4f recovery_success_rate=%.4f mttr_seconds=%.2f\n",
+					cpStatus.Metrics.BlockedRate,
+					cpStatus.Metrics.RecoverySuccessRate,
+					cpStatus.Metrics.MeanTimeToRecovery,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// runStatusWatch clears the screen and re-renders status every interval until
+// ctx is cancelled, like the fleet dashboard --watch loop.
+func runStatusWatch(ctx context.Context, paths ralph.Paths, since, interval time.Duration, w io.Writer) error {
+	for {
+		fmt.Fprint(w, "\033[H\033[2J")
+		if err := renderStatusText(paths, since, w); err != nil {
+			return err
+		}
+		if err := sleepOrInterrupt(ctx, interval); err != nil {
+			fmt.Fprintln(w, "[status] interrupted")
+			return nil
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"codex-ralph/internal/ralph"
+)
+
+func TestRunStatusWatchRerendersUntilInterrupted(t *testing.T) {
+	controlDir := filepath.Join(t.TempDir(), "control")
+	projectDir := filepath.Join(t.TempDir(), "project")
+	paths, err := ralph.NewPaths(controlDir, projectDir)
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	if err := ralph.EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	var out bytes.Buffer
+	if err := runStatusWatch(ctx, paths, time.Hour, 100*time.Millisecond, &out); err != nil {
+		t.Fatalf("status watch: %v", err)
+	}
+	text := out.String()
+	if n := strings.Count(text, "\033[H\033[2J"); n < 2 {
+		t.Fatalf("expected repeated screen clears, got %d:\n%s", n, text)
+	}
+	if n := strings.Count(text, "Path:    "+paths.ProjectDir); n < 2 {
+		t.Fatalf("expected status re-rendered per refresh, got %d", n)
+	}
+	if !strings.HasSuffix(text, "[status] interrupted\n") {
+		t.Fatalf("watch should report the interrupt: %q", text[len(text)-40:])
+	}
+}
@@ -7,8 +7,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+
+	"codex-ralph/internal/ralph"
 )
 
 const telegramTokenBindingStoreVersion = 1
@@ -73,12 +76,51 @@ func ensureTelegramTokenBound(controlDir, token, projectDir string, rebind bool)
 	}
 
 	path := telegramTokenBindingsPath(controlDir)
-	lockPath := path + ".lock"
-	if err := acquireTelegramBindingLock(lockPath); err != nil {
-		return err
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create telegram token binding lock dir: %w", err)
 	}
-	defer releaseTelegramBindingLock(lockPath)
+	return ralph.WithOwnedLock(path+".lock", 5*time.Second, func() error {
+		return bindTelegramToken(path, token, projectDir, rebind)
+	})
+}
+
+// moveTelegramTokenBindings repoints bot tokens bound to oldDir at newDir, so a
+// moved project keeps its bot without --rebind-bot. It returns how many moved.
+func moveTelegramTokenBindings(controlDir, oldDir, newDir string) (int, error) {
+	oldDir = filepath.Clean(strings.TrimSpace(oldDir))
+	newDir = filepath.Clean(strings.TrimSpace(newDir))
+	path := telegramTokenBindingsPath(controlDir)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return 0, nil
+	}
+	moved := 0
+	err := ralph.WithOwnedLock(path+".lock", 5*time.Second, func() error {
+		store, err := loadTelegramTokenBindingStore(path)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		for hash, entry := range store.Bindings {
+			if filepath.Clean(strings.TrimSpace(entry.ProjectDir)) != oldDir {
+				continue
+			}
+			entry.ProjectDir = newDir
+			entry.UpdatedAtUTC = now
+			store.Bindings[hash] = entry
+			moved++
+		}
+		if moved == 0 {
+			return nil
+		}
+		store.UpdatedAtUTC = now
+		return saveTelegramTokenBindingStore(path, store)
+	})
+	return moved, err
+}
 
+// bindTelegramToken records token -> projectDir in the binding store at path.
+// Callers hold the store lock.
+func bindTelegramToken(path, token, projectDir string, rebind bool) error {
 	store, err := loadTelegramTokenBindingStore(path)
 	if err != nil {
 		return err
@@ -118,30 +160,70 @@ func telegramTokenHash(token string) string {
 	return hex.EncodeToString(sum[:])
 }
 
-func acquireTelegramBindingLock(lockPath string) error {
-	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
-		return fmt.Errorf("create telegram token binding lock dir: %w", err)
+// telegramTokenRuntimeLockPath is held by the running `telegram run` process for a
+// token, so a second daemon polling the same bot (Telegram 409) is refused up front.
+func telegramTokenRuntimeLockPath(controlDir, token string) string {
+	return filepath.Join(controlDir, "telegram-token-locks", telegramTokenHash(token)[:16]+".lock")
+}
+
+// telegramTokenRuntimeOwner returns the live pid and project holding the token lock.
+// Dead or unreadable owners are reported as not running.
+func telegramTokenRuntimeOwner(lockPath string) (int, string, bool) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return 0, "", false
 	}
-	deadline := time.Now().Add(5 * time.Second)
-	for {
-		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
-		if err == nil {
-			_, _ = f.WriteString(fmt.Sprintf("%d\n", os.Getpid()))
-			_ = f.Close()
-			return nil
-		}
-		if !os.IsExist(err) {
-			return fmt.Errorf("acquire telegram token binding lock: %w", err)
-		}
-		if time.Now().After(deadline) {
-			return fmt.Errorf("acquire telegram token binding lock timeout")
-		}
-		time.Sleep(100 * time.Millisecond)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	pid, err := strconv.Atoi(strings.TrimSpace(lines[0]))
+	if err != nil || pid <= 0 {
+		return 0, "", false
+	}
+	if alive, aliveErr := telegramPRDProcessAlive(pid); aliveErr != nil || !alive {
+		return 0, "", false
+	}
+	// Lines follow ralph.TryCreateOwnedLock: pid, acquired time, project dir.
+	projectDir := ""
+	if len(lines) > 2 {
+		projectDir = strings.TrimSpace(lines[2])
+	}
+	return pid, projectDir, true
+}
+
+func telegramTokenInUseError(pid int, projectDir string) error {
+	return fmt.Errorf(
+		"bot token is already polled by a running telegram daemon (project=%s pid=%d); stop it first: ralphctl --project-dir %s telegram stop",
+		valueOrDash(projectDir), pid, valueOrDash(projectDir),
+	)
+}
+
+// checkTelegramTokenAvailable fails when a live daemon for another project holds the
+// token lock. Same-project daemons are left to the pid file check in startTelegramDaemon.
+func checkTelegramTokenAvailable(controlDir, token, projectDir string) error {
+	pid, owner, running := telegramTokenRuntimeOwner(telegramTokenRuntimeLockPath(controlDir, token))
+	if running && pid != os.Getpid() && filepath.Clean(owner) != filepath.Clean(projectDir) {
+		return telegramTokenInUseError(pid, owner)
 	}
+	return nil
 }
 
-func releaseTelegramBindingLock(lockPath string) {
-	_ = os.Remove(lockPath)
+// acquireTelegramTokenRuntimeLock records this process as the poller for token until release.
+// Locks left behind by dead processes are reclaimed by ralph.TryCreateOwnedLock.
+func acquireTelegramTokenRuntimeLock(controlDir, token, projectDir string) (func(), error) {
+	lockPath := telegramTokenRuntimeLockPath(controlDir, token)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		return nil, fmt.Errorf("create telegram token lock dir: %w", err)
+	}
+	ok, err := ralph.TryCreateOwnedLock(lockPath, filepath.Clean(projectDir))
+	if err != nil {
+		return nil, fmt.Errorf("acquire telegram token lock: %w", err)
+	}
+	if !ok {
+		if pid, owner, running := telegramTokenRuntimeOwner(lockPath); running {
+			return nil, telegramTokenInUseError(pid, owner)
+		}
+		return nil, fmt.Errorf("acquire telegram token lock: %s is held", lockPath)
+	}
+	return func() { _ = os.Remove(lockPath) }, nil
 }
 
 func loadTelegramTokenBindingStore(path string) (telegramTokenBindingStore, error) {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -79,3 +80,76 @@ func TestTelegramTokenBindingStoreDoesNotPersistRawToken(t *testing.T) {
 		t.Fatalf("binding file mode mismatch: got=%#o want=%#o", info.Mode().Perm(), 0o600)
 	}
 }
+
+func TestTelegramTokenRuntimeLockRejectsSecondDaemon(t *testing.T) {
+	t.Parallel()
+
+	controlDir := filepath.Join(t.TempDir(), "control")
+	token := "123456:ABCDEF"
+	projectA := filepath.Join(t.TempDir(), "project-a")
+	projectB := filepath.Join(t.TempDir(), "project-b")
+	lockPath := telegramTokenRuntimeLockPath(controlDir, token)
+	if strings.Contains(lockPath, token) {
+		t.Fatalf("lock path must not contain raw token: %q", lockPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		t.Fatalf("mkdir lock dir: %v", err)
+	}
+	// The parent test process stands in for a live daemon owned by projectA.
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n2026-01-01T00:00:00Z\n%s\n", os.Getppid(), projectA)), 0o600); err != nil {
+		t.Fatalf("write lock file: %v", err)
+	}
+
+	err := checkTelegramTokenAvailable(controlDir, token, projectB)
+	if err == nil || !strings.Contains(err.Error(), projectA) {
+		t.Fatalf("expected conflict naming projectA, got %v", err)
+	}
+	if err := checkTelegramTokenAvailable(controlDir, token, projectA); err != nil {
+		t.Fatalf("same project should defer to pid file check: %v", err)
+	}
+	if _, err := acquireTelegramTokenRuntimeLock(controlDir, token, projectB); err == nil || !strings.Contains(err.Error(), projectA) {
+		t.Fatalf("acquire should refuse while projectA holds the token, got %v", err)
+	}
+
+	// pid_max on Linux is at most 2^22, so this pid cannot be alive.
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n2026-01-01T00:00:00Z\n%s\n", 1<<23, projectA)), 0o600); err != nil {
+		t.Fatalf("write stale lock file: %v", err)
+	}
+	release, err := acquireTelegramTokenRuntimeLock(controlDir, token, projectB)
+	if err != nil {
+		t.Fatalf("stale lock should be broken: %v", err)
+	}
+	if pid, owner, running := telegramTokenRuntimeOwner(lockPath); !running || pid != os.Getpid() || owner != filepath.Clean(projectB) {
+		t.Fatalf("lock owner mismatch: pid=%d owner=%q running=%t", pid, owner, running)
+	}
+	release()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("lock should be released: %v", err)
+	}
+}
+
+func TestMoveTelegramTokenBindingsFollowsProjectMove(t *testing.T) {
+	t.Parallel()
+
+	controlDir := filepath.Join(t.TempDir(), "control")
+	oldDir := filepath.Join(t.TempDir(), "app")
+	newDir := filepath.Join(t.TempDir(), "app-moved")
+	token := "123456:move-token"
+	if err := ensureTelegramTokenBound(controlDir, token, oldDir, false); err != nil {
+		t.Fatalf("bind token: %v", err)
+	}
+
+	moved, err := moveTelegramTokenBindings(controlDir, oldDir, newDir)
+	if err != nil || moved != 1 {
+		t.Fatalf("move bindings: moved=%d err=%v", moved, err)
+	}
+	if err := ensureTelegramTokenBound(controlDir, token, newDir, false); err != nil {
+		t.Fatalf("moved project should keep its bot without --rebind-bot: %v", err)
+	}
+	if err := ensureTelegramTokenBound(controlDir, token, oldDir, false); err == nil {
+		t.Fatalf("old dir should no longer own the bot")
+	}
+	if moved, err := moveTelegramTokenBindings(filepath.Join(t.TempDir(), "empty"), oldDir, newDir); err != nil || moved != 0 {
+		t.Fatalf("missing binding store should be a no-op: moved=%d err=%v", moved, err)
+	}
+}
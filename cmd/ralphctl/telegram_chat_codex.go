@@ -152,7 +152,7 @@ func analyzeTelegramChatWithCodex(paths ralph.Paths, chatID int64, input string)
 			lastErr = execErr
 		}
 		if attempt < retryAttempts {
-			time.Sleep(time.Duration(attempt*retryBackoffSec) * time.Second)
+			time.Sleep(ralph.ApplyRetryJitter(time.Duration(attempt*retryBackoffSec)*time.Second, profile.CodexRetryJitterPct))
 		}
 	}
 	if lastErr == nil {
@@ -186,7 +186,7 @@ func sanitizeTelegramCodexChatReply(raw string) string {
 func buildTelegramCodexChatPrompt(projectDir, conversationTail, input string) string {
 	var b strings.Builder
 	fmt.Fprintln(&b, "You are Codex in a Telegram bridge for a software project.")
-	fmt.Fprintln(&b, "Respond in concise Korean unless the user asks otherwise.")
+	fmt.Fprintf(&b, "Respond in concise %s unless the user asks otherwise.\n", telegramTextFor(telegramLangFromEnv()).ReplyLanguage)
 	fmt.Fprintln(&b, "Prioritize practical execution in the current project repository.")
 	fmt.Fprintln(&b, "If the user asks for PRD work, help refine requirements and propose concrete deliverables.")
 	fmt.Fprintln(&b, "If shell/code actions are requested, describe what you changed or what command should run.")
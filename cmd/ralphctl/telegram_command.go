@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -14,6 +15,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -29,8 +31,8 @@ var telegramProcessTableReader = defaultTelegramProcessTableReader
 
 func runTelegramCommand(controlDir string, paths ralph.Paths, args []string) error {
 	usage := func() {
-		fmt.Fprintln(os.Stderr, "Usage: ralphctl --control-dir DIR --project-dir DIR telegram <run|setup|stop|status|tail> [flags]")
-		fmt.Fprintln(os.Stderr, "Env: RALPH_TELEGRAM_BOT_TOKEN, RALPH_TELEGRAM_CHAT_IDS, RALPH_TELEGRAM_USER_IDS, RALPH_TELEGRAM_ALLOW_CONTROL, RALPH_TELEGRAM_NOTIFY, RALPH_TELEGRAM_NOTIFY_SCOPE, RALPH_TELEGRAM_COMMAND_TIMEOUT_SEC, RALPH_TELEGRAM_COMMAND_CONCURRENCY")
+		fmt.Fprintln(os.Stderr, "Usage: ralphctl --control-dir DIR --project-dir DIR telegram <run|setup|stop|status|tail|broadcast|test> [flags]")
+		fmt.Fprintln(os.Stderr, "Env: RALPH_TELEGRAM_BOT_TOKEN, RALPH_TELEGRAM_CHAT_IDS, RALPH_TELEGRAM_USER_IDS, RALPH_TELEGRAM_ALLOW_CONTROL, RALPH_TELEGRAM_NOTIFY, RALPH_TELEGRAM_NOTIFY_SCOPE, RALPH_TELEGRAM_NOTIFY_MIN_SEVERITY, RALPH_TELEGRAM_QUIET_HOURS, RALPH_TELEGRAM_QUIET_HOURS_TZ, RALPH_TELEGRAM_COMMAND_TIMEOUT_SEC, RALPH_TELEGRAM_COMMAND_CONCURRENCY, RALPH_TELEGRAM_COMMAND_RATE_PER_MIN, RALPH_TELEGRAM_LANG, RALPH_TELEGRAM_CODE_BLOCKS")
 	}
 	if len(args) == 0 {
 		usage()
@@ -41,13 +43,17 @@ func runTelegramCommand(controlDir string, paths ralph.Paths, args []string) err
 	case "run":
 		return runTelegramRunCommand(controlDir, paths, args[1:])
 	case "setup":
-		return runTelegramSetupCommand(controlDir, args[1:])
+		return runTelegramSetupCommand(controlDir, paths, args[1:])
 	case "stop":
 		return runTelegramStopCommand(paths, args[1:])
 	case "status":
 		return runTelegramStatusCommand(controlDir, paths, args[1:])
 	case "tail":
 		return runTelegramTailCommand(paths, args[1:])
+	case "broadcast":
+		return runTelegramBroadcastCommand(paths, args[1:])
+	case "test":
+		return runTelegramTestCommand(paths, args[1:])
 	default:
 		usage()
 		return fmt.Errorf("unknown telegram subcommand: %s", args[0])
@@ -55,14 +61,14 @@ func runTelegramCommand(controlDir string, paths ralph.Paths, args []string) err
 }
 
 func runTelegramRunCommand(controlDir string, paths ralph.Paths, args []string) error {
-	configFile := telegramConfigFileFromArgs(controlDir, args)
+	configFile := telegramConfigFileFromArgs(paths, args)
 	cfg, err := loadTelegramCLIConfig(configFile)
 	if err != nil {
 		return err
 	}
 
 	fs := flag.NewFlagSet("telegram run", flag.ContinueOnError)
-	configFileFlag := fs.String("config-file", configFile, "telegram config file path")
+	configFileFlag := fs.String("config-file", configFile, "telegram config file path (default: .ralph/telegram.env if present, else control dir)")
 	foreground := fs.Bool("foreground", false, "run in foreground (default: start daemon and return)")
 	token := fs.String("token", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_BOT_TOKEN")), cfg.Token), "telegram bot token")
 	chatIDsRaw := fs.String("chat-ids", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_CHAT_IDS")), cfg.ChatIDs), "allowed chat IDs CSV (required)")
@@ -73,8 +79,14 @@ func runTelegramRunCommand(controlDir string, paths ralph.Paths, args []string)
 	notifyIntervalSec := fs.Int("notify-interval-sec", envIntDefault("RALPH_TELEGRAM_NOTIFY_INTERVAL_SEC", cfg.NotifyIntervalSec), "status poll interval for notify alerts")
 	notifyRetryThreshold := fs.Int("notify-retry-threshold", envIntDefault("RALPH_TELEGRAM_NOTIFY_RETRY_THRESHOLD", cfg.NotifyRetryThreshold), "codex retry alert threshold")
 	notifyPermStreakThreshold := fs.Int("notify-perm-streak-threshold", envIntDefault("RALPH_TELEGRAM_NOTIFY_PERM_STREAK_THRESHOLD", cfg.NotifyPermStreakThreshold), "permission streak alert threshold")
+	notifyMinSeverity := fs.String("notify-min-severity", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_NOTIFY_MIN_SEVERITY")), cfg.NotifyMinSeverity), "minimum alert severity to push: info|warn|critical")
+	notifyQuietHours := fs.String("notify-quiet-hours", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_QUIET_HOURS")), cfg.NotifyQuietHours), "HH:MM-HH:MM window that holds back info/warn alerts (critical still sent)")
+	notifyQuietHoursTZ := fs.String("notify-quiet-hours-tz", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_QUIET_HOURS_TZ")), cfg.NotifyQuietHoursTZ), "IANA timezone for --notify-quiet-hours (default: local)")
 	commandTimeoutSec := fs.Int("command-timeout-sec", envIntDefault("RALPH_TELEGRAM_COMMAND_TIMEOUT_SEC", cfg.CommandTimeoutSec), "timeout seconds per telegram command")
 	commandConcurrency := fs.Int("command-concurrency", envIntDefault("RALPH_TELEGRAM_COMMAND_CONCURRENCY", cfg.CommandConcurrency), "max concurrent command workers across chats")
+	commandRatePerMin := fs.Int("command-rate-per-min", envIntDefault("RALPH_TELEGRAM_COMMAND_RATE_PER_MIN", 0), "max commands per minute per chat (0=unlimited)")
+	lang := fs.String("lang", firstNonEmpty(strings.TrimSpace(os.Getenv(telegramLangEnv)), cfg.Lang), "PRD wizard/codex reply language: ko|en")
+	codeBlocks := fs.Bool("code-blocks", envBoolDefault(telegramCodeBlocksEnv, cfg.CodeBlocks), "send /status and /doctor replies as MarkdownV2 code blocks")
 	rebindBot := fs.Bool("rebind-bot", false, "rebind this bot token to current project (1 bot = 1 project policy)")
 	pollTimeoutSec := fs.Int("poll-timeout-sec", 30, "telegram getUpdates timeout (seconds)")
 	offsetFile := fs.String("offset-file", defaultTelegramOffsetFile(controlDir, paths.ProjectDir), "telegram update offset file")
@@ -82,6 +94,10 @@ func runTelegramRunCommand(controlDir string, paths ralph.Paths, args []string)
 		return err
 	}
 	configFile = strings.TrimSpace(*configFileFlag)
+	setFlags := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = f.Value.String()
+	})
 
 	if strings.TrimSpace(*token) == "" {
 		return fmt.Errorf("--token is required (or run `ralphctl telegram setup`)")
@@ -118,11 +134,29 @@ func runTelegramRunCommand(controlDir string, paths ralph.Paths, args []string)
 	if *commandConcurrency <= 0 {
 		return fmt.Errorf("--command-concurrency must be > 0")
 	}
+	if *commandRatePerMin < 0 {
+		return fmt.Errorf("--command-rate-per-min must be >= 0")
+	}
 	resolvedNotifyScope, err := normalizeNotifyScope(*notifyScope)
 	if err != nil {
 		return fmt.Errorf("invalid --notify-scope: %w", err)
 	}
+	resolvedNotifyMinSeverity, err := normalizeNotifySeverity(*notifyMinSeverity)
+	if err != nil {
+		return fmt.Errorf("invalid --notify-min-severity: %w", err)
+	}
+	quietHours, err := parseNotifyQuietHours(*notifyQuietHours, *notifyQuietHoursTZ)
+	if err != nil {
+		return fmt.Errorf("invalid --notify-quiet-hours: %w", err)
+	}
+	resolvedLang, err := normalizeTelegramLang(*lang)
+	if err != nil {
+		return fmt.Errorf("invalid --lang: %w", err)
+	}
 	if !*foreground {
+		if err := checkTelegramTokenAvailable(controlDir, *token, paths.ProjectDir); err != nil {
+			return err
+		}
 		msg, err := startTelegramDaemon(paths, ensureTelegramForegroundArg(args))
 		if err != nil {
 			return err
@@ -133,7 +167,7 @@ func runTelegramRunCommand(controlDir string, paths ralph.Paths, args []string)
 		fmt.Println()
 		fmt.Printf("Control Dir: %s\n", controlDir)
 		fmt.Printf("Project Dir: %s\n", paths.ProjectDir)
-		fmt.Printf("Config:      %s\n", configFile)
+		fmt.Printf("Config:      %s\n", describeTelegramConfigSource(cfg, configFile))
 		fmt.Printf("PID File:    %s\n", paths.TelegramPIDFile())
 		fmt.Printf("Log File:    %s\n", paths.TelegramLogFile())
 		fmt.Println("Mode:        daemon")
@@ -145,21 +179,39 @@ func runTelegramRunCommand(controlDir string, paths ralph.Paths, args []string)
 		return nil
 	}
 
+	// Handlers read the language from the env, like --plugin-dir does for plugins.
+	if err := os.Setenv(telegramLangEnv, resolvedLang); err != nil {
+		return fmt.Errorf("set %s: %w", telegramLangEnv, err)
+	}
+
+	releaseTokenLock, err := acquireTelegramTokenRuntimeLock(controlDir, *token, paths.ProjectDir)
+	if err != nil {
+		return err
+	}
+	defer releaseTokenLock()
+
 	fmt.Println("Telegram Bot")
 	fmt.Println("============")
 	fmt.Println("Started in foreground mode")
 	fmt.Println()
 	fmt.Printf("Control Dir:   %s\n", controlDir)
 	fmt.Printf("Project Dir:   %s\n", paths.ProjectDir)
-	fmt.Printf("Config:        %s\n", configFile)
+	fmt.Printf("Config:        %s\n", describeTelegramConfigSource(cfg, configFile))
 	fmt.Printf("Allow Control: %t\n", *allowControl)
 	fmt.Printf("Notify:        %t\n", *enableNotify)
 	fmt.Printf("Notify Scope:  %s\n", resolvedNotifyScope)
+	fmt.Printf("Notify Level:  %s+\n", resolvedNotifyMinSeverity)
+	fmt.Printf("Quiet Hours:   %s\n", quietHours.String())
+	fmt.Printf("Language:      %s\n", resolvedLang)
+	fmt.Printf("Code Blocks:   %t\n", *codeBlocks)
 	fmt.Printf("Notify Every:  %ds\n", *notifyIntervalSec)
 	fmt.Printf("Retry Alert:   %d\n", *notifyRetryThreshold)
 	fmt.Printf("Perm Alert:    %d\n", *notifyPermStreakThreshold)
 	fmt.Printf("Cmd Timeout:   %ds\n", *commandTimeoutSec)
 	fmt.Printf("Cmd Workers:   %d\n", *commandConcurrency)
+	if *commandRatePerMin > 0 {
+		fmt.Printf("Cmd Rate:      %d/min per chat\n", *commandRatePerMin)
+	}
 	fmt.Printf("Allowed Chats: %d\n", len(allowedChatIDs))
 	if len(allowedUserIDs) > 0 {
 		fmt.Printf("Allowed Users: %d\n", len(allowedUserIDs))
@@ -167,15 +219,24 @@ func runTelegramRunCommand(controlDir string, paths ralph.Paths, args []string)
 		fmt.Printf("Allowed Users: any (chat allowlist only)\n")
 	}
 	fmt.Printf("Offset File:   %s\n", *offsetFile)
+	fmt.Printf("Reload:        kill -HUP %d (re-reads allowlist; flags/env still win)\n", os.Getpid())
 
 	notifyHandler := ralph.TelegramNotifyHandler(nil)
 	if *enableNotify {
 		notifyHandler = newScopedStatusNotifyHandler(controlDir, paths, resolvedNotifyScope, *notifyRetryThreshold, *notifyPermStreakThreshold)
+		notifyHandler = withNotifySeverityFilter(notifyHandler, resolvedNotifyMinSeverity)
+		notifyHandler = withNotifyQuietHours(notifyHandler, quietHours)
 	}
 
+	control := &atomic.Bool{}
+	control.Store(*allowControl)
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+	defer signal.Stop(reloadCh)
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
-	return ralph.RunTelegramBot(ctx, ralph.TelegramBotOptions{
+	err = ralph.RunTelegramBot(ctx, ralph.TelegramBotOptions{
 		Token:              *token,
 		AllowedChatIDs:     allowedChatIDs,
 		AllowedUserIDs:     allowedUserIDs,
@@ -183,11 +244,19 @@ func runTelegramRunCommand(controlDir string, paths ralph.Paths, args []string)
 		NotifyIntervalSec:  *notifyIntervalSec,
 		CommandTimeoutSec:  *commandTimeoutSec,
 		CommandConcurrency: *commandConcurrency,
+		CommandRatePerMin:  *commandRatePerMin,
 		OffsetFile:         *offsetFile,
 		Out:                os.Stdout,
-		OnCommand:          telegramCommandHandler(controlDir, paths, *allowControl),
+		CodeBlocks:         *codeBlocks,
+		OnCommand:          reloadableTelegramCommandHandler(controlDir, paths, control),
 		OnNotifyTick:       notifyHandler,
+		Reload:             reloadCh,
+		OnReload:           telegramConfigReloadHandler(configFile, setFlags, control),
 	})
+	if err != nil {
+		return errors.New(ralph.RedactTelegramToken(err.Error(), *token))
+	}
+	return nil
 }
 
 func runTelegramStopCommand(paths ralph.Paths, args []string) error {
@@ -245,8 +314,115 @@ func runTelegramTailCommand(paths ralph.Paths, args []string) error {
 	return tailFile(paths.TelegramLogFile(), *lines, *follow)
 }
 
-func runTelegramSetupCommand(controlDir string, args []string) error {
-	configFile := telegramConfigFileFromArgs(controlDir, args)
+func runTelegramBroadcastCommand(paths ralph.Paths, args []string) error {
+	configFile := telegramConfigFileFromArgs(paths, args)
+	cfg, err := loadTelegramCLIConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("telegram broadcast", flag.ContinueOnError)
+	fs.String("config-file", configFile, "telegram config file path")
+	token := fs.String("token", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_BOT_TOKEN")), cfg.Token), "telegram bot token")
+	chatIDsRaw := fs.String("chat-ids", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_CHAT_IDS")), cfg.ChatIDs), "target chat IDs CSV (default: allowed chats from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	message := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if message == "" {
+		return fmt.Errorf("usage: telegram broadcast [--chat-ids CSV] <message>")
+	}
+	if strings.TrimSpace(*token) == "" {
+		return fmt.Errorf("--token is required (or run `ralphctl telegram setup`)")
+	}
+	chatIDs, err := ralph.ParseTelegramChatIDs(*chatIDsRaw)
+	if err != nil {
+		return err
+	}
+	if len(chatIDs) == 0 {
+		return fmt.Errorf("--chat-ids is required (or run `ralphctl telegram setup`)")
+	}
+
+	results, err := ralph.TelegramBroadcast(context.Background(), ralph.TelegramBroadcastOptions{
+		Token:   *token,
+		ChatIDs: chatIDs,
+		Text:    message,
+	})
+	if err != nil {
+		return err
+	}
+	failed := 0
+	fmt.Println("telegram broadcast")
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			fmt.Printf("- chat=%d: failed (%v)\n", result.ChatID, result.Err)
+			continue
+		}
+		fmt.Printf("- chat=%d: sent\n", result.ChatID)
+	}
+	fmt.Printf("- sent: %d\n", len(results)-failed)
+	fmt.Printf("- failed: %d\n", failed)
+	if failed > 0 {
+		return fmt.Errorf("telegram broadcast failed for %d/%d chats", failed, len(results))
+	}
+	return nil
+}
+
+func runTelegramTestCommand(paths ralph.Paths, args []string) error {
+	configFile := telegramConfigFileFromArgs(paths, args)
+	cfg, err := loadTelegramCLIConfig(configFile)
+	if err != nil {
+		return err
+	}
+
+	fs := flag.NewFlagSet("telegram test", flag.ContinueOnError)
+	fs.String("config-file", configFile, "telegram config file path")
+	token := fs.String("token", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_BOT_TOKEN")), cfg.Token), "telegram bot token")
+	chatIDsRaw := fs.String("chat-ids", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_CHAT_IDS")), cfg.ChatIDs), "target chat IDs CSV (default: allowed chats from config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*token) == "" {
+		return fmt.Errorf("--token is required (or run `ralphctl telegram setup`)")
+	}
+	chatIDs, err := ralph.ParseTelegramChatIDs(*chatIDsRaw)
+	if err != nil {
+		return err
+	}
+	if len(chatIDs) == 0 {
+		return fmt.Errorf("--chat-ids is required (or run `ralphctl telegram setup`)")
+	}
+
+	fmt.Println("telegram test")
+	res, err := ralph.TelegramTest(context.Background(), ralph.TelegramBroadcastOptions{
+		Token:   *token,
+		ChatIDs: chatIDs,
+	})
+	if err != nil {
+		fmt.Printf("- token: failed (%v)\n", err)
+		return fmt.Errorf("telegram token check failed: %w", err)
+	}
+	fmt.Printf("- token: ok (bot=@%s id=%d)\n", valueOrDash(res.Bot.Username), res.Bot.ID)
+	failed := 0
+	for _, result := range res.Chats {
+		if result.Err != nil {
+			failed++
+			fmt.Printf("- chat=%d: failed (%v)\n", result.ChatID, result.Err)
+			continue
+		}
+		fmt.Printf("- chat=%d: delivered\n", result.ChatID)
+	}
+	fmt.Printf("- delivered: %d\n", len(res.Chats)-failed)
+	fmt.Printf("- failed: %d\n", failed)
+	if failed > 0 {
+		return fmt.Errorf("telegram test delivery failed for %d/%d chats", failed, len(res.Chats))
+	}
+	return nil
+}
+
+func runTelegramSetupCommand(controlDir string, paths ralph.Paths, args []string) error {
+	configFile := telegramConfigFileFromArgs(paths, args)
 	cfg, err := loadTelegramCLIConfig(configFile)
 	if err != nil {
 		return err
@@ -264,8 +440,13 @@ func runTelegramSetupCommand(controlDir string, args []string) error {
 	defaultNotifyInterval := envIntDefault("RALPH_TELEGRAM_NOTIFY_INTERVAL_SEC", cfg.NotifyIntervalSec)
 	defaultNotifyRetry := envIntDefault("RALPH_TELEGRAM_NOTIFY_RETRY_THRESHOLD", cfg.NotifyRetryThreshold)
 	defaultNotifyPerm := envIntDefault("RALPH_TELEGRAM_NOTIFY_PERM_STREAK_THRESHOLD", cfg.NotifyPermStreakThreshold)
+	defaultNotifyMinSeverity := firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_NOTIFY_MIN_SEVERITY")), cfg.NotifyMinSeverity)
+	defaultNotifyQuietHours := firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_QUIET_HOURS")), cfg.NotifyQuietHours)
+	defaultNotifyQuietHoursTZ := firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_QUIET_HOURS_TZ")), cfg.NotifyQuietHoursTZ)
 	defaultCommandTimeout := envIntDefault("RALPH_TELEGRAM_COMMAND_TIMEOUT_SEC", cfg.CommandTimeoutSec)
 	defaultCommandConcurrency := envIntDefault("RALPH_TELEGRAM_COMMAND_CONCURRENCY", cfg.CommandConcurrency)
+	defaultLang := firstNonEmpty(strings.TrimSpace(os.Getenv(telegramLangEnv)), cfg.Lang)
+	defaultCodeBlocks := envBoolDefault(telegramCodeBlocksEnv, cfg.CodeBlocks)
 
 	fs := flag.NewFlagSet("telegram setup", flag.ContinueOnError)
 	configFileFlag := fs.String("config-file", configFile, "telegram config file path")
@@ -279,8 +460,13 @@ func runTelegramSetupCommand(controlDir string, args []string) error {
 	notifyIntervalFlag := fs.Int("notify-interval-sec", defaultNotifyInterval, "notify interval seconds")
 	notifyRetryFlag := fs.Int("notify-retry-threshold", defaultNotifyRetry, "notify retry threshold")
 	notifyPermFlag := fs.Int("notify-perm-streak-threshold", defaultNotifyPerm, "notify permission streak threshold")
+	notifyMinSeverityFlag := fs.String("notify-min-severity", defaultNotifyMinSeverity, "minimum alert severity to push: info|warn|critical")
+	notifyQuietHoursFlag := fs.String("notify-quiet-hours", defaultNotifyQuietHours, "HH:MM-HH:MM window that holds back info/warn alerts (empty=off)")
+	notifyQuietHoursTZFlag := fs.String("notify-quiet-hours-tz", defaultNotifyQuietHoursTZ, "IANA timezone for quiet hours (default: local)")
 	commandTimeoutFlag := fs.Int("command-timeout-sec", defaultCommandTimeout, "timeout seconds per telegram command")
 	commandConcurrencyFlag := fs.Int("command-concurrency", defaultCommandConcurrency, "max concurrent command workers across chats")
+	langFlag := fs.String("lang", defaultLang, "PRD wizard/codex reply language: ko|en")
+	codeBlocksFlag := fs.Bool("code-blocks", defaultCodeBlocks, "send /status and /doctor replies as MarkdownV2 code blocks")
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
@@ -295,8 +481,13 @@ func runTelegramSetupCommand(controlDir string, args []string) error {
 		NotifyIntervalSec:         *notifyIntervalFlag,
 		NotifyRetryThreshold:      *notifyRetryFlag,
 		NotifyPermStreakThreshold: *notifyPermFlag,
+		NotifyMinSeverity:         strings.TrimSpace(*notifyMinSeverityFlag),
+		NotifyQuietHours:          strings.TrimSpace(*notifyQuietHoursFlag),
+		NotifyQuietHoursTZ:        strings.TrimSpace(*notifyQuietHoursTZFlag),
 		CommandTimeoutSec:         *commandTimeoutFlag,
 		CommandConcurrency:        *commandConcurrencyFlag,
+		Lang:                      strings.TrimSpace(*langFlag),
+		CodeBlocks:                *codeBlocksFlag,
 	}
 	configFile = strings.TrimSpace(*configFileFlag)
 
@@ -366,6 +557,25 @@ func runTelegramSetupCommand(controlDir string, args []string) error {
 			final.NotifyPermStreakThreshold = v
 		}
 
+		severityInput, err := promptFleetChoice(reader, "Notify min severity", []string{"info", "warn", "critical"}, firstNonEmpty(final.NotifyMinSeverity, "info"))
+		if err != nil {
+			return err
+		}
+		final.NotifyMinSeverity = strings.TrimSpace(severityInput)
+
+		quietInput, err := promptFleetInput(reader, "Quiet hours HH:MM-HH:MM (empty=off)", final.NotifyQuietHours)
+		if err != nil {
+			return err
+		}
+		final.NotifyQuietHours = strings.TrimSpace(quietInput)
+		if final.NotifyQuietHours != "" {
+			tzInput, err := promptFleetInput(reader, "Quiet hours timezone (empty=local)", final.NotifyQuietHoursTZ)
+			if err != nil {
+				return err
+			}
+			final.NotifyQuietHoursTZ = strings.TrimSpace(tzInput)
+		}
+
 		timeoutInput, err := promptFleetInput(reader, "Command timeout sec", strconv.Itoa(final.CommandTimeoutSec))
 		if err != nil {
 			return err
@@ -383,58 +593,132 @@ func runTelegramSetupCommand(controlDir string, args []string) error {
 		}
 	}
 
+	final, quietHours, err := finalizeTelegramCLIConfig(final)
+	if err != nil {
+		return err
+	}
+	if err := saveTelegramCLIConfig(configFile, final); err != nil {
+		return err
+	}
+
+	fmt.Println("Telegram Setup Complete")
+	fmt.Println("======================")
+	fmt.Printf("Config:        %s\n", configFile)
+	fmt.Printf("Allow Control: %t\n", final.AllowControl)
+	fmt.Printf("Notify:        %t\n", final.Notify)
+	fmt.Printf("Notify Scope:  %s\n", final.NotifyScope)
+	fmt.Printf("Quiet Hours:   %s\n", quietHours.String())
+	fmt.Printf("Language:      %s\n", final.Lang)
+	fmt.Printf("Code Blocks:   %t\n", final.CodeBlocks)
+	fmt.Printf("Cmd Timeout:   %ds\n", final.CommandTimeoutSec)
+	fmt.Printf("Cmd Workers:   %d\n", final.CommandConcurrency)
+	fmt.Println()
+	fmt.Println("Next Commands")
+	fmt.Printf("- run:    ralphctl --project-dir \"$PWD\" telegram run --config-file %s\n", configFile)
+	fmt.Printf("- status: ralphctl --project-dir \"$PWD\" telegram status\n")
+	fmt.Printf("- stop:   ralphctl --project-dir \"$PWD\" telegram stop\n")
+	return nil
+}
+
+// telegramSetupWizardStep is the inline telegram step of `setup --advanced`. It
+// asks only for the bot credentials and notify toggle, keeps every other value
+// from the existing config, and saves it the same way `telegram setup` does.
+func telegramSetupWizardStep(paths ralph.Paths) ralph.SetupTelegramStep {
+	return func(reader *bufio.Reader, out io.Writer) error {
+		configFile := telegramConfigFileFromArgs(paths, nil)
+		cfg, err := loadTelegramCLIConfig(configFile)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, "## Telegram Setup")
+		fmt.Fprintf(out, "- config_file: %s\n", configFile)
+
+		token, err := promptFleetInput(reader, "Bot token", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_BOT_TOKEN")), cfg.Token))
+		if err != nil {
+			return err
+		}
+		cfg.Token = strings.TrimSpace(token)
+		chatIDs, err := promptFleetInput(reader, "Allowed chat IDs (CSV)", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_CHAT_IDS")), cfg.ChatIDs))
+		if err != nil {
+			return err
+		}
+		cfg.ChatIDs = strings.TrimSpace(chatIDs)
+		userIDs, err := promptFleetInput(reader, "Allowed user IDs (CSV, optional)", firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_USER_IDS")), cfg.UserIDs))
+		if err != nil {
+			return err
+		}
+		cfg.UserIDs = strings.TrimSpace(userIDs)
+		notify, err := promptFleetBool(reader, "Enable notify alerts?", cfg.Notify)
+		if err != nil {
+			return err
+		}
+		cfg.Notify = notify
+
+		cfg, _, err = finalizeTelegramCLIConfig(cfg)
+		if err != nil {
+			return err
+		}
+		if err := saveTelegramCLIConfig(configFile, cfg); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "- telegram: configured (%s)\n", configFile)
+		fmt.Fprintf(out, "- telegram run: ralphctl --project-dir \"$PWD\" telegram run --config-file %s\n", configFile)
+		return nil
+	}
+}
+
+// finalizeTelegramCLIConfig checks the settings `telegram setup` is about to save
+// and normalizes scope, severity and language.
+func finalizeTelegramCLIConfig(final telegramCLIConfig) (telegramCLIConfig, notifyQuietHours, error) {
 	if strings.TrimSpace(final.Token) == "" {
-		return fmt.Errorf("token is required")
+		return final, notifyQuietHours{}, fmt.Errorf("token is required")
 	}
 	if strings.TrimSpace(final.ChatIDs) == "" {
-		return fmt.Errorf("chat-ids is required")
+		return final, notifyQuietHours{}, fmt.Errorf("chat-ids is required")
 	}
 	allowedChatIDs, err := ralph.ParseTelegramChatIDs(final.ChatIDs)
 	if err != nil {
-		return err
+		return final, notifyQuietHours{}, err
 	}
 	allowedUserIDs := map[int64]struct{}{}
 	if strings.TrimSpace(final.UserIDs) != "" {
 		allowedUserIDs, err = ralph.ParseTelegramUserIDs(final.UserIDs)
 		if err != nil {
-			return err
+			return final, notifyQuietHours{}, err
 		}
 	}
 	if final.AllowControl && len(allowedUserIDs) == 0 && requiresUserAllowlistForControl(allowedChatIDs) {
-		return fmt.Errorf("allow-control with group/supergroup chat requires user-ids")
+		return final, notifyQuietHours{}, fmt.Errorf("allow-control with group/supergroup chat requires user-ids")
 	}
 	if final.NotifyIntervalSec <= 0 {
-		return fmt.Errorf("notify-interval-sec must be > 0")
+		return final, notifyQuietHours{}, fmt.Errorf("notify-interval-sec must be > 0")
 	}
 	if final.CommandTimeoutSec <= 0 {
-		return fmt.Errorf("command-timeout-sec must be > 0")
+		return final, notifyQuietHours{}, fmt.Errorf("command-timeout-sec must be > 0")
 	}
 	if final.CommandConcurrency <= 0 {
-		return fmt.Errorf("command-concurrency must be > 0")
+		return final, notifyQuietHours{}, fmt.Errorf("command-concurrency must be > 0")
 	}
 	scope, err := normalizeNotifyScope(final.NotifyScope)
 	if err != nil {
-		return fmt.Errorf("notify-scope: %w", err)
+		return final, notifyQuietHours{}, fmt.Errorf("notify-scope: %w", err)
 	}
 	final.NotifyScope = scope
-	if err := saveTelegramCLIConfig(configFile, final); err != nil {
-		return err
+	minSeverity, err := normalizeNotifySeverity(final.NotifyMinSeverity)
+	if err != nil {
+		return final, notifyQuietHours{}, fmt.Errorf("notify-min-severity: %w", err)
 	}
-
-	fmt.Println("Telegram Setup Complete")
-	fmt.Println("======================")
-	fmt.Printf("Config:        %s\n", configFile)
-	fmt.Printf("Allow Control: %t\n", final.AllowControl)
-	fmt.Printf("Notify:        %t\n", final.Notify)
-	fmt.Printf("Notify Scope:  %s\n", final.NotifyScope)
-	fmt.Printf("Cmd Timeout:   %ds\n", final.CommandTimeoutSec)
-	fmt.Printf("Cmd Workers:   %d\n", final.CommandConcurrency)
-	fmt.Println()
-	fmt.Println("Next Commands")
-	fmt.Printf("- run:    ralphctl --project-dir \"$PWD\" telegram run --config-file %s\n", configFile)
-	fmt.Printf("- status: ralphctl --project-dir \"$PWD\" telegram status\n")
-	fmt.Printf("- stop:   ralphctl --project-dir \"$PWD\" telegram stop\n")
-	return nil
+	final.NotifyMinSeverity = minSeverity
+	quietHours, err := parseNotifyQuietHours(final.NotifyQuietHours, final.NotifyQuietHoursTZ)
+	if err != nil {
+		return final, notifyQuietHours{}, fmt.Errorf("notify-quiet-hours: %w", err)
+	}
+	lang, err := normalizeTelegramLang(final.Lang)
+	if err != nil {
+		return final, notifyQuietHours{}, fmt.Errorf("lang: %w", err)
+	}
+	final.Lang = lang
+	return final, quietHours, nil
 }
 
 type telegramCLIConfig struct {
@@ -447,8 +731,15 @@ type telegramCLIConfig struct {
 	NotifyIntervalSec         int
 	NotifyRetryThreshold      int
 	NotifyPermStreakThreshold int
+	NotifyMinSeverity         string
+	NotifyQuietHours          string
+	NotifyQuietHoursTZ        string
 	CommandTimeoutSec         int
 	CommandConcurrency        int
+	Lang                      string
+	CodeBlocks                bool
+	// Source is the file the values were read from; empty means defaults only.
+	Source string
 }
 
 func defaultTelegramCLIConfig() telegramCLIConfig {
@@ -459,13 +750,20 @@ func defaultTelegramCLIConfig() telegramCLIConfig {
 		NotifyIntervalSec:         30,
 		NotifyRetryThreshold:      2,
 		NotifyPermStreakThreshold: 3,
+		NotifyMinSeverity:         telegramAlertSeverityInfo,
 		CommandTimeoutSec:         900,
 		CommandConcurrency:        4,
+		Lang:                      telegramLangKO,
 	}
 }
 
-func telegramConfigFileFromArgs(controlDir string, args []string) string {
-	defaultPath := filepath.Join(controlDir, "telegram.env")
+const telegramConfigFileName = "telegram.env"
+
+// telegramConfigFileFromArgs resolves the config file: an explicit --config-file,
+// then the project's .ralph/telegram.env (a per-project bot), then the shared
+// control dir file. The control dir path is used when neither exists, so setup
+// writes there unless the project opts in.
+func telegramConfigFileFromArgs(paths ralph.Paths, args []string) string {
 	for i := 0; i < len(args); i++ {
 		raw := strings.TrimSpace(args[i])
 		if strings.HasPrefix(raw, "--config-file=") {
@@ -482,7 +780,20 @@ func telegramConfigFileFromArgs(controlDir string, args []string) string {
 			}
 		}
 	}
-	return defaultPath
+	if paths.RalphDir != "" {
+		projectPath := filepath.Join(paths.RalphDir, telegramConfigFileName)
+		if _, err := os.Stat(projectPath); err == nil {
+			return projectPath
+		}
+	}
+	return filepath.Join(paths.ControlDir, telegramConfigFileName)
+}
+
+func describeTelegramConfigSource(cfg telegramCLIConfig, configFile string) string {
+	if cfg.Source == "" {
+		return configFile + " (not found; defaults)"
+	}
+	return cfg.Source
 }
 
 func loadTelegramCLIConfig(path string) (telegramCLIConfig, error) {
@@ -498,6 +809,7 @@ func loadTelegramCLIConfig(path string) (telegramCLIConfig, error) {
 		}
 		return cfg, fmt.Errorf("read telegram config: %w", err)
 	}
+	cfg.Source = path
 	if v := strings.TrimSpace(values["RALPH_TELEGRAM_BOT_TOKEN"]); v != "" {
 		cfg.Token = v
 	}
@@ -525,12 +837,27 @@ func loadTelegramCLIConfig(path string) (telegramCLIConfig, error) {
 	if v, ok := parseIntRaw(values["RALPH_TELEGRAM_NOTIFY_PERM_STREAK_THRESHOLD"]); ok {
 		cfg.NotifyPermStreakThreshold = v
 	}
+	if v := strings.TrimSpace(values["RALPH_TELEGRAM_NOTIFY_MIN_SEVERITY"]); v != "" {
+		cfg.NotifyMinSeverity = v
+	}
+	if v := strings.TrimSpace(values["RALPH_TELEGRAM_QUIET_HOURS"]); v != "" {
+		cfg.NotifyQuietHours = v
+	}
+	if v := strings.TrimSpace(values["RALPH_TELEGRAM_QUIET_HOURS_TZ"]); v != "" {
+		cfg.NotifyQuietHoursTZ = v
+	}
 	if v, ok := parseIntRaw(values["RALPH_TELEGRAM_COMMAND_TIMEOUT_SEC"]); ok {
 		cfg.CommandTimeoutSec = v
 	}
 	if v, ok := parseIntRaw(values["RALPH_TELEGRAM_COMMAND_CONCURRENCY"]); ok {
 		cfg.CommandConcurrency = v
 	}
+	if v := strings.TrimSpace(values[telegramLangEnv]); v != "" {
+		cfg.Lang = v
+	}
+	if v, ok := parseBoolRaw(values[telegramCodeBlocksEnv]); ok {
+		cfg.CodeBlocks = v
+	}
 	return cfg, nil
 }
 
@@ -553,8 +880,13 @@ func saveTelegramCLIConfig(path string, cfg telegramCLIConfig) error {
 	b.WriteString("RALPH_TELEGRAM_NOTIFY_INTERVAL_SEC=" + strconv.Itoa(cfg.NotifyIntervalSec) + "\n")
 	b.WriteString("RALPH_TELEGRAM_NOTIFY_RETRY_THRESHOLD=" + strconv.Itoa(cfg.NotifyRetryThreshold) + "\n")
 	b.WriteString("RALPH_TELEGRAM_NOTIFY_PERM_STREAK_THRESHOLD=" + strconv.Itoa(cfg.NotifyPermStreakThreshold) + "\n")
+	b.WriteString("RALPH_TELEGRAM_NOTIFY_MIN_SEVERITY=" + firstNonEmpty(cfg.NotifyMinSeverity, telegramAlertSeverityInfo) + "\n")
+	b.WriteString("RALPH_TELEGRAM_QUIET_HOURS=" + envQuoteValue(cfg.NotifyQuietHours) + "\n")
+	b.WriteString("RALPH_TELEGRAM_QUIET_HOURS_TZ=" + envQuoteValue(cfg.NotifyQuietHoursTZ) + "\n")
 	b.WriteString("RALPH_TELEGRAM_COMMAND_TIMEOUT_SEC=" + strconv.Itoa(cfg.CommandTimeoutSec) + "\n")
 	b.WriteString("RALPH_TELEGRAM_COMMAND_CONCURRENCY=" + strconv.Itoa(cfg.CommandConcurrency) + "\n")
+	b.WriteString(telegramLangEnv + "=" + firstNonEmpty(cfg.Lang, telegramLangKO) + "\n")
+	b.WriteString(telegramCodeBlocksEnv + "=" + strconv.FormatBool(cfg.CodeBlocks) + "\n")
 	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
 		return err
 	}
@@ -564,29 +896,90 @@ func saveTelegramCLIConfig(path string, cfg telegramCLIConfig) error {
 	return nil
 }
 
+// telegramConfigReloadHandler re-reads the allowlist and allow-control on SIGHUP
+// with the same precedence as `telegram run`: flags set at startup (setFlags, by
+// flag name) win over env, and env wins over the config file.
+func telegramConfigReloadHandler(configFile string, setFlags map[string]string, allowControl *atomic.Bool) ralph.TelegramReloadHandler {
+	return func() (ralph.TelegramAllowlist, error) {
+		cfg, err := loadTelegramCLIConfig(configFile)
+		if err != nil {
+			return ralph.TelegramAllowlist{}, err
+		}
+		chatIDsRaw := telegramReloadValue(setFlags, "chat-ids", "RALPH_TELEGRAM_CHAT_IDS", cfg.ChatIDs)
+		userIDsRaw := telegramReloadValue(setFlags, "user-ids", "RALPH_TELEGRAM_USER_IDS", cfg.UserIDs)
+		control := envBoolDefault("RALPH_TELEGRAM_ALLOW_CONTROL", cfg.AllowControl)
+		if raw, ok := setFlags["allow-control"]; ok {
+			control, err = strconv.ParseBool(raw)
+			if err != nil {
+				return ralph.TelegramAllowlist{}, fmt.Errorf("invalid --allow-control %q: %w", raw, err)
+			}
+		}
+
+		chatIDs, err := ralph.ParseTelegramChatIDs(chatIDsRaw)
+		if err != nil {
+			return ralph.TelegramAllowlist{}, err
+		}
+		if len(chatIDs) == 0 {
+			return ralph.TelegramAllowlist{}, fmt.Errorf("chat-ids is required in %s", configFile)
+		}
+		userIDs := map[int64]struct{}{}
+		if strings.TrimSpace(userIDsRaw) != "" {
+			userIDs, err = ralph.ParseTelegramUserIDs(userIDsRaw)
+			if err != nil {
+				return ralph.TelegramAllowlist{}, err
+			}
+		}
+		if control && len(userIDs) == 0 && requiresUserAllowlistForControl(chatIDs) {
+			return ralph.TelegramAllowlist{}, fmt.Errorf("allow-control with group/supergroup chat requires user-ids")
+		}
+		allowControl.Store(control)
+		return ralph.TelegramAllowlist{ChatIDs: chatIDs, UserIDs: userIDs}, nil
+	}
+}
+
+// telegramReloadValue resolves one `telegram run` string setting: a flag set at
+// startup, then a non-empty env value, then the config file value.
+func telegramReloadValue(setFlags map[string]string, flagName, envKey, fileValue string) string {
+	if v, ok := setFlags[flagName]; ok {
+		return v
+	}
+	return firstNonEmpty(strings.TrimSpace(os.Getenv(envKey)), fileValue)
+}
+
 func telegramCommandHandler(controlDir string, paths ralph.Paths, allowControl bool) ralph.TelegramCommandHandler {
-	return func(ctx context.Context, chatID int64, text string) (string, error) {
+	control := &atomic.Bool{}
+	control.Store(allowControl)
+	return reloadableTelegramCommandHandler(controlDir, paths, control)
+}
+
+func reloadableTelegramCommandHandler(controlDir string, paths ralph.Paths, control *atomic.Bool) ralph.TelegramCommandHandler {
+	return func(ctx context.Context, chatID int64, text string) (ralph.TelegramReply, error) {
 		_ = ctx
 		text = strings.TrimSpace(text)
 		if text == "" {
-			return "", nil
+			return ralph.TelegramReply{}, nil
 		}
+		allowControl := control.Load()
 
 		if strings.HasPrefix(text, "/") {
 			cmd, cmdArgs := parseTelegramCommandLine(text)
-			return dispatchTelegramCommand(controlDir, paths, allowControl, chatID, cmd, cmdArgs)
+			reply, err := dispatchTelegramCommand(controlDir, paths, allowControl, chatID, cmd, cmdArgs)
+			_, pre := telegramStructuredCommands[cmd]
+			return ralph.TelegramReply{Text: reply, Pre: pre}, err
 		}
 
 		if allowControl {
 			hasSession, err := telegramHasActivePRDSession(paths, chatID)
 			if err != nil {
-				return "", err
+				return ralph.TelegramReply{}, err
 			}
 			if hasSession {
-				return telegramPRDHandleInput(paths, chatID, text)
+				reply, err := telegramPRDHandleInput(paths, chatID, text)
+				return ralph.TelegramReply{Text: reply}, err
 			}
 		}
-		return telegramChatConversationInput(paths, chatID, text)
+		reply, err := telegramChatConversationInput(paths, chatID, text)
+		return ralph.TelegramReply{Text: reply}, err
 	}
 }
 
@@ -604,6 +997,9 @@ func dispatchTelegramCommand(controlDir string, paths ralph.Paths, allowControl
 	case "/fleet", "/fleet_status", "/dashboard":
 		return telegramFleetDashboardCommand(controlDir, cmdArgs)
 
+	case "/queue":
+		return telegramQueueCommand(controlDir, paths, cmdArgs)
+
 	case "/doctor":
 		return telegramDoctorCommand(controlDir, paths, cmdArgs)
 
@@ -764,6 +1160,89 @@ func telegramFleetDashboardCommand(controlDir, rawArgs string) (string, error) {
 	return b.String(), nil
 }
 
+const (
+	telegramQueueDefaultLimit = 10
+	telegramQueueMaxLimit     = 50
+)
+
+// parseTelegramQueueArgs accepts "[N] [all|<project_id>]" in either order.
+func parseTelegramQueueArgs(rawArgs string) (int, telegramTargetSpec, error) {
+	limit := telegramQueueDefaultLimit
+	targetArgs := []string{}
+	for _, field := range strings.Fields(rawArgs) {
+		if n, err := strconv.Atoi(field); err == nil {
+			if n <= 0 {
+				return 0, telegramTargetSpec{}, fmt.Errorf("queue size must be > 0")
+			}
+			limit = n
+			continue
+		}
+		targetArgs = append(targetArgs, field)
+	}
+	if limit > telegramQueueMaxLimit {
+		limit = telegramQueueMaxLimit
+	}
+	spec, err := parseTelegramTargetSpec(strings.Join(targetArgs, " "))
+	if err != nil {
+		return 0, telegramTargetSpec{}, err
+	}
+	return limit, spec, nil
+}
+
+func telegramQueueCommand(controlDir string, paths ralph.Paths, rawArgs string) (string, error) {
+	limit, spec, err := parseTelegramQueueArgs(rawArgs)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	fmt.Fprintln(&b, "Ralph Ready Queue")
+	fmt.Fprintln(&b, "=================")
+	if !spec.HasTarget() {
+		if err := writeTelegramReadyQueue(&b, paths.ProjectDir, paths, limit); err != nil {
+			return "", err
+		}
+		return strings.TrimRight(b.String(), "\n"), nil
+	}
+	projects, pathsByID, err := resolveTelegramFleetPaths(controlDir, spec)
+	if err != nil {
+		return "", err
+	}
+	for i, p := range projects {
+		if i > 0 {
+			fmt.Fprintln(&b)
+		}
+		if err := writeTelegramReadyQueue(&b, fmt.Sprintf("%s (%s)", p.ID, p.ProjectDir), pathsByID[p.ID], limit); err != nil {
+			return "", fmt.Errorf("project=%s: %w", p.ID, err)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+func writeTelegramReadyQueue(w io.Writer, label string, paths ralph.Paths, limit int) error {
+	entries, err := ralph.ListIssues(paths, ralph.IssueListOptions{Statuses: []string{"ready"}, Sort: "priority"})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "- project: %s\n", label)
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "- ready: 0")
+		return nil
+	}
+	shown := entries
+	if len(shown) > limit {
+		shown = shown[:limit]
+	}
+	fmt.Fprintf(w, "- ready: %d (showing %d)\n", len(entries), len(shown))
+	for i, entry := range shown {
+		priority := "-"
+		if entry.Meta.Priority != 0 {
+			priority = strconv.Itoa(entry.Meta.Priority)
+		}
+		fmt.Fprintf(w, "%d. %s role=%s priority=%s %s\n", i+1, entry.Meta.ID, entry.Meta.Role, priority, compactSingleLine(entry.Meta.Title, 60))
+	}
+	return nil
+}
+
 func telegramDoctorCommand(controlDir string, paths ralph.Paths, rawArgs string) (string, error) {
 	spec, err := parseTelegramTargetSpec(rawArgs)
 	if err != nil {
@@ -791,12 +1270,12 @@ func telegramStartCommand(controlDir string, paths ralph.Paths, rawArgs string)
 			Out:          io.Discard,
 		})
 		if err != nil {
-			return "", err
+			return telegramControlBusyReply(err)
 		}
 		return res, nil
 	}
 	if err := runFleetCommand(controlDir, buildFleetTargetArgs("start", spec)); err != nil {
-		return "", err
+		return telegramControlBusyReply(err)
 	}
 	return fmt.Sprintf("fleet start completed (target=%s)", spec.Label()), nil
 }
@@ -807,37 +1286,51 @@ func telegramStopCommand(controlDir string, paths ralph.Paths, rawArgs string) (
 		return "", err
 	}
 	if !spec.HasTarget() {
-		if err := ralph.StopDaemon(paths); err != nil {
-			return "", err
+		if err := withProjectControlLock(paths, "stop", func() error {
+			return ralph.StopDaemon(paths)
+		}); err != nil {
+			return telegramControlBusyReply(err)
 		}
 		return "ralph-loop stopped", nil
 	}
 	if err := runFleetCommand(controlDir, buildFleetTargetArgs("stop", spec)); err != nil {
-		return "", err
+		return telegramControlBusyReply(err)
 	}
 	return fmt.Sprintf("fleet stop completed (target=%s)", spec.Label()), nil
 }
 
+// telegramControlBusyReply turns a lost control-lock race into a normal reply.
+func telegramControlBusyReply(err error) (string, error) {
+	if errors.Is(err, errProjectControlInProgress) {
+		return err.Error(), nil
+	}
+	return "", err
+}
+
 func telegramRestartCommand(controlDir string, paths ralph.Paths, rawArgs string) (string, error) {
 	spec, err := parseTelegramTargetSpec(rawArgs)
 	if err != nil {
 		return "", err
 	}
 	if !spec.HasTarget() {
-		if err := ralph.StopDaemon(paths); err != nil {
-			return "", err
-		}
-		pid, _, err := ralph.StartDaemon(paths)
-		if err != nil {
-			return "", err
+		var pid int
+		if err := withProjectControlLock(paths, "restart", func() error {
+			if err := ralph.StopDaemon(paths); err != nil {
+				return err
+			}
+			var err error
+			pid, _, err = ralph.StartDaemon(paths)
+			return err
+		}); err != nil {
+			return telegramControlBusyReply(err)
 		}
 		return fmt.Sprintf("ralph-loop restarted (pid=%d)", pid), nil
 	}
 	if err := runFleetCommand(controlDir, buildFleetTargetArgs("stop", spec)); err != nil {
-		return "", err
+		return telegramControlBusyReply(err)
 	}
 	if err := runFleetCommand(controlDir, buildFleetTargetArgs("start", spec)); err != nil {
-		return "", err
+		return telegramControlBusyReply(err)
 	}
 	return fmt.Sprintf("fleet restart completed (target=%s)", spec.Label()), nil
 }
@@ -1084,7 +1577,14 @@ func telegramNewIssueCommand(paths ralph.Paths, rawArgs string) (string, error)
 	if err != nil {
 		return "", err
 	}
-	issuePath, issueID, err := ralph.CreateIssue(paths, role, title)
+	opts := ralph.IssueCreateOptions{}
+	if profile, err := ralph.LoadProfile(paths); err == nil {
+		opts.Dedupe = profile.IssueDedupe
+	}
+	issuePath, issueID, err := ralph.CreateIssueWithOptions(paths, role, title, opts)
+	if errors.Is(err, ralph.ErrDuplicateIssue) {
+		return fmt.Sprintf("issue not created\n- reason: %v\n- hint: issue_dedupe=true in profile; reword the title or finish the existing issue", err), nil
+	}
 	if err != nil {
 		return "", err
 	}
@@ -1138,17 +1638,32 @@ func runFleetDoctorReports(controlDir string, spec telegramTargetSpec) (string,
 	fmt.Fprintf(&b, "- target: %s\n", spec.Label())
 	fmt.Fprintf(&b, "- projects: %d\n", len(projects))
 	for _, p := range projects {
-		report, err := ralph.RunDoctor(pathsByID[p.ID])
+		report, err := runFleetProjectDoctor(pathsByID[p.ID])
 		if err != nil {
 			fmt.Fprintf(&b, "- project=%s status=fail detail=%s\n", p.ID, compactSingleLine(err.Error(), 160))
 			continue
 		}
-		pass, warn, fail := countDoctorChecks(report)
-		fmt.Fprintf(&b, "- project=%s pass=%d warn=%d fail=%d\n", p.ID, pass, warn, fail)
+		fmt.Fprintln(&b, formatFleetDoctorSummary(p.ID, report))
 	}
 	return b.String(), nil
 }
 
+// runFleetProjectDoctor is the per-project check behind both `fleet doctor`
+// and Telegram /doctor, so the two report the same checks.
+func runFleetProjectDoctor(paths ralph.Paths) (ralph.DoctorReport, error) {
+	report, err := ralph.RunDoctor(paths)
+	if err != nil {
+		return ralph.DoctorReport{}, err
+	}
+	report.Checks = append(report.Checks, telegramConfigDoctorChecks(paths)...)
+	return report, nil
+}
+
+func formatFleetDoctorSummary(projectID string, report ralph.DoctorReport) string {
+	pass, warn, fail := countDoctorChecks(report)
+	return fmt.Sprintf("- project=%s pass=%d warn=%d fail=%d", projectID, pass, warn, fail)
+}
+
 func parseTelegramCommandLine(raw string) (string, string) {
 	fields := strings.Fields(strings.TrimSpace(raw))
 	if len(fields) == 0 {
@@ -1176,6 +1691,7 @@ func buildTelegramHelp(allowControl bool) string {
 		"- /status [all|<project_id>]",
 		"- /doctor [all|<project_id>]",
 		"- /fleet [all|<project_id>]",
+		"- /queue [N] [all|<project_id>]",
 		"",
 		"Codex Chat",
 		"- plain text message -> Codex conversation in project context",
@@ -1205,6 +1721,15 @@ func buildTelegramHelp(allowControl bool) string {
 	return strings.Join(lines, "\n")
 }
 
+const telegramCodeBlocksEnv = "RALPH_TELEGRAM_CODE_BLOCKS"
+
+// telegramStructuredCommands reply with column-aligned output, sent as a code
+// block when `telegram run --code-blocks` is on; plain text stays the default.
+var telegramStructuredCommands = map[string]struct{}{
+	"/status": {},
+	"/doctor": {},
+}
+
 func formatStatusForTelegram(st ralph.Status) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "Ralph Status\n")
@@ -1244,8 +1769,9 @@ func formatStatusForTelegram(st ralph.Status) string {
 		}
 		fmt.Fprintf(
 			&b,
-			"- Last Failure: %s\n- Codex Retries: %d\n- Permission Streak: %d\n",
+			"- Last Failure: %s\n- Failure Category: %s\n- Codex Retries: %d\n- Permission Streak: %d\n",
 			compactSingleLine(st.LastFailureCause, 120),
+			valueOrDash(st.LastFailureCategory),
 			st.LastCodexRetryCount,
 			st.LastPermissionStreak,
 		)
@@ -1416,18 +1942,22 @@ func newFleetStatusNotifyHandler(controlDir string, defaultPaths ralph.Paths, re
 		}
 
 		type notifyProject struct {
-			ID       string
-			Paths    ralph.Paths
-			FullName string
+			ID             string
+			Paths          ralph.Paths
+			FullName       string
+			RetryThreshold int
+			PermThreshold  int
 		}
 		targets := make([]notifyProject, 0, len(cfg.Projects))
 		seenTargetIDs := map[string]struct{}{}
 		seenTargetDirs := map[string]struct{}{}
 		if len(cfg.Projects) == 0 {
 			targets = append(targets, notifyProject{
-				ID:       "current",
-				Paths:    defaultPaths,
-				FullName: defaultPaths.ProjectDir,
+				ID:             "current",
+				Paths:          defaultPaths,
+				FullName:       defaultPaths.ProjectDir,
+				RetryThreshold: retryThreshold,
+				PermThreshold:  permThreshold,
 			})
 		} else {
 			for _, p := range cfg.Projects {
@@ -1454,9 +1984,11 @@ func newFleetStatusNotifyHandler(controlDir string, defaultPaths ralph.Paths, re
 					seenTargetDirs[dirKey] = struct{}{}
 				}
 				targets = append(targets, notifyProject{
-					ID:       p.ID,
-					Paths:    projectPaths,
-					FullName: p.ProjectDir,
+					ID:             p.ID,
+					Paths:          projectPaths,
+					FullName:       p.ProjectDir,
+					RetryThreshold: fleetNotifyThreshold(p.NotifyRetryThreshold, retryThreshold),
+					PermThreshold:  fleetNotifyThreshold(p.NotifyPermStreakThreshold, permThreshold),
 				})
 			}
 		}
@@ -1474,7 +2006,7 @@ func newFleetStatusNotifyHandler(controlDir string, defaultPaths ralph.Paths, re
 				continue
 			}
 			prev := prevByProject[target.ID]
-			projectAlerts := buildStatusAlerts(prev, current, retryThreshold, permThreshold)
+			projectAlerts := buildStatusAlerts(prev, current, target.RetryThreshold, target.PermThreshold)
 			projectAlerts = suppressDuplicateStuckAlertsForProject(target.Paths, projectAlerts)
 			alerts = append(alerts, projectAlerts...)
 			now := time.Now().UTC()
@@ -1501,6 +2033,14 @@ func newFleetStatusNotifyHandler(controlDir string, defaultPaths ralph.Paths, re
 	}
 }
 
+// fleetNotifyThreshold prefers a fleet project's own threshold over the global one.
+func fleetNotifyThreshold(projectValue, globalValue int) int {
+	if projectValue > 0 {
+		return projectValue
+	}
+	return globalValue
+}
+
 func newStatusNotifyHandler(paths ralph.Paths, retryThreshold, permThreshold int) ralph.TelegramNotifyHandler {
 	initialized := false
 	prev := ralph.Status{}
@@ -1530,6 +2070,173 @@ func newStatusNotifyHandler(paths ralph.Paths, retryThreshold, permThreshold int
 	}
 }
 
+const (
+	telegramAlertSeverityInfo     = "info"
+	telegramAlertSeverityWarn     = "warn"
+	telegramAlertSeverityCritical = "critical"
+)
+
+func normalizeNotifySeverity(raw string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "all", "info":
+		return telegramAlertSeverityInfo, nil
+	case "warn", "warning":
+		return telegramAlertSeverityWarn, nil
+	case "critical", "crit":
+		return telegramAlertSeverityCritical, nil
+	default:
+		return "", fmt.Errorf("unsupported severity %q (expected info|warn|critical)", raw)
+	}
+}
+
+func telegramSeverityRank(severity string) int {
+	switch severity {
+	case telegramAlertSeverityCritical:
+		return 2
+	case telegramAlertSeverityWarn:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func telegramAlertKind(alert string) string {
+	alert = strings.TrimSpace(alert)
+	if !strings.HasPrefix(alert, "[ralph alert][") {
+		return ""
+	}
+	rest := strings.TrimPrefix(alert, "[ralph alert][")
+	end := strings.Index(rest, "]")
+	if end < 0 {
+		return ""
+	}
+	return rest[:end]
+}
+
+func telegramAlertSeverity(alert string) string {
+	switch telegramAlertKind(alert) {
+	case "input_required":
+		return telegramAlertSeverityInfo
+	case "blocked", "permission":
+		return telegramAlertSeverityCritical
+	default:
+		return telegramAlertSeverityWarn
+	}
+}
+
+func filterTelegramAlertsBySeverity(alerts []string, minSeverity string) []string {
+	minRank := telegramSeverityRank(minSeverity)
+	if minRank == 0 {
+		return alerts
+	}
+	out := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		if telegramSeverityRank(telegramAlertSeverity(alert)) >= minRank {
+			out = append(out, alert)
+		}
+	}
+	return out
+}
+
+func withNotifySeverityFilter(handler ralph.TelegramNotifyHandler, minSeverity string) ralph.TelegramNotifyHandler {
+	if handler == nil || telegramSeverityRank(minSeverity) == 0 {
+		return handler
+	}
+	return func(ctx context.Context) ([]string, error) {
+		alerts, err := handler(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return filterTelegramAlertsBySeverity(alerts, minSeverity), nil
+	}
+}
+
+// notifyQuietHours is a daily [start, end) window in minutes since midnight; it may wrap past midnight.
+type notifyQuietHours struct {
+	Enabled  bool
+	Start    int
+	End      int
+	Location *time.Location
+}
+
+func parseNotifyQuietHours(window, tz string) (notifyQuietHours, error) {
+	window = strings.TrimSpace(window)
+	if window == "" || strings.EqualFold(window, "off") {
+		return notifyQuietHours{}, nil
+	}
+	startRaw, endRaw, ok := strings.Cut(window, "-")
+	if !ok {
+		return notifyQuietHours{}, fmt.Errorf("expected HH:MM-HH:MM, got %q", window)
+	}
+	start, err := parseClockMinutes(startRaw)
+	if err != nil {
+		return notifyQuietHours{}, err
+	}
+	end, err := parseClockMinutes(endRaw)
+	if err != nil {
+		return notifyQuietHours{}, err
+	}
+	if start == end {
+		return notifyQuietHours{}, fmt.Errorf("quiet hours start and end must differ: %q", window)
+	}
+	loc := time.Local
+	if tz = strings.TrimSpace(tz); tz != "" {
+		loc, err = time.LoadLocation(tz)
+		if err != nil {
+			return notifyQuietHours{}, fmt.Errorf("invalid timezone %q: %w", tz, err)
+		}
+	}
+	return notifyQuietHours{Enabled: true, Start: start, End: end, Location: loc}, nil
+}
+
+func parseClockMinutes(raw string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (expected HH:MM)", strings.TrimSpace(raw))
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (q notifyQuietHours) Contains(t time.Time) bool {
+	if !q.Enabled {
+		return false
+	}
+	local := t.In(q.Location)
+	minute := local.Hour()*60 + local.Minute()
+	if q.Start < q.End {
+		return minute >= q.Start && minute < q.End
+	}
+	return minute >= q.Start || minute < q.End
+}
+
+func (q notifyQuietHours) String() string {
+	if !q.Enabled {
+		return "off"
+	}
+	return fmt.Sprintf("%02d:%02d-%02d:%02d %s", q.Start/60, q.Start%60, q.End/60, q.End%60, q.Location)
+}
+
+// filterTelegramAlertsForQuietHours drops non-critical alerts while inside the quiet window.
+func filterTelegramAlertsForQuietHours(alerts []string, quiet notifyQuietHours, now time.Time) []string {
+	if !quiet.Contains(now) {
+		return alerts
+	}
+	return filterTelegramAlertsBySeverity(alerts, telegramAlertSeverityCritical)
+}
+
+func withNotifyQuietHours(handler ralph.TelegramNotifyHandler, quiet notifyQuietHours) ralph.TelegramNotifyHandler {
+	if handler == nil || !quiet.Enabled {
+		return handler
+	}
+	return func(ctx context.Context) ([]string, error) {
+		alerts, err := handler(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return filterTelegramAlertsForQuietHours(alerts, quiet, time.Now()), nil
+	}
+}
+
 func dedupeTelegramAlerts(alerts []string) []string {
 	if len(alerts) <= 1 {
 		return alerts
@@ -1559,18 +2266,20 @@ func buildStatusAlerts(prev, current ralph.Status, retryThreshold, permThreshold
 
 	if current.Blocked > prev.Blocked {
 		out = append(out, fmt.Sprintf(
-			"[ralph alert][blocked]\n- project: %s\n- blocked: %d (+%d)\n- reason: %s\n- updated_at: %s",
+			"[ralph alert][blocked]\n- project: %s\n- blocked: %d (+%d)\n- reason: %s\n- category: %s\n- updated_at: %s",
 			project,
 			current.Blocked,
 			current.Blocked-prev.Blocked,
 			valueOrDash(compactSingleLine(current.LastFailureCause, 160)),
+			valueOrDash(current.LastFailureCategory),
 			valueOrDash(current.LastFailureUpdatedAt),
 		))
 	} else if current.LastFailureUpdatedAt != "" && current.LastFailureUpdatedAt != prev.LastFailureUpdatedAt {
 		out = append(out, fmt.Sprintf(
-			"[ralph alert][failure]\n- project: %s\n- reason: %s\n- updated_at: %s",
+			"[ralph alert][failure]\n- project: %s\n- reason: %s\n- category: %s\n- updated_at: %s",
 			project,
 			valueOrDash(compactSingleLine(current.LastFailureCause, 160)),
+			valueOrDash(current.LastFailureCategory),
 			current.LastFailureUpdatedAt,
 		))
 	}
@@ -1598,6 +2307,16 @@ func buildStatusAlerts(prev, current ralph.Status, retryThreshold, permThreshold
 		))
 	}
 
+	if current.LastCrashLoopAt != "" && current.LastCrashLoopAt != prev.LastCrashLoopAt {
+		out = append(out, fmt.Sprintf(
+			"[ralph alert][crashloop]\n- project: %s\n- role: %s\n- backoff_sec: %d\n- detected_at: %s",
+			project,
+			current.LastCrashLoopRole,
+			current.CrashLoopBackoffSec,
+			current.LastCrashLoopAt,
+		))
+	}
+
 	if permThreshold > 0 && current.LastPermissionStreak >= permThreshold && current.LastPermissionStreak > prev.LastPermissionStreak {
 		out = append(out, fmt.Sprintf(
 			"[ralph alert][permission]\n- project: %s\n- permission_streak: %d (threshold=%d)\n- last_failure: %s",
@@ -1770,12 +2489,21 @@ func startTelegramDaemon(paths ralph.Paths, runArgs []string) (string, error) {
 		return "", fmt.Errorf("resolve executable: %w", err)
 	}
 	logFile := paths.TelegramLogFile()
+	profile, err := ralph.LoadProfile(paths)
+	if err != nil {
+		return "", err
+	}
+	maxLogBytes, logBackups := ralph.LogRotationLimits(profile)
+	if _, err := ralph.RotateLogIfNeeded(logFile, maxLogBytes, logBackups); err != nil {
+		return "", err
+	}
 	logHandle, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
 		return "", fmt.Errorf("open telegram log: %w", err)
 	}
 	defer logHandle.Close()
 
+	runArgs, token := splitTelegramTokenArg(runArgs)
 	args := []string{
 		"--control-dir", paths.ControlDir,
 		"--project-dir", paths.ProjectDir,
@@ -1785,12 +2513,15 @@ func startTelegramDaemon(paths ralph.Paths, runArgs []string) (string, error) {
 	args = append(args, runArgs...)
 
 	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(), ralph.DaemonLogEnv(logFile, profile)...)
+	if token != "" {
+		// Hand the token over via env: argv is visible to every local user through ps.
+		cmd.Env = append(cmd.Env, "RALPH_TELEGRAM_BOT_TOKEN="+token)
+	}
 	cmd.Stdout = logHandle
 	cmd.Stderr = logHandle
 	cmd.Stdin = nil
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setsid: true,
-	}
+	cmd.SysProcAttr = detachedSysProcAttr()
 
 	if err := cmd.Start(); err != nil {
 		return "", fmt.Errorf("start telegram daemon: %w", err)
@@ -2058,6 +2789,27 @@ func stopTelegramDaemonByPID(pid int) error {
 	return nil
 }
 
+// splitTelegramTokenArg removes -token/--token (both "x" and "=x" forms) from args,
+// returning the remaining args and the last token value seen.
+func splitTelegramTokenArg(args []string) ([]string, string) {
+	out := make([]string, 0, len(args))
+	token := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "token" {
+			out = append(out, arg)
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		token = strings.TrimSpace(value)
+	}
+	return out, token
+}
+
 func ensureTelegramForegroundArg(args []string) []string {
 	out := append([]string{}, args...)
 	out = append(out, "--foreground")
@@ -2070,7 +2822,7 @@ func tailFile(path string, lines int, follow bool) error {
 	}
 	tailArgs := []string{"-n", strconv.Itoa(lines)}
 	if follow {
-		tailArgs = append(tailArgs, "-f")
+		tailArgs = append(tailArgs, "-F")
 	}
 	tailArgs = append(tailArgs, path)
 
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 	"unicode/utf8"
@@ -87,8 +89,8 @@ func TestTelegramCommandHandlerFallsBackToCodexChat(t *testing.T) {
 	if err != nil {
 		t.Fatalf("handler failed: %v", err)
 	}
-	if !strings.Contains(reply, "chat-ok: status") {
-		t.Fatalf("unexpected chat reply: %q", reply)
+	if reply.Pre || !strings.Contains(reply.Text, "chat-ok: status") {
+		t.Fatalf("unexpected chat reply: %+v", reply)
 	}
 }
 
@@ -134,6 +136,55 @@ func TestTelegramTaskIssueCommand(t *testing.T) {
 	}
 }
 
+func TestTelegramQueueCommandListsReadyIssuesByPriority(t *testing.T) {
+	t.Parallel()
+
+	controlDir := filepath.Join(t.TempDir(), "control")
+	projectDir := filepath.Join(t.TempDir(), "project")
+	paths, err := ralph.NewPaths(controlDir, projectDir)
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	issues := []struct {
+		role     string
+		title    string
+		priority int
+	}{
+		{role: "developer", title: "low priority cleanup", priority: 900},
+		{role: "qa", title: "urgent regression check", priority: 10},
+		{role: "planner", title: "mid priority plan", priority: 100},
+	}
+	for _, issue := range issues {
+		if _, _, err := ralph.CreateIssueWithOptions(paths, issue.role, issue.title, ralph.IssueCreateOptions{Priority: issue.priority}); err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+	}
+
+	reply, err := telegramQueueCommand(controlDir, paths, "2")
+	if err != nil {
+		t.Fatalf("telegramQueueCommand failed: %v", err)
+	}
+	if !strings.Contains(reply, "- ready: 3 (showing 2)") {
+		t.Fatalf("queue summary mismatch:\n%s", reply)
+	}
+	urgent := strings.Index(reply, "role=qa priority=10 urgent regression check")
+	mid := strings.Index(reply, "role=planner priority=100 mid priority plan")
+	if urgent < 0 || mid < 0 || urgent > mid {
+		t.Fatalf("queue should list highest priority first:\n%s", reply)
+	}
+	if strings.Contains(reply, "low priority cleanup") {
+		t.Fatalf("queue should respect N:\n%s", reply)
+	}
+
+	if _, _, err := parseTelegramQueueArgs("0"); err == nil {
+		t.Fatalf("expected error for zero queue size")
+	}
+	limit, spec, err := parseTelegramQueueArgs("all 500")
+	if err != nil || !spec.All || limit != telegramQueueMaxLimit {
+		t.Fatalf("parse queue args mismatch: limit=%d spec=%+v err=%v", limit, spec, err)
+	}
+}
+
 func TestParseTelegramTargetSpec(t *testing.T) {
 	t.Parallel()
 
@@ -282,16 +333,143 @@ func TestRequiresUserAllowlistForControl(t *testing.T) {
 func TestTelegramConfigFileFromArgs(t *testing.T) {
 	t.Parallel()
 
-	controlDir := "/tmp/ralph-control"
-	if got := telegramConfigFileFromArgs(controlDir, nil); got != filepath.Join(controlDir, "telegram.env") {
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	controlPath := filepath.Join(paths.ControlDir, "telegram.env")
+	if got := telegramConfigFileFromArgs(paths, nil); got != controlPath {
 		t.Fatalf("default config path mismatch: got=%q", got)
 	}
-	if got := telegramConfigFileFromArgs(controlDir, []string{"--config-file=/tmp/custom.env"}); got != "/tmp/custom.env" {
+	if got := telegramConfigFileFromArgs(paths, []string{"--config-file=/tmp/custom.env"}); got != "/tmp/custom.env" {
 		t.Fatalf("inline config path mismatch: got=%q", got)
 	}
-	if got := telegramConfigFileFromArgs(controlDir, []string{"--config-file", "/tmp/custom2.env"}); got != "/tmp/custom2.env" {
+	if got := telegramConfigFileFromArgs(paths, []string{"--config-file", "/tmp/custom2.env"}); got != "/tmp/custom2.env" {
 		t.Fatalf("split config path mismatch: got=%q", got)
 	}
+
+	projectPath := filepath.Join(paths.RalphDir, "telegram.env")
+	if err := os.MkdirAll(paths.RalphDir, 0o755); err != nil {
+		t.Fatalf("mkdir ralph dir failed: %v", err)
+	}
+	if err := os.WriteFile(projectPath, []byte("RALPH_TELEGRAM_CHAT_IDS=1\n"), 0o600); err != nil {
+		t.Fatalf("write project config failed: %v", err)
+	}
+	if got := telegramConfigFileFromArgs(paths, nil); got != projectPath {
+		t.Fatalf("project config should win over control dir: got=%q", got)
+	}
+	if got := telegramConfigFileFromArgs(paths, []string{"--config-file=/tmp/custom.env"}); got != "/tmp/custom.env" {
+		t.Fatalf("explicit config should win over project config: got=%q", got)
+	}
+
+	cfg, err := loadTelegramCLIConfig(projectPath)
+	if err != nil {
+		t.Fatalf("load project config failed: %v", err)
+	}
+	if cfg.Source != projectPath || describeTelegramConfigSource(cfg, projectPath) != projectPath {
+		t.Fatalf("config source mismatch: %q", cfg.Source)
+	}
+	missing, err := loadTelegramCLIConfig(controlPath)
+	if err != nil {
+		t.Fatalf("load missing config failed: %v", err)
+	}
+	if got := describeTelegramConfigSource(missing, controlPath); !strings.Contains(got, "not found") {
+		t.Fatalf("missing config should be reported: %q", got)
+	}
+}
+
+func TestTelegramCommandHandlerMarksStructuredReplies(t *testing.T) {
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	if err := ralph.EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout failed: %v", err)
+	}
+
+	handler := telegramCommandHandler(paths.ControlDir, paths, false)
+	status, err := handler(context.Background(), 701, "/status")
+	if err != nil {
+		t.Fatalf("status failed: %v", err)
+	}
+	if !status.Pre || !strings.HasPrefix(status.Text, "Ralph Status\n") {
+		t.Fatalf("status should be a structured reply: %+v", status)
+	}
+	help, err := handler(context.Background(), 701, "/help")
+	if err != nil {
+		t.Fatalf("help failed: %v", err)
+	}
+	if help.Pre {
+		t.Fatalf("help should stay plain text: %+v", help)
+	}
+}
+
+func TestTelegramConfigReloadHandlerRefreshesAllowlist(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "telegram.env")
+	cfg := defaultTelegramCLIConfig()
+	cfg.Token = "123456:ABC-DEF"
+	cfg.ChatIDs = "1001,1002"
+	cfg.UserIDs = "2001"
+	cfg.AllowControl = true
+	if err := saveTelegramCLIConfig(path, cfg); err != nil {
+		t.Fatalf("save config failed: %v", err)
+	}
+
+	control := &atomic.Bool{}
+	reload := telegramConfigReloadHandler(path, nil, control)
+	allowlist, err := reload()
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if len(allowlist.ChatIDs) != 2 || len(allowlist.UserIDs) != 1 || !control.Load() {
+		t.Fatalf("reload mismatch: chats=%d users=%d control=%t", len(allowlist.ChatIDs), len(allowlist.UserIDs), control.Load())
+	}
+
+	cfg.ChatIDs = "-1001234567890"
+	cfg.UserIDs = ""
+	if err := saveTelegramCLIConfig(path, cfg); err != nil {
+		t.Fatalf("save config failed: %v", err)
+	}
+	control.Store(false)
+	if _, err := reload(); err == nil {
+		t.Fatalf("expected group chat allow-control without user-ids to be rejected")
+	}
+	if control.Load() {
+		t.Fatalf("rejected reload must not change allow-control")
+	}
+}
+
+func TestTelegramConfigReloadHandlerKeepsFlagAndEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "telegram.env")
+	cfg := defaultTelegramCLIConfig()
+	cfg.Token = "123456:ABC-DEF"
+	cfg.ChatIDs = "1001"
+	cfg.UserIDs = "2001"
+	cfg.AllowControl = true
+	if err := saveTelegramCLIConfig(path, cfg); err != nil {
+		t.Fatalf("save config failed: %v", err)
+	}
+	t.Setenv("RALPH_TELEGRAM_CHAT_IDS", "3001,3002")
+	t.Setenv("RALPH_TELEGRAM_USER_IDS", "")
+	t.Setenv("RALPH_TELEGRAM_ALLOW_CONTROL", "")
+
+	control := &atomic.Bool{}
+	reload := telegramConfigReloadHandler(path, map[string]string{"allow-control": "false", "user-ids": "4001,4002,4003"}, control)
+	allowlist, err := reload()
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if _, ok := allowlist.ChatIDs[3001]; !ok || len(allowlist.ChatIDs) != 2 {
+		t.Fatalf("env chat-ids should win over the file: %v", allowlist.ChatIDs)
+	}
+	if len(allowlist.UserIDs) != 3 {
+		t.Fatalf("--user-ids should win over the file: %v", allowlist.UserIDs)
+	}
+	if control.Load() {
+		t.Fatalf("--allow-control=false should win over the file")
+	}
 }
 
 func TestSaveLoadTelegramCLIConfig(t *testing.T) {
@@ -308,8 +486,13 @@ func TestSaveLoadTelegramCLIConfig(t *testing.T) {
 		NotifyIntervalSec:         45,
 		NotifyRetryThreshold:      3,
 		NotifyPermStreakThreshold: 5,
+		NotifyMinSeverity:         "critical",
+		NotifyQuietHours:          "22:00-07:00",
+		NotifyQuietHoursTZ:        "Asia/Seoul",
 		CommandTimeoutSec:         180,
 		CommandConcurrency:        6,
+		Lang:                      "en",
+		CodeBlocks:                true,
 	}
 	if err := saveTelegramCLIConfig(path, want); err != nil {
 		t.Fatalf("saveTelegramCLIConfig failed: %v", err)
@@ -328,6 +511,9 @@ func TestSaveLoadTelegramCLIConfig(t *testing.T) {
 	if got.UserIDs != want.UserIDs {
 		t.Fatalf("user ids mismatch: got=%q want=%q", got.UserIDs, want.UserIDs)
 	}
+	if got.Lang != want.Lang || got.CodeBlocks != want.CodeBlocks {
+		t.Fatalf("lang/code blocks mismatch: got=%q,%t want=%q,%t", got.Lang, got.CodeBlocks, want.Lang, want.CodeBlocks)
+	}
 	if got.AllowControl != want.AllowControl {
 		t.Fatalf("allow control mismatch: got=%t want=%t", got.AllowControl, want.AllowControl)
 	}
@@ -337,6 +523,12 @@ func TestSaveLoadTelegramCLIConfig(t *testing.T) {
 	if got.NotifyScope != want.NotifyScope {
 		t.Fatalf("notify scope mismatch: got=%q want=%q", got.NotifyScope, want.NotifyScope)
 	}
+	if got.NotifyMinSeverity != want.NotifyMinSeverity {
+		t.Fatalf("notify min severity mismatch: got=%q want=%q", got.NotifyMinSeverity, want.NotifyMinSeverity)
+	}
+	if got.NotifyQuietHours != want.NotifyQuietHours || got.NotifyQuietHoursTZ != want.NotifyQuietHoursTZ {
+		t.Fatalf("quiet hours mismatch: got=%q/%q want=%q/%q", got.NotifyQuietHours, got.NotifyQuietHoursTZ, want.NotifyQuietHours, want.NotifyQuietHoursTZ)
+	}
 	if got.NotifyIntervalSec != want.NotifyIntervalSec {
 		t.Fatalf("notify interval mismatch: got=%d want=%d", got.NotifyIntervalSec, want.NotifyIntervalSec)
 	}
@@ -393,6 +585,92 @@ func TestDedupeTelegramAlerts(t *testing.T) {
 	}
 }
 
+func TestFilterTelegramAlertsBySeverity(t *testing.T) {
+	t.Parallel()
+
+	alerts := []string{
+		"[ralph alert][blocked]\n- project: a",
+		"[ralph alert][retry]\n- project: a",
+		"[ralph alert][stuck]\n- project: a",
+		"[ralph alert][permission]\n- project: a",
+		buildInputRequiredAlert("a"),
+	}
+	cases := []struct {
+		min  string
+		want int
+	}{
+		{min: "", want: 5},
+		{min: "info", want: 5},
+		{min: "warn", want: 4},
+		{min: "critical", want: 2},
+	}
+	for _, tc := range cases {
+		severity, err := normalizeNotifySeverity(tc.min)
+		if err != nil {
+			t.Fatalf("normalizeNotifySeverity(%q) failed: %v", tc.min, err)
+		}
+		if got := filterTelegramAlertsBySeverity(alerts, severity); len(got) != tc.want {
+			t.Fatalf("min=%q filtered length mismatch: got=%d want=%d", tc.min, len(got), tc.want)
+		}
+	}
+	if _, err := normalizeNotifySeverity("loud"); err == nil {
+		t.Fatalf("expected error for unsupported severity")
+	}
+}
+
+func TestNotifyQuietHoursSuppressesNonCriticalAlerts(t *testing.T) {
+	t.Parallel()
+
+	quiet, err := parseNotifyQuietHours("22:00-07:00", "UTC")
+	if err != nil {
+		t.Fatalf("parseNotifyQuietHours failed: %v", err)
+	}
+	cases := []struct {
+		at   string
+		want bool
+	}{
+		{at: "2026-03-01T21:59:00Z", want: false},
+		{at: "2026-03-01T22:00:00Z", want: true},
+		{at: "2026-03-02T03:00:00Z", want: true},
+		{at: "2026-03-02T07:00:00Z", want: false},
+		{at: "2026-03-02T12:00:00Z", want: false},
+	}
+	for _, tc := range cases {
+		at, _ := time.Parse(time.RFC3339, tc.at)
+		if got := quiet.Contains(at); got != tc.want {
+			t.Fatalf("Contains(%s)=%t want=%t", tc.at, got, tc.want)
+		}
+	}
+
+	alerts := []string{
+		"[ralph alert][blocked]\n- project: a",
+		"[ralph alert][retry]\n- project: a",
+		"[ralph alert][permission]\n- project: a",
+		buildInputRequiredAlert("a"),
+	}
+	night, _ := time.Parse(time.RFC3339, "2026-03-02T03:00:00Z")
+	got := filterTelegramAlertsForQuietHours(alerts, quiet, night)
+	if len(got) != 2 || !strings.Contains(got[0], "[blocked]") || !strings.Contains(got[1], "[permission]") {
+		t.Fatalf("quiet hours should keep only critical alerts: %q", got)
+	}
+	noon, _ := time.Parse(time.RFC3339, "2026-03-02T12:00:00Z")
+	if got := filterTelegramAlertsForQuietHours(alerts, quiet, noon); len(got) != len(alerts) {
+		t.Fatalf("outside quiet hours all alerts should pass: %q", got)
+	}
+
+	if off, err := parseNotifyQuietHours("", ""); err != nil || off.Enabled {
+		t.Fatalf("empty window should disable quiet hours: %+v err=%v", off, err)
+	}
+	for _, bad := range []string{"22:00", "25:00-07:00", "07:00-07:00"} {
+		if _, err := parseNotifyQuietHours(bad, ""); err == nil {
+			t.Fatalf("expected error for window %q", bad)
+		}
+	}
+	if _, err := parseNotifyQuietHours("22:00-07:00", "Mars/Olympus"); err == nil {
+		t.Fatalf("expected error for unknown timezone")
+	}
+}
+
 func TestBuildStatusAlerts(t *testing.T) {
 	t.Parallel()
 
@@ -411,6 +689,7 @@ func TestBuildStatusAlerts(t *testing.T) {
 		InProgress:             1,
 		Blocked:                2,
 		LastFailureCause:       "codex_failed_after_3_attempts",
+		LastFailureCategory:    "network",
 		LastFailureUpdatedAt:   "2026-02-20T08:10:00Z",
 		LastCodexRetryCount:    3,
 		LastBusyWaitDetectedAt: "2026-02-20T08:11:00Z",
@@ -423,7 +702,7 @@ func TestBuildStatusAlerts(t *testing.T) {
 		t.Fatalf("expected multiple alerts, got=%d", len(alerts))
 	}
 	joined := strings.Join(alerts, "\n")
-	if !strings.Contains(joined, "[blocked]") {
+	if !strings.Contains(joined, "[blocked]") || !strings.Contains(joined, "- category: network") {
 		t.Fatalf("missing blocked alert: %q", joined)
 	}
 	if !strings.Contains(joined, "[retry]") {
@@ -437,6 +716,20 @@ func TestBuildStatusAlerts(t *testing.T) {
 	}
 }
 
+func TestBuildStatusAlertsReportsSupervisorCrashLoop(t *testing.T) {
+	t.Parallel()
+
+	prev := ralph.Status{ProjectDir: "/tmp/p"}
+	curr := ralph.Status{ProjectDir: "/tmp/p", LastCrashLoopAt: "2026-02-20T10:00:00Z", LastCrashLoopRole: "developer", CrashLoopBackoffSec: 8}
+	alerts := buildStatusAlerts(prev, curr, 2, 3)
+	if len(alerts) != 1 || !strings.Contains(alerts[0], "[crashloop]") || !strings.Contains(alerts[0], "- role: developer") {
+		t.Fatalf("expected crash loop alert: %q", alerts)
+	}
+	if alerts := buildStatusAlerts(curr, curr, 2, 3); len(alerts) != 0 {
+		t.Fatalf("unchanged crash loop should not re-alert: %q", alerts)
+	}
+}
+
 func TestBuildStatusAlertsSkipsStuckWhenDaemonStopped(t *testing.T) {
 	t.Parallel()
 
@@ -718,6 +1011,38 @@ func TestEnsureTelegramForegroundArg(t *testing.T) {
 	}
 }
 
+func TestSplitTelegramTokenArgKeepsTokenOutOfDaemonArgv(t *testing.T) {
+	t.Parallel()
+
+	cases := [][]string{
+		{"--config-file", "/tmp/telegram.env", "--token", "123456:secret", "--foreground"},
+		{"--config-file", "/tmp/telegram.env", "-token=123456:secret", "--foreground"},
+	}
+	for _, args := range cases {
+		rest, token := splitTelegramTokenArg(args)
+		if token != "123456:secret" {
+			t.Fatalf("token mismatch for %v: got=%q", args, token)
+		}
+		if strings.Contains(strings.Join(rest, " "), "secret") {
+			t.Fatalf("token left in argv: %v", rest)
+		}
+		if strings.Join(rest, " ") != "--config-file /tmp/telegram.env --foreground" {
+			t.Fatalf("unexpected remaining args: %v", rest)
+		}
+	}
+}
+
+func TestFleetNotifyThresholdFallsBackToGlobal(t *testing.T) {
+	t.Parallel()
+
+	if got := fleetNotifyThreshold(5, 2); got != 5 {
+		t.Fatalf("project override should win: got=%d", got)
+	}
+	if got := fleetNotifyThreshold(0, 2); got != 2 {
+		t.Fatalf("unset project threshold should use global: got=%d", got)
+	}
+}
+
 func TestTelegramPIDState(t *testing.T) {
 	t.Parallel()
 
@@ -885,6 +1210,7 @@ func TestParseTelegramPRDStoryPriority(t *testing.T) {
 		{in: "", want: telegramPRDDefaultPriority},
 		{in: "default", want: telegramPRDDefaultPriority},
 		{in: "25", want: 25},
+		{in: "-3", want: -3},
 		{in: "0", wantErr: true},
 		{in: "x", wantErr: true},
 	}
@@ -1000,6 +1326,154 @@ func TestAdvanceTelegramPRDSessionFlow(t *testing.T) {
 	}
 }
 
+func TestTelegramPRDEditStoryReplacesInPlace(t *testing.T) {
+	t.Parallel()
+
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	session := telegramPRDSession{
+		ChatID:      9,
+		Stage:       telegramPRDStageAwaitStoryTitle,
+		ProductName: "Wallet",
+		Stories: []telegramPRDStory{
+			{ID: "TG-001", Title: "결제", Description: "설명", Role: "developer", Priority: 10},
+			{ID: "TG-002", Title: "검증", Description: "QA 설명", Role: "qa", Priority: 20},
+		},
+	}
+	if err := telegramUpsertPRDSession(paths, session); err != nil {
+		t.Fatalf("upsert session failed: %v", err)
+	}
+	if _, err := telegramPRDCommand(paths, 9, "edit 3"); err == nil {
+		t.Fatalf("expected out-of-range index error")
+	}
+	reply, err := telegramPRDCommand(paths, 9, "edit 1")
+	if err != nil || !strings.Contains(reply, "editing story 1") {
+		t.Fatalf("edit reply mismatch: reply=%q err=%v", reply, err)
+	}
+	s, _, err := telegramLoadPRDSession(paths, 9)
+	if err != nil {
+		t.Fatalf("load session failed: %v", err)
+	}
+	if s.EditIndex != 1 || s.DraftTitle != "결제" || s.Stage != telegramPRDStageAwaitStoryTitle {
+		t.Fatalf("edit draft mismatch: %+v", s)
+	}
+
+	if s, _, err = advanceTelegramPRDSession(paths, s, "결제 재시도"); err != nil {
+		t.Fatalf("set title failed: %v", err)
+	}
+	if s, _, err = advanceTelegramPRDSession(paths, s, "keep"); err != nil {
+		t.Fatalf("keep description failed: %v", err)
+	}
+	s, reply, err = advanceTelegramPRDSession(paths, s, "keep")
+	if err != nil {
+		t.Fatalf("keep role failed: %v", err)
+	}
+	if !strings.Contains(reply, "story updated") {
+		t.Fatalf("reply should report update: %q", reply)
+	}
+	if len(s.Stories) != 2 || s.EditIndex != 0 {
+		t.Fatalf("story must be replaced, not appended: %+v", s)
+	}
+	got := s.Stories[0]
+	if got.ID != "TG-001" || got.Title != "결제 재시도" || got.Description != "설명" || got.Role != "developer" || got.Priority != 10 {
+		t.Fatalf("edited story mismatch: %+v", got)
+	}
+}
+
+func TestTelegramPRDAttachRecordsProjectFilesOnly(t *testing.T) {
+	t.Parallel()
+
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(paths.ProjectDir, "docs"), 0o755); err != nil {
+		t.Fatalf("mkdir docs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(paths.ProjectDir, "docs", "design.md"), []byte("design"), 0o644); err != nil {
+		t.Fatalf("write design doc: %v", err)
+	}
+	if err := telegramUpsertPRDSession(paths, telegramPRDSession{ChatID: 9, Stage: telegramPRDStageAwaitStoryTitle, ProductName: "Wallet"}); err != nil {
+		t.Fatalf("upsert session failed: %v", err)
+	}
+
+	if _, err := telegramPRDCommand(paths, 9, "attach /etc/passwd"); err == nil {
+		t.Fatalf("attach outside the project must fail")
+	}
+	reply, err := telegramPRDCommand(paths, 9, "attach docs/design.md")
+	if err != nil || !strings.Contains(reply, "prd attachment added") {
+		t.Fatalf("attach reply mismatch: reply=%q err=%v", reply, err)
+	}
+	if reply, _ := telegramPRDCommand(paths, 9, "attach docs/design.md"); !strings.Contains(reply, "already recorded") {
+		t.Fatalf("duplicate attach should be ignored: %q", reply)
+	}
+	s, _, err := telegramLoadPRDSession(paths, 9)
+	if err != nil {
+		t.Fatalf("load session failed: %v", err)
+	}
+	if len(s.Context.Attachments) != 1 || s.Context.Attachments[0] != "docs/design.md" {
+		t.Fatalf("attachments mismatch: %+v", s.Context.Attachments)
+	}
+
+	target := filepath.Join(paths.ProjectDir, "prd.json")
+	s.Stories = []telegramPRDStory{{ID: "US-001", Title: "결제", Role: "developer"}}
+	if err := writeTelegramPRDFile(target, s); err != nil {
+		t.Fatalf("write prd file failed: %v", err)
+	}
+	content, err := os.ReadFile(target)
+	if err != nil || !strings.Contains(string(content), "docs/design.md") {
+		t.Fatalf("saved prd should record attachments: %s err=%v", string(content), err)
+	}
+
+	if reply, err := telegramPRDCommand(paths, 9, "attach clear"); err != nil || reply != "prd attachments cleared" {
+		t.Fatalf("clear reply mismatch: reply=%q err=%v", reply, err)
+	}
+}
+
+func TestTelegramPRDRemoveStoryKeepsIDsStable(t *testing.T) {
+	t.Parallel()
+
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	session := telegramPRDSession{
+		ChatID:       11,
+		Stage:        telegramPRDStageAwaitStoryTitle,
+		ProductName:  "Wallet",
+		CreatedAtUTC: "2026-02-22T00:00:00Z",
+	}
+	for i, title := range []string{"first", "second", "third"} {
+		session, _, _ = telegramPRDPlaceStory(session, telegramPRDStory{Title: title, Description: "d", Role: "developer", Priority: 10 * (i + 1)})
+	}
+	if err := telegramUpsertPRDSession(paths, session); err != nil {
+		t.Fatalf("upsert session failed: %v", err)
+	}
+	if _, err := telegramPRDCommand(paths, 11, "remove 0"); err == nil {
+		t.Fatalf("expected out-of-range index error")
+	}
+	reply, err := telegramPRDCommand(paths, 11, "remove 2")
+	if err != nil {
+		t.Fatalf("remove story failed: %v", err)
+	}
+	if !strings.Contains(reply, "story removed") || !strings.Contains(reply, "- stories_total: 2") || !strings.Contains(reply, "clarity_score:") {
+		t.Fatalf("remove reply mismatch: %q", reply)
+	}
+	got, _, err := telegramLoadPRDSession(paths, 11)
+	if err != nil {
+		t.Fatalf("load session failed: %v", err)
+	}
+	if len(got.Stories) != 2 || got.Stories[0].ID != session.Stories[0].ID || got.Stories[1].ID != session.Stories[2].ID {
+		t.Fatalf("remaining ids must stay stable: %+v", got.Stories)
+	}
+	got, _, _ = telegramPRDPlaceStory(got, telegramPRDStory{Title: "fourth", Description: "d", Role: "qa", Priority: 40})
+	if got.Stories[2].ID == got.Stories[1].ID {
+		t.Fatalf("new story must not reuse an existing id: %s", got.Stories[2].ID)
+	}
+}
+
 func TestParseTelegramPRDStoryRoleAndPriorityInput(t *testing.T) {
 	t.Parallel()
 
@@ -1178,14 +1652,40 @@ func TestParseTelegramPRDCodexStoryPriorityResponse(t *testing.T) {
 	if err != nil {
 		t.Fatalf("parse failed: %v", err)
 	}
-	if parsed.Priority != 100 {
-		t.Fatalf("priority should be clamped to minimum 100: %d", parsed.Priority)
+	if parsed.Priority != 95 {
+		t.Fatalf("priority below 100 should be kept: %d", parsed.Priority)
 	}
 	if parsed.Reason == "" {
 		t.Fatalf("reason should not be empty")
 	}
 }
 
+func TestClampTelegramPRDStoryPriorityAllowsNegative(t *testing.T) {
+	t.Parallel()
+
+	cases := map[int]int{
+		-50:  -50,
+		-1:   -1,
+		0:    0,
+		95:   95,
+		1000: 1000,
+		9999: 3000,
+	}
+	for in, want := range cases {
+		if got := clampTelegramPRDStoryPriority(in); got != want {
+			t.Fatalf("clamp(%d)=%d, want %d", in, got, want)
+		}
+	}
+
+	parsed, err := parseTelegramPRDCodexStoryPriorityResponse(`{"priority":-20,"reason":"hotfix"}`)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if parsed.Priority != -20 {
+		t.Fatalf("negative codex priority should be kept: %d", parsed.Priority)
+	}
+}
+
 func TestEvaluateTelegramPRDClarityReady(t *testing.T) {
 	t.Parallel()
 
@@ -1621,6 +2121,58 @@ func TestTelegramPRDRefineSessionCodexUnavailableNoHeuristicQuestion(t *testing.
 	}
 }
 
+func TestTelegramPRDApplyReusesCachedCodexScore(t *testing.T) {
+	oldScore := telegramPRDScoreAnalyzer
+	t.Cleanup(func() { telegramPRDScoreAnalyzer = oldScore })
+	calls := 0
+	telegramPRDScoreAnalyzer = func(_ ralph.Paths, _ telegramPRDSession) (telegramPRDCodexScoreResponse, error) {
+		calls++
+		return telegramPRDCodexScoreResponse{Score: 50, Missing: []string{"goal"}}, nil
+	}
+
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	session := telegramPRDSession{
+		ChatID:      77,
+		Stage:       telegramPRDStageAwaitStoryTitle,
+		ProductName: "Wallet",
+		Stories: []telegramPRDStory{
+			{ID: "TG-001", Title: "결제", Description: "설명", Role: "developer", Priority: 10},
+		},
+	}
+	if err := telegramUpsertPRDSession(paths, session); err != nil {
+		t.Fatalf("upsert session failed: %v", err)
+	}
+
+	if _, err := telegramPRDScoreSession(paths, 77); err != nil {
+		t.Fatalf("score session failed: %v", err)
+	}
+	reply, err := telegramPRDApplySession(paths, 77, "")
+	if err != nil {
+		t.Fatalf("apply session failed: %v", err)
+	}
+	if calls != 1 || !strings.Contains(reply, "scoring_mode: codex(cached)") {
+		t.Fatalf("apply should reuse cached score: calls=%d reply=%q", calls, reply)
+	}
+
+	scored, _, err := telegramLoadPRDSession(paths, 77)
+	if err != nil {
+		t.Fatalf("load session failed: %v", err)
+	}
+	scored.Context.Goal = "실패율 30% 감소"
+	if err := telegramUpsertPRDSession(paths, scored); err != nil {
+		t.Fatalf("upsert mutated session failed: %v", err)
+	}
+	if _, err := telegramPRDApplySession(paths, 77, ""); err != nil {
+		t.Fatalf("apply mutated session failed: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("mutated session must be re-scored: calls=%d", calls)
+	}
+}
+
 func TestClassifyTelegramCodexFailure(t *testing.T) {
 	t.Parallel()
 
@@ -1653,7 +2205,7 @@ func TestClassifyTelegramCodexFailure(t *testing.T) {
 func TestFormatTelegramPRDRefineUnavailableIncludesCodexReason(t *testing.T) {
 	t.Parallel()
 
-	out := formatTelegramPRDRefineUnavailable(telegramPRDStageAwaitProblem, 42, fmt.Errorf("could not resolve host: api.openai.com"))
+	out := formatTelegramPRDRefineUnavailable(telegramTextFor(telegramLangKO), telegramPRDStageAwaitProblem, 42, fmt.Errorf("could not resolve host: api.openai.com"))
 	if !strings.Contains(out, "codex_error: network") {
 		t.Fatalf("expected network codex_error in fallback output: %q", out)
 	}
@@ -1735,6 +2287,62 @@ func TestTelegramPRDSessionStoreRoundTrip(t *testing.T) {
 	}
 }
 
+func TestTelegramPRDNamedDraftsSaveListResume(t *testing.T) {
+	t.Parallel()
+
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	wallet := telegramPRDSession{
+		ChatID:      42,
+		Stage:       telegramPRDStageAwaitStoryTitle,
+		ProductName: "Wallet",
+		Stories: []telegramPRDStory{
+			{ID: "US-001", Title: "결제", Description: "설명", Role: "developer", Priority: 10},
+		},
+	}
+	if err := telegramUpsertPRDSession(paths, wallet); err != nil {
+		t.Fatalf("upsert session failed: %v", err)
+	}
+	reply, err := telegramPRDCommand(paths, 42, "save-draft wallet")
+	if err != nil || !strings.Contains(reply, "PRD draft saved") {
+		t.Fatalf("save-draft mismatch: reply=%q err=%v", reply, err)
+	}
+	if _, err := telegramPRDCommand(paths, 42, "start Billing"); err != nil {
+		t.Fatalf("start second draft failed: %v", err)
+	}
+	if reply, err := telegramPRDCommand(paths, 42, "save-draft billing"); err != nil || !strings.Contains(reply, "saved") {
+		t.Fatalf("save second draft mismatch: reply=%q err=%v", reply, err)
+	}
+
+	reply, err = telegramPRDCommand(paths, 42, "list")
+	if err != nil {
+		t.Fatalf("list drafts failed: %v", err)
+	}
+	if !strings.Contains(reply, "PRD drafts (2)") || !strings.Contains(reply, "- wallet: product=Wallet stories=1") || !strings.Contains(reply, "- billing: product=Billing stories=0") {
+		t.Fatalf("list reply mismatch: %q", reply)
+	}
+	if reply, err := telegramPRDCommand(paths, 7, "list"); err != nil || !strings.Contains(reply, "no saved PRD drafts") {
+		t.Fatalf("drafts must be scoped per chat: reply=%q err=%v", reply, err)
+	}
+
+	reply, err = telegramPRDCommand(paths, 42, "resume wallet")
+	if err != nil || !strings.Contains(reply, "PRD draft resumed") {
+		t.Fatalf("resume mismatch: reply=%q err=%v", reply, err)
+	}
+	active, found, err := telegramLoadPRDSession(paths, 42)
+	if err != nil || !found {
+		t.Fatalf("active session missing after resume: found=%t err=%v", found, err)
+	}
+	if active.ProductName != "Wallet" || len(active.Stories) != 1 || active.Stage != telegramPRDStageAwaitStoryTitle {
+		t.Fatalf("resumed session mismatch: %+v", active)
+	}
+	if reply, err := telegramPRDCommand(paths, 42, "resume missing"); err != nil || !strings.Contains(reply, "not found") {
+		t.Fatalf("missing draft reply mismatch: reply=%q err=%v", reply, err)
+	}
+}
+
 func TestWriteTelegramPRDFile(t *testing.T) {
 	t.Parallel()
 
@@ -1957,3 +2565,33 @@ func TestBuildTelegramPRDTurnPromptIncludesConversation(t *testing.T) {
 		t.Fatalf("turn prompt should include schema: %q", prompt)
 	}
 }
+
+func TestTelegramSetupWizardStepSavesConfig(t *testing.T) {
+	for _, key := range []string{"RALPH_TELEGRAM_BOT_TOKEN", "RALPH_TELEGRAM_CHAT_IDS", "RALPH_TELEGRAM_USER_IDS"} {
+		t.Setenv(key, "")
+	}
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	step := telegramSetupWizardStep(paths)
+	var out strings.Builder
+	if err := step(bufio.NewReader(strings.NewReader("123456:abc\n1001\n\nn\n")), &out); err != nil {
+		t.Fatalf("wizard step: %v", err)
+	}
+	configFile := filepath.Join(paths.ControlDir, telegramConfigFileName)
+	cfg, err := loadTelegramCLIConfig(configFile)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Token != "123456:abc" || cfg.ChatIDs != "1001" || cfg.Notify {
+		t.Fatalf("unexpected saved config: %+v", cfg)
+	}
+	if cfg.CommandTimeoutSec != defaultTelegramCLIConfig().CommandTimeoutSec {
+		t.Fatalf("unprompted values should keep defaults: %+v", cfg)
+	}
+
+	if err := step(bufio.NewReader(strings.NewReader("123456:abc\nnot-a-chat\n\n\n")), &out); err == nil {
+		t.Fatalf("expected invalid chat ids to be rejected")
+	}
+}
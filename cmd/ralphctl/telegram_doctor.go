@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"codex-ralph/internal/ralph"
+)
+
+// telegramConfigDoctorChecks catches a telegram config that `telegram run` would
+// reject, so the problem shows up in doctor rather than when the bot starts.
+// Values resolve like `telegram run` without flags: env over the config file.
+// A missing config file with no env is fine: telegram is optional.
+func telegramConfigDoctorChecks(paths ralph.Paths) []ralph.DoctorCheck {
+	configFile := telegramConfigFileFromArgs(paths, nil)
+	check := func(name, status, detail string) ralph.DoctorCheck {
+		return ralph.DoctorCheck{Name: "telegram:" + name, Status: status, Detail: detail}
+	}
+	cfg, err := loadTelegramCLIConfig(configFile)
+	if err != nil {
+		return []ralph.DoctorCheck{check("config", "fail", err.Error())}
+	}
+	token := firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_BOT_TOKEN")), cfg.Token)
+	chatIDsRaw := firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_CHAT_IDS")), cfg.ChatIDs)
+	userIDsRaw := firstNonEmpty(strings.TrimSpace(os.Getenv("RALPH_TELEGRAM_USER_IDS")), cfg.UserIDs)
+	allowControl := envBoolDefault("RALPH_TELEGRAM_ALLOW_CONTROL", cfg.AllowControl)
+	if cfg.Source == "" && strings.TrimSpace(token) == "" && strings.TrimSpace(chatIDsRaw) == "" {
+		return []ralph.DoctorCheck{check("config", "pass", "not configured (optional): "+configFile)}
+	}
+	checks := []ralph.DoctorCheck{check("config", "pass", firstNonEmpty(cfg.Source, "env only (no "+configFile+")"))}
+
+	switch token := strings.TrimSpace(token); {
+	case token == "":
+		checks = append(checks, check("token", "fail", "RALPH_TELEGRAM_BOT_TOKEN is empty"))
+	case !looksLikeTelegramBotToken(token):
+		checks = append(checks, check("token", "warn", "RALPH_TELEGRAM_BOT_TOKEN does not look like <bot id>:<secret>"))
+	default:
+		checks = append(checks, check("token", "pass", "set"))
+	}
+
+	chatIDs, chatErr := ralph.ParseTelegramChatIDs(chatIDsRaw)
+	switch {
+	case chatErr != nil:
+		checks = append(checks, check("chat_ids", "fail", chatErr.Error()))
+	case len(chatIDs) == 0:
+		checks = append(checks, check("chat_ids", "fail", "RALPH_TELEGRAM_CHAT_IDS is empty"))
+	default:
+		checks = append(checks, check("chat_ids", "pass", fmt.Sprintf("allowed_chats=%d", len(chatIDs))))
+	}
+
+	userIDs := map[int64]struct{}{}
+	if strings.TrimSpace(userIDsRaw) != "" {
+		userIDs, err = ralph.ParseTelegramUserIDs(userIDsRaw)
+		if err != nil {
+			return append(checks, check("user_ids", "fail", err.Error()))
+		}
+	}
+	checks = append(checks, check("user_ids", "pass", fmt.Sprintf("allowed_users=%d", len(userIDs))))
+
+	if chatErr == nil && allowControl && len(userIDs) == 0 && requiresUserAllowlistForControl(chatIDs) {
+		checks = append(checks, check("allow_control", "fail", "allow-control with group/supergroup chat requires user-ids"))
+	} else {
+		checks = append(checks, check("allow_control", "pass", fmt.Sprintf("allow_control=%t", allowControl)))
+	}
+	return checks
+}
+
+func looksLikeTelegramBotToken(token string) bool {
+	id, secret, ok := strings.Cut(token, ":")
+	if !ok || secret == "" {
+		return false
+	}
+	_, err := strconv.ParseInt(id, 10, 64)
+	return err == nil
+}
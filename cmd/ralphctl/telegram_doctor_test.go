@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"codex-ralph/internal/ralph"
+)
+
+func telegramDoctorStatuses(checks []ralph.DoctorCheck) map[string]string {
+	out := map[string]string{}
+	for _, check := range checks {
+		out[check.Name] = check.Status
+	}
+	return out
+}
+
+func clearTelegramDoctorEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{"RALPH_TELEGRAM_BOT_TOKEN", "RALPH_TELEGRAM_CHAT_IDS", "RALPH_TELEGRAM_USER_IDS", "RALPH_TELEGRAM_ALLOW_CONTROL"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestTelegramConfigDoctorChecks(t *testing.T) {
+	clearTelegramDoctorEnv(t)
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	if got := telegramDoctorStatuses(telegramConfigDoctorChecks(paths)); len(got) != 1 || got["telegram:config"] != "pass" {
+		t.Fatalf("missing config should be a single pass check, got %v", got)
+	}
+
+	configFile := filepath.Join(paths.ControlDir, telegramConfigFileName)
+	write := func(content string) {
+		t.Helper()
+		if err := os.MkdirAll(paths.ControlDir, 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(configFile, []byte(content), 0o644); err != nil {
+			t.Fatalf("write config: %v", err)
+		}
+	}
+
+	write("RALPH_TELEGRAM_BOT_TOKEN=123456:secret\nRALPH_TELEGRAM_CHAT_IDS=1001\n")
+	got := telegramDoctorStatuses(telegramConfigDoctorChecks(paths))
+	for _, name := range []string{"telegram:config", "telegram:token", "telegram:chat_ids", "telegram:user_ids", "telegram:allow_control"} {
+		if got[name] != "pass" {
+			t.Fatalf("expected %s to pass, got %v", name, got)
+		}
+	}
+
+	write("RALPH_TELEGRAM_BOT_TOKEN=oops\nRALPH_TELEGRAM_CHAT_IDS=abc\nRALPH_TELEGRAM_USER_IDS=1,x\n")
+	got = telegramDoctorStatuses(telegramConfigDoctorChecks(paths))
+	if got["telegram:token"] != "warn" || got["telegram:chat_ids"] != "fail" || got["telegram:user_ids"] != "fail" {
+		t.Fatalf("expected token warn and id failures, got %v", got)
+	}
+
+	write("RALPH_TELEGRAM_CHAT_IDS=-100123\nRALPH_TELEGRAM_ALLOW_CONTROL=true\n")
+	got = telegramDoctorStatuses(telegramConfigDoctorChecks(paths))
+	if got["telegram:token"] != "fail" || got["telegram:allow_control"] != "fail" {
+		t.Fatalf("expected missing token and group control without user-ids to fail, got %v", got)
+	}
+}
+
+func TestTelegramConfigDoctorChecksPreferEnvOverFile(t *testing.T) {
+	clearTelegramDoctorEnv(t)
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+
+	t.Setenv("RALPH_TELEGRAM_BOT_TOKEN", "123456:secret")
+	t.Setenv("RALPH_TELEGRAM_CHAT_IDS", "1001")
+	got := telegramDoctorStatuses(telegramConfigDoctorChecks(paths))
+	if got["telegram:config"] != "pass" || got["telegram:token"] != "pass" || got["telegram:chat_ids"] != "pass" {
+		t.Fatalf("env-only telegram config should be checked and pass, got %v", got)
+	}
+
+	// The file is broken, but `telegram run` would use the env values.
+	if err := os.MkdirAll(paths.ControlDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(paths.ControlDir, telegramConfigFileName), []byte("RALPH_TELEGRAM_BOT_TOKEN=\nRALPH_TELEGRAM_CHAT_IDS=abc\n"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	got = telegramDoctorStatuses(telegramConfigDoctorChecks(paths))
+	if got["telegram:token"] != "pass" || got["telegram:chat_ids"] != "pass" {
+		t.Fatalf("env should win over the config file, got %v", got)
+	}
+}
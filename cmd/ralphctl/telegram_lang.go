@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	telegramLangEnv = "RALPH_TELEGRAM_LANG"
+	telegramLangKO  = "ko"
+	telegramLangEN  = "en"
+)
+
+// telegramPRDText is the message bundle for the PRD wizard and the language the
+// telegram codex prompts ask for. Field keys (problem, goal, ...) match
+// telegramPRDContext json names.
+type telegramPRDText struct {
+	ReplyLanguage        string
+	HelpFlow             []string
+	StagePrompts         map[string]string
+	DefaultAssumptions   map[string]string
+	ClarityAssumptions   map[string]string
+	FirstStoryPrompt     string
+	ReplaceAssumedPrompt string
+	NoStoriesNext        string
+	EditStoryNext        string
+	TitleKeptNext        string
+	TitleSavedNext       string
+	DescKeptNext         string
+	DescSavedNext        string
+	QuickFormatError     string
+	StoryAddedNext       string
+	StoryAddedRefineNext string
+	ScoreFailedReason    string
+	ScoreFailedNext      string
+	ApplyBlockedReason   string
+	ApplyBlockedNext     string
+	RefineFailedReason   string
+	RefineFailedNext     string
+	RefineHint           string
+	RefineFailedHint     string
+	TaskDefaultTitle     string
+}
+
+var telegramPRDTexts = map[string]telegramPRDText{
+	telegramLangKO: {
+		ReplyLanguage: "Korean",
+		HelpFlow: []string{
+			"2) /prd refine (Codex가 부족한 컨텍스트를 동적으로 질문)",
+			"3) (optional) /prd priority 로 에이전트별 기본 priority 조정",
+			"4) answer prompts, then add stories",
+			"   - 기본: title -> description -> role(선택: priority)",
+			"   - 빠른 입력: title | description | role [priority]",
+		},
+		StagePrompts: map[string]string{
+			telegramPRDStageAwaitProduct:     "제품/프로젝트 이름을 입력하세요",
+			telegramPRDStageAwaitProblem:     "문제 정의를 입력하세요 (왜 이 작업이 필요한가?)",
+			telegramPRDStageAwaitGoal:        "목표를 입력하세요 (완료 기준 한 줄)",
+			telegramPRDStageAwaitInScope:     "포함 범위를 입력하세요 (이번 사이클에서 반드시 할 것)",
+			telegramPRDStageAwaitOutOfScope:  "제외 범위를 입력하세요 (이번 사이클에서 하지 않을 것)",
+			telegramPRDStageAwaitAcceptance:  "수용 기준을 입력하세요 (검증 가능한 기준)",
+			telegramPRDStageAwaitConstraints: "제약 사항을 입력하세요 (옵션, skip 가능)",
+			telegramPRDStageAwaitStoryTitle:  "story 제목을 입력하세요 (quick: 제목 | 설명 | role [priority])",
+			telegramPRDStageAwaitStoryDesc:   "story 설명을 입력하세요",
+			telegramPRDStageAwaitStoryRole:   "role 입력 (manager|planner|developer|qa, optional: role priority)",
+			telegramPRDStageAwaitStoryPrio:   "priority 입력 (숫자, default=role 기본값)",
+		},
+		DefaultAssumptions: map[string]string{
+			"problem":      "현재 기능/운영상 pain point는 명시되지 않음",
+			"goal":         "단기 목표는 첫 동작 가능한 자동화 루프 확보",
+			"in_scope":     "초기 릴리즈에서는 핵심 사용자 흐름만 포함",
+			"out_of_scope": "대규모 리팩터/새 인프라 구축은 제외",
+			"acceptance":   "주요 시나리오 성공 + 실패 시 복구 경로 확인",
+			"constraints":  "시간/리소스 제약은 일반적인 단일 개발자 환경 가정",
+		},
+		ClarityAssumptions: map[string]string{
+			"problem":      "skip/default 입력 시: 현재 운영 pain point 해결이 우선이라고 가정",
+			"goal":         "skip/default 입력 시: 첫 안정 운영 가능 상태 도달로 가정",
+			"in_scope":     "skip/default 입력 시: 핵심 사용자 흐름 중심으로 가정",
+			"out_of_scope": "skip/default 입력 시: 대규모 리팩터/인프라 변경 제외로 가정",
+			"acceptance":   "skip/default 입력 시: 핵심 시나리오 성공 + 회귀 없음으로 가정",
+		},
+		FirstStoryPrompt:     "첫 user story 제목을 입력하세요",
+		ReplaceAssumedPrompt: "%s의 실제 값을 입력하세요 (현재 가정값으로 설정됨)",
+		NoStoriesNext:        "story 제목을 입력하세요",
+		EditStoryNext:        "새 제목을 입력하세요 (keep=기존 값 유지, quick: 제목 | 설명 | role [priority])",
+		TitleKeptNext:        "설명을 입력하세요 (keep=기존 값 유지)",
+		TitleSavedNext:       "설명을 입력하세요 (quick: 제목 | 설명 | role [priority])",
+		DescKeptNext:         "role 입력 (manager|planner|developer|qa, optional: role priority, keep=기존 값 유지)",
+		DescSavedNext:        "role 입력 (manager|planner|developer|qa, optional: role priority)",
+		QuickFormatError:     "quick format: 제목 | 설명 | role [priority] 또는 제목 | 설명 | role | priority",
+		StoryAddedNext:       "다음 story 제목 입력 또는 /prd preview /prd save /prd apply",
+		StoryAddedRefineNext: "/prd refine (부족 컨텍스트 질문 진행) 또는 다음 story 제목 입력",
+		ScoreFailedReason:    "codex scoring 실패",
+		ScoreFailedNext:      "codex 상태 복구 후 `/prd score` 재시도",
+		ApplyBlockedReason:   "codex scoring 실패로 apply gate 판단 불가",
+		ApplyBlockedNext:     "codex 상태 복구 후 `/prd score` 또는 `/prd refine` 재시도",
+		RefineFailedReason:   "codex refine 실패로 동적 질문 생성 불가",
+		RefineFailedNext:     "codex 상태 복구 후 `/prd refine` 재시도",
+		RefineHint:           "답변이 애매하면 `skip` 또는 `default` 입력",
+		RefineFailedHint:     "`/doctor` 또는 telegram tail 로그로 원인 확인",
+		TaskDefaultTitle:     "업무 요청 정리",
+	},
+	telegramLangEN: {
+		ReplyLanguage: "English",
+		HelpFlow: []string{
+			"2) /prd refine (Codex asks for the missing context)",
+			"3) (optional) /prd priority to adjust the default priority per agent",
+			"4) answer prompts, then add stories",
+			"   - default: title -> description -> role (optional: priority)",
+			"   - quick: title | description | role [priority]",
+		},
+		StagePrompts: map[string]string{
+			telegramPRDStageAwaitProduct:     "Enter the product/project name",
+			telegramPRDStageAwaitProblem:     "Describe the problem (why is this work needed?)",
+			telegramPRDStageAwaitGoal:        "Enter the goal (one-line definition of done)",
+			telegramPRDStageAwaitInScope:     "Enter the in-scope items (must be done this cycle)",
+			telegramPRDStageAwaitOutOfScope:  "Enter the out-of-scope items (not done this cycle)",
+			telegramPRDStageAwaitAcceptance:  "Enter the acceptance criteria (verifiable)",
+			telegramPRDStageAwaitConstraints: "Enter constraints (optional, skip allowed)",
+			telegramPRDStageAwaitStoryTitle:  "Enter a story title (quick: title | description | role [priority])",
+			telegramPRDStageAwaitStoryDesc:   "Enter the story description",
+			telegramPRDStageAwaitStoryRole:   "Enter the role (manager|planner|developer|qa, optional: role priority)",
+			telegramPRDStageAwaitStoryPrio:   "Enter the priority (number, default=role default)",
+		},
+		DefaultAssumptions: map[string]string{
+			"problem":      "no specific product/operational pain point stated",
+			"goal":         "short-term goal is a first working automation loop",
+			"in_scope":     "initial release covers only the core user flows",
+			"out_of_scope": "large refactors and new infrastructure are excluded",
+			"acceptance":   "main scenarios succeed and failure recovery paths are verified",
+			"constraints":  "typical single-developer time/resource constraints assumed",
+		},
+		ClarityAssumptions: map[string]string{
+			"problem":      "on skip/default: assume fixing the current operational pain point comes first",
+			"goal":         "on skip/default: assume reaching a first stable operating state",
+			"in_scope":     "on skip/default: assume the core user flows",
+			"out_of_scope": "on skip/default: assume large refactors/infra changes are excluded",
+			"acceptance":   "on skip/default: assume core scenarios pass with no regressions",
+		},
+		FirstStoryPrompt:     "Enter the first user story title",
+		ReplaceAssumedPrompt: "Enter the real value for %s (currently an assumed value)",
+		NoStoriesNext:        "Enter a story title",
+		EditStoryNext:        "Enter the new title (keep=keep current value, quick: title | description | role [priority])",
+		TitleKeptNext:        "Enter the description (keep=keep current value)",
+		TitleSavedNext:       "Enter the description (quick: title | description | role [priority])",
+		DescKeptNext:         "Enter the role (manager|planner|developer|qa, optional: role priority, keep=keep current value)",
+		DescSavedNext:        "Enter the role (manager|planner|developer|qa, optional: role priority)",
+		QuickFormatError:     "quick format: title | description | role [priority] or title | description | role | priority",
+		StoryAddedNext:       "enter the next story title or /prd preview /prd save /prd apply",
+		StoryAddedRefineNext: "/prd refine (answer missing-context questions) or enter the next story title",
+		ScoreFailedReason:    "codex scoring failed",
+		ScoreFailedNext:      "retry `/prd score` after codex recovers",
+		ApplyBlockedReason:   "codex scoring failed, so the apply gate cannot be evaluated",
+		ApplyBlockedNext:     "retry `/prd score` or `/prd refine` after codex recovers",
+		RefineFailedReason:   "codex refine failed, so no follow-up question could be generated",
+		RefineFailedNext:     "retry `/prd refine` after codex recovers",
+		RefineHint:           "reply `skip` or `default` if unsure",
+		RefineFailedHint:     "check `/doctor` or the telegram tail log for the cause",
+		TaskDefaultTitle:     "Task request summary",
+	},
+}
+
+func normalizeTelegramLang(raw string) (string, error) {
+	switch lang := strings.ToLower(strings.TrimSpace(raw)); lang {
+	case "":
+		return telegramLangKO, nil
+	case telegramLangKO, telegramLangEN:
+		return lang, nil
+	default:
+		return "", fmt.Errorf("invalid telegram language: %s (expected en|ko)", raw)
+	}
+}
+
+// telegramLangFromEnv is the bot-wide language; `telegram run` exports its
+// resolved --lang so command handlers see the config file value too.
+func telegramLangFromEnv() string {
+	lang, err := normalizeTelegramLang(os.Getenv(telegramLangEnv))
+	if err != nil {
+		return telegramLangKO
+	}
+	return lang
+}
+
+func telegramTextFor(lang string) telegramPRDText {
+	if text, ok := telegramPRDTexts[lang]; ok {
+		return text
+	}
+	return telegramPRDTexts[telegramLangFromEnv()]
+}
+
+// telegramPRDSessionText keeps a session in the language it was started in;
+// sessions saved before the language setting fall back to the bot language.
+func telegramPRDSessionText(session telegramPRDSession) telegramPRDText {
+	return telegramTextFor(session.Lang)
+}
+
+func (t telegramPRDText) stagePrompt(stage string) string {
+	if prompt, ok := t.StagePrompts[stage]; ok {
+		return prompt
+	}
+	return "unknown stage"
+}
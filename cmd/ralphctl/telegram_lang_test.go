@@ -0,0 +1,64 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"codex-ralph/internal/ralph"
+)
+
+func TestTelegramPRDSessionFollowsTelegramLang(t *testing.T) {
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+
+	t.Setenv(telegramLangEnv, "")
+	reply, err := telegramPRDStartSession(paths, 1, "")
+	if err != nil {
+		t.Fatalf("start ko session failed: %v", err)
+	}
+	if !strings.Contains(reply, "제품/프로젝트 이름을 입력하세요") {
+		t.Fatalf("default language should stay korean: %q", reply)
+	}
+
+	t.Setenv(telegramLangEnv, "en")
+	reply, err = telegramPRDStartSession(paths, 2, "")
+	if err != nil {
+		t.Fatalf("start en session failed: %v", err)
+	}
+	if !strings.Contains(reply, "Enter the product/project name") {
+		t.Fatalf("expected english stage prompt: %q", reply)
+	}
+	session, found, err := telegramLoadPRDSession(paths, 2)
+	if err != nil || !found {
+		t.Fatalf("load en session failed: found=%t err=%v", found, err)
+	}
+	if session.Lang != telegramLangEN {
+		t.Fatalf("session lang mismatch: %q", session.Lang)
+	}
+	if prompt := buildTelegramPRDTurnPrompt(session, "hello", ""); !strings.Contains(prompt, "Reply in English") {
+		t.Fatalf("codex turn prompt should ask for english: %q", prompt)
+	}
+
+	// A session keeps its language even if the bot is restarted with another one.
+	t.Setenv(telegramLangEnv, "ko")
+	if prompt := buildTelegramPRDRefinePrompt(session, ""); !strings.Contains(prompt, "in English") {
+		t.Fatalf("refine prompt should keep the session language: %q", prompt)
+	}
+}
+
+func TestNormalizeTelegramLang(t *testing.T) {
+	t.Parallel()
+
+	for raw, want := range map[string]string{"": "ko", "KO": "ko", " en ": "en"} {
+		got, err := normalizeTelegramLang(raw)
+		if err != nil || got != want {
+			t.Fatalf("normalize %q: got=%q err=%v want=%q", raw, got, err, want)
+		}
+	}
+	if _, err := normalizeTelegramLang("jp"); err == nil {
+		t.Fatalf("expected error for unsupported language")
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,13 +19,13 @@ import (
 )
 
 func analyzeTelegramPRDTurnWithCodex(paths ralph.Paths, session telegramPRDSession, input string) (telegramPRDCodexTurnResponse, error) {
-	if _, err := exec.LookPath("codex"); err != nil {
-		return telegramPRDCodexTurnResponse{}, fmt.Errorf("codex command not found")
-	}
 	profile, err := ralph.LoadProfile(paths)
 	if err != nil {
 		return telegramPRDCodexTurnResponse{}, err
 	}
+	if err := ensureTelegramCodexCommand(profile); err != nil {
+		return telegramPRDCodexTurnResponse{}, err
+	}
 	if !profile.RequireCodex {
 		return telegramPRDCodexTurnResponse{}, fmt.Errorf("codex turn disabled (require_codex=false)")
 	}
@@ -62,7 +64,7 @@ func analyzeTelegramPRDTurnWithCodex(paths ralph.Paths, session telegramPRDSessi
 			lastErr = execErr
 		}
 		if attempt < retryAttempts {
-			time.Sleep(time.Duration(attempt*retryBackoffSec) * time.Second)
+			time.Sleep(ralph.ApplyRetryJitter(time.Duration(attempt*retryBackoffSec)*time.Second, profile.CodexRetryJitterPct))
 		}
 	}
 	return telegramPRDCodexTurnResponse{}, fmt.Errorf("codex turn retries exhausted: %w", lastErr)
@@ -84,7 +86,7 @@ func buildTelegramPRDTurnPrompt(session telegramPRDSession, userInput, conversat
 	fmt.Fprintln(&b, "- Never re-ask dimensions already clear in the current session.")
 	fmt.Fprintln(&b, "- If enough information for a story, set story with title+description+role. priority can be 0 when unknown.")
 	fmt.Fprintln(&b, "- role must be one of manager|planner|developer|qa.")
-	fmt.Fprintln(&b, "- Keep Korean concise and practical.")
+	fmt.Fprintf(&b, "- Reply in %s; keep it concise and practical.\n", telegramPRDSessionText(session).ReplyLanguage)
 	fmt.Fprintf(&b, "\nCurrent stage: %s\n", session.Stage)
 	fmt.Fprintln(&b, "\nCurrent session JSON:")
 	fmt.Fprintln(&b, string(payload))
@@ -134,11 +136,7 @@ func parseTelegramPRDCodexTurnResponse(raw string) (telegramPRDCodexTurnResponse
 		parsed.Story.Title = sanitizeTelegramPRDTurnText(parsed.Story.Title, 140)
 		parsed.Story.Description = sanitizeTelegramPRDTurnText(parsed.Story.Description, 320)
 		parsed.Story.Role = strings.ToLower(strings.TrimSpace(parsed.Story.Role))
-		if parsed.Story.Priority <= 0 {
-			parsed.Story.Priority = 0
-		} else {
-			parsed.Story.Priority = clampTelegramPRDStoryPriority(parsed.Story.Priority)
-		}
+		parsed.Story.Priority = clampTelegramPRDStoryPriority(parsed.Story.Priority)
 	}
 	return parsed, nil
 }
@@ -159,13 +157,13 @@ func sanitizeTelegramPRDTurnText(raw string, maxLen int) string {
 }
 
 func estimateTelegramPRDStoryPriorityWithCodex(paths ralph.Paths, session telegramPRDSession, story telegramPRDStory) (int, string, error) {
-	if _, err := exec.LookPath("codex"); err != nil {
-		return 0, "", fmt.Errorf("codex command not found")
-	}
 	profile, err := ralph.LoadProfile(paths)
 	if err != nil {
 		return 0, "", err
 	}
+	if err := ensureTelegramCodexCommand(profile); err != nil {
+		return 0, "", err
+	}
 	if !profile.RequireCodex {
 		return 0, "", fmt.Errorf("codex priority disabled (require_codex=false)")
 	}
@@ -185,7 +183,7 @@ func estimateTelegramPRDStoryPriorityWithCodex(paths ralph.Paths, session telegr
 		return 0, "", err
 	}
 	priority := parsed.Priority
-	if priority <= 0 {
+	if priority == 0 {
 		return 0, "", fmt.Errorf("invalid codex priority: %d", parsed.Priority)
 	}
 	return priority, "codex_auto", nil
@@ -200,9 +198,10 @@ func buildTelegramPRDStoryPriorityPrompt(session telegramPRDSession, story teleg
 	fmt.Fprintln(&b, `Schema: {"priority":1000,"reason":"..."}`)
 	fmt.Fprintln(&b, "Rules:")
 	fmt.Fprintln(&b, "- Lower number means higher priority.")
-	fmt.Fprintln(&b, "- Use integer range 100..3000.")
+	fmt.Fprintln(&b, "- Use integer range 100..3000; use a negative number only when the story must run before every queued issue.")
+	fmt.Fprintln(&b, "- Never return 0 (0 means default priority).")
 	fmt.Fprintln(&b, "- Consider role urgency, business risk, operational impact, and PRD context.")
-	fmt.Fprintln(&b, "- Keep reason concise in Korean.")
+	fmt.Fprintf(&b, "- Keep reason concise in %s.\n", telegramPRDSessionText(session).ReplyLanguage)
 	fmt.Fprintln(&b, "\nPRD Session JSON:")
 	fmt.Fprintln(&b, string(payload))
 	fmt.Fprintln(&b, "\nCandidate Story JSON:")
@@ -241,10 +240,10 @@ func parseTelegramPRDCodexStoryPriorityResponse(raw string) (telegramPRDCodexSto
 	return parsed, nil
 }
 
+// clampTelegramPRDStoryPriority caps codex estimates at 3000. There is no lower
+// bound: negative priorities are valid issue priorities that run ahead of
+// every positive one, and 0 keeps meaning "unknown, use the default".
 func clampTelegramPRDStoryPriority(v int) int {
-	if v < 100 {
-		return 100
-	}
 	if v > 3000 {
 		return 3000
 	}
@@ -252,13 +251,13 @@ func clampTelegramPRDStoryPriority(v int) int {
 }
 
 func analyzeTelegramPRDScoreWithCodex(paths ralph.Paths, session telegramPRDSession) (telegramPRDCodexScoreResponse, error) {
-	if _, err := exec.LookPath("codex"); err != nil {
-		return telegramPRDCodexScoreResponse{}, fmt.Errorf("codex command not found")
-	}
 	profile, err := ralph.LoadProfile(paths)
 	if err != nil {
 		return telegramPRDCodexScoreResponse{}, err
 	}
+	if err := ensureTelegramCodexCommand(profile); err != nil {
+		return telegramPRDCodexScoreResponse{}, err
+	}
 	if !profile.RequireCodex {
 		return telegramPRDCodexScoreResponse{}, fmt.Errorf("codex scoring disabled (require_codex=false)")
 	}
@@ -277,8 +276,42 @@ func analyzeTelegramPRDScoreWithCodex(paths ralph.Paths, session telegramPRDSess
 	return parseTelegramPRDCodexScoreResponse(raw)
 }
 
+// telegramPRDScoreContentHash covers what the codex score is judged on (product,
+// stories and context), so any mutation of those invalidates a cached score.
+func telegramPRDScoreContentHash(session telegramPRDSession) string {
+	data, err := json.Marshal(struct {
+		ProductName string             `json:"product_name"`
+		Stories     []telegramPRDStory `json:"stories"`
+		Context     telegramPRDContext `json:"context"`
+	}{session.ProductName, session.Stories, session.Context})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// cachedTelegramPRDScoreValid reports whether the stored codex score was computed
+// for the session's current content.
+func cachedTelegramPRDScoreValid(session telegramPRDSession) bool {
+	if strings.TrimSpace(session.CodexScoredAtUT) == "" || session.CodexScoreHash == "" {
+		return false
+	}
+	return session.CodexScoreHash == telegramPRDScoreContentHash(session)
+}
+
+// refreshTelegramPRDScoreCached reuses the stored codex score when the session
+// content is unchanged since it was scored; cached reports whether codex was skipped.
+func refreshTelegramPRDScoreCached(paths ralph.Paths, session telegramPRDSession) (telegramPRDSession, bool, bool, error) {
+	if cachedTelegramPRDScoreValid(session) {
+		return session, true, true, nil
+	}
+	updated, used, err := refreshTelegramPRDScoreWithCodex(paths, session)
+	return updated, used, false, err
+}
+
 func refreshTelegramPRDScoreWithCodex(paths ralph.Paths, session telegramPRDSession) (telegramPRDSession, bool, error) {
-	score, err := analyzeTelegramPRDScoreWithCodex(paths, session)
+	score, err := telegramPRDScoreAnalyzer(paths, session)
 	if err != nil {
 		return session, false, err
 	}
@@ -287,6 +320,7 @@ func refreshTelegramPRDScoreWithCodex(paths ralph.Paths, session telegramPRDSess
 	session.CodexMissing = sanitizeTelegramPRDMissingList(score.Missing)
 	session.CodexSummary = strings.TrimSpace(score.Summary)
 	session.CodexScoredAtUT = time.Now().UTC().Format(time.RFC3339)
+	session.CodexScoreHash = telegramPRDScoreContentHash(session)
 	return session, true, nil
 }
 
@@ -300,6 +334,8 @@ func refreshTelegramPRDRefineWithCodex(paths ralph.Paths, session telegramPRDSes
 	session.CodexMissing = sanitizeTelegramPRDMissingList(refine.Missing)
 	session.CodexSummary = compactSingleLine(strings.TrimSpace(refine.Reason), 200)
 	session.CodexScoredAtUT = time.Now().UTC().Format(time.RFC3339)
+	// Refine scores with a different prompt, so it never feeds the apply cache.
+	session.CodexScoreHash = ""
 	refine.Score = session.CodexScore
 	refine.ReadyToApply = session.CodexReady
 	refine.Missing = append([]string(nil), session.CodexMissing...)
@@ -307,13 +343,13 @@ func refreshTelegramPRDRefineWithCodex(paths ralph.Paths, session telegramPRDSes
 }
 
 func analyzeTelegramPRDRefineWithCodex(paths ralph.Paths, session telegramPRDSession) (telegramPRDCodexRefineResponse, error) {
-	if _, err := exec.LookPath("codex"); err != nil {
-		return telegramPRDCodexRefineResponse{}, fmt.Errorf("codex command not found")
-	}
 	profile, err := ralph.LoadProfile(paths)
 	if err != nil {
 		return telegramPRDCodexRefineResponse{}, err
 	}
+	if err := ensureTelegramCodexCommand(profile); err != nil {
+		return telegramPRDCodexRefineResponse{}, err
+	}
 	if !profile.RequireCodex {
 		return telegramPRDCodexRefineResponse{}, fmt.Errorf("codex refine disabled (require_codex=false)")
 	}
@@ -351,12 +387,49 @@ func analyzeTelegramPRDRefineWithCodex(paths ralph.Paths, session telegramPRDSes
 			lastErr = execErr
 		}
 		if attempt < retryAttempts {
-			time.Sleep(time.Duration(attempt*retryBackoffSec) * time.Second)
+			time.Sleep(ralph.ApplyRetryJitter(time.Duration(attempt*retryBackoffSec)*time.Second, profile.CodexRetryJitterPct))
 		}
 	}
 	return telegramPRDCodexRefineResponse{}, fmt.Errorf("codex refine retries exhausted: %w", lastErr)
 }
 
+// ensureTelegramCodexCommand requires the codex binary unless codex_dry_run is on,
+// in which case nothing is executed.
+func ensureTelegramCodexCommand(profile ralph.Profile) error {
+	if profile.CodexDryRun {
+		return nil
+	}
+	if _, err := exec.LookPath("codex"); err != nil {
+		return fmt.Errorf("codex command not found")
+	}
+	return nil
+}
+
+// telegramCodexDryRunResponse logs the prompt and returns one canned JSON object
+// carrying the fields every telegram codex parser reads.
+func telegramCodexDryRunResponse(paths ralph.Paths, tmpPrefix string, args []string, prompt string) (string, error) {
+	kind := strings.TrimSuffix(strings.TrimPrefix(tmpPrefix, "ralph-"), "-*")
+	recordPath, err := ralph.WriteCodexDryRunRecord(paths, kind, args, prompt)
+	if err != nil {
+		return "", err
+	}
+	note := "codex dry-run: prompt written to " + recordPath
+	data, err := json.Marshal(map[string]any{
+		"reply":          note,
+		"ask":            note,
+		"summary":        note,
+		"reason":         "codex dry-run",
+		"score":          0,
+		"ready_to_apply": false,
+		"missing":        []string{"codex dry-run"},
+		"priority":       telegramPRDDefaultPriority,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 func runTelegramPRDCodexExec(
 	ctx context.Context,
 	paths ralph.Paths,
@@ -377,6 +450,9 @@ func runTelegramPRDCodexExec(
 	outPath := filepath.Join(tmpDir, "assistant-last-message.txt")
 	projectDir, hasProjectDir := resolveTelegramCodexProjectDir(paths.ProjectDir)
 	args := buildTelegramCodexExecArgs(profile, model, projectDir, outPath)
+	if profile.CodexDryRun {
+		return telegramCodexDryRunResponse(paths, tmpPrefix, args, prompt)
+	}
 
 	cmd := exec.CommandContext(ctx, "codex", args...)
 	if hasProjectDir {
@@ -556,7 +632,7 @@ func buildTelegramPRDRefinePrompt(session telegramPRDSession, conversationTail s
 	fmt.Fprintln(&b, "Rules:")
 	fmt.Fprintln(&b, "- score must be 0..100 and reflect execution readiness.")
 	fmt.Fprintf(&b, "- ready_to_apply=true only when score>=%d and critical context is sufficient.\n", telegramPRDClarityMinScore)
-	fmt.Fprintln(&b, "- ask must be ONE concrete next question in Korean (not a list).")
+	fmt.Fprintf(&b, "- ask must be ONE concrete next question in %s (not a list).\n", telegramPRDSessionText(session).ReplyLanguage)
 	fmt.Fprintln(&b, "- missing should include top missing/weak items.")
 	fmt.Fprintln(&b, "- suggested_stage should be one of:")
 	fmt.Fprintln(&b, "  await_product, await_problem, await_goal, await_in_scope, await_out_of_scope, await_acceptance, await_constraints, await_story_title")
@@ -628,7 +704,7 @@ func buildTelegramPRDScorePrompt(session telegramPRDSession, conversationTail st
 	fmt.Fprintln(&b, "- Must consider: problem, goal, in-scope, out-of-scope, acceptance, stories quality.")
 	fmt.Fprintf(&b, "- ready_to_apply=true only when score>=%d and no critical missing context.\n", telegramPRDClarityMinScore)
 	fmt.Fprintln(&b, "- missing should contain the top missing/weak items.")
-	fmt.Fprintln(&b, "- summary should be concise, practical, in Korean.")
+	fmt.Fprintf(&b, "- summary should be concise, practical, in %s.\n", telegramPRDSessionText(session).ReplyLanguage)
 	fmt.Fprintln(&b, "\nSession JSON:")
 	fmt.Fprintln(&b, string(payload))
 	if strings.TrimSpace(conversationTail) != "" {
@@ -54,3 +54,29 @@ func TestBuildTelegramCodexExecArgsOmitsCDWhenProjectMissing(t *testing.T) {
 		t.Fatalf("args should contain output path: %v", args)
 	}
 }
+
+func TestTelegramPRDScoreCodexDryRunReturnsCannedResponse(t *testing.T) {
+	t.Setenv("RALPH_CODEX_DRY_RUN", "true")
+	t.Setenv("PATH", t.TempDir())
+
+	paths, err := ralph.NewPaths(filepath.Join(t.TempDir(), "control"), filepath.Join(t.TempDir(), "project"))
+	if err != nil {
+		t.Fatalf("new paths failed: %v", err)
+	}
+	session := telegramPRDSession{ChatID: 5, ProductName: "Wallet"}
+	score, err := analyzeTelegramPRDScoreWithCodex(paths, session)
+	if err != nil {
+		t.Fatalf("dry-run score should not need codex: %v", err)
+	}
+	if score.ReadyToApply || !strings.Contains(score.Summary, "codex dry-run") {
+		t.Fatalf("canned score mismatch: %+v", score)
+	}
+	records, _ := filepath.Glob(filepath.Join(ralph.CodexDryRunDir(paths), "*-telegram-prd-score-*.md"))
+	if len(records) != 1 {
+		t.Fatalf("expected one dry-run record, got %v", records)
+	}
+	data, err := os.ReadFile(records[0])
+	if err != nil || !strings.Contains(string(data), "Wallet") {
+		t.Fatalf("record should contain the score prompt: err=%v", err)
+	}
+}
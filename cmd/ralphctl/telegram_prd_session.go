@@ -3,14 +3,13 @@ package main
 import (
 	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 	"unicode/utf8"
 
@@ -59,10 +58,12 @@ type telegramPRDContext struct {
 	Constraints   string         `json:"constraints,omitempty"`
 	Assumptions   []string       `json:"assumptions,omitempty"`
 	AgentPriority map[string]int `json:"agent_priority,omitempty"`
+	Attachments   []string       `json:"attachments,omitempty"`
 }
 
 type telegramPRDSession struct {
 	ChatID          int64              `json:"chat_id"`
+	Lang            string             `json:"lang,omitempty"`
 	Stage           string             `json:"stage"`
 	ProductName     string             `json:"product_name"`
 	Stories         []telegramPRDStory `json:"stories"`
@@ -70,11 +71,13 @@ type telegramPRDSession struct {
 	DraftTitle      string             `json:"draft_title,omitempty"`
 	DraftDesc       string             `json:"draft_desc,omitempty"`
 	DraftRole       string             `json:"draft_role,omitempty"`
+	EditIndex       int                `json:"edit_index,omitempty"`
 	CodexScore      int                `json:"codex_score,omitempty"`
 	CodexReady      bool               `json:"codex_ready,omitempty"`
 	CodexMissing    []string           `json:"codex_missing,omitempty"`
 	CodexSummary    string             `json:"codex_summary,omitempty"`
 	CodexScoredAtUT string             `json:"codex_scored_at_utc,omitempty"`
+	CodexScoreHash  string             `json:"codex_score_hash,omitempty"`
 	Approved        bool               `json:"approved,omitempty"`
 	CreatedAtUTC    string             `json:"created_at_utc,omitempty"`
 	LastUpdatedAtUT string             `json:"last_updated_at_utc,omitempty"`
@@ -139,6 +142,8 @@ type telegramPRDCodexRefineResponse struct {
 
 type telegramPRDSessionStore struct {
 	Sessions map[string]telegramPRDSession `json:"sessions"`
+	// Drafts holds named, parked sessions per chat key: chat -> name -> session.
+	Drafts map[string]map[string]telegramPRDSession `json:"drafts,omitempty"`
 }
 
 var telegramPRDSessionStoreMu sync.Mutex
@@ -172,10 +177,22 @@ func telegramPRDCommand(paths ralph.Paths, chatID int64, rawArgs string) (string
 		reply, err = telegramPRDPreviewSession(paths, chatID)
 	case "priority":
 		reply, err = telegramPRDPrioritySession(paths, chatID, arg)
+	case "attach":
+		reply, err = telegramPRDAttachSession(paths, chatID, arg)
 	case "save":
 		reply, err = telegramPRDSaveSession(paths, chatID, arg)
 	case "apply":
 		reply, err = telegramPRDApplySession(paths, chatID, arg)
+	case "save-draft":
+		reply, err = telegramPRDSaveDraft(paths, chatID, arg)
+	case "list":
+		reply, err = telegramPRDListDrafts(paths, chatID)
+	case "resume":
+		reply, err = telegramPRDResumeDraft(paths, chatID, arg)
+	case "edit":
+		reply, err = telegramPRDEditStory(paths, chatID, arg)
+	case "remove":
+		reply, err = telegramPRDRemoveStory(paths, chatID, arg)
 	case "cancel", "stop":
 		reply, err = telegramPRDCancelSession(paths, chatID)
 	default:
@@ -194,7 +211,7 @@ func telegramPRDCommand(paths ralph.Paths, chatID int64, rawArgs string) (string
 }
 
 func telegramPRDHelp() string {
-	return strings.Join([]string{
+	lines := []string{
 		"Ralph PRD Wizard",
 		"================",
 		"",
@@ -203,27 +220,33 @@ func telegramPRDHelp() string {
 		"- /prd refine",
 		"- /prd score",
 		"- /prd preview",
+		"- /prd edit <story_index>",
+		"- /prd remove <story_index>",
 		"- /prd priority [manager=900 planner=950 developer=1000 qa=1100|default]",
+		"- /prd attach [path|clear]",
 		"- /prd save [file]",
 		"- /prd apply [file]",
+		"- /prd save-draft <name>",
+		"- /prd list",
+		"- /prd resume <name>",
 		"- /prd cancel",
 		"",
 		"Flow",
 		"1) /prd start",
-		"2) /prd refine (Codex가 부족한 컨텍스트를 동적으로 질문)",
-		"3) (optional) /prd priority 로 에이전트별 기본 priority 조정",
-		"4) answer prompts, then add stories",
-		"   - 기본: title -> description -> role(선택: priority)",
-		"   - 빠른 입력: title | description | role [priority]",
+	}
+	lines = append(lines, telegramTextFor(telegramLangFromEnv()).HelpFlow...)
+	lines = append(lines,
 		"5) /prd score or /prd preview",
 		"6) /prd apply",
-	}, "\n")
+	)
+	return strings.Join(lines, "\n")
 }
 
 func telegramPRDStartSession(paths ralph.Paths, chatID int64, productName string) (string, error) {
 	now := time.Now().UTC().Format(time.RFC3339)
 	session := telegramPRDSession{
 		ChatID:      chatID,
+		Lang:        telegramLangFromEnv(),
 		Stage:       telegramPRDStageAwaitProduct,
 		ProductName: "",
 		Stories:     []telegramPRDStory{},
@@ -248,7 +271,7 @@ func telegramPRDStartSession(paths ralph.Paths, chatID int64, productName string
 	if session.Stage == telegramPRDStageAwaitProblem {
 		return fmt.Sprintf("PRD wizard started\n- product: %s\n- next: /prd refine", session.ProductName), nil
 	}
-	return "PRD wizard started\n- next: 제품/프로젝트 이름을 입력하세요", nil
+	return "PRD wizard started\n- next: " + telegramPRDSessionText(session).stagePrompt(session.Stage), nil
 }
 
 func telegramPRDDefaultPriorityForRole(role string) int {
@@ -394,6 +417,51 @@ func telegramPRDPrioritySession(paths ralph.Paths, chatID int64, raw string) (st
 	return fmt.Sprintf("agent priorities updated\n- current: %s", formatTelegramPRDAgentPriorityInline(session.Context.AgentPriority)), nil
 }
 
+// telegramPRDAttachSession records project files whose content apply copies
+// (size-capped) into every generated issue body.
+func telegramPRDAttachSession(paths ralph.Paths, chatID int64, raw string) (string, error) {
+	session, found, err := telegramLoadPRDSession(paths, chatID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "no active PRD session\n- run: /prd start", nil
+	}
+	arg := strings.TrimSpace(raw)
+	if arg == "" {
+		lines := []string{"prd attachments", fmt.Sprintf("- count: %d", len(session.Context.Attachments))}
+		for _, attachment := range session.Context.Attachments {
+			lines = append(lines, "- file: "+attachment)
+		}
+		lines = append(lines, "- add: /prd attach docs/design.md", "- clear: /prd attach clear")
+		return strings.Join(lines, "\n"), nil
+	}
+	if strings.EqualFold(arg, "clear") {
+		session.Context.Attachments = nil
+		session.LastUpdatedAtUT = time.Now().UTC().Format(time.RFC3339)
+		if err := telegramUpsertPRDSession(paths, session); err != nil {
+			return "", err
+		}
+		return "prd attachments cleared", nil
+	}
+
+	rel, _, err := ralph.ResolvePRDAttachment(paths, arg)
+	if err != nil {
+		return "", err
+	}
+	for _, existing := range session.Context.Attachments {
+		if existing == rel {
+			return fmt.Sprintf("prd attachment already recorded\n- file: %s", rel), nil
+		}
+	}
+	session.Context.Attachments = append(session.Context.Attachments, rel)
+	session.LastUpdatedAtUT = time.Now().UTC().Format(time.RFC3339)
+	if err := telegramUpsertPRDSession(paths, session); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("prd attachment added\n- file: %s\n- attachments: %d\n- max_bytes_per_file: %d", rel, len(session.Context.Attachments), ralph.PRDAttachmentMaxBytes), nil
+}
+
 func telegramPRDStoryPriorityForRole(session telegramPRDSession, role string) int {
 	role = strings.ToLower(strings.TrimSpace(role))
 	if v := session.Context.AgentPriority[role]; v > 0 {
@@ -405,7 +473,7 @@ func telegramPRDStoryPriorityForRole(session telegramPRDSession, role string) in
 func resolveTelegramPRDStoryPriority(paths ralph.Paths, session telegramPRDSession, story telegramPRDStory) (int, string) {
 	fallback := telegramPRDStoryPriorityForRole(session, story.Role)
 	priority, source, err := telegramPRDStoryPriorityEstimator(paths, session, story)
-	if err != nil || priority <= 0 {
+	if err != nil || priority == 0 {
 		return fallback, "fallback_role_profile"
 	}
 	return priority, source
@@ -436,14 +504,14 @@ func telegramPRDRefineSession(paths ralph.Paths, chatID int64) (string, error) {
 		if err := telegramUpsertPRDSession(paths, session); err != nil {
 			return "", err
 		}
-		return formatTelegramPRDCodexRefineQuestion(codexRefine), nil
+		return formatTelegramPRDCodexRefineQuestion(telegramPRDSessionText(session), codexRefine), nil
 	}
 
 	status := evaluateTelegramPRDClarity(session)
 	if codexRefineErr != nil {
 		fmt.Fprintf(os.Stderr, "[telegram] prd refine codex fallback: %v\n", codexRefineErr)
 	}
-	return formatTelegramPRDRefineUnavailable(session.Stage, status.Score, codexRefineErr), nil
+	return formatTelegramPRDRefineUnavailable(telegramPRDSessionText(session), session.Stage, status.Score, codexRefineErr), nil
 }
 
 func telegramPRDScoreSession(paths ralph.Paths, chatID int64) (string, error) {
@@ -463,11 +531,12 @@ func telegramPRDScoreSession(paths ralph.Paths, chatID int64) (string, error) {
 		return formatTelegramPRDCodexScore(updated), nil
 	}
 	category, detail := classifyTelegramCodexFailure(scoreErr)
+	text := telegramPRDSessionText(session)
 	lines := []string{
 		"prd score unavailable",
 		"- scoring_mode: codex_unavailable",
-		"- reason: codex scoring 실패",
-		"- next: codex 상태 복구 후 `/prd score` 재시도",
+		"- reason: " + text.ScoreFailedReason,
+		"- next: " + text.ScoreFailedNext,
 	}
 	if category != "" {
 		lines = append(lines, "- codex_error: "+category)
@@ -534,6 +603,9 @@ func telegramPRDPreviewSession(paths ralph.Paths, chatID int64) (string, error)
 	if len(session.Context.Assumptions) > 0 {
 		fmt.Fprintf(&b, "- assumptions: %d\n", len(session.Context.Assumptions))
 	}
+	if len(session.Context.Attachments) > 0 {
+		fmt.Fprintf(&b, "- attachments: %s\n", strings.Join(session.Context.Attachments, ", "))
+	}
 	maxRows := len(session.Stories)
 	if maxRows > 10 {
 		maxRows = 10
@@ -555,7 +627,7 @@ func telegramPRDPreviewSession(paths ralph.Paths, chatID int64) (string, error)
 			fmt.Fprintf(&b, "  - %s\n", m)
 		}
 	}
-	fmt.Fprintf(&b, "- next: %s\n", telegramPRDStagePrompt(session.Stage))
+	fmt.Fprintf(&b, "- next: %s\n", telegramPRDSessionText(session).stagePrompt(session.Stage))
 	return b.String(), nil
 }
 
@@ -592,9 +664,9 @@ func telegramPRDApplySession(paths ralph.Paths, chatID int64, rawPath string) (s
 		return "", fmt.Errorf("no stories in session yet")
 	}
 
-	// Prefer codex-based scoring when available.
-	sessionForGate, usedCodexGate, codexScoreErr := refreshTelegramPRDScoreWithCodex(paths, session)
-	if codexScoreErr == nil && usedCodexGate {
+	// Prefer codex-based scoring when available; an unchanged session reuses the last score.
+	sessionForGate, usedCodexGate, cachedGate, codexScoreErr := refreshTelegramPRDScoreCached(paths, session)
+	if codexScoreErr == nil && usedCodexGate && !cachedGate {
 		session = sessionForGate
 		if err := telegramUpsertPRDSession(paths, session); err != nil {
 			return "", err
@@ -603,11 +675,12 @@ func telegramPRDApplySession(paths ralph.Paths, chatID int64, rawPath string) (s
 
 	if codexScoreErr != nil {
 		category, detail := classifyTelegramCodexFailure(codexScoreErr)
+		text := telegramPRDSessionText(session)
 		lines := []string{
 			"prd apply blocked",
 			"- scoring_mode: codex_unavailable",
-			"- reason: codex scoring 실패로 apply gate 판단 불가",
-			"- next: codex 상태 복구 후 `/prd score` 또는 `/prd refine` 재시도",
+			"- reason: " + text.ApplyBlockedReason,
+			"- next: " + text.ApplyBlockedNext,
 		}
 		if category != "" {
 			lines = append(lines, "- codex_error: "+category)
@@ -629,11 +702,15 @@ func telegramPRDApplySession(paths ralph.Paths, chatID int64, rawPath string) (s
 		if len(missingForReply) > 0 {
 			missingPreview = compactSingleLine(strings.Join(missingForReply, ", "), 180)
 		}
+		scoringMode := "codex"
+		if cachedGate {
+			scoringMode = "codex(cached)"
+		}
 		return strings.Join([]string{
 			"prd apply blocked",
 			fmt.Sprintf("- clarity_score: %d/100", scoreForReply),
 			fmt.Sprintf("- clarity_gate: %d", telegramPRDClarityMinScore),
-			"- scoring_mode: codex",
+			"- scoring_mode: " + scoringMode,
 			"- reason: missing required context",
 			fmt.Sprintf("- missing: %s", missingPreview),
 			"- next: /prd refine",
@@ -672,6 +749,190 @@ func telegramPRDCancelSession(paths ralph.Paths, chatID int64) (string, error) {
 	return "PRD session canceled", nil
 }
 
+func normalizeTelegramPRDDraftName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" {
+		return "", fmt.Errorf("draft name is required")
+	}
+	if len(strings.Fields(name)) != 1 || utf8.RuneCountInString(name) > 64 {
+		return "", fmt.Errorf("invalid draft name: %q (single word, max 64 chars)", name)
+	}
+	return name, nil
+}
+
+func telegramPRDSaveDraft(paths ralph.Paths, chatID int64, rawName string) (string, error) {
+	name, err := normalizeTelegramPRDDraftName(rawName)
+	if err != nil {
+		return "usage: /prd save-draft <name>\n- " + err.Error(), nil
+	}
+	session, found, err := telegramLoadPRDSession(paths, chatID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "no active PRD session\n- run: /prd start", nil
+	}
+	replaced, err := telegramSavePRDDraft(paths, chatID, name, session)
+	if err != nil {
+		return "", err
+	}
+	action := "saved"
+	if replaced {
+		action = "updated"
+	}
+	return strings.Join([]string{
+		fmt.Sprintf("PRD draft %s", action),
+		fmt.Sprintf("- name: %s", name),
+		fmt.Sprintf("- product: %s", valueOrDash(strings.TrimSpace(session.ProductName))),
+		fmt.Sprintf("- stories: %d", len(session.Stories)),
+		"- next: keep editing, /prd start for a new draft, or /prd resume " + name + " later",
+	}, "\n"), nil
+}
+
+func telegramPRDListDrafts(paths ralph.Paths, chatID int64) (string, error) {
+	drafts, err := telegramLoadPRDDrafts(paths, chatID)
+	if err != nil {
+		return "", err
+	}
+	if len(drafts) == 0 {
+		return "no saved PRD drafts\n- save: /prd save-draft <name>", nil
+	}
+	names := make([]string, 0, len(drafts))
+	for name := range drafts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := []string{fmt.Sprintf("PRD drafts (%d)", len(names))}
+	for _, name := range names {
+		draft := drafts[name]
+		lines = append(lines, fmt.Sprintf(
+			"- %s: product=%s stories=%d stage=%s updated=%s",
+			name,
+			valueOrDash(strings.TrimSpace(draft.ProductName)),
+			len(draft.Stories),
+			draft.Stage,
+			valueOrDash(draft.LastUpdatedAtUT),
+		))
+	}
+	lines = append(lines, "- resume: /prd resume <name>")
+	return strings.Join(lines, "\n"), nil
+}
+
+func telegramPRDResumeDraft(paths ralph.Paths, chatID int64, rawName string) (string, error) {
+	name, err := normalizeTelegramPRDDraftName(rawName)
+	if err != nil {
+		return "usage: /prd resume <name>\n- " + err.Error(), nil
+	}
+	drafts, err := telegramLoadPRDDrafts(paths, chatID)
+	if err != nil {
+		return "", err
+	}
+	draft, ok := drafts[name]
+	if !ok {
+		return fmt.Sprintf("PRD draft not found: %s\n- list: /prd list", name), nil
+	}
+	draft.ChatID = chatID
+	draft.LastUpdatedAtUT = time.Now().UTC().Format(time.RFC3339)
+	if err := telegramUpsertPRDSession(paths, draft); err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		"PRD draft resumed",
+		fmt.Sprintf("- name: %s", name),
+		fmt.Sprintf("- product: %s", valueOrDash(strings.TrimSpace(draft.ProductName))),
+		fmt.Sprintf("- stories: %d", len(draft.Stories)),
+		fmt.Sprintf("- next: %s", telegramPRDSessionText(draft).stagePrompt(draft.Stage)),
+	}, "\n"), nil
+}
+
+func isTelegramPRDKeepInput(input string) bool {
+	return strings.EqualFold(strings.TrimSpace(input), "keep")
+}
+
+func telegramPRDEditStory(paths ralph.Paths, chatID int64, rawIndex string) (string, error) {
+	session, found, err := telegramLoadPRDSession(paths, chatID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "no active PRD session\n- run: /prd start", nil
+	}
+	if len(session.Stories) == 0 {
+		return "no stories to edit\n- next: " + telegramPRDSessionText(session).NoStoriesNext, nil
+	}
+	idx, err := strconv.Atoi(strings.TrimSpace(rawIndex))
+	if err != nil || idx < 1 || idx > len(session.Stories) {
+		return "", fmt.Errorf("usage: /prd edit <story_index> (1-%d)", len(session.Stories))
+	}
+	story := session.Stories[idx-1]
+	session.DraftTitle = story.Title
+	session.DraftDesc = story.Description
+	session.DraftRole = story.Role
+	session.EditIndex = idx
+	session.Stage = telegramPRDStageAwaitStoryTitle
+	session.Approved = false
+	session.LastUpdatedAtUT = time.Now().UTC().Format(time.RFC3339)
+	if err := telegramUpsertPRDSession(paths, session); err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		fmt.Sprintf("editing story %d", idx),
+		fmt.Sprintf("- id: %s", story.ID),
+		fmt.Sprintf("- title: %s", compactSingleLine(story.Title, 90)),
+		fmt.Sprintf("- description: %s", compactSingleLine(story.Description, 120)),
+		fmt.Sprintf("- role: %s", story.Role),
+		fmt.Sprintf("- priority: %d", story.Priority),
+		"- next: " + telegramPRDSessionText(session).EditStoryNext,
+	}, "\n"), nil
+}
+
+func telegramPRDRemoveStory(paths ralph.Paths, chatID int64, rawIndex string) (string, error) {
+	session, found, err := telegramLoadPRDSession(paths, chatID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "no active PRD session\n- run: /prd start", nil
+	}
+	if len(session.Stories) == 0 {
+		return "no stories to remove", nil
+	}
+	idx, err := strconv.Atoi(strings.TrimSpace(rawIndex))
+	if err != nil || idx < 1 || idx > len(session.Stories) {
+		return "", fmt.Errorf("usage: /prd remove <story_index> (1-%d)", len(session.Stories))
+	}
+	removed := session.Stories[idx-1]
+	session.Stories = append(session.Stories[:idx-1:idx-1], session.Stories[idx:]...)
+	switch {
+	case session.EditIndex == idx:
+		session.EditIndex = 0
+		session.DraftTitle = ""
+		session.DraftDesc = ""
+		session.DraftRole = ""
+		session.Stage = telegramPRDStageAwaitStoryTitle
+	case session.EditIndex > idx:
+		session.EditIndex--
+	}
+	session.Approved = false
+	session.LastUpdatedAtUT = time.Now().UTC().Format(time.RFC3339)
+	if err := telegramUpsertPRDSession(paths, session); err != nil {
+		return "", err
+	}
+
+	clarity := evaluateTelegramPRDClarity(session)
+	var b strings.Builder
+	fmt.Fprintln(&b, "story removed")
+	fmt.Fprintf(&b, "- id: %s\n", removed.ID)
+	fmt.Fprintf(&b, "- title: %s\n", compactSingleLine(removed.Title, 90))
+	fmt.Fprintf(&b, "- stories_total: %d\n", len(session.Stories))
+	for i, s := range session.Stories {
+		fmt.Fprintf(&b, "- [%d] %s | %s | role=%s | priority=%d\n", i+1, s.ID, compactSingleLine(s.Title, 70), s.Role, s.Priority)
+	}
+	fmt.Fprintf(&b, "- clarity_score: %d/100\n", clarity.Score)
+	fmt.Fprintf(&b, "- next: %s", telegramPRDSessionText(session).stagePrompt(session.Stage))
+	return b.String(), nil
+}
+
 func telegramPRDHandleInput(paths ralph.Paths, chatID int64, input string) (string, error) {
 	session, found, err := telegramLoadPRDSession(paths, chatID)
 	if err != nil {
@@ -739,28 +1000,29 @@ func applyTelegramPRDCodexTurn(paths ralph.Paths, session telegramPRDSession, tu
 		updatedFields = append(updatedFields, field)
 	}
 
+	text := telegramPRDSessionText(session)
 	patch := turn.SessionPatch
 	productName := strings.TrimSpace(patch.ProductName)
 	if productName != "" && productName != strings.TrimSpace(session.ProductName) {
 		session.ProductName = productName
 		appendUpdated("product")
 	}
-	if applyTelegramPRDContextPatch(&session.Context, "problem", &session.Context.Problem, patch.Problem, "현재 기능/운영상 pain point는 명시되지 않음") {
+	if applyTelegramPRDContextPatch(&session.Context, "problem", &session.Context.Problem, patch.Problem, text.DefaultAssumptions["problem"]) {
 		appendUpdated("problem")
 	}
-	if applyTelegramPRDContextPatch(&session.Context, "goal", &session.Context.Goal, patch.Goal, "단기 목표는 첫 동작 가능한 자동화 루프 확보") {
+	if applyTelegramPRDContextPatch(&session.Context, "goal", &session.Context.Goal, patch.Goal, text.DefaultAssumptions["goal"]) {
 		appendUpdated("goal")
 	}
-	if applyTelegramPRDContextPatch(&session.Context, "in_scope", &session.Context.InScope, patch.InScope, "초기 릴리즈에서는 핵심 사용자 흐름만 포함") {
+	if applyTelegramPRDContextPatch(&session.Context, "in_scope", &session.Context.InScope, patch.InScope, text.DefaultAssumptions["in_scope"]) {
 		appendUpdated("in_scope")
 	}
-	if applyTelegramPRDContextPatch(&session.Context, "out_of_scope", &session.Context.OutOfScope, patch.OutOfScope, "대규모 리팩터/새 인프라 구축은 제외") {
+	if applyTelegramPRDContextPatch(&session.Context, "out_of_scope", &session.Context.OutOfScope, patch.OutOfScope, text.DefaultAssumptions["out_of_scope"]) {
 		appendUpdated("out_of_scope")
 	}
-	if applyTelegramPRDContextPatch(&session.Context, "acceptance", &session.Context.Acceptance, patch.Acceptance, "주요 시나리오 성공 + 실패 시 복구 경로 확인") {
+	if applyTelegramPRDContextPatch(&session.Context, "acceptance", &session.Context.Acceptance, patch.Acceptance, text.DefaultAssumptions["acceptance"]) {
 		appendUpdated("acceptance")
 	}
-	if applyTelegramPRDContextPatch(&session.Context, "constraints", &session.Context.Constraints, patch.Constraints, "시간/리소스 제약은 일반적인 단일 개발자 환경 가정") {
+	if applyTelegramPRDContextPatch(&session.Context, "constraints", &session.Context.Constraints, patch.Constraints, text.DefaultAssumptions["constraints"]) {
 		appendUpdated("constraints")
 	}
 
@@ -849,7 +1111,7 @@ func formatTelegramPRDCodexTurnReply(session telegramPRDSession, turn telegramPR
 	}
 	if nextQuestion == "" && !status.ReadyToApply {
 		if strings.TrimSpace(status.NextStage) != "" {
-			nextQuestion = telegramPRDStagePrompt(status.NextStage)
+			nextQuestion = telegramPRDSessionText(session).stagePrompt(status.NextStage)
 		}
 	}
 	if nextQuestion != "" {
@@ -864,9 +1126,10 @@ func formatTelegramPRDCodexTurnReply(session telegramPRDSession, turn telegramPR
 func advanceTelegramPRDSession(paths ralph.Paths, session telegramPRDSession, input string) (telegramPRDSession, string, error) {
 	session.LastUpdatedAtUT = time.Now().UTC().Format(time.RFC3339)
 	session.Approved = false
+	text := telegramPRDSessionText(session)
 	input = strings.TrimSpace(input)
 	if input == "" {
-		return session, telegramPRDStagePrompt(session.Stage), nil
+		return session, text.stagePrompt(session.Stage), nil
 	}
 
 	switch session.Stage {
@@ -880,36 +1143,40 @@ func advanceTelegramPRDSession(paths ralph.Paths, session telegramPRDSession, in
 		return session, fmt.Sprintf("product set: %s\n- next: /prd refine", session.ProductName), nil
 
 	case telegramPRDStageAwaitProblem:
-		session.Context.Problem = normalizeTelegramPRDContextAnswer(input, "현재 기능/운영상 pain point는 명시되지 않음")
+		session.Context.Problem = normalizeTelegramPRDContextAnswer(input, text.DefaultAssumptions["problem"])
 		recordTelegramPRDAssumption(&session.Context, "problem", session.Context.Problem)
 		return advanceTelegramPRDRefineFlow(paths, session)
 
 	case telegramPRDStageAwaitGoal:
-		session.Context.Goal = normalizeTelegramPRDContextAnswer(input, "단기 목표는 첫 동작 가능한 자동화 루프 확보")
+		session.Context.Goal = normalizeTelegramPRDContextAnswer(input, text.DefaultAssumptions["goal"])
 		recordTelegramPRDAssumption(&session.Context, "goal", session.Context.Goal)
 		return advanceTelegramPRDRefineFlow(paths, session)
 
 	case telegramPRDStageAwaitInScope:
-		session.Context.InScope = normalizeTelegramPRDContextAnswer(input, "초기 릴리즈에서는 핵심 사용자 흐름만 포함")
+		session.Context.InScope = normalizeTelegramPRDContextAnswer(input, text.DefaultAssumptions["in_scope"])
 		recordTelegramPRDAssumption(&session.Context, "in_scope", session.Context.InScope)
 		return advanceTelegramPRDRefineFlow(paths, session)
 
 	case telegramPRDStageAwaitOutOfScope:
-		session.Context.OutOfScope = normalizeTelegramPRDContextAnswer(input, "대규모 리팩터/새 인프라 구축은 제외")
+		session.Context.OutOfScope = normalizeTelegramPRDContextAnswer(input, text.DefaultAssumptions["out_of_scope"])
 		recordTelegramPRDAssumption(&session.Context, "out_of_scope", session.Context.OutOfScope)
 		return advanceTelegramPRDRefineFlow(paths, session)
 
 	case telegramPRDStageAwaitAcceptance:
-		session.Context.Acceptance = normalizeTelegramPRDContextAnswer(input, "주요 시나리오 성공 + 실패 시 복구 경로 확인")
+		session.Context.Acceptance = normalizeTelegramPRDContextAnswer(input, text.DefaultAssumptions["acceptance"])
 		recordTelegramPRDAssumption(&session.Context, "acceptance", session.Context.Acceptance)
 		return advanceTelegramPRDRefineFlow(paths, session)
 
 	case telegramPRDStageAwaitConstraints:
-		session.Context.Constraints = normalizeTelegramPRDContextAnswer(input, "시간/리소스 제약은 일반적인 단일 개발자 환경 가정")
+		session.Context.Constraints = normalizeTelegramPRDContextAnswer(input, text.DefaultAssumptions["constraints"])
 		recordTelegramPRDAssumption(&session.Context, "constraints", session.Context.Constraints)
 		return advanceTelegramPRDRefineFlow(paths, session)
 
 	case telegramPRDStageAwaitStoryTitle:
+		if session.EditIndex > 0 && isTelegramPRDKeepInput(input) {
+			session.Stage = telegramPRDStageAwaitStoryDesc
+			return session, "story title kept\n- next: " + text.TitleKeptNext, nil
+		}
 		if story, quick, err := parseTelegramPRDQuickStoryInput(session, input); err != nil {
 			if quick {
 				return session, "", err
@@ -920,23 +1187,39 @@ func advanceTelegramPRDSession(paths ralph.Paths, session telegramPRDSession, in
 		}
 		session.DraftTitle = input
 		session.Stage = telegramPRDStageAwaitStoryDesc
-		return session, "story title saved\n- next: 설명을 입력하세요 (quick: 제목 | 설명 | role [priority])", nil
+		return session, "story title saved\n- next: " + text.TitleSavedNext, nil
 
 	case telegramPRDStageAwaitStoryDesc:
+		if session.EditIndex > 0 && isTelegramPRDKeepInput(input) {
+			session.Stage = telegramPRDStageAwaitStoryRole
+			return session, "story description kept\n- next: " + text.DescKeptNext, nil
+		}
 		session.DraftDesc = input
 		session.Stage = telegramPRDStageAwaitStoryRole
-		return session, "story description saved\n- next: role 입력 (manager|planner|developer|qa, optional: role priority)", nil
+		return session, "story description saved\n- next: " + text.DescSavedNext, nil
 
 	case telegramPRDStageAwaitStoryRole:
-		role, priority, explicitPriority, err := parseTelegramPRDStoryRoleAndPriorityInput(session, input, "")
-		if err != nil {
-			return session, "", err
+		editing := session.EditIndex > 0
+		var (
+			role             string
+			priority         int
+			explicitPriority bool
+			err              error
+		)
+		if editing && isTelegramPRDKeepInput(input) && session.EditIndex <= len(session.Stories) {
+			original := session.Stories[session.EditIndex-1]
+			role, priority, explicitPriority = session.DraftRole, original.Priority, true
+		} else {
+			role, priority, explicitPriority, err = parseTelegramPRDStoryRoleAndPriorityInput(session, input, "")
+			if err != nil {
+				return session, "", err
+			}
 		}
 		updated, story, source, err := telegramPRDAppendStoryFromDraft(paths, session, role, priority, explicitPriority)
 		if err != nil {
 			return session, "", err
 		}
-		return updated, telegramPRDStoryAddedReply(updated, story, source), nil
+		return updated, telegramPRDStoryAddedReply(updated, story, source, editing), nil
 
 	case telegramPRDStageAwaitStoryPrio:
 		priority, err := parseTelegramPRDStoryPriority(input)
@@ -945,11 +1228,12 @@ func advanceTelegramPRDSession(paths ralph.Paths, session telegramPRDSession, in
 		}
 		rawPriority := strings.TrimSpace(strings.ToLower(input))
 		explicitPriority := !(rawPriority == "" || rawPriority == "default" || rawPriority == "skip")
+		editing := session.EditIndex > 0
 		updated, story, source, err := telegramPRDAppendStoryFromDraft(paths, session, strings.TrimSpace(session.DraftRole), priority, explicitPriority)
 		if err != nil {
 			return session, "", err
 		}
-		return updated, telegramPRDStoryAddedReply(updated, story, source), nil
+		return updated, telegramPRDStoryAddedReply(updated, story, source, editing), nil
 
 	default:
 		status := evaluateTelegramPRDClarity(session)
@@ -967,7 +1251,7 @@ func advanceTelegramPRDRefineFlow(paths ralph.Paths, session telegramPRDSession)
 		session = sessionForCodex
 		if codexRefine.ReadyToApply {
 			session.Stage = telegramPRDStageAwaitStoryTitle
-			return session, formatTelegramPRDCodexRefineQuestion(codexRefine), nil
+			return session, formatTelegramPRDCodexRefineQuestion(telegramPRDSessionText(session), codexRefine), nil
 		}
 		if stage, ok := normalizeTelegramPRDRefineSuggestedStage(codexRefine.SuggestedStage); ok {
 			session.Stage = stage
@@ -975,14 +1259,14 @@ func advanceTelegramPRDRefineFlow(paths ralph.Paths, session telegramPRDSession)
 		if strings.TrimSpace(session.Stage) == "" {
 			session.Stage = telegramPRDStageAwaitStoryTitle
 		}
-		return session, formatTelegramPRDCodexRefineQuestion(codexRefine), nil
+		return session, formatTelegramPRDCodexRefineQuestion(telegramPRDSessionText(session), codexRefine), nil
 	}
 
 	status := evaluateTelegramPRDClarity(session)
 	if codexRefineErr != nil {
 		fmt.Fprintf(os.Stderr, "[telegram] prd refine codex fallback: %v\n", codexRefineErr)
 	}
-	return session, formatTelegramPRDRefineUnavailable(session.Stage, status.Score, codexRefineErr), nil
+	return session, formatTelegramPRDRefineUnavailable(telegramPRDSessionText(session), session.Stage, status.Score, codexRefineErr), nil
 }
 
 func normalizeTelegramPRDContextAnswer(input, defaultAssumption string) string {
@@ -1019,48 +1303,18 @@ func isTelegramPRDAssumedValue(value string) bool {
 
 func evaluateTelegramPRDClarity(session telegramPRDSession) telegramPRDClarityStatus {
 	type requiredField struct {
-		Label      string
-		Value      string
-		Stage      string
-		Prompt     string
-		Assumption string
+		Label string
+		Field string
+		Value string
+		Stage string
 	}
+	text := telegramPRDSessionText(session)
 	required := []requiredField{
-		{
-			Label:      "problem statement",
-			Value:      session.Context.Problem,
-			Stage:      telegramPRDStageAwaitProblem,
-			Prompt:     "문제 정의를 입력하세요 (왜 이 작업이 필요한가?)",
-			Assumption: "skip/default 입력 시: 현재 운영 pain point 해결이 우선이라고 가정",
-		},
-		{
-			Label:      "goal",
-			Value:      session.Context.Goal,
-			Stage:      telegramPRDStageAwaitGoal,
-			Prompt:     "목표를 입력하세요 (완료 기준 한 줄)",
-			Assumption: "skip/default 입력 시: 첫 안정 운영 가능 상태 도달로 가정",
-		},
-		{
-			Label:      "in-scope",
-			Value:      session.Context.InScope,
-			Stage:      telegramPRDStageAwaitInScope,
-			Prompt:     "포함 범위를 입력하세요 (이번 사이클에서 반드시 할 것)",
-			Assumption: "skip/default 입력 시: 핵심 사용자 흐름 중심으로 가정",
-		},
-		{
-			Label:      "out-of-scope",
-			Value:      session.Context.OutOfScope,
-			Stage:      telegramPRDStageAwaitOutOfScope,
-			Prompt:     "제외 범위를 입력하세요 (이번 사이클에서 하지 않을 것)",
-			Assumption: "skip/default 입력 시: 대규모 리팩터/인프라 변경 제외로 가정",
-		},
-		{
-			Label:      "acceptance criteria",
-			Value:      session.Context.Acceptance,
-			Stage:      telegramPRDStageAwaitAcceptance,
-			Prompt:     "수용 기준을 입력하세요 (검증 가능한 기준)",
-			Assumption: "skip/default 입력 시: 핵심 시나리오 성공 + 회귀 없음으로 가정",
-		},
+		{Label: "problem statement", Field: "problem", Value: session.Context.Problem, Stage: telegramPRDStageAwaitProblem},
+		{Label: "goal", Field: "goal", Value: session.Context.Goal, Stage: telegramPRDStageAwaitGoal},
+		{Label: "in-scope", Field: "in_scope", Value: session.Context.InScope, Stage: telegramPRDStageAwaitInScope},
+		{Label: "out-of-scope", Field: "out_of_scope", Value: session.Context.OutOfScope, Stage: telegramPRDStageAwaitOutOfScope},
+		{Label: "acceptance criteria", Field: "acceptance", Value: session.Context.Acceptance, Stage: telegramPRDStageAwaitAcceptance},
 	}
 
 	score := 0
@@ -1078,7 +1332,7 @@ func evaluateTelegramPRDClarity(session telegramPRDSession) telegramPRDClaritySt
 	} else {
 		missing = append(missing, "product name")
 		nextStage = telegramPRDStageAwaitProduct
-		nextPrompt = "제품/프로젝트 이름을 입력하세요"
+		nextPrompt = text.stagePrompt(telegramPRDStageAwaitProduct)
 	}
 
 	for _, f := range required {
@@ -1087,7 +1341,7 @@ func evaluateTelegramPRDClarity(session telegramPRDSession) telegramPRDClaritySt
 			missing = append(missing, f.Label)
 			if nextStage == "" {
 				nextStage = f.Stage
-				nextPrompt = fmt.Sprintf("%s\n- %s", f.Prompt, f.Assumption)
+				nextPrompt = fmt.Sprintf("%s\n- %s", text.stagePrompt(f.Stage), text.ClarityAssumptions[f.Field])
 			}
 			continue
 		}
@@ -1109,7 +1363,7 @@ func evaluateTelegramPRDClarity(session telegramPRDSession) telegramPRDClaritySt
 		missing = append(missing, "at least 1 user story")
 		if nextStage == "" {
 			nextStage = telegramPRDStageAwaitStoryTitle
-			nextPrompt = "첫 user story 제목을 입력하세요"
+			nextPrompt = text.FirstStoryPrompt
 		}
 	} else {
 		score += 20
@@ -1133,7 +1387,7 @@ func evaluateTelegramPRDClarity(session telegramPRDSession) telegramPRDClaritySt
 	ready := score >= telegramPRDClarityMinScore && requiredReady == len(required) && storyCount > 0 && assumedRequired == 0
 	if !ready && nextStage == "" && firstAssumedStage != "" {
 		nextStage = firstAssumedStage
-		nextPrompt = fmt.Sprintf("%s의 실제 값을 입력하세요 (현재 가정값으로 설정됨)", firstAssumedLabel)
+		nextPrompt = fmt.Sprintf(text.ReplaceAssumedPrompt, firstAssumedLabel)
 		missing = append([]string{"replace assumed value: " + firstAssumedLabel}, missing...)
 	}
 	if ready {
@@ -1152,7 +1406,7 @@ func evaluateTelegramPRDClarity(session telegramPRDSession) telegramPRDClaritySt
 	}
 }
 
-func formatTelegramPRDCodexRefineQuestion(refine telegramPRDCodexRefineResponse) string {
+func formatTelegramPRDCodexRefineQuestion(text telegramPRDText, refine telegramPRDCodexRefineResponse) string {
 	lines := []string{
 		"prd refine question",
 		fmt.Sprintf("- score: %d/100 (gate=%d)", refine.Score, telegramPRDClarityMinScore),
@@ -1175,18 +1429,18 @@ func formatTelegramPRDCodexRefineQuestion(refine telegramPRDCodexRefineResponse)
 	if strings.TrimSpace(refine.Reason) != "" {
 		lines = append(lines, "- reason: "+refine.Reason)
 	}
-	lines = append(lines, "- hint: 답변이 애매하면 `skip` 또는 `default` 입력")
+	lines = append(lines, "- hint: "+text.RefineHint)
 	return strings.Join(lines, "\n")
 }
 
-func formatTelegramPRDRefineUnavailable(currentStage string, fallbackScore int, err error) string {
+func formatTelegramPRDRefineUnavailable(text telegramPRDText, currentStage string, fallbackScore int, err error) string {
 	lines := []string{
 		"prd refine unavailable",
 		fmt.Sprintf("- score: %d/100 (gate=%d)", fallbackScore, telegramPRDClarityMinScore),
 		"- scoring_mode: codex_unavailable",
 		fmt.Sprintf("- current_stage: %s", valueOrDash(currentStage)),
-		"- reason: codex refine 실패로 동적 질문 생성 불가",
-		"- next: codex 상태 복구 후 `/prd refine` 재시도",
+		"- reason: " + text.RefineFailedReason,
+		"- next: " + text.RefineFailedNext,
 	}
 	if err != nil {
 		lines = append(lines, "- note: codex refine unavailable")
@@ -1198,7 +1452,7 @@ func formatTelegramPRDRefineUnavailable(currentStage string, fallbackScore int,
 			lines = append(lines, "- codex_detail: "+detail)
 		}
 	}
-	lines = append(lines, "- hint: `/doctor` 또는 telegram tail 로그로 원인 확인")
+	lines = append(lines, "- hint: "+text.RefineFailedHint)
 	return strings.Join(lines, "\n")
 }
 
@@ -1206,25 +1460,8 @@ func classifyTelegramCodexFailure(err error) (string, string) {
 	if err == nil {
 		return "", ""
 	}
-	raw := strings.ToLower(strings.TrimSpace(err.Error()))
 	detail := compactSingleLine(strings.TrimSpace(err.Error()), 180)
-	switch {
-	case strings.Contains(raw, "not found"):
-		return "not_installed", detail
-	case strings.Contains(raw, "no such file or directory"), strings.Contains(raw, "os error 2"):
-		return "file_not_found", detail
-	case strings.Contains(raw, "timeout"), strings.Contains(raw, "deadline exceeded"):
-		return "timeout", detail
-	case strings.Contains(raw, "operation not permitted"), strings.Contains(raw, "permission denied"):
-		return "permission", detail
-	case strings.Contains(raw, "could not resolve host"), strings.Contains(raw, "connection refused"),
-		strings.Contains(raw, "network"), strings.Contains(raw, "i/o timeout"), strings.Contains(raw, "temporary failure in name resolution"):
-		return "network", detail
-	case strings.Contains(raw, "json"), strings.Contains(raw, "parse"):
-		return "invalid_response", detail
-	default:
-		return "exec_failure", detail
-	}
+	return ralph.ClassifyFailureCategory(err.Error()), detail
 }
 
 func formatTelegramPRDCodexScore(session telegramPRDSession) string {
@@ -1277,8 +1514,8 @@ func parseTelegramPRDStoryPriority(input string) (int, error) {
 		return telegramPRDDefaultPriority, nil
 	}
 	n, err := strconv.Atoi(v)
-	if err != nil || n <= 0 {
-		return 0, fmt.Errorf("invalid priority: %q (use positive number)", input)
+	if err != nil || n == 0 {
+		return 0, fmt.Errorf("invalid priority: %q (use a non-zero number; negative runs first)", input)
 	}
 	return n, nil
 }
@@ -1328,7 +1565,7 @@ func parseTelegramPRDQuickStoryInput(session telegramPRDSession, input string) (
 		parts = append(parts, strings.TrimSpace(p))
 	}
 	if len(parts) < 3 || len(parts) > 4 {
-		return telegramPRDStory{}, true, fmt.Errorf("quick format: 제목 | 설명 | role [priority] 또는 제목 | 설명 | role | priority")
+		return telegramPRDStory{}, true, fmt.Errorf("%s", telegramPRDSessionText(session).QuickFormatError)
 	}
 	title := strings.TrimSpace(parts[0])
 	desc := strings.TrimSpace(parts[1])
@@ -1366,20 +1603,15 @@ func telegramPRDAppendStoryFromDraft(paths ralph.Paths, session telegramPRDSessi
 		return session, telegramPRDStory{}, "", fmt.Errorf("incomplete story draft; run /prd cancel then /prd start")
 	}
 	prioritySource := "manual"
-	if !explicitPriority || story.Priority <= 0 {
+	if !explicitPriority || story.Priority == 0 {
 		resolvedPriority, source := resolveTelegramPRDStoryPriority(paths, session, story)
 		story.Priority = resolvedPriority
 		prioritySource = source
-	} else if story.Priority <= 0 {
+	} else if story.Priority == 0 {
 		story.Priority = telegramPRDStoryPriorityForRole(session, story.Role)
 		prioritySource = "fallback_role_profile"
 	}
-	story.ID = telegramPRDStoryID(session, len(session.Stories)+1)
-	session.Stories = append(session.Stories, story)
-	session.DraftTitle = ""
-	session.DraftDesc = ""
-	session.DraftRole = ""
-	session.Stage = telegramPRDStageAwaitStoryTitle
+	session, story, _ = telegramPRDPlaceStory(session, story)
 	return session, story, prioritySource, nil
 }
 
@@ -1389,31 +1621,67 @@ func telegramPRDAppendStoryFromQuick(paths ralph.Paths, session telegramPRDSessi
 		return session, "", fmt.Errorf("quick story role is required")
 	}
 	prioritySource := "manual"
-	if s.Priority <= 0 {
+	if s.Priority == 0 {
 		resolvedPriority, source := resolveTelegramPRDStoryPriority(paths, session, s)
 		s.Priority = resolvedPriority
 		prioritySource = source
 	}
-	s.ID = telegramPRDStoryID(session, len(session.Stories)+1)
-	session.Stories = append(session.Stories, s)
+	session, s, replaced := telegramPRDPlaceStory(session, s)
+	return session, telegramPRDStoryAddedReply(session, s, prioritySource, replaced), nil
+}
+
+// telegramPRDPlaceStory appends story, or replaces the story under /prd edit
+// (keeping its id), then clears the draft fields for the next story.
+func telegramPRDPlaceStory(session telegramPRDSession, story telegramPRDStory) (telegramPRDSession, telegramPRDStory, bool) {
+	replaced := false
+	if idx := session.EditIndex; idx > 0 && idx <= len(session.Stories) {
+		story.ID = session.Stories[idx-1].ID
+		session.Stories[idx-1] = story
+		replaced = true
+	} else {
+		story.ID = telegramPRDNextStoryID(session)
+		session.Stories = append(session.Stories, story)
+	}
 	session.DraftTitle = ""
 	session.DraftDesc = ""
 	session.DraftRole = ""
+	session.EditIndex = 0
 	session.Stage = telegramPRDStageAwaitStoryTitle
-	return session, telegramPRDStoryAddedReply(session, s, prioritySource), nil
+	return session, story, replaced
 }
 
-func telegramPRDStoryAddedReply(session telegramPRDSession, story telegramPRDStory, prioritySource string) string {
+// telegramPRDNextStoryID skips ids still in use, since /prd remove leaves gaps
+// and ids are never renumbered.
+func telegramPRDNextStoryID(session telegramPRDSession) string {
+	used := make(map[string]struct{}, len(session.Stories))
+	for _, story := range session.Stories {
+		used[story.ID] = struct{}{}
+	}
+	for idx := len(session.Stories) + 1; ; idx++ {
+		id := telegramPRDStoryID(session, idx)
+		if _, ok := used[id]; !ok {
+			return id
+		}
+	}
+}
+
+func telegramPRDStoryAddedReply(session telegramPRDSession, story telegramPRDStory, prioritySource string, replaced bool) string {
 	clarity := evaluateTelegramPRDClarity(session)
-	next := "다음 story 제목 입력 또는 /prd preview /prd save /prd apply"
+	text := telegramPRDSessionText(session)
+	next := text.StoryAddedNext
 	if !clarity.ReadyToApply {
-		next = "/prd refine (부족 컨텍스트 질문 진행) 또는 다음 story 제목 입력"
+		next = text.StoryAddedRefineNext
 	}
 	if strings.TrimSpace(prioritySource) == "" {
 		prioritySource = "manual"
 	}
+	header := "story added"
+	if replaced {
+		header = "story updated"
+	}
 	return fmt.Sprintf(
-		"story added\n- id: %s\n- title: %s\n- role: %s\n- priority: %d\n- priority_source: %s\n- stories_total: %d\n- clarity_score: %d/100\n- next: %s",
+		"%s\n- id: %s\n- title: %s\n- role: %s\n- priority: %d\n- priority_source: %s\n- stories_total: %d\n- clarity_score: %d/100\n- next: %s",
+		header,
 		story.ID,
 		compactSingleLine(story.Title, 90),
 		story.Role,
@@ -1425,35 +1693,6 @@ func telegramPRDStoryAddedReply(session telegramPRDSession, story telegramPRDSto
 	)
 }
 
-func telegramPRDStagePrompt(stage string) string {
-	switch stage {
-	case telegramPRDStageAwaitProduct:
-		return "제품/프로젝트 이름을 입력하세요"
-	case telegramPRDStageAwaitProblem:
-		return "문제 정의를 입력하세요 (왜 이 작업이 필요한가?)"
-	case telegramPRDStageAwaitGoal:
-		return "목표를 입력하세요 (완료 기준 한 줄)"
-	case telegramPRDStageAwaitInScope:
-		return "포함 범위를 입력하세요 (이번 사이클에서 반드시 할 것)"
-	case telegramPRDStageAwaitOutOfScope:
-		return "제외 범위를 입력하세요 (이번 사이클에서 하지 않을 것)"
-	case telegramPRDStageAwaitAcceptance:
-		return "수용 기준을 입력하세요 (검증 가능한 기준)"
-	case telegramPRDStageAwaitConstraints:
-		return "제약 사항을 입력하세요 (옵션, skip 가능)"
-	case telegramPRDStageAwaitStoryTitle:
-		return "story 제목을 입력하세요 (quick: 제목 | 설명 | role [priority])"
-	case telegramPRDStageAwaitStoryDesc:
-		return "story 설명을 입력하세요"
-	case telegramPRDStageAwaitStoryRole:
-		return "role 입력 (manager|planner|developer|qa, optional: role priority)"
-	case telegramPRDStageAwaitStoryPrio:
-		return "priority 입력 (숫자, default=role 기본값)"
-	default:
-		return "unknown stage"
-	}
-}
-
 func telegramHasActivePRDSession(paths ralph.Paths, chatID int64) (bool, error) {
 	_, found, err := telegramLoadPRDSession(paths, chatID)
 	return found, err
@@ -1557,23 +1796,6 @@ func telegramPRDLockOwnerPID(lockPath string) (int, bool) {
 	return pid, true
 }
 
-func telegramPRDProcessAlive(pid int) (bool, error) {
-	if pid <= 0 {
-		return false, nil
-	}
-	err := syscall.Kill(pid, 0)
-	if err == nil {
-		return true, nil
-	}
-	if errors.Is(err, syscall.ESRCH) {
-		return false, nil
-	}
-	if errors.Is(err, syscall.EPERM) {
-		return true, nil
-	}
-	return false, err
-}
-
 func parseTelegramPRDSessionStoreData(data []byte) (telegramPRDSessionStore, error) {
 	store := telegramPRDSessionStore{Sessions: map[string]telegramPRDSession{}}
 	if len(bytes.TrimSpace(data)) == 0 {
@@ -1709,6 +1931,42 @@ func telegramDeletePRDSession(paths ralph.Paths, chatID int64) error {
 	})
 }
 
+// telegramSavePRDDraft stores session under name for the chat and reports whether
+// an existing draft with that name was replaced.
+func telegramSavePRDDraft(paths ralph.Paths, chatID int64, name string, session telegramPRDSession) (bool, error) {
+	replaced := false
+	err := withTelegramPRDSessionStoreLock(paths, func(path string) error {
+		store, err := loadTelegramPRDSessionStoreUnlocked(paths, path)
+		if err != nil {
+			return err
+		}
+		if store.Drafts == nil {
+			store.Drafts = map[string]map[string]telegramPRDSession{}
+		}
+		key := telegramSessionKey(chatID)
+		if store.Drafts[key] == nil {
+			store.Drafts[key] = map[string]telegramPRDSession{}
+		}
+		_, replaced = store.Drafts[key][name]
+		store.Drafts[key][name] = session
+		return saveTelegramPRDSessionStoreUnlocked(path, store)
+	})
+	return replaced, err
+}
+
+func telegramLoadPRDDrafts(paths ralph.Paths, chatID int64) (map[string]telegramPRDSession, error) {
+	var drafts map[string]telegramPRDSession
+	err := withTelegramPRDSessionStoreLock(paths, func(path string) error {
+		store, err := loadTelegramPRDSessionStoreUnlocked(paths, path)
+		if err != nil {
+			return err
+		}
+		drafts = store.Drafts[telegramSessionKey(chatID)]
+		return nil
+	})
+	return drafts, err
+}
+
 func telegramPRDConversationDir(paths ralph.Paths, chatID int64) string {
 	return filepath.Join(telegramPRDSessionStoreDir(paths), "conversations", strconv.FormatInt(chatID, 10))
 }
@@ -1816,7 +2074,7 @@ func writeTelegramPRDFile(path string, session telegramPRDSession) error {
 		if strings.TrimSpace(s.Role) == "" {
 			s.Role = "developer"
 		}
-		if s.Priority <= 0 {
+		if s.Priority == 0 {
 			s.Priority = telegramPRDStoryPriorityForRole(session, s.Role)
 		}
 		stories = append(stories, s)
@@ -1837,6 +2095,7 @@ func writeTelegramPRDFile(path string, session telegramPRDSession) error {
 				"constraints":    strings.TrimSpace(session.Context.Constraints),
 				"assumptions":    session.Context.Assumptions,
 				"agent_priority": normalizeTelegramPRDAgentPriorityMap(session.Context.AgentPriority),
+				"attachments":    session.Context.Attachments,
 			},
 		},
 		"userStories": telegramPRDDocument{
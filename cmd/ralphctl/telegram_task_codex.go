@@ -98,7 +98,7 @@ func analyzeTelegramTaskIntakeWithCodex(paths ralph.Paths, chatID int64, input s
 			lastErr = execErr
 		}
 		if attempt < retryAttempts {
-			time.Sleep(time.Duration(attempt*retryBackoffSec) * time.Second)
+			time.Sleep(ralph.ApplyRetryJitter(time.Duration(attempt*retryBackoffSec)*time.Second, profile.CodexRetryJitterPct))
 		}
 	}
 	return telegramTaskIntake{}, fmt.Errorf("codex task intake retries exhausted: %w", lastErr)
@@ -115,7 +115,7 @@ func buildTelegramTaskIntakePrompt(projectDir, conversationTail, input string) s
 	fmt.Fprintln(&b, "- objective should be concrete and short.")
 	fmt.Fprintln(&b, "- acceptance should contain 2~4 checklist items, each testable.")
 	fmt.Fprintln(&b, "- priority is optional; use 0 when unknown.")
-	fmt.Fprintln(&b, "- Prefer Korean concise phrasing.")
+	fmt.Fprintf(&b, "- Prefer concise %s phrasing.\n", telegramTextFor(telegramLangFromEnv()).ReplyLanguage)
 	fmt.Fprintf(&b, "Project directory: %s\n", strings.TrimSpace(projectDir))
 	if strings.TrimSpace(conversationTail) != "" {
 		fmt.Fprintln(&b, "\nRecent chat context:")
@@ -158,7 +158,7 @@ func parseTelegramTaskIntake(raw string, fallbackInput string) (telegramTaskInta
 		title = compactSingleLine(strings.TrimSpace(sanitizeTelegramUTF8String(fallbackInput)), 120)
 	}
 	if title == "" {
-		title = "업무 요청 정리"
+		title = telegramTextFor(telegramLangFromEnv()).TaskDefaultTitle
 	}
 	objective := compactSingleLine(strings.TrimSpace(sanitizeTelegramUTF8String(parsed.Objective)), 240)
 	if objective == "" {
@@ -17,15 +17,11 @@ func EnsureRoleBootstrapIssues(paths Paths, prdPath string) ([]string, error) {
 	}
 
 	created := []string{}
-	for _, role := range RequiredAgentRoles {
-		hasActive, err := hasActiveIssueForRole(paths, role)
-		if err != nil {
-			return created, err
-		}
-		if hasActive {
-			continue
-		}
-
+	missingRoles, err := MissingBootstrapRoles(paths)
+	if err != nil {
+		return created, err
+	}
+	for _, role := range missingRoles {
 		title := bootstrapTitle(role, prdPath)
 		issuePath, _, err := CreateIssue(paths, role, title)
 		if err != nil {
@@ -40,6 +36,20 @@ func EnsureRoleBootstrapIssues(paths Paths, prdPath string) ([]string, error) {
 	return created, nil
 }
 
+func MissingBootstrapRoles(paths Paths) ([]string, error) {
+	missing := []string{}
+	for _, role := range RequiredAgentRoles {
+		hasActive, err := hasActiveIssueForRole(paths, role)
+		if err != nil {
+			return missing, err
+		}
+		if !hasActive {
+			missing = append(missing, role)
+		}
+	}
+	return missing, nil
+}
+
 func hasActiveIssueForRole(paths Paths, role string) (bool, error) {
 	candidates := []string{paths.IssuesDir, paths.InProgressDir}
 	for _, dir := range candidates {
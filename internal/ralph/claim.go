@@ -0,0 +1,42 @@
+package ralph
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+var ErrIssueAlreadyClaimed = errors.New("issue already claimed")
+
+// ClaimIssue moves a ready issue into in-progress for this worker.
+// The rename out of the ready dir is the claim: only one worker can move the file,
+// and every other worker sees it gone and gets ErrIssueAlreadyClaimed.
+// The claim file is keyed by worker PID and still matches I-*.md, so a crash
+// between the two renames leaves it recoverable by RecoverInProgress.
+func ClaimIssue(paths Paths, issuePath string, meta IssueMeta) (string, error) {
+	pid := os.Getpid()
+	claimPath := filepath.Join(paths.InProgressDir, fmt.Sprintf("%s.claim-%d.md", meta.ID, pid))
+	if err := os.Rename(issuePath, claimPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %s", ErrIssueAlreadyClaimed, meta.ID)
+		}
+		return "", fmt.Errorf("move to in-progress: %w", err)
+	}
+	if err := SetIssueStatus(claimPath, "in-progress"); err != nil {
+		return "", err
+	}
+	if err := setIssueMetaField(claimPath, "claimed_by_pid", strconv.Itoa(pid)); err != nil {
+		return "", err
+	}
+	if err := setIssueMetaField(claimPath, "claimed_at_utc", time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return "", err
+	}
+	inProgressPath := filepath.Join(paths.InProgressDir, meta.ID+".md")
+	if err := os.Rename(claimPath, inProgressPath); err != nil {
+		return "", fmt.Errorf("move to in-progress: %w", err)
+	}
+	return inProgressPath, nil
+}
@@ -0,0 +1,232 @@
+package ralph
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClaimIssueConcurrentClaimersExactlyOneWins(t *testing.T) {
+	paths := newTestPaths(t)
+
+	readyPath, id, err := CreateIssue(paths, "developer", "contended issue")
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	meta, err := ReadIssueMeta(readyPath)
+	if err != nil {
+		t.Fatalf("read meta: %v", err)
+	}
+
+	const claimers = 2
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	results := make([]error, claimers)
+	claimedPaths := make([]string, claimers)
+	for i := 0; i < claimers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			claimedPaths[i], results[i] = ClaimIssue(paths, readyPath, meta)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	winners := 0
+	winnerPath := ""
+	for i, err := range results {
+		switch {
+		case err == nil:
+			winners++
+			winnerPath = claimedPaths[i]
+		case errors.Is(err, ErrIssueAlreadyClaimed):
+		default:
+			t.Fatalf("claimer %d: unexpected error: %v", i, err)
+		}
+	}
+	if winners != 1 {
+		t.Fatalf("expected exactly one winner, got %d (results=%v)", winners, results)
+	}
+	if winnerPath != filepath.Join(paths.InProgressDir, id+".md") {
+		t.Fatalf("unexpected claim path: %s", winnerPath)
+	}
+	if _, err := os.Stat(readyPath); !os.IsNotExist(err) {
+		t.Fatalf("ready issue should be gone: %v", err)
+	}
+	claimed, err := ReadIssueMeta(winnerPath)
+	if err != nil || claimed.Status != "in-progress" {
+		t.Fatalf("claimed issue status mismatch: meta=%+v err=%v", claimed, err)
+	}
+	data, err := os.ReadFile(winnerPath)
+	if err != nil {
+		t.Fatalf("read claimed issue: %v", err)
+	}
+	if !strings.Contains(string(data), "claimed_by_pid: "+strconv.Itoa(os.Getpid())) {
+		t.Fatalf("claimed issue should record worker pid:\n%s", string(data))
+	}
+	if n, err := CountIssueFiles(paths.InProgressDir); err != nil || n != 1 {
+		t.Fatalf("in-progress should hold one issue: n=%d err=%v", n, err)
+	}
+}
+
+func TestRecoverInProgressSkipsLiveWorkerUnlessForced(t *testing.T) {
+	paths := newTestPaths(t)
+
+	livePath, liveID, err := CreateIssue(paths, "developer", "owned by live worker")
+	if err != nil {
+		t.Fatalf("create live issue: %v", err)
+	}
+	liveMeta, _ := ReadIssueMeta(livePath)
+	if _, err := ClaimIssue(paths, livePath, liveMeta); err != nil {
+		t.Fatalf("claim live issue: %v", err)
+	}
+	deadPath, deadID, err := CreateIssue(paths, "qa", "owned by dead worker")
+	if err != nil {
+		t.Fatalf("create dead issue: %v", err)
+	}
+	deadMeta, _ := ReadIssueMeta(deadPath)
+	deadInProgress, err := ClaimIssue(paths, deadPath, deadMeta)
+	if err != nil {
+		t.Fatalf("claim dead issue: %v", err)
+	}
+	// pid_max on Linux is at most 2^22, so this pid cannot be alive.
+	if err := setIssueMetaField(deadInProgress, "claimed_by_pid", strconv.Itoa(1<<23)); err != nil {
+		t.Fatalf("rewrite owner pid: %v", err)
+	}
+
+	issues, err := ListInProgressIssues(paths)
+	if err != nil || len(issues) != 2 {
+		t.Fatalf("list in-progress: issues=%+v err=%v", issues, err)
+	}
+	for _, issue := range issues {
+		switch issue.Meta.ID {
+		case liveID:
+			if issue.ClaimedByPID != os.Getpid() || !issue.WorkerAlive {
+				t.Fatalf("live worker mismatch: %+v", issue)
+			}
+		case deadID:
+			if issue.ClaimedByPID != 1<<23 || issue.WorkerAlive {
+				t.Fatalf("dead worker mismatch: %+v", issue)
+			}
+		}
+	}
+
+	res, err := RecoverInProgressWithOptions(paths, RecoverInProgressOptions{})
+	if err != nil || res.Recovered != 1 || res.SkippedLive != 1 {
+		t.Fatalf("recover without force: result=%+v err=%v", res, err)
+	}
+	if _, err := os.Stat(filepath.Join(paths.IssuesDir, deadID+".md")); err != nil {
+		t.Fatalf("dead worker issue should be back in ready: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(paths.InProgressDir, liveID+".md")); err != nil {
+		t.Fatalf("live worker issue must stay in-progress: %v", err)
+	}
+
+	res, err = RecoverInProgressWithOptions(paths, RecoverInProgressOptions{Force: true})
+	if err != nil || res.Recovered != 1 || res.SkippedLive != 0 {
+		t.Fatalf("recover with force: result=%+v err=%v", res, err)
+	}
+}
+
+func TestRecoverInProgressStaleAfterUsesClaimTimestamp(t *testing.T) {
+	paths := newTestPaths(t)
+
+	ids := []string{}
+	for i, age := range []time.Duration{2 * time.Hour, 5 * time.Minute} {
+		readyPath, id, err := CreateIssue(paths, "developer", fmt.Sprintf("issue %d", i))
+		if err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+		meta, _ := ReadIssueMeta(readyPath)
+		inProgressPath, err := ClaimIssue(paths, readyPath, meta)
+		if err != nil {
+			t.Fatalf("claim issue: %v", err)
+		}
+		data, _ := os.ReadFile(inProgressPath)
+		if !strings.Contains(string(data), "claimed_at_utc: ") {
+			t.Fatalf("claim should record claimed_at_utc:\n%s", string(data))
+		}
+		// Backdate the claim and orphan it; the file mtime stays fresh on purpose.
+		if err := setIssueMetaField(inProgressPath, "claimed_at_utc", time.Now().UTC().Add(-age).Format(time.RFC3339)); err != nil {
+			t.Fatalf("backdate claim: %v", err)
+		}
+		if err := setIssueMetaField(inProgressPath, "claimed_by_pid", strconv.Itoa(1<<23)); err != nil {
+			t.Fatalf("rewrite owner pid: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	res, err := RecoverInProgressWithOptions(paths, RecoverInProgressOptions{StaleAfter: time.Hour})
+	if err != nil || res.Recovered != 1 || res.SkippedFresh != 1 {
+		t.Fatalf("stale recover: result=%+v err=%v", res, err)
+	}
+	if _, err := os.Stat(filepath.Join(paths.IssuesDir, ids[0]+".md")); err != nil {
+		t.Fatalf("stale issue should be back in ready: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(paths.InProgressDir, ids[1]+".md")); err != nil {
+		t.Fatalf("fresh issue must stay in-progress: %v", err)
+	}
+}
+
+func TestRecoverStaleInProgressWatchdogUsesClaimAndLiveness(t *testing.T) {
+	paths := newTestPaths(t)
+
+	type claim struct {
+		age  time.Duration
+		pid  int
+		name string
+	}
+	claims := []claim{
+		{2 * time.Hour, 1 << 23, "orphaned long ago"},
+		{2 * time.Hour, os.Getpid(), "live worker still on it"},
+		{5 * time.Minute, 1 << 23, "orphaned recently"},
+	}
+	ids := []string{}
+	for _, c := range claims {
+		readyPath, id, err := CreateIssue(paths, "developer", c.name)
+		if err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+		meta, _ := ReadIssueMeta(readyPath)
+		inProgressPath, err := ClaimIssue(paths, readyPath, meta)
+		if err != nil {
+			t.Fatalf("claim issue: %v", err)
+		}
+		if err := setIssueMetaField(inProgressPath, "claimed_at_utc", time.Now().UTC().Add(-c.age).Format(time.RFC3339)); err != nil {
+			t.Fatalf("backdate claim: %v", err)
+		}
+		if err := setIssueMetaField(inProgressPath, "claimed_by_pid", strconv.Itoa(c.pid)); err != nil {
+			t.Fatalf("rewrite owner pid: %v", err)
+		}
+		// mtime says the opposite of claimed_at_utc; the watchdog must not use it.
+		mtime := time.Now().Add(-3 * time.Hour)
+		if c.age > time.Hour {
+			mtime = time.Now()
+		}
+		if err := os.Chtimes(inProgressPath, mtime, mtime); err != nil {
+			t.Fatalf("set mtime: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	recovered, err := RecoverStaleInProgressWithCount(paths, time.Hour)
+	if err != nil || recovered != 1 {
+		t.Fatalf("watchdog recover: recovered=%d err=%v", recovered, err)
+	}
+	if _, err := os.Stat(filepath.Join(paths.IssuesDir, ids[0]+".md")); err != nil {
+		t.Fatalf("stale orphaned issue should be back in ready: %v", err)
+	}
+	for _, id := range ids[1:] {
+		if _, err := os.Stat(filepath.Join(paths.InProgressDir, id+".md")); err != nil {
+			t.Fatalf("%s must stay in-progress: %v", id, err)
+		}
+	}
+}
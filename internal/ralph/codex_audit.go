@@ -0,0 +1,67 @@
+package ralph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CodexAuditLogPath is where codex_audit_log appends every codex exec for an issue.
+func CodexAuditLogPath(paths Paths, issueID string) string {
+	return filepath.Join(paths.ReportsDir, "codex", issueID+".log")
+}
+
+// codexAuditLog records the prompt, argv, exit code and last message of each
+// codex exec. A nil log (audit off) records nothing.
+type codexAuditLog struct {
+	path string
+}
+
+func newCodexAuditLog(paths Paths, profile Profile, issueID string) *codexAuditLog {
+	issueID = strings.TrimSpace(issueID)
+	if !profile.CodexAuditLog || issueID == "" {
+		return nil
+	}
+	return &codexAuditLog{path: CodexAuditLogPath(paths, issueID)}
+}
+
+func (a *codexAuditLog) record(args []string, prompt string, runErr error, lastMessagePath string) error {
+	if a == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o755); err != nil {
+		return fmt.Errorf("create codex audit dir: %w", err)
+	}
+	f, err := os.OpenFile(a.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open codex audit log: %w", err)
+	}
+	defer f.Close()
+
+	lastMessage := "(none)"
+	if strings.TrimSpace(lastMessagePath) != "" {
+		if data, err := os.ReadFile(lastMessagePath); err == nil {
+			lastMessage = strings.TrimRight(string(data), "\n")
+		}
+	}
+	result := "ok"
+	if runErr != nil {
+		result = runErr.Error()
+	}
+	_, err = fmt.Fprintf(
+		f,
+		"=== codex exec %s ===\n- command: codex %s\n- exit_code: %d\n- result: %s\n\n## Prompt\n%s\n\n## Last Message\n%s\n\n",
+		time.Now().UTC().Format(time.RFC3339),
+		strings.Join(args, " "),
+		exitCode(runErr),
+		result,
+		strings.TrimRight(prompt, "\n"),
+		lastMessage,
+	)
+	if err != nil {
+		return fmt.Errorf("write codex audit log: %w", err)
+	}
+	return nil
+}
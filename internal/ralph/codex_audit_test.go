@@ -0,0 +1,59 @@
+package ralph
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodexAuditLogRecordsEachExecPerIssue(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	binDir := t.TempDir()
+	// The fake codex writes its last message, then fails the first call only.
+	script := "#!/usr/bin/env bash\nwhile [ $# -gt 0 ]; do if [ \"$1\" = --output-last-message ]; then out=$2; fi; shift; done\n" +
+		"cat >/dev/null\nif [ ! -f \"$out.seen\" ]; then touch \"$out.seen\"; echo first > \"$out\"; exit 3; fi\necho finished > \"$out\"\n"
+	writeFile(t, filepath.Join(binDir, "codex"), script)
+	if err := os.Chmod(filepath.Join(binDir, "codex"), 0o755); err != nil {
+		t.Fatalf("chmod fake codex: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	profile := DefaultProfile()
+	profile.CodexMaxConcurrent = 0
+	profile.CodexRetryBackoffSec = 0
+	profile.CodexRetryJitterPct = 0
+	if audit := newCodexAuditLog(paths, profile, "I-1"); audit != nil {
+		t.Fatalf("audit log should be off by default")
+	}
+	profile.CodexAuditLog = true
+
+	logFile, err := os.Create(filepath.Join(paths.LogsDir, "audit.log"))
+	if err != nil {
+		t.Fatalf("create log: %v", err)
+	}
+	defer logFile.Close()
+	lastMessagePath := filepath.Join(paths.LogsDir, "audit.last.txt")
+	attempts, err := runCodexWithRetryCount(context.Background(), paths, profile, "", "implement I-1", logFile, lastMessagePath, newCodexAuditLog(paths, profile, "I-1"))
+	if err != nil || attempts != 2 {
+		t.Fatalf("expected success on attempt 2: attempts=%d err=%v", attempts, err)
+	}
+
+	data, err := os.ReadFile(CodexAuditLogPath(paths, "I-1"))
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	out := string(data)
+	if got := strings.Count(out, "=== codex exec "); got != 2 {
+		t.Fatalf("expected one record per exec, got=%d\n%s", got, out)
+	}
+	for _, want := range []string{"- exit_code: 3", "- exit_code: 0", "--output-last-message " + lastMessagePath, "## Prompt\nimplement I-1", "## Last Message\nfirst", "## Last Message\nfinished"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("audit log missing %q:\n%s", want, out)
+		}
+	}
+}
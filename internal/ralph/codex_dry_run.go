@@ -0,0 +1,55 @@
+package ralph
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// errCodexDryRun marks a loop codex call that codex_dry_run logged instead of
+// running. The issue goes back to ready untouched rather than counting as a failure.
+var errCodexDryRun = errors.New("codex_dry_run")
+
+// CodexDryRunDir collects the prompts that codex_dry_run logged instead of sending.
+func CodexDryRunDir(paths Paths) string {
+	return filepath.Join(paths.ReportsDir, "codex-dry-run")
+}
+
+// WriteCodexDryRunRecord writes the prompt and the codex argv that would have run,
+// returning the record path.
+func WriteCodexDryRunRecord(paths Paths, kind string, args []string, prompt string) (string, error) {
+	dir := CodexDryRunDir(paths)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create codex dry-run dir: %w", err)
+	}
+	kind = strings.Trim(strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == '*' || r == ' ' {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(kind)), "-")
+	if kind == "" {
+		kind = "codex"
+	}
+	now := time.Now().UTC()
+	f, err := os.CreateTemp(dir, fmt.Sprintf("%s-%s-*.md", now.Format("20060102T150405Z"), kind))
+	if err != nil {
+		return "", fmt.Errorf("create codex dry-run record: %w", err)
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(
+		f,
+		"# Codex Dry Run\n- kind: %s\n- created_at_utc: %s\n- command: codex %s\n\n## Prompt\n%s\n",
+		kind,
+		now.Format(time.RFC3339),
+		strings.Join(args, " "),
+		strings.TrimRight(prompt, "\n"),
+	)
+	if err != nil {
+		return "", fmt.Errorf("write codex dry-run record: %w", err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,85 @@
+package ralph
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodexDryRunLogsPromptWithoutExec(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	t.Setenv("PATH", t.TempDir())
+	profile := DefaultProfile()
+	profile.CodexDryRun = true
+
+	logFile, err := os.Create(filepath.Join(paths.LogsDir, "dry-run.log"))
+	if err != nil {
+		t.Fatalf("create log: %v", err)
+	}
+	defer logFile.Close()
+	lastMessagePath := filepath.Join(paths.LogsDir, "dry-run.last.txt")
+
+	err, retryable := runSingleCodexAttempt(context.Background(), paths, profile, "gpt-test", "implement the thing", logFile, lastMessagePath, nil)
+	if !errors.Is(err, errCodexDryRun) || retryable {
+		t.Fatalf("dry-run should stop without retry: err=%v retryable=%t", err, retryable)
+	}
+	records, _ := filepath.Glob(filepath.Join(CodexDryRunDir(paths), "*-loop-*.md"))
+	if len(records) != 1 {
+		t.Fatalf("expected one dry-run record, got %v", records)
+	}
+	data, err := os.ReadFile(records[0])
+	if err != nil {
+		t.Fatalf("read record: %v", err)
+	}
+	text := string(data)
+	if !strings.Contains(text, "implement the thing") || !strings.Contains(text, "--model gpt-test") {
+		t.Fatalf("record should include prompt and args:\n%s", text)
+	}
+	if msg, err := os.ReadFile(lastMessagePath); err != nil || !strings.Contains(string(msg), records[0]) {
+		t.Fatalf("canned last message mismatch: %q err=%v", msg, err)
+	}
+}
+
+func TestProcessIssueCodexDryRunKeepsIssueReady(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	t.Setenv("PATH", t.TempDir())
+
+	profile := DefaultProfile()
+	profile.CodexDryRun = true
+	profile.RoleRulesEnabled = false
+	profile.MaxIssueAttempts = 1
+
+	readyPath, id, err := CreateIssue(paths, "developer", "dry run issue")
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	meta, err := ReadIssueMeta(readyPath)
+	if err != nil {
+		t.Fatalf("read meta: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		res, err := processIssue(context.Background(), paths, profile, readyPath, meta, io.Discard)
+		if err != nil || res.Outcome != "dry_run" {
+			t.Fatalf("dry run %d outcome mismatch: outcome=%s err=%v", i+1, res.Outcome, err)
+		}
+	}
+	for _, dir := range []string{paths.BlockedDir, paths.DeadLetterDir, paths.DoneDir, paths.InProgressDir} {
+		if _, err := os.Stat(filepath.Join(dir, id+".md")); !os.IsNotExist(err) {
+			t.Fatalf("dry-run issue should not be in %s: %v", dir, err)
+		}
+	}
+	if attempts, err := issueFailedAttempts(readyPath); err != nil || attempts != 0 {
+		t.Fatalf("dry run should not record failed attempts: attempts=%d err=%v", attempts, err)
+	}
+	if meta, err := ReadIssueMeta(readyPath); err != nil || meta.Status != "ready" {
+		t.Fatalf("dry-run issue should stay ready: %+v err=%v", meta, err)
+	}
+}
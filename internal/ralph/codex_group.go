@@ -0,0 +1,42 @@
+package ralph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// codexGroupFile records the process group of the codex a daemon is running.
+// codex leads its own group so an exec timeout can kill its tools, which puts it
+// out of reach of stop's kill of the daemon group.
+func codexGroupFile(dir string, owner int) string {
+	return filepath.Join(dir, fmt.Sprintf("codex.%d.pgid", owner))
+}
+
+// recordCodexGroup notes codex's group under the daemon group owner and returns
+// the cleanup to call once codex exits. owner <= 0 records nothing.
+func recordCodexGroup(dir string, owner, pgid int) func() {
+	if owner <= 0 || pgid <= 0 {
+		return func() {}
+	}
+	path := codexGroupFile(dir, owner)
+	if err := os.WriteFile(path, []byte(strconv.Itoa(pgid)+"\n"), 0o644); err != nil {
+		return func() {}
+	}
+	return func() { _ = os.Remove(path) }
+}
+
+// killRecordedCodexGroup kills the codex group recorded for the daemon owner, if any.
+func killRecordedCodexGroup(dir string, owner int) {
+	path := codexGroupFile(dir, owner)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	if pgid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pgid > 0 {
+		_ = killDaemonProcessGroup(pgid)
+	}
+	_ = os.Remove(path)
+}
@@ -0,0 +1,76 @@
+package ralph
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	codexSlotPollInterval     = 200 * time.Millisecond
+	defaultCodexSlotWaitLimit = 15 * time.Minute
+)
+
+// CodexSlotsDir holds one lock file per running codex exec, shared by every
+// role worker of the project so profile.CodexMaxConcurrent caps them together.
+func CodexSlotsDir(paths Paths) string {
+	return filepath.Join(paths.RalphDir, "codex-slots")
+}
+
+func codexSlotPath(paths Paths, slot int) string {
+	return filepath.Join(CodexSlotsDir(paths), fmt.Sprintf("slot-%d.lock", slot))
+}
+
+// codexSlotWaitLimit bounds how long a worker blocks for a free slot: one full
+// codex exec timeout, since that is the longest a holder can keep its slot.
+func codexSlotWaitLimit(profile Profile) time.Duration {
+	if profile.CodexExecTimeoutSec > 0 {
+		return time.Duration(profile.CodexExecTimeoutSec) * time.Second
+	}
+	return defaultCodexSlotWaitLimit
+}
+
+// AcquireCodexSlot blocks until fewer than max codex execs are running for the
+// project, then claims a slot. max <= 0 disables the limit. Slots held by dead
+// processes are reclaimed.
+func AcquireCodexSlot(ctx context.Context, paths Paths, max int, wait time.Duration) (func(), error) {
+	if max <= 0 {
+		return func() {}, nil
+	}
+	if err := os.MkdirAll(CodexSlotsDir(paths), 0o755); err != nil {
+		return nil, fmt.Errorf("create codex slots dir: %w", err)
+	}
+	deadline := time.Now().Add(wait)
+	for {
+		for slot := 0; slot < max; slot++ {
+			path := codexSlotPath(paths, slot)
+			ok, err := tryClaimCodexSlot(path)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return func() { _ = os.Remove(path) }, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("codex_concurrency_wait_timeout: %d/%d slots busy after %s", max, max, wait)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(codexSlotPollInterval):
+		}
+	}
+}
+
+// tryClaimCodexSlot claims the slot file; a slot left by a dead worker is
+// reclaimed through TryCreateOwnedLock so two workers cannot both take it over.
+func tryClaimCodexSlot(path string) (bool, error) {
+	ok, err := TryCreateOwnedLock(path)
+	if err != nil {
+		return false, fmt.Errorf("claim codex slot: %w", err)
+	}
+	return ok, nil
+}
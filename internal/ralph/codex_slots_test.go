@@ -0,0 +1,95 @@
+package ralph
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireCodexSlotCapsConcurrentExecs(t *testing.T) {
+	paths := newTestPaths(t)
+	ctx := context.Background()
+
+	release, err := AcquireCodexSlot(ctx, paths, 1, time.Second)
+	if err != nil {
+		t.Fatalf("acquire first slot: %v", err)
+	}
+	if _, err := AcquireCodexSlot(ctx, paths, 1, 300*time.Millisecond); err == nil || !strings.Contains(err.Error(), "codex_concurrency_wait_timeout") {
+		t.Fatalf("second acquire should time out while the slot is held: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		next, err := AcquireCodexSlot(ctx, paths, 1, 5*time.Second)
+		if err == nil {
+			next()
+		}
+		done <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	release()
+	if err := <-done; err != nil {
+		t.Fatalf("waiting worker should get the slot after release: %v", err)
+	}
+
+	unlimited, err := AcquireCodexSlot(ctx, paths, 0, 0)
+	if err != nil {
+		t.Fatalf("limit 0 must not block: %v", err)
+	}
+	unlimited()
+}
+
+func TestAcquireCodexSlotReclaimsDeadOwner(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := os.MkdirAll(CodexSlotsDir(paths), 0o755); err != nil {
+		t.Fatalf("mkdir slots dir: %v", err)
+	}
+	// pid_max on Linux is at most 2^22, so this pid cannot be alive.
+	if err := os.WriteFile(codexSlotPath(paths, 0), []byte(fmt.Sprintf("%d\n2026-01-01T00:00:00Z\n", 1<<23)), 0o600); err != nil {
+		t.Fatalf("write stale slot: %v", err)
+	}
+	release, err := AcquireCodexSlot(context.Background(), paths, 1, 0)
+	if err != nil {
+		t.Fatalf("dead owner slot should be reclaimed: %v", err)
+	}
+	release()
+}
+
+func TestTryClaimCodexSlotDeadOwnerGoesToOneWorker(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := os.MkdirAll(CodexSlotsDir(paths), 0o755); err != nil {
+		t.Fatalf("mkdir slots dir: %v", err)
+	}
+	path := codexSlotPath(paths, 0)
+	if err := os.WriteFile(path, []byte(fmt.Sprintf("%d\n2026-01-01T00:00:00Z\n", 1<<23)), 0o600); err != nil {
+		t.Fatalf("write stale slot: %v", err)
+	}
+	const workers = 8
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	claimed := 0
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := tryClaimCodexSlot(path)
+			if err != nil {
+				t.Errorf("claim slot: %v", err)
+				return
+			}
+			if ok {
+				mu.Lock()
+				claimed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if claimed != 1 {
+		t.Fatalf("dead owner slot should go to exactly one worker, got %d", claimed)
+	}
+}
@@ -0,0 +1,75 @@
+package ralph
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCodexTimeoutKillsProcessGroup(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	binDir := t.TempDir()
+	childPIDFile := filepath.Join(t.TempDir(), "child.pid")
+	groupFile := codexGroupFile(paths.runtimeDir(), daemonGroupOwner())
+	seenGroupFile := filepath.Join(t.TempDir(), "seen.pgid")
+	// The fake codex leaves a child that inherits its stdout and outlives it
+	// unless the whole group is killed, and copies the group record stop reads.
+	writeFile(t, filepath.Join(binDir, "codex"), "#!/usr/bin/env bash\nfor i in $(seq 40); do [ -f "+groupFile+" ] && break; sleep 0.05; done\ncp "+groupFile+" "+seenGroupFile+"\nsleep 60 &\necho $! > "+childPIDFile+"\nwait\n")
+	if err := os.Chmod(filepath.Join(binDir, "codex"), 0o755); err != nil {
+		t.Fatalf("chmod fake codex: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	profile := DefaultProfile()
+	profile.CodexExecTimeoutSec = 1
+	profile.CodexMaxConcurrent = 0
+	logFile, err := os.Create(filepath.Join(paths.LogsDir, "timeout.log"))
+	if err != nil {
+		t.Fatalf("create log: %v", err)
+	}
+	defer logFile.Close()
+
+	started := time.Now()
+	err, retryable := runSingleCodexAttempt(context.Background(), paths, profile, "", "hang", logFile, "", nil)
+	if err == nil || err.Error() != "codex_timeout_1s" || !retryable {
+		t.Fatalf("expected retryable timeout: err=%v retryable=%t", err, retryable)
+	}
+	if elapsed := time.Since(started); elapsed >= codexKillWaitDelay {
+		t.Fatalf("run should return once the group is killed, took %s", elapsed)
+	}
+
+	seen, err := os.ReadFile(seenGroupFile)
+	if err != nil {
+		t.Fatalf("codex group should be recorded while codex runs: %v", err)
+	}
+	if _, err := strconv.Atoi(strings.TrimSpace(string(seen))); err != nil {
+		t.Fatalf("codex group record mismatch: %q", seen)
+	}
+	if _, err := os.Stat(groupFile); !os.IsNotExist(err) {
+		t.Fatalf("codex group record should be removed after codex exits: %v", err)
+	}
+
+	data, err := os.ReadFile(childPIDFile)
+	if err != nil {
+		t.Fatalf("read child pid: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatalf("parse child pid: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for isPIDRunning(pid) {
+		if time.Now().After(deadline) {
+			_ = killDaemonProcessGroup(pid)
+			t.Fatalf("codex child %d survived the timeout", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -12,6 +12,10 @@ func EnsureDefaultControlAssets(controlDir string) error {
 	if controlDir == "" {
 		return fmt.Errorf("control-dir is required")
 	}
+	if strings.TrimSpace(os.Getenv(PluginDirEnv)) != "" {
+		// A --plugin-dir is vendored or shared; never seed builtins into it.
+		return nil
+	}
 
 	hasPlugin, err := hasAnyPlugin(controlDir)
 	if err != nil {
@@ -44,7 +48,7 @@ func EnsureDefaultControlAssets(controlDir string) error {
 }
 
 func hasAnyPlugin(controlDir string) (bool, error) {
-	pluginRoot := filepath.Join(controlDir, "plugins")
+	pluginRoot := PluginsDir(controlDir)
 	entries, err := os.ReadDir(pluginRoot)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -4359,7 +4359,7 @@ func normalizeMigratedRole(role string) string {
 }
 
 func normalizeMigratedPriority(priority int) int {
-	if priority <= 0 {
+	if priority == 0 {
 		return defaultIssuePriority
 	}
 	return priority
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
@@ -25,31 +26,70 @@ func StartRoleDaemon(paths Paths, role string) (int, bool, error) {
 	return startDaemonWithRoleScope(paths, paths.RolePIDFile(role), paths.RoleRunnerLogFile(role), roleSet)
 }
 
+const (
+	daemonDrainGrace      = 15 * time.Second
+	daemonDrainMaxDefault = 15 * time.Minute
+)
+
+// DaemonDrainTimeout is how long stop waits for a daemon to finish its
+// current codex exec after SIGTERM before escalating to SIGKILL.
+func DaemonDrainTimeout(profile Profile) time.Duration {
+	if profile.CodexExecTimeoutSec <= 0 {
+		return daemonDrainMaxDefault
+	}
+	return time.Duration(profile.CodexExecTimeoutSec)*time.Second + daemonDrainGrace
+}
+
 func StopDaemon(paths Paths) error {
+	return StopDaemonWithDrain(paths, 0)
+}
+
+// StopDaemonWithDrain stops the primary and role daemons. drainTimeout <= 0
+// uses DaemonDrainTimeout for the project profile.
+func StopDaemonWithDrain(paths Paths, drainTimeout time.Duration) error {
 	if err := SetEnabled(paths, false); err != nil {
 		return err
 	}
-	if err := stopDaemonByPIDFile(paths.PIDFile); err != nil {
-		return err
-	}
+	pidFiles := []string{paths.PIDFile}
 	for _, role := range RequiredAgentRoles {
-		if err := stopDaemonByPIDFile(paths.RolePIDFile(role)); err != nil {
-			return err
-		}
+		pidFiles = append(pidFiles, paths.RolePIDFile(role))
+	}
+	if err := stopDaemonsByPIDFiles(pidFiles, resolveDaemonDrainTimeout(paths, drainTimeout)); err != nil {
+		return err
 	}
 	return RecoverInProgress(paths)
 }
 
 func StopPrimaryDaemon(paths Paths) error {
-	return stopDaemonByPIDFile(paths.PIDFile)
+	return stopDaemonByPIDFile(paths.PIDFile, resolveDaemonDrainTimeout(paths, 0))
 }
 
 func StopRoleDaemon(paths Paths, role string) error {
+	return StopRoleDaemonWithDrain(paths, role, 0)
+}
+
+func StopRoleDaemonWithDrain(paths Paths, role string, drainTimeout time.Duration) error {
 	role = strings.TrimSpace(role)
 	if !IsSupportedRole(role) {
 		return fmt.Errorf("unsupported role: %s", role)
 	}
-	return stopDaemonByPIDFile(paths.RolePIDFile(role))
+	return stopDaemonByPIDFile(paths.RolePIDFile(role), resolveDaemonDrainTimeout(paths, drainTimeout))
+}
+
+func resolveDaemonDrainTimeout(paths Paths, drainTimeout time.Duration) time.Duration {
+	if drainTimeout > 0 {
+		return drainTimeout
+	}
+	profile, err := LoadProfile(paths)
+	if err != nil {
+		return DaemonDrainTimeout(DefaultProfile())
+	}
+	return DaemonDrainTimeout(profile)
+}
+
+// PrimaryDaemonPID returns the pid of the running primary (all-roles) daemon.
+func PrimaryDaemonPID(paths Paths) (int, bool) {
+	return daemonPID(paths)
 }
 
 func RunningRoleDaemons(paths Paths) ([]string, map[string]int) {
@@ -65,7 +105,7 @@ func RunningRoleDaemons(paths Paths) ([]string, map[string]int) {
 	return running, pids
 }
 
-func RunSupervisor(ctx context.Context, paths Paths, profile Profile, allowedRoles map[string]struct{}, engine string, executeWithCodex bool, stdout io.Writer) error {
+func RunSupervisor(ctx context.Context, paths Paths, profile Profile, allowedRoles map[string]struct{}, engine string, executeWithCodex bool, maxRuntime time.Duration, stdout io.Writer) error {
 	if stdout == nil {
 		stdout = os.Stdout
 	}
@@ -87,16 +127,59 @@ func RunSupervisor(ctx context.Context, paths Paths, profile Profile, allowedRol
 		// v2 currently does not support role-scoped supervisor workers.
 		engineRaw = "v1"
 	}
+	drainTimeout := DaemonDrainTimeout(profile)
 	restartDelaySec := profile.SupervisorRestartDelaySec
 	if restartDelaySec < 0 {
 		restartDelaySec = 0
 	}
+	runCtx := ctx
+	var deadline time.Time
+	if maxRuntime > 0 {
+		deadline = time.Now().Add(maxRuntime)
+		var cancelRun context.CancelFunc
+		runCtx, cancelRun = context.WithDeadline(ctx, deadline)
+		defer cancelRun()
+	}
+	supervisorState := SupervisorState{StartedAt: time.Now().UTC()}
+	backoff := supervisorRestartBackoff{maxPerMin: profile.SupervisorMaxRestartsPerMin}
+	if err := SaveSupervisorState(paths, roleScope, supervisorState); err != nil {
+		fmt.Fprintf(stdout, "[ralph-supervisor] warning: save supervisor state failed: %v\n", err)
+	}
+	var workerStartedAt time.Time
+	beat := func(workerPID int) {
+		if workerPID != 0 && supervisorState.recoverFromCrashLoop(time.Since(workerStartedAt)) {
+			fmt.Fprintf(stdout, "[ralph-supervisor] worker stable for %s; crash loop cleared\n", supervisorCrashLoopWindow)
+			if err := SaveSupervisorState(paths, roleScope, supervisorState); err != nil {
+				fmt.Fprintf(stdout, "[ralph-supervisor] warning: save supervisor state failed: %v\n", err)
+			}
+		}
+		hb := SupervisorHeartbeat{
+			UpdatedAt:     time.Now().UTC(),
+			SupervisorPID: os.Getpid(),
+			WorkerPID:     workerPID,
+			Roles:         roleScope,
+			RestartCount:  supervisorState.RestartCount,
+		}
+		if err := WriteSupervisorHeartbeat(paths, roleScope, hb); err != nil {
+			fmt.Fprintf(stdout, "[ralph-supervisor] warning: write heartbeat failed: %v\n", err)
+		}
+	}
+	defer os.Remove(paths.SupervisorHeartbeatFile(roleScope))
 
 	for {
+		beat(0)
 		if err := ctx.Err(); err != nil {
 			fmt.Fprintln(stdout, "[ralph-supervisor] interrupted; stopping")
 			return nil
 		}
+		remaining := time.Duration(0)
+		if !deadline.IsZero() {
+			remaining = time.Until(deadline)
+			if remaining <= 0 {
+				fmt.Fprintf(stdout, "[ralph-supervisor] max runtime reached (%s); stopping\n", maxRuntime)
+				return nil
+			}
+		}
 		enabled, err := IsEnabled(paths)
 		if err != nil {
 			fmt.Fprintf(stdout, "[ralph-supervisor] warning: read enabled state failed: %v\n", err)
@@ -125,12 +208,23 @@ func RunSupervisor(ctx context.Context, paths Paths, profile Profile, allowedRol
 		if roleScope != "" {
 			args = append(args, "--roles", roleScope)
 		}
+		if remaining > 0 {
+			// The worker stops itself at the deadline after finishing its current issue.
+			args = append(args, "--max-runtime", remaining.Round(time.Second).String())
+		}
 
 		fmt.Fprintf(stdout, "[ralph-supervisor] starting worker (engine=%s roles=%s)\n", engineRaw, roleScopeOrAll(roleScope))
 		worker := exec.CommandContext(ctx, exe, args...)
 		worker.Stdout = stdout
 		worker.Stderr = stdout
-		runErr := worker.Run()
+		// On stop, let the worker drain its current issue before it is killed.
+		worker.Cancel = func() error {
+			fmt.Fprintf(stdout, "[ralph-supervisor] draining worker (timeout=%s)\n", drainTimeout)
+			return worker.Process.Signal(syscall.SIGTERM)
+		}
+		worker.WaitDelay = drainTimeout
+		workerStartedAt = time.Now()
+		runErr := runSupervisedWorker(worker, beat)
 		if ctx.Err() != nil {
 			fmt.Fprintln(stdout, "[ralph-supervisor] interrupted; stopping")
 			return nil
@@ -141,20 +235,78 @@ func RunSupervisor(ctx context.Context, paths Paths, profile Profile, allowedRol
 			fmt.Fprintln(stdout, "[ralph-supervisor] disabled; stopping")
 			return nil
 		}
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			fmt.Fprintf(stdout, "[ralph-supervisor] max runtime reached (%s); stopping\n", maxRuntime)
+			return nil
+		}
 		if runErr == nil {
 			fmt.Fprintln(stdout, "[ralph-supervisor] worker exited; restarting")
 		} else {
 			fmt.Fprintf(stdout, "[ralph-supervisor] worker exited (rc=%d); restarting\n", exitCode(runErr))
 		}
-		if restartDelaySec > 0 {
-			fmt.Fprintf(stdout, "[ralph-supervisor] restart delay: %ds\n", restartDelaySec)
-			if err := sleepOrCancel(ctx, time.Duration(restartDelaySec)*time.Second); err != nil {
+		now := time.Now().UTC()
+		delay, crashLoop := backoff.next(now, now.Sub(workerStartedAt), time.Duration(restartDelaySec)*time.Second)
+		supervisorState.RestartCount++
+		supervisorState.LastRestartAt = now
+		supervisorState.LastExitCode = exitCode(runErr)
+		if crashLoop {
+			supervisorState.LastCrashLoopAt = now
+			supervisorState.CrashLoopBackoffSec = int(delay / time.Second)
+			fmt.Fprintf(stdout, "[ralph-supervisor] alert: worker crash loop (roles=%s restarts>%d/min); backing off %s\n", roleScopeOrAll(roleScope), profile.SupervisorMaxRestartsPerMin, delay)
+		} else {
+			// The backoff reset, so the worker is no longer crash-looping.
+			supervisorState.LastCrashLoopAt = time.Time{}
+			supervisorState.CrashLoopBackoffSec = 0
+		}
+		if err := SaveSupervisorState(paths, roleScope, supervisorState); err != nil {
+			fmt.Fprintf(stdout, "[ralph-supervisor] warning: save supervisor state failed: %v\n", err)
+		}
+		if delay > 0 {
+			if !crashLoop {
+				fmt.Fprintf(stdout, "[ralph-supervisor] restart delay: %ds\n", restartDelaySec)
+			}
+			if err := sleepWithHeartbeat(runCtx, delay, beat); err != nil && ctx.Err() != nil {
 				return nil
 			}
 		}
 	}
 }
 
+// sleepWithHeartbeat keeps the heartbeat fresh through long restart backoffs.
+func sleepWithHeartbeat(ctx context.Context, d time.Duration, beat func(workerPID int)) error {
+	for d > 0 {
+		step := d
+		if step > supervisorHeartbeatInterval {
+			step = supervisorHeartbeatInterval
+		}
+		if err := sleepOrCancel(ctx, step); err != nil {
+			return err
+		}
+		d -= step
+		beat(0)
+	}
+	return nil
+}
+
+// runSupervisedWorker runs worker to completion, refreshing the heartbeat meanwhile.
+func runSupervisedWorker(worker *exec.Cmd, beat func(workerPID int)) error {
+	if err := worker.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- worker.Wait() }()
+	ticker := time.NewTicker(supervisorHeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		beat(worker.Process.Pid)
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
 func startDaemonWithRoleScope(paths Paths, pidFile, logFile string, allowedRoles map[string]struct{}) (int, bool, error) {
 	if err := EnsureLayout(paths); err != nil {
 		return 0, false, err
@@ -176,6 +328,10 @@ func startDaemonWithRoleScope(paths Paths, pidFile, logFile string, allowedRoles
 		return 0, false, fmt.Errorf("resolve executable: %w", err)
 	}
 
+	maxLogBytes, logBackups := LogRotationLimits(profile)
+	if _, err := RotateLogIfNeeded(logFile, maxLogBytes, logBackups); err != nil {
+		return 0, false, err
+	}
 	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
 		return 0, false, fmt.Errorf("open daemon log: %w", err)
@@ -207,9 +363,11 @@ func startDaemonWithRoleScope(paths Paths, pidFile, logFile string, allowedRoles
 	}
 
 	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(), DaemonLogEnv(logFile, profile)...)
 	cmd.Stdout = f
 	cmd.Stderr = f
 	cmd.Stdin = nil
+	cmd.SysProcAttr = daemonSysProcAttr()
 
 	if err := cmd.Start(); err != nil {
 		return 0, false, fmt.Errorf("start daemon: %w", err)
@@ -222,29 +380,52 @@ func startDaemonWithRoleScope(paths Paths, pidFile, logFile string, allowedRoles
 	return pid, false, nil
 }
 
-func stopDaemonByPIDFile(pidFile string) error {
-	pid, running := daemonPIDFromFile(pidFile)
-	if !running {
-		_ = os.Remove(pidFile)
-		return nil
-	}
+func stopDaemonByPIDFile(pidFile string, drainTimeout time.Duration) error {
+	return stopDaemonsByPIDFiles([]string{pidFile}, drainTimeout)
+}
 
-	proc, err := os.FindProcess(pid)
-	if err == nil {
-		_ = proc.Signal(syscall.SIGTERM)
+// stopDaemonsByPIDFiles sends SIGTERM to every running daemon, waits up to
+// drainTimeout for them to finish in-flight work, then SIGKILLs the rest.
+func stopDaemonsByPIDFiles(pidFiles []string, drainTimeout time.Duration) error {
+	pids := map[string]int{}
+	for _, pidFile := range pidFiles {
+		pid, running := daemonPIDFromFile(pidFile)
+		if !running {
+			if pid > 0 {
+				killRecordedCodexGroup(filepath.Dir(pidFile), pid)
+			}
+			_ = os.Remove(pidFile)
+			continue
+		}
+		if proc, err := os.FindProcess(pid); err == nil {
+			_ = proc.Signal(syscall.SIGTERM)
+		}
+		pids[pidFile] = pid
 	}
-	for i := 0; i < 30; i++ {
-		if !isPIDRunning(pid) {
+
+	deadline := time.Now().Add(drainTimeout)
+	for len(pids) > 0 {
+		for pidFile, pid := range pids {
+			if !isPIDRunning(pid) {
+				_ = os.Remove(pidFile)
+				delete(pids, pidFile)
+			}
+		}
+		if len(pids) == 0 || !time.Now().Before(deadline) {
 			break
 		}
 		time.Sleep(100 * time.Millisecond)
 	}
-	if isPIDRunning(pid) {
-		if proc, findErr := os.FindProcess(pid); findErr == nil {
-			_ = proc.Signal(syscall.SIGKILL)
+	for pidFile, pid := range pids {
+		// Daemons lead their own process group; fall back to the pid for older daemons.
+		if err := killDaemonProcessGroup(pid); err != nil {
+			if proc, findErr := os.FindProcess(pid); findErr == nil {
+				_ = proc.Signal(syscall.SIGKILL)
+			}
 		}
+		killRecordedCodexGroup(filepath.Dir(pidFile), pid)
+		_ = os.Remove(pidFile)
 	}
-	_ = os.Remove(pidFile)
 	return nil
 }
 
@@ -290,7 +471,8 @@ func TailRunner(paths Paths, lines int, follow bool) error {
 
 	args := []string{"-n", strconv.Itoa(lines)}
 	if follow {
-		args = append(args, "-f")
+		// -F reopens the log by name after rotation.
+		args = append(args, "-F")
 	}
 	args = append(args, paths.RunnerLogFile)
 
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package ralph
+
+import "syscall"
+
+// A dedicated process group lets stop kill the worker if draining times out. codex
+// runs in a group of its own (see setCodexProcessGroup), recorded per daemon group
+// so stop can kill it too.
+func daemonSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setpgid: true}
+}
+
+// daemonGroupOwner is the daemon group this process belongs to: the daemon
+// itself, or a worker its supervisor spawned.
+func daemonGroupOwner() int {
+	return syscall.Getpgrp()
+}
+
+func killDaemonProcessGroup(pid int) error {
+	return syscall.Kill(-pid, syscall.SIGKILL)
+}
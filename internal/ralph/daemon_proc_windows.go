@@ -0,0 +1,23 @@
+//go:build windows
+
+package ralph
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+func daemonSysProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// taskkill /T follows the process tree, so codex needs no separate record.
+func daemonGroupOwner() int {
+	return 0
+}
+
+// taskkill /T covers the worker and codex children that a process group covers on unix.
+func killDaemonProcessGroup(pid int) error {
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
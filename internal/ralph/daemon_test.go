@@ -0,0 +1,205 @@
+package ralph
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStopDaemonEscalatesAfterDrainTimeout(t *testing.T) {
+	cmd := exec.Command("bash", "-c", `trap "" TERM; sleep 30`)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start process: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+	// Give bash a moment to install the trap before stop sends SIGTERM.
+	time.Sleep(200 * time.Millisecond)
+
+	pidFile := filepath.Join(t.TempDir(), "daemon.pid")
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(cmd.Process.Pid)+"\n"), 0o644); err != nil {
+		t.Fatalf("write pid file: %v", err)
+	}
+
+	started := time.Now()
+	if err := stopDaemonByPIDFile(pidFile, 300*time.Millisecond); err != nil {
+		t.Fatalf("stop daemon: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed < 300*time.Millisecond {
+		t.Fatalf("stop should wait for the drain timeout before SIGKILL: elapsed=%s", elapsed)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("process survived SIGKILL escalation")
+	}
+	if _, err := os.Stat(pidFile); !os.IsNotExist(err) {
+		t.Fatalf("pid file should be removed: %v", err)
+	}
+}
+
+func TestStopDaemonKillsHungCodexGroup(t *testing.T) {
+	dir := t.TempDir()
+	daemon := exec.Command("bash", "-c", `trap "" TERM; sleep 30`)
+	daemon.SysProcAttr = daemonSysProcAttr()
+	if err := daemon.Start(); err != nil {
+		t.Fatalf("start daemon: %v", err)
+	}
+	go func() { _ = daemon.Wait() }()
+
+	// A hung codex in its own group, as runSingleCodexAttempt starts it, with a
+	// child tool that only dies if the whole codex group is killed.
+	childPIDFile := filepath.Join(t.TempDir(), "child.pid")
+	codex := exec.CommandContext(context.Background(), "bash", "-c", "sleep 60 &\necho $! > "+childPIDFile+"\nwait\n")
+	setCodexProcessGroup(codex)
+	if err := codex.Start(); err != nil {
+		t.Fatalf("start codex: %v", err)
+	}
+	codexDone := make(chan struct{})
+	go func() {
+		_ = codex.Wait()
+		close(codexDone)
+	}()
+	_ = recordCodexGroup(dir, daemon.Process.Pid, codex.Process.Pid)
+	time.Sleep(200 * time.Millisecond)
+
+	pidFile := filepath.Join(dir, "runner.pid")
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(daemon.Process.Pid)+"\n"), 0o644); err != nil {
+		t.Fatalf("write pid file: %v", err)
+	}
+	if err := stopDaemonByPIDFile(pidFile, 300*time.Millisecond); err != nil {
+		t.Fatalf("stop daemon: %v", err)
+	}
+	select {
+	case <-codexDone:
+	case <-time.After(5 * time.Second):
+		_ = killDaemonProcessGroup(codex.Process.Pid)
+		t.Fatalf("codex survived stop")
+	}
+	data, err := os.ReadFile(childPIDFile)
+	if err != nil {
+		t.Fatalf("read child pid: %v", err)
+	}
+	child, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	deadline := time.Now().Add(2 * time.Second)
+	for isPIDRunning(child) {
+		if time.Now().After(deadline) {
+			_ = killDaemonProcessGroup(child)
+			t.Fatalf("codex child %d survived stop", child)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if _, err := os.Stat(codexGroupFile(dir, daemon.Process.Pid)); !os.IsNotExist(err) {
+		t.Fatalf("codex group record should be removed: %v", err)
+	}
+}
+
+func TestDaemonDrainTimeoutFollowsCodexTimeout(t *testing.T) {
+	profile := DefaultProfile()
+	profile.CodexExecTimeoutSec = 60
+	if got := DaemonDrainTimeout(profile); got != 60*time.Second+daemonDrainGrace {
+		t.Fatalf("drain timeout mismatch: %s", got)
+	}
+	profile.CodexExecTimeoutSec = 0
+	if got := DaemonDrainTimeout(profile); got != daemonDrainMaxDefault {
+		t.Fatalf("unbounded codex timeout should use default cap: %s", got)
+	}
+}
+
+func TestSupervisorRestartBackoffEscalatesOnCrashLoop(t *testing.T) {
+	t.Parallel()
+
+	b := supervisorRestartBackoff{maxPerMin: 3}
+	now := time.Now()
+	base := 2 * time.Second
+	for i := 0; i < 3; i++ {
+		if delay, crashLoop := b.next(now.Add(time.Duration(i)*time.Second), time.Second, base); crashLoop || delay != base {
+			t.Fatalf("restart %d should use base delay: delay=%s crashLoop=%t", i+1, delay, crashLoop)
+		}
+	}
+	want := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second}
+	for i, w := range want {
+		delay, crashLoop := b.next(now.Add(time.Duration(3+i)*time.Second), time.Second, base)
+		if !crashLoop || delay != w {
+			t.Fatalf("crash loop restart %d: delay=%s crashLoop=%t want %s", i+1, delay, crashLoop, w)
+		}
+	}
+	// Backoff spacing pushes restarts out of the window but must not reset the level.
+	if delay, crashLoop := b.next(now.Add(10*time.Minute), time.Second, base); !crashLoop || delay != 16*time.Second {
+		t.Fatalf("backoff should keep escalating while the worker still crashes: delay=%s crashLoop=%t", delay, crashLoop)
+	}
+	if delay, crashLoop := b.next(now.Add(2*time.Hour), 2*time.Minute, base); crashLoop || delay != base {
+		t.Fatalf("a worker that ran a full window should reset backoff: delay=%s crashLoop=%t", delay, crashLoop)
+	}
+
+	capped := supervisorRestartBackoff{maxPerMin: 1, level: 20}
+	if delay, _ := capped.next(now, time.Second, base); delay != supervisorMaxBackoff {
+		t.Fatalf("backoff should be capped at %s, got %s", supervisorMaxBackoff, delay)
+	}
+}
+
+func TestLatestSupervisorCrashLoopPicksMostRecentScope(t *testing.T) {
+	paths := newTestPaths(t)
+	if _, _, ok := LatestSupervisorCrashLoop(paths); ok {
+		t.Fatalf("no crash loop expected on a fresh project")
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := SaveSupervisorState(paths, "developer", SupervisorState{LastCrashLoopAt: now.Add(-time.Minute), CrashLoopBackoffSec: 4}); err != nil {
+		t.Fatalf("save developer state: %v", err)
+	}
+	if err := SaveSupervisorState(paths, "qa", SupervisorState{LastCrashLoopAt: now, CrashLoopBackoffSec: 8}); err != nil {
+		t.Fatalf("save qa state: %v", err)
+	}
+	scope, state, ok := LatestSupervisorCrashLoop(paths)
+	if !ok || scope != "qa" || state.CrashLoopBackoffSec != 8 || !state.LastCrashLoopAt.Equal(now) {
+		t.Fatalf("latest crash loop mismatch: scope=%s state=%+v ok=%t", scope, state, ok)
+	}
+	if err := SaveSupervisorState(paths, "planner,qa", SupervisorState{LastCrashLoopAt: now.Add(time.Second), CrashLoopBackoffSec: 16}); err != nil {
+		t.Fatalf("save role set state: %v", err)
+	}
+	if scope, _, ok := LatestSupervisorCrashLoop(paths); !ok || scope != "planner,qa" {
+		t.Fatalf("role set crash loop should be found: scope=%s ok=%t", scope, ok)
+	}
+	st, err := GetStatus(paths)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if st.LastCrashLoopRole != "planner,qa" || st.CrashLoopBackoffSec != 16 {
+		t.Fatalf("status should surface crash loop: %+v", st)
+	}
+}
+
+func TestSupervisorStateRecoversFromCrashLoopAfterStableRun(t *testing.T) {
+	paths := newTestPaths(t)
+	state := SupervisorState{RestartCount: 5, LastCrashLoopAt: time.Now().UTC(), CrashLoopBackoffSec: 8}
+	if state.recoverFromCrashLoop(30 * time.Second) {
+		t.Fatalf("a worker running less than the crash-loop window should not clear it")
+	}
+	if !state.recoverFromCrashLoop(supervisorCrashLoopWindow) {
+		t.Fatalf("a worker stable for the crash-loop window should clear it")
+	}
+	if state.recoverFromCrashLoop(time.Hour) {
+		t.Fatalf("clearing again should be a no-op")
+	}
+	if err := SaveSupervisorState(paths, "developer", state); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+	if _, _, ok := LatestSupervisorCrashLoop(paths); ok {
+		t.Fatalf("recovered supervisor should not report a crash loop")
+	}
+	st, err := GetStatus(paths)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if st.LastCrashLoopAt != "" || st.RoleRestartCounts["developer"] != 5 {
+		t.Fatalf("status should drop the crash loop but keep restarts: %+v", st)
+	}
+}
@@ -0,0 +1,127 @@
+package ralph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const deadLetterRequeueReason = "dead_letter_requeue"
+
+type DeadLetterIssue struct {
+	IssueEntry
+	Cause string
+}
+
+// issueFailedAttempts counts failed Ralph Result sections (blocked or requeued)
+// recorded since the issue was last pulled back from the dead-letter queue.
+func issueFailedAttempts(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	inResult := false
+	status := ""
+	flush := func() {
+		if inResult && status != "" && status != "done" {
+			count++
+		}
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "## Ralph Result":
+			flush()
+			inResult = true
+			status = ""
+		case inResult && strings.HasPrefix(trimmed, "- status:"):
+			status = strings.TrimSpace(strings.TrimPrefix(trimmed, "- status:"))
+		case inResult && strings.HasPrefix(trimmed, "- reason:"):
+			reason := strings.TrimSpace(strings.TrimPrefix(trimmed, "- reason:"))
+			if strings.HasPrefix(reason, deadLetterRequeueReason) {
+				count = 0
+				inResult = false
+			}
+		}
+	}
+	flush()
+	return count, nil
+}
+
+func moveIssueToDeadLetter(paths Paths, inProgressPath string, meta IssueMeta, reason, logPath string) (string, error) {
+	if err := os.MkdirAll(paths.DeadLetterDir, 0o755); err != nil {
+		return "", fmt.Errorf("create dead-letter dir: %w", err)
+	}
+	_ = SetIssueStatus(inProgressPath, "dead-letter")
+	_ = AppendIssueResult(inProgressPath, "dead-letter", reason, logPath)
+	deadPath := filepath.Join(paths.DeadLetterDir, meta.ID+".md")
+	if err := os.Rename(inProgressPath, deadPath); err != nil {
+		return "", fmt.Errorf("move to dead-letter: %w", err)
+	}
+	return deadPath, nil
+}
+
+func ListDeadLetterIssues(paths Paths) ([]DeadLetterIssue, error) {
+	files, err := filepath.Glob(filepath.Join(paths.DeadLetterDir, "I-*.md"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	out := []DeadLetterIssue{}
+	for _, file := range files {
+		meta, err := ReadIssueMeta(file)
+		if err != nil {
+			continue
+		}
+		cause, _ := latestIssueResultReason(file)
+		out = append(out, DeadLetterIssue{
+			IssueEntry: IssueEntry{
+				Path:      file,
+				Status:    "dead-letter",
+				Meta:      meta,
+				CreatedAt: issueCreatedAt(file, meta),
+			},
+			Cause: cause,
+		})
+	}
+	return out, nil
+}
+
+// RequeueDeadLetterIssue moves a dead-lettered issue back to ready with a fresh attempt budget.
+func RequeueDeadLetterIssue(paths Paths, id string) (IssueEntry, error) {
+	id = strings.TrimSuffix(strings.TrimSpace(id), ".md")
+	if id == "" {
+		return IssueEntry{}, fmt.Errorf("issue id is required")
+	}
+	if strings.ContainsAny(id, `/\`) {
+		return IssueEntry{}, fmt.Errorf("invalid issue id: %s", id)
+	}
+	src := filepath.Join(paths.DeadLetterDir, id+".md")
+	if _, err := os.Stat(src); err != nil {
+		if os.IsNotExist(err) {
+			return IssueEntry{}, fmt.Errorf("issue not in dead-letter queue: %s", id)
+		}
+		return IssueEntry{}, err
+	}
+	dst := filepath.Join(paths.IssuesDir, id+".md")
+	if _, err := os.Stat(dst); err == nil {
+		return IssueEntry{}, fmt.Errorf("ready issue already exists: %s", dst)
+	}
+	if err := SetIssueStatus(src, "ready"); err != nil {
+		return IssueEntry{}, err
+	}
+	if err := AppendIssueResult(src, "ready", deadLetterRequeueReason, ""); err != nil {
+		return IssueEntry{}, err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return IssueEntry{}, fmt.Errorf("move to ready: %w", err)
+	}
+	meta, err := ReadIssueMeta(dst)
+	if err != nil {
+		return IssueEntry{}, err
+	}
+	return IssueEntry{Path: dst, Status: "ready", Meta: meta, CreatedAt: issueCreatedAt(dst, meta)}, nil
+}
@@ -0,0 +1,96 @@
+package ralph
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProcessIssueMovesRepeatedFailureToDeadLetter(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+
+	profile := DefaultProfile()
+	profile.RequireCodex = false
+	profile.RoleRulesEnabled = false
+	profile.ValidateRoles = map[string]struct{}{"developer": {}}
+	profile.ValidateCmd = "exit 3"
+	profile.MaxIssueAttempts = 2
+
+	readyPath, id, err := CreateIssue(paths, "developer", "poison issue")
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	meta, err := ReadIssueMeta(readyPath)
+	if err != nil {
+		t.Fatalf("read meta: %v", err)
+	}
+	res, err := processIssue(context.Background(), paths, profile, readyPath, meta, io.Discard)
+	if err != nil || res.Outcome != "blocked" {
+		t.Fatalf("first attempt should block: outcome=%s err=%v", res.Outcome, err)
+	}
+	if moved, err := RetryBlockedIssues(paths, "", 0); err != nil || moved != 1 {
+		t.Fatalf("retry blocked: moved=%d err=%v", moved, err)
+	}
+
+	res, err = processIssue(context.Background(), paths, profile, readyPath, meta, io.Discard)
+	if err != nil || res.Outcome != "dead-letter" {
+		t.Fatalf("second attempt should dead-letter: outcome=%s err=%v", res.Outcome, err)
+	}
+	deadPath := filepath.Join(paths.DeadLetterDir, id+".md")
+	if _, err := os.Stat(deadPath); err != nil {
+		t.Fatalf("dead-letter file missing: %v", err)
+	}
+	entries, err := ListDeadLetterIssues(paths)
+	if err != nil || len(entries) != 1 || entries[0].Meta.ID != id {
+		t.Fatalf("dead-letter list mismatch: %+v err=%v", entries, err)
+	}
+
+	entry, err := RequeueDeadLetterIssue(paths, id)
+	if err != nil {
+		t.Fatalf("requeue: %v", err)
+	}
+	if entry.Meta.Status != "ready" {
+		t.Fatalf("requeued status mismatch: %s", entry.Meta.Status)
+	}
+	if attempts, err := issueFailedAttempts(entry.Path); err != nil || attempts != 0 {
+		t.Fatalf("requeue should reset failed attempts: attempts=%d err=%v", attempts, err)
+	}
+}
+
+func TestProcessIssueDoesNotDeadLetterByDefault(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+
+	profile := DefaultProfile()
+	if profile.MaxIssueAttempts != 0 {
+		t.Fatalf("default max_issue_attempts should be unlimited, got %d", profile.MaxIssueAttempts)
+	}
+	profile.RequireCodex = false
+	profile.RoleRulesEnabled = false
+	profile.ValidateRoles = map[string]struct{}{"developer": {}}
+	profile.ValidateCmd = "exit 3"
+
+	readyPath, _, err := CreateIssue(paths, "developer", "flaky issue")
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	for attempt := 1; attempt <= 3; attempt++ {
+		meta, err := ReadIssueMeta(readyPath)
+		if err != nil {
+			t.Fatalf("read meta: %v", err)
+		}
+		res, err := processIssue(context.Background(), paths, profile, readyPath, meta, io.Discard)
+		if err != nil || res.Outcome != "blocked" {
+			t.Fatalf("attempt %d should block: outcome=%s err=%v", attempt, res.Outcome, err)
+		}
+		if moved, err := RetryBlockedIssues(paths, "", 0); err != nil || moved != 1 {
+			t.Fatalf("retry blocked: moved=%d err=%v", moved, err)
+		}
+	}
+	if entries, err := ListDeadLetterIssues(paths); err != nil || len(entries) != 0 {
+		t.Fatalf("dead-letter should stay empty by default: %+v err=%v", entries, err)
+	}
+}
@@ -0,0 +1,135 @@
+package ralph
+
+import (
+	"fmt"
+	"strings"
+)
+
+const unifiedDiffContext = 3
+
+type diffLineOp struct {
+	kind byte // ' ', '-', '+'
+	text string
+}
+
+// UnifiedDiff renders a line-based unified diff of from -> to, or "" when they are equal.
+func UnifiedDiff(fromName, toName, from, to string) string {
+	ops := diffLines(splitDiffLines(from), splitDiffLines(to))
+	changes := []int{}
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(changes); {
+		end := start
+		for end+1 < len(changes) && changes[end+1]-changes[end] <= 2*unifiedDiffContext {
+			end++
+		}
+		lo := changes[start] - unifiedDiffContext
+		if lo < 0 {
+			lo = 0
+		}
+		hi := changes[end] + unifiedDiffContext + 1
+		if hi > len(ops) {
+			hi = len(ops)
+		}
+		writeDiffHunk(&b, ops, lo, hi)
+		start = end + 1
+	}
+	return b.String()
+}
+
+func writeDiffHunk(b *strings.Builder, ops []diffLineOp, lo, hi int) {
+	fromLine, toLine := 0, 0
+	for _, op := range ops[:lo] {
+		if op.kind != '+' {
+			fromLine++
+		}
+		if op.kind != '-' {
+			toLine++
+		}
+	}
+	fromLen, toLen := 0, 0
+	for _, op := range ops[lo:hi] {
+		if op.kind != '+' {
+			fromLen++
+		}
+		if op.kind != '-' {
+			toLen++
+		}
+	}
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", diffRange(fromLine, fromLen), diffRange(toLine, toLen))
+	for _, op := range ops[lo:hi] {
+		b.WriteByte(op.kind)
+		b.WriteString(op.text)
+		b.WriteByte('\n')
+	}
+}
+
+// diffRange follows the unified format: an empty range names the line before it.
+func diffRange(before, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, length)
+}
+
+func splitDiffLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes an edit script from the longest common subsequence of a and b.
+func diffLines(a, b []string) []diffLineOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]diffLineOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffLineOp{kind: ' ', text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffLineOp{kind: '-', text: a[i]})
+			i++
+		default:
+			ops = append(ops, diffLineOp{kind: '+', text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffLineOp{kind: '-', text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffLineOp{kind: '+', text: b[j]})
+	}
+	return ops
+}
@@ -0,0 +1,24 @@
+package ralph
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	if got := UnifiedDiff("a", "b", "x\ny\n", "x\ny\n"); got != "" {
+		t.Fatalf("equal inputs should produce no diff: %q", got)
+	}
+
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	to := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	want := "--- a\n+++ b\n" +
+		"@@ -1,6 +1,6 @@\n 1\n 2\n-3\n+three\n 4\n 5\n 6\n" +
+		"@@ -10,3 +10,4 @@\n 10\n 11\n 12\n+13\n"
+	if got := UnifiedDiff("a", "b", from, to); got != want {
+		t.Fatalf("diff mismatch:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := UnifiedDiff("a", "b", "", "new\n"); got != "--- a\n+++ b\n@@ -0,0 +1 @@\n+new\n" {
+		t.Fatalf("diff against empty file mismatch: %q", got)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package ralph
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetDiskFreeSpaceExW = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// diskFreeBytes is the space available to the calling user on dir's volume.
+func diskFreeBytes(dir string) (uint64, error) {
+	ptr, err := syscall.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+	var free uint64
+	r, _, callErr := procGetDiskFreeSpaceExW.Call(uintptr(unsafe.Pointer(ptr)), uintptr(unsafe.Pointer(&free)), 0, 0)
+	if r == 0 {
+		return 0, callErr
+	}
+	return free, nil
+}
@@ -2,6 +2,7 @@ package ralph
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -21,15 +22,21 @@ const (
 )
 
 type DoctorCheck struct {
-	Name   string
-	Status string
-	Detail string
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
 }
 
 type DoctorReport struct {
-	UpdatedUTC time.Time
-	ProjectDir string
-	Checks     []DoctorCheck
+	UpdatedUTC time.Time     `json:"updated_utc"`
+	ProjectDir string        `json:"project_dir"`
+	Checks     []DoctorCheck `json:"checks"`
+}
+
+type DoctorSummary struct {
+	Pass int `json:"pass"`
+	Warn int `json:"warn"`
+	Fail int `json:"fail"`
 }
 
 type DoctorRepairAction struct {
@@ -60,6 +67,25 @@ func (r DoctorReport) HasFailures() bool {
 	return r.count(doctorStatusFail) > 0
 }
 
+func (r DoctorReport) Summary() DoctorSummary {
+	return DoctorSummary{
+		Pass: r.count(doctorStatusPass),
+		Warn: r.count(doctorStatusWarn),
+		Fail: r.count(doctorStatusFail),
+	}
+}
+
+func (r DoctorReport) MarshalJSON() ([]byte, error) {
+	type reportAlias DoctorReport
+	return json.Marshal(struct {
+		reportAlias
+		Summary DoctorSummary `json:"summary"`
+	}{
+		reportAlias: reportAlias(r),
+		Summary:     r.Summary(),
+	})
+}
+
 func (r DoctorReport) Print(w io.Writer) {
 	fmt.Fprintln(w, "## Ralph Doctor")
 	fmt.Fprintf(w, "- updated_utc: %s\n", r.UpdatedUTC.Format(time.RFC3339))
@@ -121,9 +147,11 @@ func RunDoctor(paths Paths) (DoctorReport, error) {
 		report.add("plugin", doctorStatusWarn, fmt.Sprintf("plugin file not found: %s", pluginFilePath(paths.ControlDir, profile.PluginName)))
 	} else {
 		report.add("plugin", doctorStatusPass, fmt.Sprintf("plugin file found: %s", profile.PluginName))
+		appendPluginCompatChecks(&report, paths, profile.PluginName)
 	}
 	appendPluginRegistryChecks(&report, paths.ControlDir)
 	appendSecurityChecks(&report, paths, profile)
+	appendDiskChecks(&report, paths, profile)
 
 	if _, err := exec.LookPath("bash"); err != nil {
 		report.add("command:bash", doctorStatusFail, "bash command not found")
@@ -174,6 +202,13 @@ func RunDoctor(paths Paths) (DoctorReport, error) {
 		}
 	}
 
+	if exe, err := CurrentExecutablePath(); err != nil {
+		report.add("wrapper", doctorStatusWarn, fmt.Sprintf("resolve current executable: %v", err))
+	} else {
+		status, detail := checkProjectWrapper(paths, exe)
+		report.add("wrapper", status, detail)
+	}
+
 	status, detail := evaluatePIDFile(paths.PIDFile)
 	report.add("daemon:primary", status, detail)
 	for _, role := range RequiredAgentRoles {
@@ -182,6 +217,9 @@ func RunDoctor(paths Paths) (DoctorReport, error) {
 	}
 	status, detail = evaluatePIDFile(paths.TelegramPIDFile())
 	report.add("daemon:telegram", status, detail)
+	if profile.SupervisorEnabled {
+		appendSupervisorHeartbeatChecks(&report, paths, profile, time.Now().UTC())
+	}
 
 	inProgressCount, inProgressErr := CountIssueFiles(paths.InProgressDir)
 	if inProgressErr != nil {
@@ -267,7 +305,7 @@ func RepairProject(paths Paths) ([]DoctorRepairAction, error) {
 	}
 	removedCount := 0
 	for _, pidFile := range pidFiles {
-		removed, err := removeStalePIDFile(pidFile)
+		reason, err := removeStalePIDFile(pidFile)
 		if err != nil {
 			actions = append(actions, DoctorRepairAction{
 				Name:   "stale-pid",
@@ -276,8 +314,13 @@ func RepairProject(paths Paths) ([]DoctorRepairAction, error) {
 			})
 			continue
 		}
-		if removed {
+		if reason != "" {
 			removedCount++
+			actions = append(actions, DoctorRepairAction{
+				Name:   "stale-pid",
+				Status: doctorStatusPass,
+				Detail: fmt.Sprintf("removed %s (%s)", pidFile, reason),
+			})
 		}
 	}
 	actions = append(actions, DoctorRepairAction{
@@ -286,6 +329,22 @@ func RepairProject(paths Paths) ([]DoctorRepairAction, error) {
 		Detail: fmt.Sprintf("removed %d stale pid file(s)", removedCount),
 	})
 
+	if profile, err := LoadProfile(paths); err != nil {
+		actions = append(actions, DoctorRepairAction{Name: "log-size", Status: doctorStatusFail, Detail: err.Error()})
+	} else {
+		actions = append(actions, repairOversizedLogs(paths, profile))
+	}
+
+	if exe, err := CurrentExecutablePath(); err != nil {
+		actions = append(actions, DoctorRepairAction{
+			Name:   "wrapper",
+			Status: doctorStatusFail,
+			Detail: fmt.Sprintf("resolve current executable: %v", err),
+		})
+	} else {
+		actions = append(actions, repairProjectWrapper(paths, exe))
+	}
+
 	_, primaryRunning := daemonPID(paths)
 	roleRunning, _ := RunningRoleDaemons(paths)
 	if !primaryRunning && len(roleRunning) == 0 {
@@ -314,6 +373,47 @@ func RepairProject(paths Paths) ([]DoctorRepairAction, error) {
 	return actions, nil
 }
 
+// checkProjectWrapper flags a ./ralph wrapper that still points at a moved or replaced binary.
+func checkProjectWrapper(paths Paths, currentExe string) (string, string) {
+	embedded, err := ProjectWrapperExecutable(paths)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return doctorStatusPass, "no ./ralph wrapper installed"
+		}
+		return doctorStatusWarn, err.Error()
+	}
+	if sameExecutable(embedded, currentExe) {
+		return doctorStatusPass, embedded
+	}
+	detail := fmt.Sprintf("wrapper points to %s but current binary is %s", embedded, currentExe)
+	if _, statErr := os.Stat(embedded); statErr != nil {
+		detail += " (wrapper target missing)"
+	}
+	return doctorStatusWarn, detail + " (run: ralphctl doctor --repair or ralphctl reload)"
+}
+
+func repairProjectWrapper(paths Paths, currentExe string) DoctorRepairAction {
+	status, detail := checkProjectWrapper(paths, currentExe)
+	if status == doctorStatusPass {
+		return DoctorRepairAction{Name: "wrapper", Status: doctorStatusPass, Detail: "wrapper up to date"}
+	}
+	if reason := temporaryExecutableReason(currentExe); reason != "" {
+		return DoctorRepairAction{
+			Name:   "wrapper",
+			Status: doctorStatusWarn,
+			Detail: fmt.Sprintf("not rewriting wrapper: current binary %s is %s (run the installed ralphctl)", currentExe, reason),
+		}
+	}
+	previous, _ := ProjectWrapperExecutable(paths)
+	if err := WriteProjectWrapper(paths, currentExe); err != nil {
+		return DoctorRepairAction{Name: "wrapper", Status: doctorStatusFail, Detail: err.Error()}
+	}
+	if previous == "" {
+		return DoctorRepairAction{Name: "wrapper", Status: doctorStatusPass, Detail: fmt.Sprintf("rewrote wrapper (%s)", detail)}
+	}
+	return DoctorRepairAction{Name: "wrapper", Status: doctorStatusPass, Detail: fmt.Sprintf("rewrote wrapper: %s -> %s", previous, currentExe)}
+}
+
 func evaluatePIDFile(path string) (string, string) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -334,7 +434,7 @@ func evaluatePIDFile(path string) (string, string) {
 	if isPIDRunning(pid) {
 		return doctorStatusPass, fmt.Sprintf("running (pid=%d)", pid)
 	}
-	return doctorStatusWarn, fmt.Sprintf("stale pid file (pid=%d not running)", pid)
+	return doctorStatusWarn, fmt.Sprintf("stale pid file (pid=%d not running; doctor --repair removes it)", pid)
 }
 
 func checkNonEmptyFile(path string) (string, string) {
@@ -351,31 +451,34 @@ func checkNonEmptyFile(path string) (string, string) {
 	return doctorStatusPass, path
 }
 
-func removeStalePIDFile(pidFile string) (bool, error) {
+// removeStalePIDFile deletes pidFile unless it names a running process and
+// returns why it was removed, or "" when it was kept or absent.
+func removeStalePIDFile(pidFile string) (string, error) {
 	data, err := os.ReadFile(pidFile)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return false, nil
+			return "", nil
 		}
-		return false, err
+		return "", err
 	}
 
+	reason := ""
 	raw := strings.TrimSpace(string(data))
-	if raw == "" {
-		if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
-			return false, err
-		}
-		return true, nil
-	}
-
 	pid, convErr := strconv.Atoi(raw)
-	if convErr != nil || pid <= 0 || !isPIDRunning(pid) {
-		if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
-			return false, err
-		}
-		return true, nil
+	switch {
+	case raw == "":
+		reason = "empty"
+	case convErr != nil || pid <= 0:
+		reason = "invalid value"
+	case !isPIDRunning(pid):
+		reason = fmt.Sprintf("pid=%d not running", pid)
+	default:
+		return "", nil
+	}
+	if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
+		return "", err
 	}
-	return false, nil
+	return reason, nil
 }
 
 func legacyGoDefaultValidateTargetGap(projectDir string) string {
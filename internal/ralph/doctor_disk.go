@@ -0,0 +1,93 @@
+package ralph
+
+import (
+	"fmt"
+	"os"
+)
+
+const bytesPerMB = 1024 * 1024
+
+// appendDiskChecks warns before a full disk takes the daemons down: free space
+// under the project/control dirs and any log past log_max_size_mb, which a
+// rotating daemon writer would have rotated already.
+func appendDiskChecks(report *DoctorReport, paths Paths, profile Profile) {
+	minFree := uint64(profile.DoctorMinFreeDiskMB) * bytesPerMB
+	for _, target := range []struct{ name, dir string }{
+		{"disk:project", paths.ProjectDir},
+		{"disk:control", paths.ControlDir},
+	} {
+		free, err := diskFreeBytes(target.dir)
+		if err != nil {
+			report.add(target.name, doctorStatusWarn, fmt.Sprintf("free space unavailable: %v", err))
+			continue
+		}
+		detail := fmt.Sprintf("%s free on %s", formatDiskMB(free), target.dir)
+		if minFree > 0 && free < minFree {
+			report.add(target.name, doctorStatusWarn, fmt.Sprintf("%s (below doctor_min_free_disk_mb=%d)", detail, profile.DoctorMinFreeDiskMB))
+		} else {
+			report.add(target.name, doctorStatusPass, detail)
+		}
+	}
+
+	maxSize, _ := LogRotationLimits(profile)
+	for _, source := range ProjectLogSources(paths) {
+		name := "log-size:" + source.Name
+		info, err := os.Stat(source.Path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				report.add(name, doctorStatusPass, "no log yet")
+			} else {
+				report.add(name, doctorStatusWarn, err.Error())
+			}
+			continue
+		}
+		detail := fmt.Sprintf("%s %s", formatDiskMB(uint64(info.Size())), source.Path)
+		if maxSize > 0 && info.Size() > maxSize {
+			report.add(name, doctorStatusWarn, fmt.Sprintf("%s (over log_max_size_mb=%d, run: ralphctl doctor --repair)", detail, profile.LogMaxSizeMB))
+		} else {
+			report.add(name, doctorStatusPass, detail)
+		}
+	}
+}
+
+func formatDiskMB(n uint64) string {
+	return fmt.Sprintf("%.1fMB", float64(n)/bytesPerMB)
+}
+
+// repairOversizedLogs rotates logs past log_max_size_mb, keeping log_max_backups.
+func repairOversizedLogs(paths Paths, profile Profile) DoctorRepairAction {
+	maxSize, backups := LogRotationLimits(profile)
+	if maxSize <= 0 {
+		return DoctorRepairAction{Name: "log-size", Status: doctorStatusPass, Detail: "log size limit disabled (log_max_size_mb=0)"}
+	}
+	rotated := 0
+	for _, source := range ProjectLogSources(paths) {
+		info, err := os.Stat(source.Path)
+		if err != nil || info.Size() <= maxSize {
+			continue
+		}
+		if err := copyTruncateLog(source.Path, backups); err != nil {
+			return DoctorRepairAction{Name: "log-size", Status: doctorStatusFail, Detail: fmt.Sprintf("%s: %v", source.Name, err)}
+		}
+		rotated++
+	}
+	return DoctorRepairAction{Name: "log-size", Status: doctorStatusPass, Detail: fmt.Sprintf("rotated %d oversized log(s)", rotated)}
+}
+
+// copyTruncateLog compresses path into path.1.gz and truncates it in place, so a
+// daemon still appending to the open file keeps logging into the same path. A
+// RotatingLogWriter in that daemon picks up the new size on its next write.
+func copyTruncateLog(path string, backups int) error {
+	if backups > 0 {
+		if err := shiftLogBackups(path, backups); err != nil {
+			return err
+		}
+		if err := gzipLogFile(path, rotatedLogPath(path, 1)); err != nil {
+			return err
+		}
+	}
+	if err := os.Truncate(path, 0); err != nil {
+		return fmt.Errorf("truncate log: %w", err)
+	}
+	return nil
+}
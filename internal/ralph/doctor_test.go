@@ -0,0 +1,211 @@
+package ralph
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDoctorReportJSONIncludesSummary(t *testing.T) {
+	t.Parallel()
+
+	report := DoctorReport{ProjectDir: "/tmp/project"}
+	report.add("codex", doctorStatusPass, "ok")
+	report.add("telegram", doctorStatusWarn, "not configured")
+	report.add("validate", doctorStatusFail, "missing")
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("marshal doctor report: %v", err)
+	}
+	var decoded struct {
+		ProjectDir string        `json:"project_dir"`
+		Checks     []DoctorCheck `json:"checks"`
+		Summary    DoctorSummary `json:"summary"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal doctor report: %v", err)
+	}
+	if decoded.ProjectDir != "/tmp/project" || len(decoded.Checks) != 3 {
+		t.Fatalf("unexpected doctor json: %s", string(data))
+	}
+	if decoded.Checks[2].Name != "validate" || decoded.Checks[2].Status != doctorStatusFail {
+		t.Fatalf("check fields mismatch: %+v", decoded.Checks[2])
+	}
+	if decoded.Summary != (DoctorSummary{Pass: 1, Warn: 1, Fail: 1}) {
+		t.Fatalf("summary mismatch: %+v", decoded.Summary)
+	}
+}
+
+func TestCheckProjectWrapperDetectsMovedBinaryAndRepairs(t *testing.T) {
+	paths := newTestPaths(t)
+
+	if status, _ := checkProjectWrapper(paths, "/usr/local/bin/ralphctl"); status != doctorStatusPass {
+		t.Fatalf("missing wrapper should pass, got %s", status)
+	}
+
+	oldExe := filepath.Join(t.TempDir(), "old dir", "ralphctl")
+	if err := WriteProjectWrapper(paths, oldExe); err != nil {
+		t.Fatalf("write wrapper: %v", err)
+	}
+	if got, err := ProjectWrapperExecutable(paths); err != nil || got != oldExe {
+		t.Fatalf("embedded path mismatch: got=%q err=%v", got, err)
+	}
+
+	newExe := filepath.Join(t.TempDir(), "ralphctl")
+	// Only binaries under the temp dir are refused; move it out of the way.
+	t.Setenv("TMPDIR", t.TempDir())
+	status, detail := checkProjectWrapper(paths, newExe)
+	if status != doctorStatusWarn || !strings.Contains(detail, oldExe) || !strings.Contains(detail, "target missing") {
+		t.Fatalf("stale wrapper should warn: status=%s detail=%s", status, detail)
+	}
+
+	action := repairProjectWrapper(paths, newExe)
+	if action.Status != doctorStatusPass || !strings.Contains(action.Detail, "rewrote wrapper") {
+		t.Fatalf("repair action mismatch: %+v", action)
+	}
+	if got, err := ProjectWrapperExecutable(paths); err != nil || got != newExe {
+		t.Fatalf("wrapper should point at current binary: got=%q err=%v", got, err)
+	}
+	if status, _ := checkProjectWrapper(paths, newExe); status != doctorStatusPass {
+		t.Fatalf("repaired wrapper should pass, got %s", status)
+	}
+}
+
+func TestCheckProjectWrapperResolvesSymlinkedInstall(t *testing.T) {
+	paths := newTestPaths(t)
+	root := t.TempDir()
+	cellar := filepath.Join(root, "Cellar", "ralphctl", "1.2.0", "bin", "ralphctl")
+	link := filepath.Join(root, "bin", "ralphctl")
+	if err := os.MkdirAll(filepath.Dir(cellar), 0o755); err != nil {
+		t.Fatalf("mkdir cellar: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+		t.Fatalf("mkdir bin: %v", err)
+	}
+	writeFile(t, cellar, "#!/bin/sh\n")
+	if err := os.Symlink(cellar, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := WriteProjectWrapper(paths, link); err != nil {
+		t.Fatalf("write wrapper: %v", err)
+	}
+	if status, detail := checkProjectWrapper(paths, cellar); status != doctorStatusPass {
+		t.Fatalf("wrapper through the install symlink should pass: %s %s", status, detail)
+	}
+	if action := repairProjectWrapper(paths, cellar); action.Detail != "wrapper up to date" {
+		t.Fatalf("repair must keep the symlinked path: %+v", action)
+	}
+	if got, _ := ProjectWrapperExecutable(paths); got != link {
+		t.Fatalf("wrapper should still point at the symlink: %s", got)
+	}
+}
+
+func TestRepairProjectWrapperRefusesTemporaryBinary(t *testing.T) {
+	paths := newTestPaths(t)
+	installed := "/usr/local/bin/ralphctl"
+	if err := WriteProjectWrapper(paths, installed); err != nil {
+		t.Fatalf("write wrapper: %v", err)
+	}
+	for _, exe := range []string{
+		filepath.Join(os.TempDir(), "go-run-123", "ralphctl"),
+		filepath.Join("/home/dev/.cache", "go-build", "ab", "ralphctl"),
+	} {
+		action := repairProjectWrapper(paths, exe)
+		if action.Status != doctorStatusWarn || !strings.Contains(action.Detail, "not rewriting wrapper") {
+			t.Fatalf("temporary binary %s must not be baked into the wrapper: %+v", exe, action)
+		}
+		if got, _ := ProjectWrapperExecutable(paths); got != installed {
+			t.Fatalf("wrapper should be left alone, got %s", got)
+		}
+	}
+}
+
+func TestRepairProjectRemovesEachStalePIDFile(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	dead := exec.Command("true")
+	if err := dead.Run(); err != nil {
+		t.Fatalf("run short-lived process: %v", err)
+	}
+	deadPID := strconv.Itoa(dead.Process.Pid)
+	writeFile(t, paths.PIDFile, deadPID+"\n")
+	writeFile(t, paths.RolePIDFile("qa"), "not-a-pid\n")
+	writeFile(t, paths.TelegramPIDFile(), deadPID+"\n")
+	live := paths.RolePIDFile("developer")
+	writeFile(t, live, strconv.Itoa(os.Getpid())+"\n")
+
+	if status, detail := evaluatePIDFile(paths.PIDFile); status != doctorStatusWarn || !strings.Contains(detail, "stale pid file") {
+		t.Fatalf("stale pid file should warn: status=%s detail=%s", status, detail)
+	}
+
+	actions, err := RepairProject(paths)
+	if err != nil {
+		t.Fatalf("repair project: %v", err)
+	}
+	removed := []string{}
+	for _, action := range actions {
+		if action.Name == "stale-pid" && strings.HasPrefix(action.Detail, "removed ") && !strings.Contains(action.Detail, "stale pid file(s)") {
+			removed = append(removed, action.Detail)
+		}
+	}
+	if len(removed) != 3 {
+		t.Fatalf("expected one action per stale pid file, got %v", removed)
+	}
+	joined := strings.Join(removed, "\n")
+	if !strings.Contains(joined, paths.RolePIDFile("qa")+" (invalid value)") || !strings.Contains(joined, "pid="+deadPID+" not running") {
+		t.Fatalf("removal details mismatch:\n%s", joined)
+	}
+	for _, path := range []string{paths.PIDFile, paths.RolePIDFile("qa"), paths.TelegramPIDFile()} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("stale pid file should be removed: %s", path)
+		}
+	}
+	if _, err := os.Stat(live); err != nil {
+		t.Fatalf("live pid file must be kept: %v", err)
+	}
+}
+
+func TestDiskChecksWarnOnLowSpaceAndOversizedLogsAndRepairRotates(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	if err := os.WriteFile(paths.RunnerLogFile, make([]byte, 2*bytesPerMB), 0o644); err != nil {
+		t.Fatalf("write runner log: %v", err)
+	}
+
+	profile := DefaultProfile()
+	profile.DoctorMinFreeDiskMB = 1 << 40
+	profile.LogMaxSizeMB = 1
+	profile.LogMaxBackups = 2
+	report := DoctorReport{}
+	appendDiskChecks(&report, paths, profile)
+	statuses := map[string]string{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	if statuses["disk:project"] != doctorStatusWarn || statuses["disk:control"] != doctorStatusWarn {
+		t.Fatalf("free space below threshold should warn: %+v", report.Checks)
+	}
+	if statuses["log-size:loop"] != doctorStatusWarn || statuses["log-size:telegram"] != doctorStatusPass {
+		t.Fatalf("only the oversized log should warn: %+v", report.Checks)
+	}
+
+	action := repairOversizedLogs(paths, profile)
+	if action.Status != doctorStatusPass || action.Detail != "rotated 1 oversized log(s)" {
+		t.Fatalf("repair action mismatch: %+v", action)
+	}
+	if info, err := os.Stat(paths.RunnerLogFile); err != nil || info.Size() != 0 {
+		t.Fatalf("runner log should be truncated in place: info=%v err=%v", info, err)
+	}
+	if _, err := os.Stat(paths.RunnerLogFile + ".1.gz"); err != nil {
+		t.Fatalf("rotated backup missing: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package ralph
+
+import "strings"
+
+// ClassifyFailureCategory buckets a failure cause (a loop block reason or a
+// codex error) so operators can tell a transient blip from a persistent
+// problem: auth, config, permission, timeout, network, validation,
+// completion_gate, canceled, dry_run, not_installed, file_not_found,
+// invalid_response or exec_failure. Returns "" for an empty cause.
+func ClassifyFailureCategory(cause string) string {
+	raw := strings.ToLower(strings.TrimSpace(cause))
+	if raw == "" {
+		return ""
+	}
+	// Reason tokens written by the main loop go first; the substring rules
+	// below were built for free-form codex errors.
+	switch {
+	case strings.Contains(raw, "codex_auth_error"):
+		return "auth"
+	case strings.Contains(raw, "codex_invalid_args"), strings.Contains(raw, "codex_model_error"):
+		return "config"
+	case strings.Contains(raw, "codex_permission_denied"), strings.Contains(raw, "process_permission_error"):
+		return "permission"
+	case strings.Contains(raw, "validate_exit_"):
+		return "validation"
+	case strings.Contains(raw, "completion_gate_"):
+		return "completion_gate"
+	case strings.Contains(raw, "codex_canceled"), strings.Contains(raw, "codex_retry_canceled"):
+		return "canceled"
+	case strings.Contains(raw, "codex_dry_run"):
+		return "dry_run"
+	}
+	switch {
+	case strings.Contains(raw, "not found"):
+		return "not_installed"
+	case strings.Contains(raw, "no such file or directory"), strings.Contains(raw, "os error 2"):
+		return "file_not_found"
+	case strings.Contains(raw, "timeout"), strings.Contains(raw, "deadline exceeded"):
+		return "timeout"
+	case strings.Contains(raw, "operation not permitted"), strings.Contains(raw, "permission denied"):
+		return "permission"
+	case strings.Contains(raw, "could not resolve host"), strings.Contains(raw, "connection refused"),
+		strings.Contains(raw, "network"), strings.Contains(raw, "i/o timeout"), strings.Contains(raw, "temporary failure in name resolution"):
+		return "network"
+	case strings.Contains(raw, "json"), strings.Contains(raw, "parse"):
+		return "invalid_response"
+	default:
+		return "exec_failure"
+	}
+}
@@ -0,0 +1,31 @@
+package ralph
+
+import "testing"
+
+func TestClassifyFailureCategory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		cause string
+		want  string
+	}{
+		{"", ""},
+		{"codex_failed_after_3_attempts: codex_timeout_900s", "timeout"},
+		{"codex_failed_after_3_attempts: codex_exit_1 (network)", "network"},
+		{"codex_failed_after_3_attempts: codex_exit_1", "exec_failure"},
+		{"codex_permission_denied: codex_permission_denied", "permission"},
+		{"open /work/.ralph/issues: permission denied", "permission"},
+		{"codex_auth_error", "auth"},
+		{"codex_model_error", "config"},
+		{"validate_exit_2", "validation"},
+		{"completion_gate_checklist_incomplete", "completion_gate"},
+		{"dead_letter attempts=3/3; cause=codex_failed_after_3_attempts: codex_exit_1 (network)", "network"},
+		{"could not resolve host: api.openai.com", "network"},
+		{"codex command not found", "not_installed"},
+	}
+	for _, tt := range tests {
+		if got := ClassifyFailureCategory(tt.cause); got != tt.want {
+			t.Fatalf("ClassifyFailureCategory(%q)=%q want=%q", tt.cause, got, tt.want)
+		}
+	}
+}
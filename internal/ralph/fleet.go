@@ -18,6 +18,9 @@ type FleetProject struct {
 	PRDPath       string   `json:"prd_path,omitempty"`
 	AssignedRoles []string `json:"assigned_roles"`
 	CreatedAtUTC  string   `json:"created_at_utc"`
+	// Optional per-project telegram alert thresholds; 0 falls back to the daemon's global value.
+	NotifyRetryThreshold      int `json:"notify_retry_threshold,omitempty"`
+	NotifyPermStreakThreshold int `json:"notify_perm_streak_threshold,omitempty"`
 }
 
 type FleetConfig struct {
@@ -34,6 +37,22 @@ func fleetConfigPath(controlDir string) string {
 }
 
 func LoadFleetConfig(controlDir string) (FleetConfig, error) {
+	cfg, err := LoadFleetConfigUnvalidated(controlDir)
+	if err != nil {
+		return FleetConfig{}, err
+	}
+	for i := range cfg.Projects {
+		if err := validateFleetProjectRoles(cfg.Projects[i]); err != nil {
+			return FleetConfig{}, err
+		}
+		cfg.Projects[i].AssignedRoles = NormalizeRequiredRoles(cfg.Projects[i].AssignedRoles)
+	}
+	return cfg, nil
+}
+
+// LoadFleetConfigUnvalidated parses projects.json without role validation so
+// `fleet doctor` can still report a hand-edited config that LoadFleetConfig rejects.
+func LoadFleetConfigUnvalidated(controlDir string) (FleetConfig, error) {
 	path := fleetConfigPath(controlDir)
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -53,13 +72,21 @@ func LoadFleetConfig(controlDir string) (FleetConfig, error) {
 	if cfg.Projects == nil {
 		cfg.Projects = []FleetProject{}
 	}
-	for i := range cfg.Projects {
-		cfg.Projects[i].AssignedRoles = NormalizeRequiredRoles(cfg.Projects[i].AssignedRoles)
-		if err := ValidateRequiredRoleSet(cfg.Projects[i].AssignedRoles); err != nil {
-			return FleetConfig{}, fmt.Errorf("invalid role set for project %s: %w", cfg.Projects[i].ID, err)
+	return cfg, nil
+}
+
+// validateFleetProjectRoles rejects unsupported assigned roles by name before
+// NormalizeRequiredRoles would silently drop them. An empty list means all roles.
+func validateFleetProjectRoles(p FleetProject) error {
+	for _, role := range p.AssignedRoles {
+		if !IsSupportedRole(role) {
+			return fmt.Errorf("fleet project %s has unsupported assigned role %q (supported: %s)", p.ID, strings.TrimSpace(role), strings.Join(RequiredAgentRoles, ","))
 		}
 	}
-	return cfg, nil
+	if err := ValidateRequiredRoleSet(NormalizeRequiredRoles(p.AssignedRoles)); err != nil {
+		return fmt.Errorf("invalid role set for project %s: %w", p.ID, err)
+	}
+	return nil
 }
 
 func SaveFleetConfig(controlDir string, cfg FleetConfig) error {
@@ -80,16 +107,23 @@ func SaveFleetConfig(controlDir string, cfg FleetConfig) error {
 	return nil
 }
 
-func RegisterFleetProject(controlDir, id, projectDir, plugin, prdPath string) (FleetProject, error) {
-	id = strings.TrimSpace(id)
+func validateFleetProjectID(id string) error {
 	if id == "" {
-		return FleetProject{}, fmt.Errorf("project id is required")
+		return fmt.Errorf("project id is required")
 	}
 	for _, ch := range id {
 		if !(ch == '-' || ch == '_' || ch == '.' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')) {
-			return FleetProject{}, fmt.Errorf("project id contains unsupported character: %q", ch)
+			return fmt.Errorf("project id contains unsupported character: %q", ch)
 		}
 	}
+	return nil
+}
+
+func RegisterFleetProject(controlDir, id, projectDir, plugin, prdPath string) (FleetProject, error) {
+	id = strings.TrimSpace(id)
+	if err := validateFleetProjectID(id); err != nil {
+		return FleetProject{}, err
+	}
 
 	if strings.TrimSpace(projectDir) == "" {
 		return FleetProject{}, fmt.Errorf("project-dir is required")
@@ -118,7 +152,7 @@ func RegisterFleetProject(controlDir, id, projectDir, plugin, prdPath string) (F
 			return FleetProject{}, fmt.Errorf("fleet project already exists: %s", id)
 		}
 		if samePath(p.ProjectDir, absProject) {
-			return FleetProject{}, fmt.Errorf("project-dir already registered by %s: %s", p.ID, absProject)
+			return FleetProject{}, fmt.Errorf("project-dir %s is already registered by fleet project %q; sharing a project-dir breaks daemon pid tracking (unregister %s first)", absProject, p.ID, p.ID)
 		}
 	}
 
@@ -131,6 +165,9 @@ func RegisterFleetProject(controlDir, id, projectDir, plugin, prdPath string) (F
 		CreatedAtUTC:  time.Now().UTC().Format(time.RFC3339),
 	}
 
+	if err := validateFleetProjectRoles(fp); err != nil {
+		return FleetProject{}, err
+	}
 	cfg.Projects = append(cfg.Projects, fp)
 	if err := SaveFleetConfig(controlDir, cfg); err != nil {
 		return FleetProject{}, err
@@ -159,6 +196,174 @@ func UnregisterFleetProject(controlDir, id string) error {
 	return SaveFleetConfig(controlDir, cfg)
 }
 
+// RenameFleetProject changes a project's fleet id and keeps everything else.
+// Daemons key on the project dir, so running workers are unaffected.
+func RenameFleetProject(controlDir, oldID, newID string) (FleetProject, error) {
+	oldID = strings.TrimSpace(oldID)
+	newID = strings.TrimSpace(newID)
+	if err := validateFleetProjectID(newID); err != nil {
+		return FleetProject{}, err
+	}
+	cfg, err := LoadFleetConfig(controlDir)
+	if err != nil {
+		return FleetProject{}, err
+	}
+	idx := -1
+	for i, p := range cfg.Projects {
+		if p.ID == newID && newID != oldID {
+			return FleetProject{}, fmt.Errorf("fleet project already exists: %s", newID)
+		}
+		if p.ID == oldID {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return FleetProject{}, fmt.Errorf("fleet project not found: %s", oldID)
+	}
+	cfg.Projects[idx].ID = newID
+	if err := SaveFleetConfig(controlDir, cfg); err != nil {
+		return FleetProject{}, err
+	}
+	return cfg.Projects[idx], nil
+}
+
+type FleetMoveResult struct {
+	Project       FleetProject
+	OldProjectDir string
+	RalphMigrated bool
+	// Service is the reinstalled OS service, when one was installed for the old dir.
+	Service *ServiceInstallResult
+}
+
+// MoveFleetProject repoints a fleet project at newDir, which must already exist.
+// Daemons of the project are stopped first; with migrateRalph the old .ralph dir
+// (queue, state, logs) is moved along instead of starting from an empty layout.
+// An OS service installed for the old dir is reinstalled for newDir under the
+// same name, running executablePath.
+func MoveFleetProject(controlDir, id, newDir string, migrateRalph bool, executablePath string) (FleetMoveResult, error) {
+	if strings.TrimSpace(newDir) == "" {
+		return FleetMoveResult{}, fmt.Errorf("project-dir is required")
+	}
+	absNew, err := filepath.Abs(newDir)
+	if err != nil {
+		return FleetMoveResult{}, fmt.Errorf("resolve project-dir: %w", err)
+	}
+	info, err := os.Stat(absNew)
+	if err != nil {
+		return FleetMoveResult{}, fmt.Errorf("new project-dir: %w", err)
+	}
+	if !info.IsDir() {
+		return FleetMoveResult{}, fmt.Errorf("new project-dir is not a directory: %s", absNew)
+	}
+
+	cfg, err := LoadFleetConfig(controlDir)
+	if err != nil {
+		return FleetMoveResult{}, err
+	}
+	idx := -1
+	for i, p := range cfg.Projects {
+		if p.ID == id {
+			idx = i
+			continue
+		}
+		if samePath(p.ProjectDir, absNew) {
+			return FleetMoveResult{}, fmt.Errorf("project-dir %s is already registered by fleet project %q", absNew, p.ID)
+		}
+	}
+	if idx < 0 {
+		return FleetMoveResult{}, fmt.Errorf("fleet project not found: %s", id)
+	}
+	oldDir := cfg.Projects[idx].ProjectDir
+	if samePath(oldDir, absNew) {
+		return FleetMoveResult{}, fmt.Errorf("fleet project %s already uses %s", id, absNew)
+	}
+	oldPaths, err := NewPaths(controlDir, oldDir)
+	if err != nil {
+		return FleetMoveResult{}, err
+	}
+	newPaths, err := NewPaths(controlDir, absNew)
+	if err != nil {
+		return FleetMoveResult{}, err
+	}
+
+	// The service would restart supervise in the old dir, so it goes before the daemons.
+	service, hasService, err := FindProjectService(oldPaths)
+	if err != nil {
+		return FleetMoveResult{}, fmt.Errorf("find service for %s: %w", oldDir, err)
+	}
+	if hasService {
+		if _, err := UninstallService(oldPaths, installedServiceName(service)); err != nil {
+			return FleetMoveResult{}, fmt.Errorf("uninstall service %s: %w", service.ServiceName, err)
+		}
+	}
+
+	// A repo moved on disk takes its .ralph (and pid files) along, so check both sides.
+	for _, p := range []Paths{oldPaths, newPaths} {
+		if _, err := os.Stat(p.RalphDir); err != nil {
+			continue
+		}
+		if err := StopDaemon(p); err != nil {
+			return FleetMoveResult{}, fmt.Errorf("stop daemons in %s: %w", p.ProjectDir, err)
+		}
+	}
+
+	res := FleetMoveResult{OldProjectDir: oldDir}
+	if migrateRalph {
+		if _, err := os.Stat(oldPaths.RalphDir); err == nil {
+			if _, err := os.Stat(newPaths.RalphDir); err == nil {
+				return FleetMoveResult{}, fmt.Errorf("cannot migrate .ralph: %s already exists", newPaths.RalphDir)
+			}
+			if err := moveDir(oldPaths.RalphDir, newPaths.RalphDir); err != nil {
+				return FleetMoveResult{}, fmt.Errorf("migrate .ralph: %w", err)
+			}
+			res.RalphMigrated = true
+		}
+	}
+
+	cfg.Projects[idx].ProjectDir = absNew
+	if err := SaveFleetConfig(controlDir, cfg); err != nil {
+		return FleetMoveResult{}, err
+	}
+	res.Project = cfg.Projects[idx]
+	if hasService {
+		installed, err := InstallService(newPaths, executablePath, installedServiceName(service), service.Active)
+		if err != nil {
+			return res, fmt.Errorf("reinstall service %s for %s: %w", service.ServiceName, absNew, err)
+		}
+		res.Service = &installed
+	}
+	return res, nil
+}
+
+// SetFleetProjectNotifyThresholds stores per-project alert thresholds; 0 clears
+// an override so the telegram daemon's global threshold applies again. A nil
+// threshold keeps the stored value.
+func SetFleetProjectNotifyThresholds(controlDir, id string, retryThreshold, permStreakThreshold *int) (FleetProject, error) {
+	if (retryThreshold != nil && *retryThreshold < 0) || (permStreakThreshold != nil && *permStreakThreshold < 0) {
+		return FleetProject{}, fmt.Errorf("notify thresholds must be >= 0")
+	}
+	cfg, err := LoadFleetConfig(controlDir)
+	if err != nil {
+		return FleetProject{}, err
+	}
+	for i, p := range cfg.Projects {
+		if p.ID != id {
+			continue
+		}
+		if retryThreshold != nil {
+			cfg.Projects[i].NotifyRetryThreshold = *retryThreshold
+		}
+		if permStreakThreshold != nil {
+			cfg.Projects[i].NotifyPermStreakThreshold = *permStreakThreshold
+		}
+		if err := SaveFleetConfig(controlDir, cfg); err != nil {
+			return FleetProject{}, err
+		}
+		return cfg.Projects[i], nil
+	}
+	return FleetProject{}, fmt.Errorf("fleet project not found: %s", id)
+}
+
 func FindFleetProject(cfg FleetConfig, id string) (FleetProject, bool) {
 	for _, p := range cfg.Projects {
 		if p.ID == id {
@@ -237,6 +442,72 @@ func EnsureFleetAgentSetFile(paths Paths, project FleetProject) error {
 	return os.WriteFile(paths.AgentSetFile, []byte(content), 0o644)
 }
 
+type FleetProjectDirCollision struct {
+	ProjectDir string
+	IDs        []string
+}
+
+func FleetProjectDirCollisions(cfg FleetConfig) []FleetProjectDirCollision {
+	order := []string{}
+	byDir := map[string][]string{}
+	for _, p := range cfg.Projects {
+		dir := filepath.Clean(p.ProjectDir)
+		if _, ok := byDir[dir]; !ok {
+			order = append(order, dir)
+		}
+		byDir[dir] = append(byDir[dir], p.ID)
+	}
+	out := []FleetProjectDirCollision{}
+	for _, dir := range order {
+		if ids := byDir[dir]; len(ids) > 1 {
+			out = append(out, FleetProjectDirCollision{ProjectDir: dir, IDs: ids})
+		}
+	}
+	return out
+}
+
+func FleetConfigChecks(cfg FleetConfig) []DoctorCheck {
+	collisions := FleetProjectDirCollisions(cfg)
+	if len(collisions) == 0 {
+		return []DoctorCheck{{
+			Name:   "fleet_project_dir_unique",
+			Status: doctorStatusPass,
+			Detail: fmt.Sprintf("%d projects, no shared project_dir", len(cfg.Projects)),
+		}}
+	}
+	checks := []DoctorCheck{}
+	for _, c := range collisions {
+		checks = append(checks, DoctorCheck{
+			Name:   "fleet_project_dir_unique",
+			Status: doctorStatusFail,
+			Detail: fmt.Sprintf("project_dir %s shared by %s (unregister all but one)", c.ProjectDir, strings.Join(c.IDs, ",")),
+		})
+	}
+	return checks
+}
+
+// FleetRoleChecks reports projects whose persisted assigned_roles LoadFleetConfig would reject.
+func FleetRoleChecks(cfg FleetConfig) []DoctorCheck {
+	checks := []DoctorCheck{}
+	for _, p := range cfg.Projects {
+		if err := validateFleetProjectRoles(p); err != nil {
+			checks = append(checks, DoctorCheck{
+				Name:   "fleet_assigned_roles",
+				Status: doctorStatusFail,
+				Detail: fmt.Sprintf("%v (fix assigned_roles in fleet/projects.json)", err),
+			})
+		}
+	}
+	if len(checks) == 0 {
+		return []DoctorCheck{{
+			Name:   "fleet_assigned_roles",
+			Status: doctorStatusPass,
+			Detail: "all projects use supported roles",
+		}}
+	}
+	return checks
+}
+
 func samePath(a, b string) bool {
 	ca := filepath.Clean(a)
 	cb := filepath.Clean(b)
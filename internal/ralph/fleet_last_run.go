@@ -0,0 +1,82 @@
+package ralph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FleetLastRun records the outcome of the last `fleet start` / `fleet apply-plugin`
+// so `fleet retry-last` can re-run only the projects that did not finish.
+type FleetLastRun struct {
+	Command      string   `json:"command"`
+	Args         []string `json:"args"`
+	Succeeded    []string `json:"succeeded"`
+	Failed       []string `json:"failed"`
+	Pending      []string `json:"pending"`
+	UpdatedAtUTC string   `json:"updated_at_utc"`
+}
+
+// RetryIDs are the failed projects followed by the ones never attempted.
+func (r FleetLastRun) RetryIDs() []string {
+	ids := make([]string, 0, len(r.Failed)+len(r.Pending))
+	ids = append(ids, r.Failed...)
+	return append(ids, r.Pending...)
+}
+
+func fleetLastRunPath(controlDir string) string {
+	return filepath.Join(fleetDir(controlDir), "last-run.json")
+}
+
+// LoadFleetLastRun returns ok=false when no fleet run has been recorded yet.
+func LoadFleetLastRun(controlDir string) (FleetLastRun, bool, error) {
+	data, err := os.ReadFile(fleetLastRunPath(controlDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return FleetLastRun{}, false, nil
+		}
+		return FleetLastRun{}, false, fmt.Errorf("read fleet last run: %w", err)
+	}
+	run := FleetLastRun{}
+	if err := json.Unmarshal(data, &run); err != nil {
+		return FleetLastRun{}, false, fmt.Errorf("parse fleet last run: %w", err)
+	}
+	return run, true, nil
+}
+
+func SaveFleetLastRun(controlDir string, run FleetLastRun) error {
+	if err := os.MkdirAll(fleetDir(controlDir), 0o755); err != nil {
+		return fmt.Errorf("create fleet dir: %w", err)
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal fleet last run: %w", err)
+	}
+	if err := os.WriteFile(fleetLastRunPath(controlDir), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write fleet last run: %w", err)
+	}
+	return nil
+}
+
+// ResolveFleetRetryProjects resolves the last run's retry ids against the current
+// fleet config, so a project unregistered since then is reported instead of retried.
+func ResolveFleetRetryProjects(controlDir string, run FleetLastRun) ([]FleetProject, error) {
+	cfg, err := LoadFleetConfig(controlDir)
+	if err != nil {
+		return nil, err
+	}
+	ids := run.RetryIDs()
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("last fleet %s has no failed projects to retry", run.Command)
+	}
+	projects := make([]FleetProject, 0, len(ids))
+	for _, id := range ids {
+		project, ok := FindFleetProject(cfg, id)
+		if !ok {
+			return nil, fmt.Errorf("fleet project not found: %s", id)
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
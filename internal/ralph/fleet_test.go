@@ -0,0 +1,272 @@
+package ralph
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRegisterFleetProjectRejectsSharedProjectDir(t *testing.T) {
+	t.Parallel()
+
+	controlDir := t.TempDir()
+	if err := EnsureDefaultControlAssets(controlDir); err != nil {
+		t.Fatalf("ensure control assets: %v", err)
+	}
+	projectDir := filepath.Join(t.TempDir(), "app")
+	if _, err := RegisterFleetProject(controlDir, "app", projectDir, "", ""); err != nil {
+		t.Fatalf("register first project: %v", err)
+	}
+	_, err := RegisterFleetProject(controlDir, "app-copy", projectDir+string(filepath.Separator)+".", "", "")
+	if err == nil {
+		t.Fatalf("expected shared project-dir to be rejected")
+	}
+	if !strings.Contains(err.Error(), `"app"`) {
+		t.Fatalf("error should name the existing project: %v", err)
+	}
+}
+
+func TestFleetConfigChecksFlagsProjectDirCollisions(t *testing.T) {
+	t.Parallel()
+
+	cfg := FleetConfig{Projects: []FleetProject{
+		{ID: "a", ProjectDir: "/work/app"},
+		{ID: "b", ProjectDir: "/work/other"},
+		{ID: "c", ProjectDir: "/work/app/"},
+	}}
+	collisions := FleetProjectDirCollisions(cfg)
+	if len(collisions) != 1 || collisions[0].ProjectDir != "/work/app" || strings.Join(collisions[0].IDs, ",") != "a,c" {
+		t.Fatalf("collision mismatch: %+v", collisions)
+	}
+	checks := FleetConfigChecks(cfg)
+	if len(checks) != 1 || checks[0].Status != doctorStatusFail {
+		t.Fatalf("expected one failing check: %+v", checks)
+	}
+
+	cfg.Projects = cfg.Projects[:2]
+	checks = FleetConfigChecks(cfg)
+	if len(checks) != 1 || checks[0].Status != doctorStatusPass {
+		t.Fatalf("expected passing check: %+v", checks)
+	}
+}
+
+func TestLoadFleetConfigRejectsUnsupportedAssignedRole(t *testing.T) {
+	t.Parallel()
+
+	controlDir := t.TempDir()
+	if err := SaveFleetConfig(controlDir, FleetConfig{Projects: []FleetProject{
+		{ID: "app", ProjectDir: "/work/app", AssignedRoles: []string{"manager", "planner", "developer", "qa"}},
+		{ID: "web", ProjectDir: "/work/web", AssignedRoles: []string{"manager", "planner", "developer", "qa", "tester"}},
+	}}); err != nil {
+		t.Fatalf("save fleet config: %v", err)
+	}
+
+	_, err := LoadFleetConfig(controlDir)
+	if err == nil || !strings.Contains(err.Error(), "web") || !strings.Contains(err.Error(), `"tester"`) {
+		t.Fatalf("expected error naming project and role, got %v", err)
+	}
+	cfg, err := LoadFleetConfigUnvalidated(controlDir)
+	if err != nil {
+		t.Fatalf("unvalidated load should succeed: %v", err)
+	}
+	checks := FleetRoleChecks(cfg)
+	if len(checks) != 1 || checks[0].Status != doctorStatusFail || !strings.Contains(checks[0].Detail, "web") {
+		t.Fatalf("expected one failing role check for web: %+v", checks)
+	}
+
+	cfg.Projects = cfg.Projects[:1]
+	if checks := FleetRoleChecks(cfg); len(checks) != 1 || checks[0].Status != doctorStatusPass {
+		t.Fatalf("expected passing role check: %+v", checks)
+	}
+}
+
+func TestSetFleetProjectNotifyThresholdsPersistsOverrides(t *testing.T) {
+	t.Parallel()
+
+	controlDir := t.TempDir()
+	if err := EnsureDefaultControlAssets(controlDir); err != nil {
+		t.Fatalf("ensure control assets: %v", err)
+	}
+	if _, err := RegisterFleetProject(controlDir, "proto", filepath.Join(t.TempDir(), "proto"), "", ""); err != nil {
+		t.Fatalf("register project: %v", err)
+	}
+	five, six, zero, negative := 5, 6, 0, -1
+	if _, err := SetFleetProjectNotifyThresholds(controlDir, "proto", &five, &zero); err != nil {
+		t.Fatalf("set thresholds: %v", err)
+	}
+	cfg, err := LoadFleetConfig(controlDir)
+	if err != nil {
+		t.Fatalf("load fleet config: %v", err)
+	}
+	fp, ok := FindFleetProject(cfg, "proto")
+	if !ok || fp.NotifyRetryThreshold != 5 || fp.NotifyPermStreakThreshold != 0 {
+		t.Fatalf("thresholds not persisted: %+v", fp)
+	}
+	fp, err = SetFleetProjectNotifyThresholds(controlDir, "proto", nil, &six)
+	if err != nil || fp.NotifyRetryThreshold != 5 || fp.NotifyPermStreakThreshold != 6 {
+		t.Fatalf("nil threshold should keep the stored value: %+v err=%v", fp, err)
+	}
+	if _, err := SetFleetProjectNotifyThresholds(controlDir, "proto", &negative, nil); err == nil {
+		t.Fatalf("negative threshold should be rejected")
+	}
+	if _, err := SetFleetProjectNotifyThresholds(controlDir, "missing", &five, &five); err == nil {
+		t.Fatalf("unknown project should be rejected")
+	}
+}
+
+func TestRenameFleetProjectKeepsProjectAndRejectsCollisions(t *testing.T) {
+	t.Parallel()
+
+	controlDir := t.TempDir()
+	if err := EnsureDefaultControlAssets(controlDir); err != nil {
+		t.Fatalf("ensure control assets: %v", err)
+	}
+	orig, err := RegisterFleetProject(controlDir, "app", filepath.Join(t.TempDir(), "app"), "", "docs/PRD.md")
+	if err != nil {
+		t.Fatalf("register app: %v", err)
+	}
+	if _, err := RegisterFleetProject(controlDir, "other", filepath.Join(t.TempDir(), "other"), "", ""); err != nil {
+		t.Fatalf("register other: %v", err)
+	}
+
+	if _, err := RenameFleetProject(controlDir, "app", "other"); err == nil {
+		t.Fatalf("rename onto an existing id should be rejected")
+	}
+	if _, err := RenameFleetProject(controlDir, "app", "bad id"); err == nil {
+		t.Fatalf("rename to an invalid id should be rejected")
+	}
+	if _, err := RenameFleetProject(controlDir, "app", "app-prod"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+
+	cfg, err := LoadFleetConfig(controlDir)
+	if err != nil {
+		t.Fatalf("load fleet config: %v", err)
+	}
+	if _, ok := FindFleetProject(cfg, "app"); ok {
+		t.Fatalf("old id should be gone")
+	}
+	got, ok := FindFleetProject(cfg, "app-prod")
+	if !ok {
+		t.Fatalf("renamed project not found")
+	}
+	if got.ProjectDir != orig.ProjectDir || got.Plugin != orig.Plugin || got.PRDPath != orig.PRDPath || strings.Join(got.AssignedRoles, ",") != strings.Join(orig.AssignedRoles, ",") {
+		t.Fatalf("rename should keep project fields: got=%+v want=%+v", got, orig)
+	}
+}
+
+func TestMoveFleetProjectRepointsAndMigratesRalphDir(t *testing.T) {
+	t.Parallel()
+
+	controlDir := t.TempDir()
+	if err := EnsureDefaultControlAssets(controlDir); err != nil {
+		t.Fatalf("ensure control assets: %v", err)
+	}
+	oldDir := filepath.Join(t.TempDir(), "app")
+	if _, err := RegisterFleetProject(controlDir, "app", oldDir, "", ""); err != nil {
+		t.Fatalf("register app: %v", err)
+	}
+	oldPaths, err := NewPaths(controlDir, oldDir)
+	if err != nil {
+		t.Fatalf("old paths: %v", err)
+	}
+	if _, _, err := CreateIssue(oldPaths, "developer", "keep me"); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+
+	if _, err := MoveFleetProject(controlDir, "app", filepath.Join(t.TempDir(), "missing"), true, "/usr/local/bin/ralphctl"); err == nil {
+		t.Fatalf("move to a missing dir should be rejected")
+	}
+
+	newDir := t.TempDir()
+	res, err := MoveFleetProject(controlDir, "app", newDir, true, "/usr/local/bin/ralphctl")
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if !res.RalphMigrated || res.OldProjectDir != oldPaths.ProjectDir || res.Project.ProjectDir != newDir {
+		t.Fatalf("unexpected move result: %+v", res)
+	}
+	newPaths, err := NewPaths(controlDir, newDir)
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	ready, err := CountReadyIssues(newPaths)
+	if err != nil || ready != 1 {
+		t.Fatalf("issue queue should move with .ralph: ready=%d err=%v", ready, err)
+	}
+	cfg, err := LoadFleetConfig(controlDir)
+	if err != nil {
+		t.Fatalf("load fleet config: %v", err)
+	}
+	if fp, _ := FindFleetProject(cfg, "app"); fp.ProjectDir != newDir {
+		t.Fatalf("fleet config not repointed: %+v", fp)
+	}
+}
+
+func TestMoveFleetProjectCopiesAcrossDevicesAndMovesService(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	controlDir := t.TempDir()
+	if err := EnsureDefaultControlAssets(controlDir); err != nil {
+		t.Fatalf("ensure control assets: %v", err)
+	}
+	oldDir := filepath.Join(t.TempDir(), "app")
+	if _, err := RegisterFleetProject(controlDir, "app", oldDir, "", ""); err != nil {
+		t.Fatalf("register app: %v", err)
+	}
+	oldPaths, err := NewPaths(controlDir, oldDir)
+	if err != nil {
+		t.Fatalf("old paths: %v", err)
+	}
+	if _, _, err := CreateIssue(oldPaths, "developer", "keep me"); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	withService := runtime.GOOS == "linux"
+	if withService {
+		if _, err := InstallService(oldPaths, "/usr/local/bin/ralphctl", "ralph-custom", false); err != nil {
+			t.Fatalf("install service: %v", err)
+		}
+	}
+
+	renameDir = func(src, dst string) error {
+		return &os.LinkError{Op: "rename", Old: src, New: dst, Err: errCrossDevice}
+	}
+	defer func() { renameDir = os.Rename }()
+
+	newDir := t.TempDir()
+	res, err := MoveFleetProject(controlDir, "app", newDir, true, "/opt/ralphctl")
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if !res.RalphMigrated {
+		t.Fatalf("ralph dir should be migrated: %+v", res)
+	}
+	if _, err := os.Stat(oldPaths.RalphDir); !os.IsNotExist(err) {
+		t.Fatalf("old .ralph should be removed after the copy: %v", err)
+	}
+	newPaths, err := NewPaths(controlDir, newDir)
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	if ready, err := CountReadyIssues(newPaths); err != nil || ready != 1 {
+		t.Fatalf("issue queue should be copied across devices: ready=%d err=%v", ready, err)
+	}
+
+	if !withService {
+		return
+	}
+	if res.Service == nil || res.Service.ServiceName != "ralph-custom" {
+		t.Fatalf("service should be reinstalled under its name: %+v", res.Service)
+	}
+	unit, err := os.ReadFile(res.Service.UnitPath)
+	if err != nil {
+		t.Fatalf("read unit: %v", err)
+	}
+	if !strings.Contains(string(unit), "WorkingDirectory="+systemdEscape(newDir)+"\n") || !strings.Contains(string(unit), "/opt/ralphctl") {
+		t.Fatalf("service should run the moved project:\n%s", unit)
+	}
+	if _, found, err := FindProjectService(oldPaths); err != nil || found {
+		t.Fatalf("no service should remain for the old dir: found=%t err=%v", found, err)
+	}
+}
@@ -0,0 +1,192 @@
+package ralph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CompletedHistoryLimit is the ring buffer size of the completed issue history.
+const CompletedHistoryLimit = 20
+
+// completedHistoryLockWait bounds how long a worker waits for another role
+// worker's history update.
+const completedHistoryLockWait = 10 * time.Second
+
+type CompletedIssue struct {
+	ID           string    `json:"id"`
+	Role         string    `json:"role"`
+	Title        string    `json:"title"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	DurationMS   int64     `json:"duration_ms"`
+	CodexRetries int       `json:"codex_retries"`
+}
+
+func (c CompletedIssue) Duration() time.Duration {
+	return time.Duration(c.DurationMS) * time.Millisecond
+}
+
+// completedHistoryFile keeps the last CompletedHistoryLimit entries plus a
+// running codex retry total that, unlike the entries, never drops.
+type completedHistoryFile struct {
+	Entries           []CompletedIssue `json:"entries"`
+	CodexRetriesTotal int              `json:"codex_retries_total"`
+}
+
+func loadCompletedHistoryFile(paths Paths) (completedHistoryFile, error) {
+	var file completedHistoryFile
+	data, err := os.ReadFile(paths.CompletedHistoryFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return file, nil
+		}
+		return file, fmt.Errorf("read completed history: %w", err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, fmt.Errorf("parse completed history: %w", err)
+	}
+	if file.CodexRetriesTotal == 0 {
+		// Histories written before the total was kept start from what they retain.
+		for _, entry := range file.Entries {
+			file.CodexRetriesTotal += entry.CodexRetries
+		}
+	}
+	return file, nil
+}
+
+// CodexRetriesTotal is the number of codex retries across every completed issue
+// recorded so far; it only resets if the history file is lost.
+func CodexRetriesTotal(paths Paths) (int, error) {
+	file, err := loadCompletedHistoryFile(paths)
+	if err != nil {
+		return 0, err
+	}
+	return file.CodexRetriesTotal, nil
+}
+
+// LoadCompletedHistory returns completed issues newest first.
+func LoadCompletedHistory(paths Paths) ([]CompletedIssue, error) {
+	file, err := loadCompletedHistoryFile(paths)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]CompletedIssue, 0, len(file.Entries))
+	for i := len(file.Entries) - 1; i >= 0; i-- {
+		out = append(out, file.Entries[i])
+	}
+	return out, nil
+}
+
+// AppendCompletedHistory adds entry under a lock, since every role worker
+// updates the same file. An unreadable history is kept aside as
+// <file>.corrupt-<time> and reported in the returned error after entry is saved.
+func AppendCompletedHistory(paths Paths, entry CompletedIssue) error {
+	if err := EnsureLayout(paths); err != nil {
+		return err
+	}
+	path := paths.CompletedHistoryFile()
+	return WithOwnedLock(path+".lock", completedHistoryLockWait, func() error {
+		return appendCompletedHistoryLocked(paths, entry)
+	})
+}
+
+func appendCompletedHistoryLocked(paths Paths, entry CompletedIssue) error {
+	path := paths.CompletedHistoryFile()
+	var corruptErr error
+	file, err := loadCompletedHistoryFile(paths)
+	if err != nil {
+		// A corrupt history is not worth blocking the loop; keep it for inspection and start over.
+		backup := fmt.Sprintf("%s.corrupt-%s", path, time.Now().UTC().Format("20060102T150405Z"))
+		if renameErr := os.Rename(path, backup); renameErr != nil {
+			return fmt.Errorf("%v (moving it aside failed: %v)", err, renameErr)
+		}
+		corruptErr = fmt.Errorf("%v; moved to %s and started a new history", err, backup)
+		file = completedHistoryFile{}
+	}
+	file.Entries = append(file.Entries, entry)
+	if len(file.Entries) > CompletedHistoryLimit {
+		file.Entries = file.Entries[len(file.Entries)-CompletedHistoryLimit:]
+	}
+	file.CodexRetriesTotal += entry.CodexRetries
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode completed history: %w", err)
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), ".completed-history-*.tmp")
+	if err != nil {
+		return fmt.Errorf("create completed history temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer func() {
+		_ = os.Remove(tmpPath)
+	}()
+	if _, err := tmpFile.Write(append(data, '\n')); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("write completed history: %w", err)
+	}
+	if err := tmpFile.Chmod(0o644); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return corruptErr
+}
+
+func FormatCompletedIssue(c CompletedIssue) string {
+	return fmt.Sprintf("%s | %s | %s | finished=%s duration=%s codex_retries=%d",
+		c.ID,
+		c.Role,
+		c.Title,
+		c.FinishedAt.Format(time.RFC3339),
+		c.Duration().Round(time.Second),
+		c.CodexRetries,
+	)
+}
+
+// CompletedSince keeps entries that finished at or after cutoff.
+func CompletedSince(entries []CompletedIssue, cutoff time.Time) []CompletedIssue {
+	out := []CompletedIssue{}
+	for _, entry := range entries {
+		if !entry.FinishedAt.Before(cutoff) {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// DoneThroughputPerHour is the number of issues moved to done/ within window, per
+// hour. done/ keeps every completion, unlike the capped history, and an issue
+// file is last written when it is marked done.
+func DoneThroughputPerHour(paths Paths, now time.Time, window time.Duration) float64 {
+	if window <= 0 {
+		return 0
+	}
+	entries, err := os.ReadDir(paths.DoneDir)
+	if err != nil {
+		return 0
+	}
+	cutoff := now.Add(-window)
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Before(cutoff) {
+			count++
+		}
+	}
+	return float64(count) / window.Hours()
+}
@@ -0,0 +1,151 @@
+package ralph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCompletedHistoryKeepsNewestEntries(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+
+	base := time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < CompletedHistoryLimit+5; i++ {
+		started := base.Add(time.Duration(i) * time.Minute)
+		entry := CompletedIssue{
+			ID:           fmt.Sprintf("I-%02d", i),
+			Role:         "developer",
+			StartedAt:    started,
+			FinishedAt:   started.Add(30 * time.Second),
+			DurationMS:   30000,
+			CodexRetries: i % 2,
+		}
+		if err := AppendCompletedHistory(paths, entry); err != nil {
+			t.Fatalf("append history %d: %v", i, err)
+		}
+	}
+
+	entries, err := LoadCompletedHistory(paths)
+	if err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if len(entries) != CompletedHistoryLimit {
+		t.Fatalf("history size mismatch: got=%d want=%d", len(entries), CompletedHistoryLimit)
+	}
+	if entries[0].ID != "I-24" || entries[len(entries)-1].ID != "I-05" {
+		t.Fatalf("history order mismatch: first=%s last=%s", entries[0].ID, entries[len(entries)-1].ID)
+	}
+
+	st, err := GetStatus(paths)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if len(st.RecentCompleted) != 3 || st.RecentCompleted[0].ID != "I-24" {
+		t.Fatalf("status recent completed mismatch: %+v", st.RecentCompleted)
+	}
+	// 12 of the 25 entries had a retry; only 10 of them are still retained.
+	if st.CodexRetriesTotal != 12 {
+		t.Fatalf("codex retries total should not drop as entries age out: got=%d want=12", st.CodexRetriesTotal)
+	}
+}
+
+func TestAppendCompletedHistoryConcurrentWritersKeepEveryEntry(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+
+	const writers = 12
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- AppendCompletedHistory(paths, CompletedIssue{ID: fmt.Sprintf("I-%02d", i), FinishedAt: time.Now().UTC()})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("append history: %v", err)
+		}
+	}
+	entries, err := LoadCompletedHistory(paths)
+	if err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if len(entries) != writers {
+		t.Fatalf("concurrent appends lost entries: got=%d want=%d", len(entries), writers)
+	}
+}
+
+func TestAppendCompletedHistoryKeepsCorruptFileAside(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	writeFile(t, paths.CompletedHistoryFile(), "{not json")
+
+	err := AppendCompletedHistory(paths, CompletedIssue{ID: "I-1", FinishedAt: time.Now().UTC()})
+	if err == nil || !strings.Contains(err.Error(), "corrupt-") {
+		t.Fatalf("corrupt history should be reported: %v", err)
+	}
+	backups, _ := filepath.Glob(paths.CompletedHistoryFile() + ".corrupt-*")
+	if len(backups) != 1 {
+		t.Fatalf("corrupt history should be kept aside: %v", backups)
+	}
+	if data, _ := os.ReadFile(backups[0]); string(data) != "{not json" {
+		t.Fatalf("backup content mismatch: %q", data)
+	}
+	entries, loadErr := LoadCompletedHistory(paths)
+	if loadErr != nil || len(entries) != 1 || entries[0].ID != "I-1" {
+		t.Fatalf("new history should hold the entry: %+v err=%v", entries, loadErr)
+	}
+}
+
+func TestCompletedSince(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC)
+	entries := []CompletedIssue{
+		{ID: "a", FinishedAt: now.Add(-10 * time.Minute)},
+		{ID: "b", FinishedAt: now.Add(-50 * time.Minute)},
+		{ID: "c", FinishedAt: now.Add(-90 * time.Minute)},
+		{ID: "d", FinishedAt: now.Add(-5 * time.Hour)},
+	}
+	if got := len(CompletedSince(entries, now.Add(-time.Hour))); got != 2 {
+		t.Fatalf("since filter mismatch: got=%d", got)
+	}
+}
+
+func TestDoneThroughputPerHourCountsBeyondHistoryLimit(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	now := time.Now()
+	recent := CompletedHistoryLimit + 10
+	for i := 0; i < recent+3; i++ {
+		path := filepath.Join(paths.DoneDir, fmt.Sprintf("I-%03d.md", i))
+		writeFile(t, path, "- status: done\n")
+		finished := now.Add(-time.Duration(i) * time.Minute)
+		if i >= recent {
+			finished = now.Add(-5 * time.Hour)
+		}
+		if err := os.Chtimes(path, finished, finished); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+	if got, want := DoneThroughputPerHour(paths, now, 2*time.Hour), float64(recent)/2; got != want {
+		t.Fatalf("throughput mismatch: got=%v want=%v", got, want)
+	}
+	if got := DoneThroughputPerHour(paths, now, 0); got != 0 {
+		t.Fatalf("zero window should report zero throughput: %v", got)
+	}
+}
@@ -0,0 +1,102 @@
+package ralph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultStatusHTTPHost = "127.0.0.1"
+
+// NormalizeStatusHTTPAddr fills in the localhost host for ":port" style addresses
+// so the status endpoint is never exposed on all interfaces by accident.
+func NormalizeStatusHTTPAddr(addr string) (string, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return "", fmt.Errorf("http addr is empty")
+	}
+	if !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", fmt.Errorf("invalid http addr %q: %w", addr, err)
+	}
+	if _, err := strconv.Atoi(port); err != nil {
+		return "", fmt.Errorf("invalid http port %q", port)
+	}
+	if host == "" {
+		host = DefaultStatusHTTPHost
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// NewStatusHTTPHandler serves /status (JSON Status), /healthz and /metrics (Prometheus text).
+func NewStatusHTTPHandler(paths Paths) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		status, err := GetStatus(paths)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(status)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := GetStatus(paths); err != nil {
+			http.Error(w, "unhealthy: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, "ok\n")
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		status, err := GetStatus(paths)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteStatusMetrics(w, []StatusMetricsSample{{ProjectID: StatusMetricsProjectID(paths), Status: status}})
+	})
+	return mux
+}
+
+// StartStatusHTTPServer binds addr and serves the status endpoints until ctx is done.
+// Bind errors are returned immediately; the returned address reflects the real port.
+func StartStatusHTTPServer(ctx context.Context, paths Paths, addr string, stdout io.Writer) (string, error) {
+	normalized, err := NormalizeStatusHTTPAddr(addr)
+	if err != nil {
+		return "", err
+	}
+	ln, err := net.Listen("tcp", normalized)
+	if err != nil {
+		return "", fmt.Errorf("listen status http: %w", err)
+	}
+	srv := &http.Server{
+		Handler:           NewStatusHTTPHandler(paths),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) && stdout != nil {
+			fmt.Fprintf(stdout, "[ralph-http] server stopped: %v\n", err)
+		}
+	}()
+	return ln.Addr().String(), nil
+}
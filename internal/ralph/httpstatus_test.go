@@ -0,0 +1,88 @@
+package ralph
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeStatusHTTPAddr(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		":9090":          "127.0.0.1:9090",
+		"9090":           "127.0.0.1:9090",
+		"0.0.0.0:9090":   "0.0.0.0:9090",
+		"localhost:8080": "localhost:8080",
+	}
+	for in, want := range cases {
+		got, err := NormalizeStatusHTTPAddr(in)
+		if err != nil || got != want {
+			t.Fatalf("NormalizeStatusHTTPAddr(%q)=%q err=%v want=%q", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "host:port", "a:b:c"} {
+		if _, err := NormalizeStatusHTTPAddr(bad); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestStatusHTTPHandlerServesStatusHealthAndMetrics(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	if _, _, err := CreateIssue(paths, "developer", "queued work"); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	handler := NewStatusHTTPHandler(paths)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/status code=%d body=%s", rec.Code, rec.Body.String())
+	}
+	var status Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if status.QueueReady != 1 {
+		t.Fatalf("status queue_ready mismatch: %d", status.QueueReady)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "ok" {
+		t.Fatalf("/healthz code=%d body=%q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "# TYPE ralph_queue_ready gauge") || !strings.Contains(body, "ralph_queue_ready{project=\""+filepath.Base(paths.ProjectDir)+"\"} 1\n") {
+		t.Fatalf("metrics missing queue_ready gauge:\n%s", body)
+	}
+}
+
+func TestStartStatusHTTPServerServesHealthz(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	addr, err := StartStatusHTTPServer(ctx, paths, "127.0.0.1:0", io.Discard)
+	if err != nil {
+		t.Fatalf("start server: %v", err)
+	}
+	resp, err := http.Get("http://" + addr + "/healthz")
+	if err != nil {
+		t.Fatalf("get healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("healthz status=%d", resp.StatusCode)
+	}
+	cancel()
+}
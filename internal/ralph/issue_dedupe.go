@@ -0,0 +1,41 @@
+package ralph
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDuplicateIssue is returned by CreateIssueWithOptions when Dedupe finds an
+// open issue with the same role and normalized title.
+var ErrDuplicateIssue = errors.New("duplicate issue")
+
+func normalizeIssueTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// FindDuplicateIssue looks for a ready, in-progress or blocked issue with the
+// same role and title, ignoring case and whitespace. Done issues never match.
+func FindDuplicateIssue(paths Paths, role, title string) (IssueEntry, bool, error) {
+	want := normalizeIssueTitle(title)
+	if want == "" {
+		return IssueEntry{}, false, nil
+	}
+	entries, err := ListIssues(paths, IssueListOptions{
+		Statuses: []string{"ready", "in-progress", "blocked"},
+		Roles:    map[string]struct{}{strings.TrimSpace(role): {}},
+	})
+	if err != nil {
+		return IssueEntry{}, false, err
+	}
+	for _, entry := range entries {
+		if normalizeIssueTitle(entry.Meta.Title) == want {
+			return entry, true, nil
+		}
+	}
+	return IssueEntry{}, false, nil
+}
+
+func duplicateIssueError(entry IssueEntry) error {
+	return fmt.Errorf("%w: %s (%s) has the same role/title: %s", ErrDuplicateIssue, entry.Meta.ID, entry.Status, entry.Path)
+}
@@ -0,0 +1,42 @@
+package ralph
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateIssueDedupeMatchesOpenIssuesByRoleAndTitle(t *testing.T) {
+	t.Parallel()
+
+	paths := newTestPaths(t)
+	if _, _, err := CreateIssueWithOptions(paths, "developer", "Add health endpoint", IssueCreateOptions{Dedupe: true}); err != nil {
+		t.Fatalf("create first issue failed: %v", err)
+	}
+
+	_, _, err := CreateIssueWithOptions(paths, "developer", "  add   HEALTH endpoint ", IssueCreateOptions{Dedupe: true})
+	if !errors.Is(err, ErrDuplicateIssue) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+	if _, _, err := CreateIssueWithOptions(paths, "developer", "add health endpoint", IssueCreateOptions{}); err != nil {
+		t.Fatalf("dedupe off should still create: %v", err)
+	}
+	if _, _, err := CreateIssueWithOptions(paths, "qa", "add health endpoint", IssueCreateOptions{Dedupe: true}); err != nil {
+		t.Fatalf("other role should not be a duplicate: %v", err)
+	}
+
+	// Once the matching developer issues are done, the title is free again.
+	entries, err := ListIssues(paths, IssueListOptions{Roles: map[string]struct{}{"developer": {}}})
+	if err != nil {
+		t.Fatalf("list issues failed: %v", err)
+	}
+	for _, entry := range entries {
+		if err := os.Rename(entry.Path, filepath.Join(paths.DoneDir, filepath.Base(entry.Path))); err != nil {
+			t.Fatalf("move %s to done failed: %v", entry.Path, err)
+		}
+	}
+	if _, _, err := CreateIssueWithOptions(paths, "developer", "Add health endpoint", IssueCreateOptions{Dedupe: true}); err != nil {
+		t.Fatalf("done issues should not block creation: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package ralph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func IssueTemplatesDir(controlDir string) string {
+	return filepath.Join(controlDir, "issue-templates")
+}
+
+// IssueTemplatePath is the control-dir template for a role or named template.
+func IssueTemplatePath(controlDir, name string) string {
+	return filepath.Join(IssueTemplatesDir(controlDir), strings.TrimSpace(name)+".md")
+}
+
+// resolveIssueTemplate picks the body template for a new issue. An override is
+// a template name under issue-templates/ or a file path (relative to the project);
+// without one the role's template is used when present. ok=false means no template.
+func resolveIssueTemplate(paths Paths, role, override string) (string, bool, error) {
+	override = strings.TrimSpace(override)
+	if override == "" {
+		path := IssueTemplatePath(paths.ControlDir, role)
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				return "", false, nil
+			}
+			return "", false, fmt.Errorf("check issue template: %w", err)
+		}
+		return path, true, nil
+	}
+	path := override
+	if !strings.ContainsAny(override, `/\`) && !strings.HasSuffix(override, ".md") {
+		path = IssueTemplatePath(paths.ControlDir, override)
+	} else if !filepath.IsAbs(path) {
+		path = filepath.Join(paths.ProjectDir, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", false, fmt.Errorf("issue template not found: %s", path)
+		}
+		return "", false, fmt.Errorf("check issue template: %w", err)
+	}
+	return path, true, nil
+}
+
+func renderIssueTemplate(path, role, title string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read issue template: %w", err)
+	}
+	body := strings.NewReplacer("{{title}}", title, "{{role}}", role).Replace(string(data))
+	return strings.TrimSpace(body) + "\n", nil
+}
@@ -12,6 +12,10 @@ import (
 	"time"
 )
 
+// Ready issues run in ascending priority order, ties broken by issue id (which
+// starts with the creation time). A missing or 0 priority means
+// defaultIssuePriority; negative priorities are valid and run ahead of every
+// positive one. TopIssuePriority gives "run this next" without picking a number.
 const defaultIssuePriority = 1000
 
 var issueIDCounter uint64
@@ -23,14 +27,38 @@ type IssueMeta struct {
 	Title    string
 	Priority int
 	StoryID  string
+	Assignee string
+	Created  string
+}
+
+type IssueEntry struct {
+	Path      string
+	Status    string
+	Meta      IssueMeta
+	CreatedAt time.Time
+}
+
+type IssueListOptions struct {
+	Statuses []string
+	Roles    map[string]struct{}
+	Assignee string
+	Sort     string
 }
 
 type IssueCreateOptions struct {
 	Priority           int
 	StoryID            string
+	Assignee           string
 	Objective          string
 	AcceptanceCriteria []string
 	ExtraMeta          map[string]string
+	// Template overrides the role's issue template (name or file path). Templates
+	// only seed the body when no Objective/AcceptanceCriteria are given.
+	Template string
+	// Dedupe refuses to create the issue when FindDuplicateIssue matches.
+	Dedupe bool
+	// Top overrides Priority with TopIssuePriority so the issue runs before every ready issue.
+	Top bool
 }
 
 func CreateIssue(paths Paths, role, title string) (string, string, error) {
@@ -48,17 +76,55 @@ func CreateIssueWithOptions(paths Paths, role, title string, opts IssueCreateOpt
 	if strings.TrimSpace(title) == "" {
 		return "", "", fmt.Errorf("title is required")
 	}
+	assignee := strings.TrimSpace(opts.Assignee)
+	if strings.ContainsAny(assignee, "\r\n") {
+		return "", "", fmt.Errorf("invalid assignee: must be a single line")
+	}
+	if opts.Dedupe {
+		dup, found, err := FindDuplicateIssue(paths, role, title)
+		if err != nil {
+			return "", "", err
+		}
+		if found {
+			return "", "", duplicateIssueError(dup)
+		}
+	}
+	if opts.Top {
+		top, err := TopIssuePriority(paths)
+		if err != nil {
+			return "", "", err
+		}
+		opts.Priority = top
+	}
 
-	objective := strings.TrimSpace(opts.Objective)
-	if objective == "" {
-		objective = title
+	body := ""
+	if strings.TrimSpace(opts.Objective) == "" && len(opts.AcceptanceCriteria) == 0 {
+		templatePath, ok, err := resolveIssueTemplate(paths, role, opts.Template)
+		if err != nil {
+			return "", "", err
+		}
+		if ok {
+			body, err = renderIssueTemplate(templatePath, role, title)
+			if err != nil {
+				return "", "", err
+			}
+		}
 	}
-	criteria := normalizeAcceptanceCriteria(opts.AcceptanceCriteria)
-	if len(criteria) == 0 {
-		criteria = []string{
-			"- [ ] Required changes are implemented.",
-			"- [ ] Validation command passes if this role requires validation.",
+	if body == "" {
+		objective := strings.TrimSpace(opts.Objective)
+		if objective == "" {
+			objective = title
 		}
+		criteria := normalizeAcceptanceCriteria(opts.AcceptanceCriteria)
+		if len(criteria) == 0 {
+			criteria = []string{
+				"- [ ] Required changes are implemented.",
+				"- [ ] Validation command passes if this role requires validation.",
+			}
+		}
+		bodyLines := []string{"## Objective", "- " + objective, "", "## Acceptance Criteria"}
+		bodyLines = append(bodyLines, criteria...)
+		body = strings.Join(bodyLines, "\n") + "\n"
 	}
 
 	for attempt := 0; attempt < 128; attempt++ {
@@ -73,12 +139,15 @@ func CreateIssueWithOptions(paths Paths, role, title string, opts IssueCreateOpt
 			fmt.Sprintf("title: %s", title),
 			fmt.Sprintf("created_at_utc: %s", now.Format(time.RFC3339)),
 		}
-		if opts.Priority > 0 {
+		if opts.Priority != 0 {
 			headers = append(headers, fmt.Sprintf("priority: %d", opts.Priority))
 		}
 		if sid := strings.TrimSpace(opts.StoryID); sid != "" {
 			headers = append(headers, fmt.Sprintf("story_id: %s", sid))
 		}
+		if assignee != "" {
+			headers = append(headers, fmt.Sprintf("assignee: %s", assignee))
+		}
 		if len(opts.ExtraMeta) > 0 {
 			keys := make([]string, 0, len(opts.ExtraMeta))
 			for k := range opts.ExtraMeta {
@@ -91,7 +160,7 @@ func CreateIssueWithOptions(paths Paths, role, title string, opts IssueCreateOpt
 					continue
 				}
 				switch key {
-				case "id", "role", "status", "title", "created_at_utc", "priority", "story_id":
+				case "id", "role", "status", "title", "created_at_utc", "priority", "story_id", "assignee":
 					continue
 				}
 				val := strings.TrimSpace(opts.ExtraMeta[k])
@@ -102,9 +171,7 @@ func CreateIssueWithOptions(paths Paths, role, title string, opts IssueCreateOpt
 			}
 		}
 
-		bodyLines := []string{"## Objective", "- " + objective, "", "## Acceptance Criteria"}
-		bodyLines = append(bodyLines, criteria...)
-		content := strings.Join(headers, "\n") + "\n\n" + strings.Join(bodyLines, "\n") + "\n"
+		content := strings.Join(headers, "\n") + "\n\n" + body
 
 		f, err := os.OpenFile(issuePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
 		if err != nil {
@@ -185,6 +252,10 @@ func ReadIssueMeta(path string) (IssueMeta, error) {
 			}
 		case "story_id":
 			meta.StoryID = v
+		case "assignee":
+			meta.Assignee = v
+		case "created_at_utc":
+			meta.Created = v
 		}
 	}
 	if err := s.Err(); err != nil {
@@ -213,6 +284,10 @@ func splitMeta(line string) (string, string, bool) {
 }
 
 func SetIssueStatus(path, status string) error {
+	return setIssueMetaField(path, "status", status)
+}
+
+func setIssueMetaField(path, key, value string) error {
 	input, err := os.ReadFile(path)
 	if err != nil {
 		return err
@@ -220,8 +295,11 @@ func SetIssueStatus(path, status string) error {
 	lines := strings.Split(string(input), "\n")
 	replaced := false
 	for i, line := range lines {
-		if strings.HasPrefix(strings.TrimSpace(line), "status:") {
-			lines[i] = "status: " + status
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), key+":") {
+			lines[i] = key + ": " + value
 			replaced = true
 			break
 		}
@@ -236,7 +314,7 @@ func SetIssueStatus(path, status string) error {
 		}
 		newLines := make([]string, 0, len(lines)+1)
 		newLines = append(newLines, lines[:insertAt]...)
-		newLines = append(newLines, "status: "+status)
+		newLines = append(newLines, key+": "+value)
 		newLines = append(newLines, lines[insertAt:]...)
 		lines = newLines
 	}
@@ -281,10 +359,7 @@ func PickNextReadyIssueForRoles(paths Paths, allowedRoles map[string]struct{}) (
 				continue
 			}
 		}
-		priority := meta.Priority
-		if priority <= 0 {
-			priority = defaultIssuePriority
-		}
+		priority := effectiveIssuePriority(meta)
 		if bestPath == "" || priority < bestPriority || (priority == bestPriority && f < bestPath) {
 			bestPath = f
 			bestMeta = meta
@@ -351,14 +426,137 @@ func RetryBlockedIssues(paths Paths, reasonContains string, limit int) (int, err
 	return moved, nil
 }
 
-func RecoverInProgressWithCount(paths Paths) (int, error) {
+// InProgressIssue is an in-progress issue with the worker that claimed it.
+// ClaimedByPID is 0 when no owner was recorded; ClaimedAt is zero for issues
+// claimed before claim timestamps were recorded, in which case Age uses the file mtime.
+type InProgressIssue struct {
+	IssueEntry
+	ClaimedByPID int
+	ClaimedAt    time.Time
+	WorkerAlive  bool
+	Age          time.Duration
+}
+
+// ListInProgressIssues reports each in-progress issue with its age (since claim)
+// and whether the claiming worker is still alive.
+func ListInProgressIssues(paths Paths) ([]InProgressIssue, error) {
 	files, err := filepath.Glob(filepath.Join(paths.InProgressDir, "I-*.md"))
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	sort.Strings(files)
-	moved := 0
+	now := time.Now()
+	out := []InProgressIssue{}
 	for _, f := range files {
+		info, statErr := os.Stat(f)
+		if statErr != nil {
+			continue
+		}
+		meta, err := ReadIssueMeta(f)
+		if err != nil {
+			continue
+		}
+		pid, claimedAt := issueClaimInfo(f)
+		since := info.ModTime()
+		if !claimedAt.IsZero() {
+			since = claimedAt
+		}
+		out = append(out, InProgressIssue{
+			IssueEntry:   IssueEntry{Path: f, Status: "in-progress", Meta: meta, CreatedAt: issueCreatedAt(f, meta)},
+			ClaimedByPID: pid,
+			ClaimedAt:    claimedAt,
+			WorkerAlive:  isPIDRunning(pid),
+			Age:          now.Sub(since),
+		})
+	}
+	return out, nil
+}
+
+// issueClaimInfo reads claimed_by_pid and claimed_at_utc. The PID falls back to
+// the one in a half-finished "<id>.claim-<pid>.md" claim file name.
+func issueClaimInfo(path string) (int, time.Time) {
+	pid := 0
+	claimedAt := time.Time{}
+	if data, err := os.ReadFile(path); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.TrimSpace(line) == "" {
+				break
+			}
+			k, v, ok := splitMeta(line)
+			if !ok {
+				continue
+			}
+			switch k {
+			case "claimed_by_pid":
+				if n, err := strconv.Atoi(v); err == nil && n > 0 {
+					pid = n
+				}
+			case "claimed_at_utc":
+				if ts, err := time.Parse(time.RFC3339, v); err == nil {
+					claimedAt = ts
+				}
+			}
+		}
+	}
+	if pid == 0 {
+		base := strings.TrimSuffix(filepath.Base(path), ".md")
+		if idx := strings.LastIndex(base, ".claim-"); idx >= 0 {
+			if n, err := strconv.Atoi(base[idx+len(".claim-"):]); err == nil && n > 0 {
+				pid = n
+			}
+		}
+	}
+	return pid, claimedAt
+}
+
+type RecoverInProgressOptions struct {
+	// Force also resets issues whose claiming worker is still alive.
+	Force bool
+	// StaleAfter, when > 0, only resets issues in-progress at least this long.
+	StaleAfter time.Duration
+}
+
+type RecoverInProgressResult struct {
+	Recovered    int
+	SkippedLive  int
+	SkippedFresh int
+}
+
+// RecoverAction is what RecoverInProgressWithOptions does with issue: "reset",
+// "skip_live" (owning worker alive) or "skip_fresh" (younger than StaleAfter).
+func (opts RecoverInProgressOptions) RecoverAction(issue InProgressIssue) string {
+	if issue.WorkerAlive && !opts.Force {
+		return "skip_live"
+	}
+	if opts.StaleAfter > 0 && issue.Age < opts.StaleAfter {
+		return "skip_fresh"
+	}
+	return "reset"
+}
+
+// RecoverInProgressWithCount moves in-progress issues back to ready, leaving
+// issues whose claiming worker is still alive untouched.
+func RecoverInProgressWithCount(paths Paths) (int, error) {
+	res, err := RecoverInProgressWithOptions(paths, RecoverInProgressOptions{})
+	return res.Recovered, err
+}
+
+func RecoverInProgressWithOptions(paths Paths, opts RecoverInProgressOptions) (RecoverInProgressResult, error) {
+	res := RecoverInProgressResult{}
+	issues, err := ListInProgressIssues(paths)
+	if err != nil {
+		return res, err
+	}
+	for _, issue := range issues {
+		switch opts.RecoverAction(issue) {
+		case "skip_live":
+			res.SkippedLive++
+			continue
+		case "skip_fresh":
+			res.SkippedFresh++
+			continue
+		}
+		f := issue.Path
 		if _, statErr := os.Stat(f); os.IsNotExist(statErr) {
 			continue
 		}
@@ -371,17 +569,17 @@ func RecoverInProgressWithCount(paths Paths) (int, error) {
 			if os.IsNotExist(err) {
 				continue
 			}
-			return moved, err
+			return res, err
 		}
 		if err := os.Rename(f, dst); err != nil {
 			if os.IsNotExist(err) {
 				continue
 			}
-			return moved, err
+			return res, err
 		}
-		moved++
+		res.Recovered++
 	}
-	return moved, nil
+	return res, nil
 }
 
 func latestIssueResultReason(path string) (string, error) {
@@ -399,89 +597,253 @@ func latestIssueResultReason(path string) (string, error) {
 	return reason, nil
 }
 
+// RecoverStaleInProgressWithCount is the loop watchdog's recover: issues claimed
+// at least staleAfter ago (by claimed_at_utc) go back to ready unless their
+// claiming worker is still alive.
 func RecoverStaleInProgressWithCount(paths Paths, staleAfter time.Duration) (int, error) {
 	if staleAfter <= 0 {
 		return 0, nil
 	}
+	res, err := RecoverInProgressWithOptions(paths, RecoverInProgressOptions{StaleAfter: staleAfter})
+	return res.Recovered, err
+}
 
-	files, err := filepath.Glob(filepath.Join(paths.InProgressDir, "I-*.md"))
+func CountIssueFiles(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "I-*.md"))
+	if err != nil {
+		return 0, err
+	}
+	return len(files), nil
+}
+
+func CountReadyIssues(paths Paths) (int, error) {
+	files, err := filepath.Glob(filepath.Join(paths.IssuesDir, "I-*.md"))
 	if err != nil {
 		return 0, err
 	}
 	sort.Strings(files)
+	count := 0
+	for _, f := range files {
+		meta, readErr := ReadIssueMeta(f)
+		if readErr != nil {
+			continue
+		}
+		if meta.Status == "ready" {
+			count++
+		}
+	}
+	return count, nil
+}
 
-	now := time.Now().UTC()
-	moved := 0
+// CountReadyIssuesOutsideRoles counts ready issues no worker with allowedRoles would pick.
+// An empty scope covers every role.
+func CountReadyIssuesOutsideRoles(paths Paths, allowedRoles map[string]struct{}) (int, error) {
+	if len(allowedRoles) == 0 {
+		return 0, nil
+	}
+	files, err := filepath.Glob(filepath.Join(paths.IssuesDir, "I-*.md"))
+	if err != nil {
+		return 0, err
+	}
+	count := 0
 	for _, f := range files {
-		info, statErr := os.Stat(f)
-		if statErr != nil {
-			if os.IsNotExist(statErr) {
-				continue
-			}
-			return moved, statErr
+		meta, readErr := ReadIssueMeta(f)
+		if readErr != nil || meta.Status != "ready" {
+			continue
 		}
-		if now.Sub(info.ModTime()) < staleAfter {
+		if _, ok := allowedRoles[meta.Role]; !ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func issueStatusDirs(paths Paths) []struct {
+	dir    string
+	status string
+} {
+	return []struct {
+		dir    string
+		status string
+	}{
+		{dir: paths.IssuesDir, status: "ready"},
+		{dir: paths.InProgressDir, status: "in-progress"},
+		{dir: paths.BlockedDir, status: "blocked"},
+		{dir: paths.DoneDir, status: "done"},
+	}
+}
+
+func ListIssues(paths Paths, opts IssueListOptions) ([]IssueEntry, error) {
+	statusFilter := map[string]struct{}{}
+	for _, raw := range opts.Statuses {
+		status := normalizeExportStatus(raw)
+		if status == "" {
 			continue
 		}
+		switch status {
+		case "ready", "in-progress", "blocked", "done":
+		default:
+			return nil, fmt.Errorf("invalid status filter: %s", strings.TrimSpace(raw))
+		}
+		statusFilter[status] = struct{}{}
+	}
+	assignee := strings.TrimSpace(opts.Assignee)
+	sortBy := strings.ToLower(strings.TrimSpace(opts.Sort))
+	switch sortBy {
+	case "":
+		sortBy = "created"
+	case "created", "priority":
+	default:
+		return nil, fmt.Errorf("invalid sort: %s (expected priority|created)", opts.Sort)
+	}
 
-		if err := SetIssueStatus(f, "ready"); err != nil {
-			if os.IsNotExist(err) {
+	entries := []IssueEntry{}
+	for _, scan := range issueStatusDirs(paths) {
+		if len(statusFilter) > 0 {
+			if _, ok := statusFilter[scan.status]; !ok {
 				continue
 			}
-			return moved, err
 		}
-
-		base := filepath.Base(f)
-		dst := filepath.Join(paths.IssuesDir, base)
-		if _, err := os.Stat(dst); err == nil {
-			dst = filepath.Join(paths.IssuesDir, fmt.Sprintf("recovered-%s-%s", now.Format("20060102T150405Z"), base))
-			suffix := 1
-			for {
-				if _, err := os.Stat(dst); os.IsNotExist(err) {
-					break
+		files, err := filepath.Glob(filepath.Join(scan.dir, "I-*.md"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			meta, err := ReadIssueMeta(file)
+			if err != nil {
+				continue
+			}
+			if len(opts.Roles) > 0 {
+				if _, ok := opts.Roles[meta.Role]; !ok {
+					continue
 				}
-				dst = filepath.Join(paths.IssuesDir, fmt.Sprintf("recovered-%s-%d-%s", now.Format("20060102T150405Z"), suffix, base))
-				suffix++
 			}
-		} else if !os.IsNotExist(err) {
-			return moved, err
-		}
-
-		if err := os.Rename(f, dst); err != nil {
-			if os.IsNotExist(err) {
+			if assignee != "" && meta.Assignee != assignee {
 				continue
 			}
-			return moved, err
+			entries = append(entries, IssueEntry{
+				Path:      file,
+				Status:    scan.status,
+				Meta:      meta,
+				CreatedAt: issueCreatedAt(file, meta),
+			})
 		}
-		moved++
 	}
 
-	return moved, nil
-}
-
-func CountIssueFiles(dir string) (int, error) {
-	files, err := filepath.Glob(filepath.Join(dir, "I-*.md"))
-	if err != nil {
-		return 0, err
-	}
-	return len(files), nil
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if sortBy == "priority" {
+			pa, pb := effectiveIssuePriority(a.Meta), effectiveIssuePriority(b.Meta)
+			if pa != pb {
+				return pa < pb
+			}
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return filepath.Base(a.Path) < filepath.Base(b.Path)
+	})
+	return entries, nil
 }
 
-func CountReadyIssues(paths Paths) (int, error) {
+// TopIssuePriority is one below the lowest effective priority among ready
+// issues, and never above -1, so an issue created with it is picked next.
+func TopIssuePriority(paths Paths) (int, error) {
 	files, err := filepath.Glob(filepath.Join(paths.IssuesDir, "I-*.md"))
 	if err != nil {
 		return 0, err
 	}
-	sort.Strings(files)
-	count := 0
+	top := -1
 	for _, f := range files {
-		meta, readErr := ReadIssueMeta(f)
-		if readErr != nil {
+		meta, err := ReadIssueMeta(f)
+		if err != nil || meta.Status != "ready" {
 			continue
 		}
-		if meta.Status == "ready" {
-			count++
+		if p := effectiveIssuePriority(meta) - 1; p < top {
+			top = p
 		}
 	}
-	return count, nil
+	return top, nil
+}
+
+func effectiveIssuePriority(meta IssueMeta) int {
+	if meta.Priority == 0 {
+		return defaultIssuePriority
+	}
+	return meta.Priority
+}
+
+func issueCreatedAt(path string, meta IssueMeta) time.Time {
+	if ts, err := time.Parse(time.RFC3339, meta.Created); err == nil {
+		return ts.UTC()
+	}
+	if info, err := os.Stat(path); err == nil {
+		return info.ModTime().UTC()
+	}
+	return time.Time{}
+}
+
+func FindIssue(paths Paths, id string) (IssueEntry, error) {
+	id = strings.TrimSuffix(strings.TrimSpace(id), ".md")
+	if id == "" {
+		return IssueEntry{}, fmt.Errorf("issue id is required")
+	}
+	if strings.ContainsAny(id, `/\`) {
+		return IssueEntry{}, fmt.Errorf("invalid issue id: %s", id)
+	}
+	scans := issueStatusDirs(paths)
+	scans = append(scans, struct {
+		dir    string
+		status string
+	}{dir: paths.DeadLetterDir, status: "dead-letter"})
+	for _, scan := range scans {
+		path := filepath.Join(scan.dir, id+".md")
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return IssueEntry{}, err
+		}
+		meta, err := ReadIssueMeta(path)
+		if err != nil {
+			return IssueEntry{}, fmt.Errorf("read issue %s: %w", id, err)
+		}
+		return IssueEntry{
+			Path:      path,
+			Status:    scan.status,
+			Meta:      meta,
+			CreatedAt: issueCreatedAt(path, meta),
+		}, nil
+	}
+	return IssueEntry{}, fmt.Errorf("issue not found: %s", id)
+}
+
+func RemoveIssue(paths Paths, id string, force bool) (IssueEntry, error) {
+	entry, err := FindIssue(paths, id)
+	if err != nil {
+		return IssueEntry{}, err
+	}
+	if entry.Status == "in-progress" && !force {
+		return entry, fmt.Errorf("issue %s is in-progress; use --force to remove it", entry.Meta.ID)
+	}
+	if err := os.Remove(entry.Path); err != nil {
+		return entry, fmt.Errorf("remove issue %s: %w", entry.Meta.ID, err)
+	}
+	return entry, nil
+}
+
+func SetIssuePriority(paths Paths, id string, priority int) (IssueEntry, int, error) {
+	if priority == 0 {
+		return IssueEntry{}, 0, fmt.Errorf("priority must be non-zero (0 means default)")
+	}
+	entry, err := FindIssue(paths, id)
+	if err != nil {
+		return IssueEntry{}, 0, err
+	}
+	oldPriority := entry.Meta.Priority
+	if err := setIssueMetaField(entry.Path, "priority", strconv.Itoa(priority)); err != nil {
+		return entry, oldPriority, fmt.Errorf("update issue priority: %w", err)
+	}
+	entry.Meta.Priority = priority
+	return entry, oldPriority, nil
 }
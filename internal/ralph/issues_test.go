@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -79,3 +80,231 @@ func TestRetryBlockedIssuesLimit(t *testing.T) {
 		t.Fatalf("moved mismatch: got=%d want=2", moved)
 	}
 }
+
+func TestMissingBootstrapRolesIsReadOnly(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	if _, _, err := CreateIssue(paths, "qa", "existing qa work"); err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+
+	missing, err := MissingBootstrapRoles(paths)
+	if err != nil {
+		t.Fatalf("missing bootstrap roles: %v", err)
+	}
+	if fmt.Sprint(missing) != "[manager planner developer]" {
+		t.Fatalf("missing roles mismatch: got=%v", missing)
+	}
+	count, err := CountIssueFiles(paths.IssuesDir)
+	if err != nil {
+		t.Fatalf("count issues: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("MissingBootstrapRoles should not create issues, got=%d", count)
+	}
+}
+
+func TestListIssuesFiltersAndSorts(t *testing.T) {
+	paths := newTestPaths(t)
+
+	writeFile(t, filepath.Join(paths.IssuesDir, "I-20260222T000001Z-0001.md"), ""+
+		"id: I-20260222T000001Z-0001\n"+
+		"role: developer\n"+
+		"status: ready\n"+
+		"title: first\n"+
+		"created_at_utc: 2026-02-22T00:00:01Z\n")
+	writeFile(t, filepath.Join(paths.IssuesDir, "I-20260222T000002Z-0002.md"), ""+
+		"id: I-20260222T000002Z-0002\n"+
+		"role: developer\n"+
+		"status: ready\n"+
+		"title: second\n"+
+		"created_at_utc: 2026-02-22T00:00:02Z\n"+
+		"priority: 5\n")
+	writeFile(t, filepath.Join(paths.DoneDir, "I-20260222T000003Z-0003.md"), ""+
+		"id: I-20260222T000003Z-0003\n"+
+		"role: qa\n"+
+		"status: done\n"+
+		"title: third\n"+
+		"created_at_utc: 2026-02-22T00:00:03Z\n")
+
+	all, err := ListIssues(paths, IssueListOptions{})
+	if err != nil {
+		t.Fatalf("list issues: %v", err)
+	}
+	if len(all) != 3 || all[0].Meta.Title != "first" || all[2].Status != "done" {
+		t.Fatalf("created order mismatch: %+v", all)
+	}
+
+	byPriority, err := ListIssues(paths, IssueListOptions{Sort: "priority"})
+	if err != nil {
+		t.Fatalf("list issues by priority: %v", err)
+	}
+	if byPriority[0].Meta.Title != "second" {
+		t.Fatalf("priority order mismatch: first=%s", byPriority[0].Meta.Title)
+	}
+
+	filtered, err := ListIssues(paths, IssueListOptions{
+		Statuses: []string{"ready"},
+		Roles:    map[string]struct{}{"developer": {}},
+	})
+	if err != nil {
+		t.Fatalf("list filtered issues: %v", err)
+	}
+	if len(filtered) != 2 {
+		t.Fatalf("filtered count mismatch: got=%d want=2", len(filtered))
+	}
+
+	if _, err := ListIssues(paths, IssueListOptions{Sort: "title"}); err == nil {
+		t.Fatalf("expected invalid sort error")
+	}
+}
+
+func TestRemoveIssueRefusesInProgressWithoutForce(t *testing.T) {
+	paths := newTestPaths(t)
+
+	readyPath, id, err := CreateIssue(paths, "developer", "mistake")
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+	entry, err := FindIssue(paths, id)
+	if err != nil || entry.Path != readyPath || entry.Status != "ready" {
+		t.Fatalf("find issue mismatch: entry=%+v err=%v", entry, err)
+	}
+
+	inProgress := filepath.Join(paths.InProgressDir, "I-20260222T000009Z-0009.md")
+	writeFile(t, inProgress, "id: I-20260222T000009Z-0009\nrole: qa\nstatus: in-progress\ntitle: running\n")
+	if _, err := RemoveIssue(paths, "I-20260222T000009Z-0009", false); err == nil {
+		t.Fatalf("expected in-progress removal to be refused")
+	}
+	if _, err := os.Stat(inProgress); err != nil {
+		t.Fatalf("in-progress issue should remain: %v", err)
+	}
+	if _, err := RemoveIssue(paths, "I-20260222T000009Z-0009.md", true); err != nil {
+		t.Fatalf("force remove: %v", err)
+	}
+
+	if _, err := RemoveIssue(paths, id, false); err != nil {
+		t.Fatalf("remove ready issue: %v", err)
+	}
+	if _, err := FindIssue(paths, id); err == nil {
+		t.Fatalf("expected removed issue to be missing")
+	}
+	if _, err := FindIssue(paths, "../state"); err == nil {
+		t.Fatalf("expected path-like id to be rejected")
+	}
+}
+
+func TestSetIssuePriorityReordersReadyQueue(t *testing.T) {
+	paths := newTestPaths(t)
+
+	firstPath, _, err := CreateIssueWithOptions(paths, "developer", "first", IssueCreateOptions{Priority: 10})
+	if err != nil {
+		t.Fatalf("create first issue: %v", err)
+	}
+	_, secondID, err := CreateIssue(paths, "developer", "second")
+	if err != nil {
+		t.Fatalf("create second issue: %v", err)
+	}
+
+	picked, _, err := PickNextReadyIssue(paths)
+	if err != nil || picked != firstPath {
+		t.Fatalf("initial pick mismatch: got=%s err=%v", picked, err)
+	}
+
+	entry, oldPriority, err := SetIssuePriority(paths, secondID, 1)
+	if err != nil {
+		t.Fatalf("set priority: %v", err)
+	}
+	if oldPriority != 0 || entry.Meta.Priority != 1 {
+		t.Fatalf("priority change mismatch: old=%d new=%d", oldPriority, entry.Meta.Priority)
+	}
+	picked, meta, err := PickNextReadyIssue(paths)
+	if err != nil || meta.ID != secondID {
+		t.Fatalf("reordered pick mismatch: got=%s err=%v", picked, err)
+	}
+
+	if _, _, err := SetIssuePriority(paths, secondID, 0); err == nil {
+		t.Fatalf("expected non-positive priority to be rejected")
+	}
+}
+
+func TestNegativeAndTopPriorityRunFirst(t *testing.T) {
+	paths := newTestPaths(t)
+
+	if _, _, err := CreateIssueWithOptions(paths, "developer", "urgent", IssueCreateOptions{Priority: 1}); err != nil {
+		t.Fatalf("create urgent issue: %v", err)
+	}
+	negativePath, _, err := CreateIssueWithOptions(paths, "developer", "negative", IssueCreateOptions{Priority: -5})
+	if err != nil {
+		t.Fatalf("create negative issue: %v", err)
+	}
+	picked, meta, err := PickNextReadyIssue(paths)
+	if err != nil || picked != negativePath || meta.Priority != -5 {
+		t.Fatalf("negative priority should run first: got=%s priority=%d err=%v", picked, meta.Priority, err)
+	}
+
+	topPath, _, err := CreateIssueWithOptions(paths, "qa", "top", IssueCreateOptions{Priority: 50, Top: true})
+	if err != nil {
+		t.Fatalf("create top issue: %v", err)
+	}
+	picked, meta, err = PickNextReadyIssue(paths)
+	if err != nil || picked != topPath || meta.Priority != -6 {
+		t.Fatalf("top issue should run first with priority -6: got=%s priority=%d err=%v", picked, meta.Priority, err)
+	}
+
+	empty := newTestPaths(t)
+	if top, err := TopIssuePriority(empty); err != nil || top != -1 {
+		t.Fatalf("top priority of an empty queue should be -1, got %d err=%v", top, err)
+	}
+}
+
+func TestCreateIssueUsesRoleTemplate(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := os.MkdirAll(IssueTemplatesDir(paths.ControlDir), 0o755); err != nil {
+		t.Fatalf("create template dir: %v", err)
+	}
+	writeFile(t, IssueTemplatePath(paths.ControlDir, "qa"), "## Scenario\n- {{title}} ({{role}})\n\n## Acceptance Criteria\n- [ ] regression suite passes\n")
+	writeFile(t, IssueTemplatePath(paths.ControlDir, "bug"), "## Bug\n- {{title}}\n")
+
+	qaPath, _, err := CreateIssue(paths, "qa", "login flow")
+	if err != nil {
+		t.Fatalf("create qa issue: %v", err)
+	}
+	data, _ := os.ReadFile(qaPath)
+	if !strings.Contains(string(data), "\n\n## Scenario\n- login flow (qa)\n") || strings.Contains(string(data), "## Objective") {
+		t.Fatalf("qa issue should use role template:\n%s", string(data))
+	}
+	if meta, err := ReadIssueMeta(qaPath); err != nil || meta.Title != "login flow" || meta.Role != "qa" {
+		t.Fatalf("template must not change meta: meta=%+v err=%v", meta, err)
+	}
+
+	devPath, _, err := CreateIssue(paths, "developer", "no template")
+	if err != nil {
+		t.Fatalf("create developer issue: %v", err)
+	}
+	if data, _ := os.ReadFile(devPath); !strings.Contains(string(data), "## Objective\n- no template\n") {
+		t.Fatalf("developer issue should fall back to default body:\n%s", string(data))
+	}
+
+	bugPath, _, err := CreateIssueWithOptions(paths, "qa", "crash on save", IssueCreateOptions{Template: "bug"})
+	if err != nil {
+		t.Fatalf("create with template override: %v", err)
+	}
+	if data, _ := os.ReadFile(bugPath); !strings.Contains(string(data), "## Bug\n- crash on save\n") {
+		t.Fatalf("override template not applied:\n%s", string(data))
+	}
+
+	prdPath, _, err := CreateIssueWithOptions(paths, "qa", "imported", IssueCreateOptions{Objective: "explicit objective"})
+	if err != nil {
+		t.Fatalf("create with objective: %v", err)
+	}
+	if data, _ := os.ReadFile(prdPath); !strings.Contains(string(data), "## Objective\n- explicit objective\n") {
+		t.Fatalf("explicit objective should bypass template:\n%s", string(data))
+	}
+
+	if _, _, err := CreateIssueWithOptions(paths, "qa", "missing", IssueCreateOptions{Template: "nope"}); err == nil || !strings.Contains(err.Error(), "issue template not found") {
+		t.Fatalf("expected missing template error, got %v", err)
+	}
+}
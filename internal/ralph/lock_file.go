@@ -0,0 +1,84 @@
+package ralph
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const emptyLockStaleAfter = 10 * time.Second
+
+// TryCreateOwnedLock creates path holding this process's pid and the current
+// time, followed by one line per info value. A lock whose owner is gone is
+// reclaimed by renaming it to a unique tombstone first: only one contender's
+// rename succeeds, so two processes cannot both reclaim it.
+func TryCreateOwnedLock(path string, info ...string) (bool, error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, _ = fmt.Fprintf(f, "%d\n%s\n", os.Getpid(), time.Now().UTC().Format(time.RFC3339))
+			for _, line := range info {
+				_, _ = fmt.Fprintln(f, line)
+			}
+			_ = f.Close()
+			return true, nil
+		}
+		if !os.IsExist(err) {
+			return false, err
+		}
+		if !lockOwnerDead(path) {
+			return false, nil
+		}
+		tombstone := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+		if err := os.Rename(path, tombstone); err != nil {
+			// Another process reclaimed it first; try again to create.
+			continue
+		}
+		if !lockOwnerDead(tombstone) {
+			// A fresh lock replaced the dead one between the check and the rename.
+			_ = os.Rename(tombstone, path)
+			return false, nil
+		}
+		_ = os.Remove(tombstone)
+	}
+	return false, nil
+}
+
+func lockOwnerDead(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		// The owner died between creating and writing the lock.
+		info, statErr := os.Stat(path)
+		return statErr == nil && time.Since(info.ModTime()) > emptyLockStaleAfter
+	}
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return false
+	}
+	return !isPIDRunning(pid)
+}
+
+// WithOwnedLock runs fn while holding the lock file at path, waiting up to wait.
+func WithOwnedLock(path string, wait time.Duration, fn func() error) error {
+	deadline := time.Now().Add(wait)
+	for {
+		ok, err := TryCreateOwnedLock(path)
+		if err != nil {
+			return fmt.Errorf("acquire lock %s: %w", path, err)
+		}
+		if ok {
+			defer func() { _ = os.Remove(path) }()
+			return fn()
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("acquire lock %s: still held after %s", path, wait)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,28 @@
+package ralph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTryCreateOwnedLockReclaimsDeadOwnerOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x.lock")
+	writeFile(t, path, "999999999\n2026-01-01T00:00:00Z\n")
+
+	ok, err := TryCreateOwnedLock(path)
+	if err != nil || !ok {
+		t.Fatalf("dead owner lock should be reclaimed: ok=%t err=%v", ok, err)
+	}
+	// A second contender must not reclaim the fresh lock it just lost to.
+	if ok, err := TryCreateOwnedLock(path); err != nil || ok {
+		t.Fatalf("live lock should not be reclaimed: ok=%t err=%v", ok, err)
+	}
+	stale, _ := filepath.Glob(path + ".stale-*")
+	if len(stale) != 0 {
+		t.Fatalf("tombstones should be cleaned up: %v", stale)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("lock should exist: %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package ralph
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// DaemonLogFileEnv and DaemonLogRotateEnv tell a spawned daemon which log its
+	// stdout/stderr point at and the "<max bytes>:<backups>" limits to rotate it by.
+	DaemonLogFileEnv   = "RALPH_DAEMON_LOG_FILE"
+	DaemonLogRotateEnv = "RALPH_DAEMON_LOG_ROTATE"
+)
+
+// LogRotationLimits converts the profile's log_max_size_mb/log_max_backups.
+// maxBytes <= 0 disables rotation.
+func LogRotationLimits(profile Profile) (int64, int) {
+	return int64(profile.LogMaxSizeMB) * 1024 * 1024, profile.LogMaxBackups
+}
+
+// DaemonLogEnv is the env a daemon spawner passes along with the log handle.
+func DaemonLogEnv(logFile string, profile Profile) []string {
+	maxBytes, backups := LogRotationLimits(profile)
+	return []string{
+		DaemonLogFileEnv + "=" + logFile,
+		fmt.Sprintf("%s=%d:%d", DaemonLogRotateEnv, maxBytes, backups),
+	}
+}
+
+func ParseDaemonLogRotateEnv(raw string) (int64, int) {
+	sizeRaw, backupsRaw, _ := strings.Cut(strings.TrimSpace(raw), ":")
+	maxBytes, err := strconv.ParseInt(sizeRaw, 10, 64)
+	if err != nil || maxBytes < 0 {
+		return 0, 0
+	}
+	backups, err := strconv.Atoi(backupsRaw)
+	if err != nil || backups < 0 {
+		backups = 0
+	}
+	return maxBytes, backups
+}
+
+func rotatedLogPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d.gz", path, n)
+}
+
+// RotateLogIfNeeded rotates path once it reaches maxBytes: path becomes
+// path.1.gz, older backups shift up and anything past backups is dropped.
+func RotateLogIfNeeded(path string, maxBytes int64, backups int) (bool, error) {
+	if maxBytes <= 0 {
+		return false, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("stat log: %w", err)
+	}
+	if info.Size() < maxBytes {
+		return false, nil
+	}
+	if backups <= 0 {
+		if err := os.Truncate(path, 0); err != nil {
+			return false, fmt.Errorf("truncate log: %w", err)
+		}
+		return true, nil
+	}
+	if err := shiftLogBackups(path, backups); err != nil {
+		return false, err
+	}
+	staged := path + ".1"
+	if err := os.Rename(path, staged); err != nil {
+		return false, fmt.Errorf("rotate log: %w", err)
+	}
+	if err := gzipLogFile(staged, rotatedLogPath(path, 1)); err != nil {
+		return false, err
+	}
+	return true, os.Remove(staged)
+}
+
+// shiftLogBackups frees the path.1.gz slot, dropping the backup past backups.
+func shiftLogBackups(path string, backups int) error {
+	_ = os.Remove(rotatedLogPath(path, backups))
+	for n := backups - 1; n >= 1; n-- {
+		if err := os.Rename(rotatedLogPath(path, n), rotatedLogPath(path, n+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("shift log backup: %w", err)
+		}
+	}
+	return nil
+}
+
+func gzipLogFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open rotated log: %w", err)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("create compressed log: %w", err)
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		_ = zw.Close()
+		_ = out.Close()
+		return fmt.Errorf("compress log: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("compress log: %w", err)
+	}
+	return out.Close()
+}
+
+// RotatingLogWriter appends to path and rotates it before a write would cross maxBytes.
+type RotatingLogWriter struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	backups  int
+	f        *os.File
+	size     int64
+}
+
+func NewRotatingLogWriter(path string, maxBytes int64, backups int) (*RotatingLogWriter, error) {
+	w := &RotatingLogWriter{path: path, maxBytes: maxBytes, backups: backups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingLogWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat log: %w", err)
+	}
+	w.f = f
+	w.size = info.Size()
+	return nil
+}
+
+func (w *RotatingLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.maxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		// doctor --repair may have truncated the file in place (copyTruncateLog).
+		if info, err := w.f.Stat(); err == nil {
+			w.size = info.Size()
+		}
+	}
+	if w.maxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		_ = w.f.Close()
+		// Force the rotation: the file may still be under maxBytes on disk.
+		if _, err := RotateLogIfNeeded(w.path, 1, w.backups); err != nil {
+			_ = w.open()
+			return 0, err
+		}
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RotatingLogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.f.Close()
+}
@@ -0,0 +1,114 @@
+package ralph
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readGzipFile(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip reader %s: %v", path, err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestRotatingLogWriterCompressesAndCapsBackups(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "runner.out")
+	w, err := NewRotatingLogWriter(path, 10, 2)
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+	for _, line := range []string{"first-12345\n", "second-1234\n", "third-12345\n", "fourth-1234\n"} {
+		if _, err := io.WriteString(w, line); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil || string(current) != "fourth-1234\n" {
+		t.Fatalf("current log should hold only the latest write: %q err=%v", current, err)
+	}
+	if got := readGzipFile(t, path+".1.gz"); got != "third-12345\n" {
+		t.Fatalf("newest backup mismatch: %q", got)
+	}
+	if got := readGzipFile(t, path+".2.gz"); got != "second-1234\n" {
+		t.Fatalf("oldest backup mismatch: %q", got)
+	}
+	if _, err := os.Stat(path + ".3.gz"); !os.IsNotExist(err) {
+		t.Fatalf("backups beyond the cap should be dropped: %v", err)
+	}
+}
+
+func TestRotatingLogWriterFollowsCopyTruncate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runner.out")
+	w, err := NewRotatingLogWriter(path, 20, 2)
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+	defer w.Close()
+	if _, err := io.WriteString(w, "0123456789abcdef\n"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := copyTruncateLog(path, 2); err != nil {
+		t.Fatalf("copy truncate: %v", err)
+	}
+	if _, err := io.WriteString(w, "next-line\n"); err != nil {
+		t.Fatalf("write after truncate: %v", err)
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil || string(current) != "next-line\n" {
+		t.Fatalf("writer should append to the truncated file: %q err=%v", current, err)
+	}
+	if got := readGzipFile(t, path+".1.gz"); got != "0123456789abcdef\n" {
+		t.Fatalf("copy-truncate backup mismatch: %q", got)
+	}
+	if _, err := os.Stat(path + ".2.gz"); !os.IsNotExist(err) {
+		t.Fatalf("writer must not rotate on its stale size: %v", err)
+	}
+}
+
+func TestRotateLogIfNeededSkipsSmallLogs(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "telegram.out")
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 20)), 0o644); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+	if rotated, err := RotateLogIfNeeded(path, 100, 3); err != nil || rotated {
+		t.Fatalf("small log should stay: rotated=%t err=%v", rotated, err)
+	}
+	if rotated, err := RotateLogIfNeeded(path, 20, 3); err != nil || !rotated {
+		t.Fatalf("log at the limit should rotate: rotated=%t err=%v", rotated, err)
+	}
+	if got := readGzipFile(t, path+".1.gz"); got != strings.Repeat("x", 20) {
+		t.Fatalf("rotated content mismatch: %q", got)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("rotated log should be moved away: %v", err)
+	}
+
+	if maxBytes, backups := ParseDaemonLogRotateEnv("1048576:4"); maxBytes != 1048576 || backups != 4 {
+		t.Fatalf("parse rotate env: %d %d", maxBytes, backups)
+	}
+}
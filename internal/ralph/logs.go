@@ -0,0 +1,307 @@
+package ralph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type LogSource struct {
+	Name string
+	Path string
+}
+
+type LogLine struct {
+	Source string
+	Time   time.Time
+	Text   string
+}
+
+func ProjectLogSources(paths Paths) []LogSource {
+	sources := []LogSource{{Name: "loop", Path: paths.RunnerLogFile}}
+	for _, role := range RequiredAgentRoles {
+		sources = append(sources, LogSource{Name: role, Path: paths.RoleRunnerLogFile(role)})
+	}
+	sources = append(sources, LogSource{Name: "telegram", Path: paths.TelegramLogFile()})
+	return sources
+}
+
+func ReadMergedLogs(sources []LogSource, limit int) ([]LogLine, error) {
+	if limit <= 0 {
+		limit = 120
+	}
+	merged := []LogLine{}
+	for _, source := range sources {
+		lines, err := readLastLogLines(source, limit)
+		if err != nil {
+			return nil, err
+		}
+		merged = append(merged, lines...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Time.Before(merged[j].Time)
+	})
+	if len(merged) > limit {
+		merged = merged[len(merged)-limit:]
+	}
+	return merged, nil
+}
+
+// FleetLogSources returns the primary loop log of each project, named by project id.
+func FleetLogSources(controlDir string, projects []FleetProject) ([]LogSource, error) {
+	sources := make([]LogSource, 0, len(projects))
+	for _, project := range projects {
+		paths, err := NewPaths(controlDir, project.ProjectDir)
+		if err != nil {
+			return nil, fmt.Errorf("resolve paths for %s: %w", project.ID, err)
+		}
+		sources = append(sources, LogSource{Name: project.ID, Path: paths.RunnerLogFile})
+	}
+	return sources, nil
+}
+
+func TailMergedLogs(ctx context.Context, paths Paths, w io.Writer, limit int, follow bool) error {
+	if err := EnsureLayout(paths); err != nil {
+		return err
+	}
+	return TailLogSources(ctx, ProjectLogSources(paths), w, limit, follow)
+}
+
+// TailLogSources prints the last limit lines across sources in time order,
+// prefixed with the source name, and optionally follows appended lines.
+func TailLogSources(ctx context.Context, sources []LogSource, w io.Writer, limit int, follow bool) error {
+	offsets := map[string]int64{}
+	for _, source := range sources {
+		if info, err := os.Stat(source.Path); err == nil {
+			offsets[source.Path] = info.Size()
+		}
+	}
+
+	lines, err := ReadMergedLogs(sources, limit)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		fmt.Fprintf(w, "[%s] %s\n", line.Source, line.Text)
+	}
+	if !follow {
+		return nil
+	}
+
+	for {
+		if err := sleepOrCancel(ctx, time.Second); err != nil {
+			return nil
+		}
+		for _, source := range sources {
+			next, err := printAppendedLogLines(w, source, offsets[source.Path])
+			if err != nil {
+				return err
+			}
+			offsets[source.Path] = next
+		}
+	}
+}
+
+func printAppendedLogLines(w io.Writer, source LogSource, offset int64) (int64, error) {
+	f, err := os.Open(source.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return offset, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return offset, err
+	}
+	if info.Size() < offset {
+		// Truncated or rotated: start over from the beginning.
+		offset = 0
+	}
+	if info.Size() == offset {
+		return offset, nil
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return offset, err
+	}
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// Keep a partial trailing line for the next poll.
+			return offset, nil
+		}
+		offset += int64(len(line))
+		fmt.Fprintf(w, "[%s] %s\n", source.Name, strings.TrimRight(line, "\r\n"))
+	}
+}
+
+func readLastLogLines(source LogSource, limit int) ([]LogLine, error) {
+	f, err := os.Open(source.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	texts := []string{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		texts = append(texts, scanner.Text())
+		if len(texts) > limit {
+			texts = texts[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read log %s: %w", source.Path, err)
+	}
+
+	// Lines without their own timestamp inherit the previous one; leading
+	// untimestamped lines take the first timestamp seen, or the file mtime.
+	out := make([]LogLine, 0, len(texts))
+	var current time.Time
+	pending := 0
+	for _, text := range texts {
+		if ts, ok := parseLogLineTime(text); ok {
+			current = ts
+			for i := len(out) - pending; i < len(out); i++ {
+				out[i].Time = ts
+			}
+			pending = 0
+		} else if current.IsZero() {
+			pending++
+		}
+		out = append(out, LogLine{Source: source.Name, Time: current, Text: text})
+	}
+	for i := len(out) - pending; i < len(out); i++ {
+		out[i].Time = info.ModTime().UTC()
+	}
+	return out, nil
+}
+
+// logLineTimeLayout is the prefix TimestampLogWriter adds: fixed-width UTC with
+// nanoseconds, the same precision as json "ts", so stamped text lines and json
+// lines from another source never tie or swap within a millisecond.
+const logLineTimeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// timestampLogMaxPending bounds how much of an unterminated line is held back
+// before it is written out on its own.
+const timestampLogMaxPending = 64 * 1024
+
+// TimestampLogWriter prefixes every line that does not already carry a
+// timestamp with the time it was written. Daemon output (loop, role workers,
+// supervisor, telegram) is mostly bare "[ralph-loop] ..." text; without this,
+// `logs` and `fleet logs` could only order it by file mtime. Lines that
+// parseLogLineTime already understands, including json log-format objects
+// with "ts", pass through unchanged so they stay valid JSON.
+type TimestampLogWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+	buf []byte
+	now func() time.Time
+}
+
+func NewTimestampLogWriter(out io.Writer) *TimestampLogWriter {
+	return &TimestampLogWriter{out: out, now: time.Now}
+}
+
+func (w *TimestampLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf[:i+1]
+		w.buf = w.buf[i+1:]
+		if err := w.writeLine(line); err != nil {
+			return len(p), err
+		}
+	}
+	if len(w.buf) > timestampLogMaxPending {
+		if err := w.flushLocked(); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes a trailing partial line, terminated, so nothing is lost on exit.
+func (w *TimestampLogWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flushLocked()
+}
+
+func (w *TimestampLogWriter) flushLocked() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+func (w *TimestampLogWriter) writeLine(line []byte) error {
+	text := strings.TrimRight(string(line), "\r\n")
+	if _, ok := parseLogLineTime(text); !ok && strings.TrimSpace(text) != "" {
+		line = append([]byte(w.now().UTC().Format(logLineTimeLayout)+" "), line...)
+	}
+	_, err := w.out.Write(line)
+	return err
+}
+
+func parseLogLineTime(line string) (time.Time, bool) {
+	if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "{") {
+		// --log-format json lines carry their time in "ts".
+		var entry struct {
+			TS string `json:"ts"`
+		}
+		if err := json.Unmarshal([]byte(trimmed), &entry); err == nil {
+			if ts, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(entry.TS)); err == nil {
+				return ts.UTC(), true
+			}
+		}
+		return time.Time{}, false
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+	first := strings.Trim(fields[0], "[]")
+	for _, layout := range []string{time.RFC3339Nano, time.RFC3339} {
+		if ts, err := time.Parse(layout, first); err == nil {
+			return ts.UTC(), true
+		}
+	}
+	if len(fields) >= 2 {
+		second := strings.Trim(fields[1], "[]")
+		if ts, err := time.ParseInLocation("2006/01/02 15:04:05", first+" "+second, time.Local); err == nil {
+			return ts.UTC(), true
+		}
+		if ts, err := time.ParseInLocation("2006-01-02 15:04:05", first+" "+second, time.Local); err == nil {
+			return ts.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
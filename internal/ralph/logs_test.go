@@ -0,0 +1,202 @@
+package ralph
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadMergedLogsInterleavesByTimestamp(t *testing.T) {
+	t.Parallel()
+
+	paths := newTestPaths(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	writeFile(t, paths.RunnerLogFile, strings.Join([]string{
+		"2026-02-20T00:00:00Z [ralph-loop] start",
+		"2026-02-20T00:00:03Z [ralph-loop] picked issue",
+		"continuation line",
+	}, "\n")+"\n")
+	writeFile(t, paths.RoleRunnerLogFile("qa"), "2026-02-20T00:00:02Z [ralph-loop] qa start\n")
+	writeFile(t, paths.TelegramLogFile(), "2026-02-20T00:00:01Z [telegram] bot started\n")
+
+	lines, err := ReadMergedLogs(ProjectLogSources(paths), 10)
+	if err != nil {
+		t.Fatalf("ReadMergedLogs failed: %v", err)
+	}
+	got := []string{}
+	for _, line := range lines {
+		got = append(got, line.Source)
+	}
+	if joined := strings.Join(got, ","); joined != "loop,telegram,qa,loop,loop" {
+		t.Fatalf("merged order mismatch: %s", joined)
+	}
+
+	limited, err := ReadMergedLogs(ProjectLogSources(paths), 2)
+	if err != nil {
+		t.Fatalf("ReadMergedLogs limit failed: %v", err)
+	}
+	if len(limited) != 2 {
+		t.Fatalf("limit mismatch: got=%d want=2", len(limited))
+	}
+}
+
+func TestTailLogSourcesPrefixesFleetProjectIDs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	controlDir := filepath.Join(root, "control")
+	projects := []FleetProject{
+		{ID: "alpha", ProjectDir: filepath.Join(root, "alpha")},
+		{ID: "beta", ProjectDir: filepath.Join(root, "beta")},
+	}
+	sources, err := FleetLogSources(controlDir, projects)
+	if err != nil {
+		t.Fatalf("FleetLogSources failed: %v", err)
+	}
+	for _, source := range sources {
+		if err := os.MkdirAll(filepath.Dir(source.Path), 0o755); err != nil {
+			t.Fatalf("create log dir: %v", err)
+		}
+	}
+	writeFile(t, sources[0].Path, "2026-02-20T00:00:00Z alpha start\n2026-02-20T00:00:02Z alpha done\n")
+	writeFile(t, sources[1].Path, "2026-02-20T00:00:01Z beta start\n")
+
+	var out strings.Builder
+	if err := TailLogSources(context.Background(), sources, &out, 10, false); err != nil {
+		t.Fatalf("TailLogSources failed: %v", err)
+	}
+	want := strings.Join([]string{
+		"[alpha] 2026-02-20T00:00:00Z alpha start",
+		"[beta] 2026-02-20T00:00:01Z beta start",
+		"[alpha] 2026-02-20T00:00:02Z alpha done",
+	}, "\n") + "\n"
+	if out.String() != want {
+		t.Fatalf("fleet tail mismatch:\n%s", out.String())
+	}
+}
+
+func TestTimestampLogWriterStampsBareLines(t *testing.T) {
+	t.Parallel()
+
+	var out strings.Builder
+	w := NewTimestampLogWriter(&out)
+	w.now = func() time.Time { return time.Date(2026, 2, 20, 0, 0, 1, 0, time.UTC) }
+	for _, chunk := range []string{
+		"[ralph-loop] no ready ",
+		"issues\n2026-02-20T00:00:00Z already stamped\n",
+		`{"ts":"2026-02-20T00:00:00.5Z","event":"log","msg":"json"}` + "\n",
+		"\n[ralph-loop] partial",
+	} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if strings.Contains(out.String(), "partial") {
+		t.Fatalf("an unterminated line should wait for its newline: %q", out.String())
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	want := strings.Join([]string{
+		"2026-02-20T00:00:01.000000000Z [ralph-loop] no ready issues",
+		"2026-02-20T00:00:00Z already stamped",
+		`{"ts":"2026-02-20T00:00:00.5Z","event":"log","msg":"json"}`,
+		"",
+		"2026-02-20T00:00:01.000000000Z [ralph-loop] partial",
+	}, "\n") + "\n"
+	if out.String() != want {
+		t.Fatalf("stamped output mismatch:\n%s", out.String())
+	}
+	if ts, ok := parseLogLineTime(`{"ts":"2026-02-20T00:00:00.5Z","event":"log","msg":"json"}`); !ok || ts.Nanosecond() != 500000000 {
+		t.Fatalf("json ts should be parsed: ts=%s ok=%t", ts, ok)
+	}
+}
+
+func TestFleetLogsInterleaveRealLoopOutput(t *testing.T) {
+	resetProfileEnv(t)
+	root := t.TempDir()
+	controlDir := filepath.Join(root, "control")
+	projects := []FleetProject{
+		{ID: "alpha", ProjectDir: filepath.Join(root, "alpha")},
+		{ID: "beta", ProjectDir: filepath.Join(root, "beta")},
+	}
+	sources, err := FleetLogSources(controlDir, projects)
+	if err != nil {
+		t.Fatalf("FleetLogSources failed: %v", err)
+	}
+	projectPaths := map[string]Paths{}
+	for _, p := range projects {
+		paths, err := NewPaths(controlDir, p.ProjectDir)
+		if err != nil {
+			t.Fatalf("new paths: %v", err)
+		}
+		if err := EnsureLayout(paths); err != nil {
+			t.Fatalf("ensure layout: %v", err)
+		}
+		if err := SetEnabled(paths, true); err != nil {
+			t.Fatalf("enable: %v", err)
+		}
+		projectPaths[p.ID] = paths
+	}
+
+	// Each run writes the loop's own output the way a daemon does: through
+	// TimestampLogWriter into the runner log. beta uses --log-format json.
+	runLoop := func(id, format string) {
+		t.Helper()
+		paths := projectPaths[id]
+		f, err := os.OpenFile(paths.RunnerLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatalf("open runner log: %v", err)
+		}
+		defer f.Close()
+		stamped := NewTimestampLogWriter(f)
+		profile := DefaultProfile()
+		profile.RequireCodex = false
+		profile.ExitOnIdle = false
+		profile.BusyWaitDetectLoops = 0
+		if err := RunLoop(context.Background(), paths, profile, RunOptions{
+			Stdout:     stamped,
+			LogFormat:  format,
+			MaxRuntime: 100 * time.Millisecond,
+		}); err != nil {
+			t.Fatalf("run loop %s: %v", id, err)
+		}
+		if err := stamped.Flush(); err != nil {
+			t.Fatalf("flush: %v", err)
+		}
+	}
+	runLoop("alpha", LoopLogFormatText)
+	runLoop("beta", LoopLogFormatJSON)
+	runLoop("alpha", LoopLogFormatText)
+
+	data, err := os.ReadFile(projectPaths["beta"].RunnerLogFile)
+	if err != nil {
+		t.Fatalf("read beta log: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if strings.HasPrefix(line, "{") && !json.Valid([]byte(line)) {
+			t.Fatalf("json log line should stay valid json: %q", line)
+		}
+	}
+
+	var out strings.Builder
+	if err := TailLogSources(context.Background(), sources, &out, 100, false); err != nil {
+		t.Fatalf("TailLogSources failed: %v", err)
+	}
+	runs := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		source, _, _ := strings.Cut(line, " ")
+		if len(runs) == 0 || runs[len(runs)-1] != source {
+			runs = append(runs, source)
+		}
+	}
+	if got := strings.Join(runs, ","); got != "[alpha],[beta],[alpha]" {
+		t.Fatalf("projects should interleave by write time, got %s:\n%s", got, out.String())
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -18,6 +19,10 @@ type RunOptions struct {
 	MaxLoops     int
 	Stdout       io.Writer
 	AllowedRoles map[string]struct{}
+	LogFormat    string
+	MaxRuntime   time.Duration
+	// Drain stops claiming new issues once closed; the in-flight issue keeps running.
+	Drain <-chan struct{}
 }
 
 type BusyWaitHealResult struct {
@@ -40,6 +45,7 @@ type IssueProcessResult struct {
 	CodexFailure      bool
 	CodexFailureCause string
 	CodexRetryable    bool
+	CodexRetries      int
 }
 
 type codexExecutionError struct {
@@ -73,11 +79,47 @@ func RunLoop(ctx context.Context, paths Paths, profile Profile, opts RunOptions)
 	if opts.Stdout == nil {
 		opts.Stdout = os.Stdout
 	}
+	if res, err := MigrateState(paths); err != nil {
+		return err
+	} else if res.Migrated {
+		fmt.Fprintf(opts.Stdout, "[ralph-loop] state schema migrated: v%d -> v%d\n", res.FromVersion, res.ToVersion)
+	}
 	if opts.MaxLoops < 0 {
 		opts.MaxLoops = 0
 	}
+	logFormat, err := NormalizeLoopLogFormat(opts.LogFormat)
+	if err != nil {
+		return err
+	}
+	// runCtx only bounds idle waits; in-flight issue work keeps ctx so the
+	// current iteration can finish once MaxRuntime is exceeded or a drain starts.
+	runCtx := ctx
+	var deadline time.Time
+	if opts.MaxRuntime > 0 {
+		deadline = time.Now().Add(opts.MaxRuntime)
+		var cancelRun context.CancelFunc
+		runCtx, cancelRun = context.WithDeadline(ctx, deadline)
+		defer cancelRun()
+	}
+	if opts.Drain != nil {
+		var cancelDrain context.CancelFunc
+		runCtx, cancelDrain = context.WithCancel(runCtx)
+		defer cancelDrain()
+		go func() {
+			select {
+			case <-opts.Drain:
+				cancelDrain()
+			case <-runCtx.Done():
+			}
+		}()
+	}
+	var jsonLog *loopJSONLogWriter
+	if logFormat == LoopLogFormatJSON {
+		jsonLog = newLoopJSONLogWriter(opts.Stdout)
+		opts.Stdout = jsonLog
+	}
 
-	if profile.RequireCodex {
+	if profile.RequireCodex && !profile.CodexDryRun {
 		if _, err := exec.LookPath("codex"); err != nil {
 			return fmt.Errorf("codex command not found")
 		}
@@ -134,8 +176,15 @@ func RunLoop(ctx context.Context, paths Paths, profile Profile, opts RunOptions)
 		case <-ctx.Done():
 			fmt.Fprintln(opts.Stdout, "[ralph-loop] interrupted; stopping")
 			return nil
+		case <-opts.Drain:
+			fmt.Fprintln(opts.Stdout, "[ralph-loop] drain requested; stopping")
+			return nil
 		default:
 		}
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			fmt.Fprintf(opts.Stdout, "[ralph-loop] max runtime reached (%s); stopping\n", opts.MaxRuntime)
+			return nil
+		}
 		tickCount++
 
 		enabled, err := IsEnabled(paths)
@@ -185,7 +234,7 @@ func RunLoop(ctx context.Context, paths Paths, profile Profile, opts RunOptions)
 					})
 					codexCircuitWaitingLogged = true
 				}
-				if err := sleepOrCancel(ctx, time.Duration(activeProfile.IdleSleepSec)*time.Second); err != nil {
+				if err := sleepOrCancel(runCtx, time.Duration(activeProfile.IdleSleepSec)*time.Second); err != nil && ctx.Err() != nil {
 					return nil
 				}
 				continue
@@ -236,11 +285,13 @@ func RunLoop(ctx context.Context, paths Paths, profile Profile, opts RunOptions)
 			return err
 		}
 		if issuePath == "" {
+			// Ready work outside the role scope belongs to other workers: stay idle on purpose
+			// and keep it out of the busy-wait idle count.
 			if len(opts.AllowedRoles) > 0 {
-				globalReady, _ := CountReadyIssues(paths)
-				if globalReady > 0 {
-					fmt.Fprintf(opts.Stdout, "[ralph-loop] no ready issues for roles=%s; global_ready=%d; sleeping %ds\n", roleScope, globalReady, activeProfile.IdleSleepSec)
-					if err := sleepOrCancel(ctx, time.Duration(activeProfile.IdleSleepSec)*time.Second); err != nil {
+				scopedOut, _ := CountReadyIssuesOutsideRoles(paths, opts.AllowedRoles)
+				if scopedOut > 0 {
+					fmt.Fprintf(opts.Stdout, "[ralph-loop] %d ready issues skipped: role not in scope (roles=%s); sleeping %ds\n", scopedOut, roleScope, idlePollIntervalSec(activeProfile))
+					if err := sleepOrCancel(runCtx, time.Duration(idlePollIntervalSec(activeProfile))*time.Second); err != nil && ctx.Err() != nil {
 						return nil
 					}
 					continue
@@ -351,31 +402,54 @@ func RunLoop(ctx context.Context, paths Paths, profile Profile, opts RunOptions)
 				fmt.Fprintf(opts.Stdout, "[ralph-loop] no ready issues; reached no_ready_max_loops=%d\n", activeProfile.NoReadyMaxLoops)
 				return nil
 			}
-			fmt.Fprintf(opts.Stdout, "[ralph-loop] no ready issues; sleeping %ds\n", activeProfile.IdleSleepSec)
-			if err := sleepOrCancel(ctx, time.Duration(activeProfile.IdleSleepSec)*time.Second); err != nil {
+			fmt.Fprintf(opts.Stdout, "[ralph-loop] no ready issues; sleeping %ds\n", idlePollIntervalSec(activeProfile))
+			if err := sleepOrCancel(runCtx, time.Duration(idlePollIntervalSec(activeProfile))*time.Second); err != nil && ctx.Err() != nil {
 				return nil
 			}
 			continue
 		}
 		idleCount = 0
 
+		iterationStarted := time.Now()
 		processResult, err := processIssue(ctx, paths, activeProfile, issuePath, meta, opts.Stdout)
+		if errors.Is(err, ErrIssueAlreadyClaimed) {
+			fmt.Fprintf(opts.Stdout, "[ralph-loop] %v; picking next ready issue\n", err)
+			continue
+		}
+		if jsonLog != nil {
+			event := loopIterationEvent{
+				Iteration:    loopCount + 1,
+				Role:         meta.Role,
+				IssueID:      meta.ID,
+				Outcome:      processResult.Outcome,
+				CodexRetries: processResult.CodexRetries,
+				DurationMS:   time.Since(iterationStarted).Milliseconds(),
+			}
+			if err != nil {
+				event.Outcome = "error"
+				event.Error = err.Error()
+			}
+			_ = jsonLog.WriteEvent(event)
+		}
 		if err != nil {
 			fmt.Fprintf(opts.Stdout, "[ralph-loop] issue processing error: %v\n", err)
 			if isLikelyPermissionErr(err) {
 				permissionErrStreak++
-				waitSec := permissionErrorBackoffSec(activeProfile.IdleSleepSec, permissionErrStreak)
+				waitSec, cooldown := permissionRetryWaitSec(activeProfile, permissionErrStreak)
 				if appendErr := AppendBusyWaitEvent(paths, BusyWaitEvent{
 					Type:      "process_permission_error",
 					LoopCount: loopCount,
 					Result:    "detected",
 					Error:     err.Error(),
-					Detail:    fmt.Sprintf("streak=%d; wait_sec=%d; role_scope=%s", permissionErrStreak, waitSec, roleScopeOrAll(roleScope)),
+					Detail:    fmt.Sprintf("streak=%d; wait_sec=%d; cooldown=%t; role_scope=%s", permissionErrStreak, waitSec, cooldown, roleScopeOrAll(roleScope)),
 				}); appendErr != nil {
 					fmt.Fprintf(opts.Stdout, "[ralph-loop] warning: failed to append permission-error event: %v\n", appendErr)
 				}
+				if cooldown {
+					fmt.Fprintf(opts.Stdout, "[ralph-loop] permission failures reached streak=%d; cooling down %ds before retrying (check codex_sandbox/codex_approval)\n", permissionErrStreak, waitSec)
+				}
 				fmt.Fprintf(opts.Stdout, "[ralph-loop] permission-related failure detected (streak=%d); sleeping %ds and retrying. hint: ralphctl --control-dir %s --project-dir %s doctor --repair\n", permissionErrStreak, waitSec, paths.ControlDir, paths.ProjectDir)
-				if err := sleepOrCancel(ctx, time.Duration(waitSec)*time.Second); err != nil {
+				if err := sleepOrCancel(runCtx, time.Duration(waitSec)*time.Second); err != nil && ctx.Err() != nil {
 					return nil
 				}
 			} else {
@@ -387,6 +461,12 @@ func RunLoop(ctx context.Context, paths Paths, profile Profile, opts RunOptions)
 			if changed {
 				codexCircuitState = updatedCircuit
 			}
+			if processResult.Outcome == "dry_run" {
+				// The issue is ready again; pause like an idle loop instead of re-running it at once.
+				if err := sleepOrCancel(runCtx, time.Duration(idlePollIntervalSec(activeProfile))*time.Second); err != nil && ctx.Err() != nil {
+					return nil
+				}
+			}
 		}
 		loopCount++
 	}
@@ -433,6 +513,15 @@ func roleScopeOrAll(scope string) string {
 	return scope
 }
 
+// idlePollIntervalSec is the empty-queue re-check interval. It falls back to
+// idle_sleep_sec, which still paces circuit and permission back-off waits.
+func idlePollIntervalSec(p Profile) int {
+	if p.IdlePollIntervalSec > 0 {
+		return p.IdlePollIntervalSec
+	}
+	return p.IdleSleepSec
+}
+
 func sleepOrCancel(ctx context.Context, d time.Duration) error {
 	t := time.NewTimer(d)
 	defer t.Stop()
@@ -446,17 +535,45 @@ func sleepOrCancel(ctx context.Context, d time.Duration) error {
 
 func processIssue(ctx context.Context, paths Paths, profile Profile, issuePath string, meta IssueMeta, stdout io.Writer) (IssueProcessResult, error) {
 	res := IssueProcessResult{Outcome: "unknown"}
-	inProgressPath := filepath.Join(paths.InProgressDir, meta.ID+".md")
-	if err := os.Rename(issuePath, inProgressPath); err != nil {
-		return res, fmt.Errorf("move to in-progress: %w", err)
-	}
-	if err := SetIssueStatus(inProgressPath, "in-progress"); err != nil {
+	startedAt := time.Now().UTC()
+	inProgressPath, err := ClaimIssue(paths, issuePath, meta)
+	if err != nil {
 		return res, err
 	}
 
 	logPath := filepath.Join(paths.LogsDir, fmt.Sprintf("%s-%s.log", meta.ID, time.Now().UTC().Format("20060102T150405Z")))
 	handoffPath := HandoffFilePath(paths, meta)
-	if err := runCodexAndValidate(ctx, paths, profile, inProgressPath, meta, logPath, handoffPath); err != nil {
+	if err := runCodexAndValidate(ctx, paths, profile, inProgressPath, meta, logPath, handoffPath, &res.CodexRetries); err != nil {
+		if errors.Is(err, errCodexDryRun) {
+			res.Outcome = "dry_run"
+			if requeueErr := requeueDryRunIssue(paths, inProgressPath, meta); requeueErr != nil {
+				return res, requeueErr
+			}
+			fmt.Fprintf(stdout, "[ralph-loop] codex dry-run for %s; returned to ready without recording a failure\n", meta.ID)
+			return res, nil
+		}
+		if profile.MaxIssueAttempts > 0 {
+			prevFailures, countErr := issueFailedAttempts(inProgressPath)
+			if countErr == nil && prevFailures+1 >= profile.MaxIssueAttempts {
+				res.Outcome = "dead-letter"
+				res.FailureReason = err.Error()
+				var codexErr *codexExecutionError
+				if errors.As(err, &codexErr) {
+					res.CodexFailure = true
+					res.CodexFailureCause = strings.TrimSpace(codexErr.Reason)
+					res.CodexRetryable = codexErr.Retryable
+				}
+				reason := fmt.Sprintf("dead_letter attempts=%d/%d; cause=%s", prevFailures+1, profile.MaxIssueAttempts, err.Error())
+				if _, moveErr := moveIssueToDeadLetter(paths, inProgressPath, meta, reason, logPath); moveErr != nil {
+					return res, fmt.Errorf("%v, root cause: %w", moveErr, err)
+				}
+				if progressErr := AppendProgressEntry(paths, meta, "dead-letter", reason, logPath); progressErr != nil {
+					fmt.Fprintf(stdout, "[ralph-loop] warning: progress journal append failed: %v\n", progressErr)
+				}
+				fmt.Fprintf(stdout, "[ralph-loop] dead-lettered %s after %d failed attempts: %v\n", meta.ID, prevFailures+1, err)
+				return res, nil
+			}
+		}
 		if requeue, attempt, maxAttempts := shouldAutoRequeueCompletionGateFailure(err, inProgressPath); requeue {
 			res.Outcome = "requeued"
 			res.FailureReason = err.Error()
@@ -519,12 +636,40 @@ func processIssue(ctx context.Context, paths Paths, profile Profile, issuePath s
 	if progressErr := AppendProgressEntry(paths, meta, "done", "completed", logPath); progressErr != nil {
 		fmt.Fprintf(stdout, "[ralph-loop] warning: progress journal append failed: %v\n", progressErr)
 	}
+	finishedAt := time.Now().UTC()
+	if historyErr := AppendCompletedHistory(paths, CompletedIssue{
+		ID:           meta.ID,
+		Role:         meta.Role,
+		Title:        meta.Title,
+		StartedAt:    startedAt,
+		FinishedAt:   finishedAt,
+		DurationMS:   finishedAt.Sub(startedAt).Milliseconds(),
+		CodexRetries: res.CodexRetries,
+	}); historyErr != nil {
+		fmt.Fprintf(stdout, "[ralph-loop] warning: completed history: %v\n", historyErr)
+	}
 	fmt.Fprintf(stdout, "[ralph-loop] done %s (%s)\n", meta.ID, meta.Title)
 	res.Outcome = "done"
 	return res, nil
 }
 
-func runCodexAndValidate(ctx context.Context, paths Paths, profile Profile, inProgressPath string, meta IssueMeta, logPath, handoffPath string) error {
+// requeueDryRunIssue moves a claimed issue back to ready without a result entry,
+// so dry runs never count toward blocked or dead-letter.
+func requeueDryRunIssue(paths Paths, inProgressPath string, meta IssueMeta) error {
+	if err := SetIssueStatus(inProgressPath, "ready"); err != nil {
+		return err
+	}
+	readyPath := filepath.Join(paths.IssuesDir, meta.ID+".md")
+	if _, err := os.Stat(readyPath); err == nil {
+		readyPath = filepath.Join(paths.IssuesDir, fmt.Sprintf("requeued-%s-%s.md", time.Now().UTC().Format("20060102T150405Z"), meta.ID))
+	}
+	if err := os.Rename(inProgressPath, readyPath); err != nil {
+		return fmt.Errorf("requeue dry-run issue: %w", err)
+	}
+	return nil
+}
+
+func runCodexAndValidate(ctx context.Context, paths Paths, profile Profile, inProgressPath string, meta IssueMeta, logPath, handoffPath string, codexRetries *int) error {
 	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
 	if err != nil {
 		return fmt.Errorf("open log file: %w", err)
@@ -574,7 +719,12 @@ func runCodexAndValidate(ctx context.Context, paths Paths, profile Profile, inPr
 			modelLabel = "auto(codex default)"
 		}
 		_, _ = fmt.Fprintf(logFile, "[ralph] codex role=%s model=%s\n", meta.Role, modelLabel)
-		if err := runCodexWithRetries(ctx, paths, profile, model, prompt, logFile, lastMessagePath); err != nil {
+		audit := newCodexAuditLog(paths, profile, meta.ID)
+		attempts, err := runCodexWithRetryCount(ctx, paths, profile, model, prompt, logFile, lastMessagePath, audit)
+		if codexRetries != nil && attempts > 1 {
+			*codexRetries = attempts - 1
+		}
+		if err != nil {
 			return err
 		}
 		if lastMessagePath != "" {
@@ -839,6 +989,12 @@ func isLegacyGoDefaultValidateCmd(cmd string) bool {
 }
 
 func runCodexWithRetries(ctx context.Context, paths Paths, profile Profile, model, prompt string, logFile *os.File, lastMessagePath string) error {
+	_, err := runCodexWithRetryCount(ctx, paths, profile, model, prompt, logFile, lastMessagePath, nil)
+	return err
+}
+
+// runCodexWithRetryCount also reports how many attempts were made.
+func runCodexWithRetryCount(ctx context.Context, paths Paths, profile Profile, model, prompt string, logFile *os.File, lastMessagePath string, audit *codexAuditLog) (int, error) {
 	attempts := profile.CodexRetryMaxAttempts
 	if attempts <= 0 {
 		attempts = 1
@@ -850,11 +1006,13 @@ func runCodexWithRetries(ctx context.Context, paths Paths, profile Profile, mode
 
 	var lastErr error
 	lastRetryable := false
+	made := 0
 	for attempt := 1; attempt <= attempts; attempt++ {
+		made = attempt
 		_, _ = fmt.Fprintf(logFile, "[ralph] codex attempt %d/%d\n", attempt, attempts)
-		err, retryable := runSingleCodexAttempt(ctx, paths, profile, model, prompt, logFile, lastMessagePath)
-		if err == nil {
-			return nil
+		err, retryable := runSingleCodexAttempt(ctx, paths, profile, model, prompt, logFile, lastMessagePath, audit)
+		if err == nil || errors.Is(err, errCodexDryRun) {
+			return made, err
 		}
 		lastErr = err
 		lastRetryable = retryable
@@ -862,11 +1020,11 @@ func runCodexWithRetries(ctx context.Context, paths Paths, profile Profile, mode
 			break
 		}
 
-		waitSec := codexRetryBackoff(backoffSec, attempt)
-		if waitSec > 0 {
-			_, _ = fmt.Fprintf(logFile, "[ralph] codex attempt %d failed (%v); retrying in %ds\n", attempt, err, waitSec)
-			if err := sleepOrCancel(ctx, time.Duration(waitSec)*time.Second); err != nil {
-				return fmt.Errorf("codex_retry_canceled")
+		wait := ApplyRetryJitter(time.Duration(codexRetryBackoff(backoffSec, attempt))*time.Second, profile.CodexRetryJitterPct)
+		if wait > 0 {
+			_, _ = fmt.Fprintf(logFile, "[ralph] codex attempt %d failed (%v); retrying in %s\n", attempt, err, wait.Round(time.Millisecond))
+			if err := sleepOrCancel(ctx, wait); err != nil {
+				return made, fmt.Errorf("codex_retry_canceled")
 			}
 		} else {
 			_, _ = fmt.Fprintf(logFile, "[ralph] codex attempt %d failed (%v); retrying immediately\n", attempt, err)
@@ -884,14 +1042,26 @@ func runCodexWithRetries(ctx context.Context, paths Paths, profile Profile, mode
 	if reason == "" {
 		reason = "codex_execution_error"
 	}
-	return &codexExecutionError{
+	return made, &codexExecutionError{
 		Reason:    reason,
 		Detail:    detail,
 		Retryable: lastRetryable,
 	}
 }
 
-func runSingleCodexAttempt(ctx context.Context, paths Paths, profile Profile, model, prompt string, logFile *os.File, lastMessagePath string) (error, bool) {
+func runSingleCodexAttempt(ctx context.Context, paths Paths, profile Profile, model, prompt string, logFile *os.File, lastMessagePath string, audit *codexAuditLog) (error, bool) {
+	// Held per attempt so retry backoff frees the slot; taken before the exec
+	// timeout starts so time spent waiting does not count against it.
+	releaseSlot, err := AcquireCodexSlot(ctx, paths, profile.CodexMaxConcurrent, codexSlotWaitLimit(profile))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("codex_canceled"), false
+		}
+		_, _ = fmt.Fprintf(logFile, "[ralph] %v\n", err)
+		return err, true
+	}
+	defer releaseSlot()
+
 	cmdCtx := ctx
 	cancel := func() {}
 	if profile.CodexExecTimeoutSec > 0 {
@@ -922,13 +1092,35 @@ func runSingleCodexAttempt(ctx context.Context, paths Paths, profile Profile, mo
 	// Use stdin prompt to avoid argv length limits for large issue/rule payloads.
 	args = append(args, "-")
 
+	if profile.CodexDryRun {
+		recordPath, err := WriteCodexDryRunRecord(paths, "loop", args, prompt)
+		if err != nil {
+			return fmt.Errorf("codex_dry_run_error: %w", err), false
+		}
+		_, _ = fmt.Fprintf(logFile, "[ralph] codex dry-run: prompt written to %s\n", recordPath)
+		if strings.TrimSpace(lastMessagePath) != "" {
+			_ = os.WriteFile(lastMessagePath, []byte("codex dry-run: prompt written to "+recordPath+"\n"), 0o644)
+		}
+		// Stop before validation so the issue is neither passed without work nor blocked.
+		return errCodexDryRun, false
+	}
+
 	codexCmd := exec.CommandContext(cmdCtx, "codex", args...)
 	codexCmd.Env = EnvWithCodexHome(os.Environ(), codexHome)
+	setCodexProcessGroup(codexCmd)
 	tail := newTailBuffer(64 * 1024)
 	codexCmd.Stdout = io.MultiWriter(logFile, tail)
 	codexCmd.Stderr = io.MultiWriter(logFile, tail)
 	codexCmd.Stdin = strings.NewReader(prompt)
-	runErr := codexCmd.Run()
+	runErr := codexCmd.Start()
+	if runErr == nil {
+		clearGroup := recordCodexGroup(paths.runtimeDir(), daemonGroupOwner(), codexCmd.Process.Pid)
+		runErr = codexCmd.Wait()
+		clearGroup()
+	}
+	if err := audit.record(args, prompt, runErr, lastMessagePath); err != nil {
+		_, _ = fmt.Fprintf(logFile, "[ralph] warning: %v\n", err)
+	}
 	if runErr == nil {
 		return nil, false
 	}
@@ -941,13 +1133,34 @@ func runSingleCodexAttempt(ctx context.Context, paths Paths, profile Profile, mo
 	}
 
 	code := exitCode(runErr)
-	if reason, retryable := classifyCodexFailure(code, strings.ToLower(tail.String())); !retryable {
+	outputLower := strings.ToLower(tail.String())
+	if reason, retryable := classifyCodexFailure(code, outputLower); !retryable {
 		_, _ = fmt.Fprintf(logFile, "[ralph] codex non-retryable failure: %s (rc=%d)\n", reason, code)
 		return fmt.Errorf("%s", reason), false
 	}
+	// Tag network failures so the block reason, and ClassifyFailureCategory,
+	// can tell them apart from other retryable exits.
+	if hasAnySubstring(outputLower, codexNetworkMarkers...) {
+		return fmt.Errorf("codex_exit_%d (network)", code), code != 130
+	}
 	return fmt.Errorf("codex_exit_%d", code), code != 130
 }
 
+// codexKillWaitDelay bounds how long Run waits for output pipes after the
+// process group is killed, in case a child escaped the group and holds them.
+const codexKillWaitDelay = 5 * time.Second
+
+// setCodexProcessGroup starts codex in its own process group so a timeout or
+// cancel kills codex and everything it spawned, not just the codex parent.
+// The group is recorded with recordCodexGroup so stop can reach it as well.
+func setCodexProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = daemonSysProcAttr()
+	cmd.Cancel = func() error {
+		return killDaemonProcessGroup(cmd.Process.Pid)
+	}
+	cmd.WaitDelay = codexKillWaitDelay
+}
+
 func codexLastMessagePath(logPath string) string {
 	base := strings.TrimSuffix(logPath, filepath.Ext(logPath))
 	if base == "" {
@@ -1050,6 +1263,7 @@ func preflightLoopPermissions(paths Paths) error {
 		{name: "issues-dir", path: paths.IssuesDir},
 		{name: "in-progress-dir", path: paths.InProgressDir},
 		{name: "blocked-dir", path: paths.BlockedDir},
+		{name: "dead-letter-dir", path: paths.DeadLetterDir},
 		{name: "done-dir", path: paths.DoneDir},
 		{name: "logs-dir", path: paths.LogsDir},
 	}
@@ -1078,6 +1292,18 @@ func ensureDirWritable(dir string) error {
 	return nil
 }
 
+var codexNetworkMarkers = []string{
+	"stream disconnected",
+	"could not resolve host",
+	"temporary failure in name resolution",
+	"connection refused",
+	"connection reset",
+	"i/o timeout",
+	"tls handshake timeout",
+	"no such host",
+	"network is unreachable",
+}
+
 func classifyCodexFailure(exitCode int, outputLower string) (string, bool) {
 	if exitCode == 130 {
 		return "codex_canceled", false
@@ -1115,18 +1341,7 @@ func classifyCodexFailure(exitCode int, outputLower string) (string, bool) {
 		return "codex_model_error", false
 	}
 
-	networkMarkers := []string{
-		"stream disconnected",
-		"could not resolve host",
-		"temporary failure in name resolution",
-		"connection refused",
-		"connection reset",
-		"i/o timeout",
-		"tls handshake timeout",
-		"no such host",
-		"network is unreachable",
-	}
-	if hasAnySubstring(outputLower, networkMarkers...) {
+	if hasAnySubstring(outputLower, codexNetworkMarkers...) {
 		return "", true
 	}
 
@@ -1169,6 +1384,15 @@ func isLikelyPermissionErr(err error) bool {
 	)
 }
 
+// permissionRetryWaitSec backs off per failure until the streak reaches
+// permission_cooldown_streak, then holds every retry for permission_cooldown_sec.
+func permissionRetryWaitSec(profile Profile, streak int) (int, bool) {
+	if profile.PermissionCooldownSec > 0 && profile.PermissionCooldownStreak > 0 && streak >= profile.PermissionCooldownStreak {
+		return profile.PermissionCooldownSec, true
+	}
+	return permissionErrorBackoffSec(profile.IdleSleepSec, streak), false
+}
+
 func permissionErrorBackoffSec(idleSleepSec, streak int) int {
 	base := idleSleepSec
 	if base < 5 {
@@ -1221,6 +1445,22 @@ func (b *tailBuffer) String() string {
 	return string(b.data)
 }
 
+// ApplyRetryJitter stretches a retry wait by a random fraction of up to pct
+// percent so concurrent workers do not retry in lockstep.
+func ApplyRetryJitter(wait time.Duration, pct int) time.Duration {
+	if wait <= 0 || pct <= 0 {
+		return wait
+	}
+	if pct > 100 {
+		pct = 100
+	}
+	maxJitter := int64(wait) * int64(pct) / 100
+	if maxJitter <= 0 {
+		return wait
+	}
+	return wait + time.Duration(rand.Int63n(maxJitter+1))
+}
+
 func codexRetryBackoff(baseSec, attempt int) int {
 	if baseSec <= 0 {
 		return 0
@@ -0,0 +1,98 @@
+package ralph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	LoopLogFormatText = "text"
+	LoopLogFormatJSON = "json"
+)
+
+type loopIterationEvent struct {
+	TS           string `json:"ts"`
+	Event        string `json:"event"`
+	Iteration    int    `json:"iteration"`
+	Role         string `json:"role"`
+	IssueID      string `json:"issue_id"`
+	Outcome      string `json:"outcome"`
+	CodexRetries int    `json:"codex_retries"`
+	DurationMS   int64  `json:"duration_ms"`
+	Error        string `json:"error,omitempty"`
+}
+
+type loopLogLine struct {
+	TS    string `json:"ts"`
+	Event string `json:"event"`
+	Msg   string `json:"msg"`
+}
+
+func NormalizeLoopLogFormat(raw string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", LoopLogFormatText:
+		return LoopLogFormatText, nil
+	case LoopLogFormatJSON, "jsonl":
+		return LoopLogFormatJSON, nil
+	default:
+		return "", fmt.Errorf("invalid log format: %s (expected text|json)", raw)
+	}
+}
+
+// loopJSONLogWriter wraps each complete text line written by the loop into a
+// JSON object so json mode output stays one object per line.
+type loopJSONLogWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+	buf []byte
+	now func() time.Time
+}
+
+func newLoopJSONLogWriter(out io.Writer) *loopJSONLogWriter {
+	return &loopJSONLogWriter{out: out, now: time.Now}
+}
+
+func (w *loopJSONLogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := strings.TrimRight(string(w.buf[:i]), "\r")
+		w.buf = w.buf[i+1:]
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if err := w.writeJSON(loopLogLine{TS: w.now().UTC().Format(time.RFC3339Nano), Event: "log", Msg: line}); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *loopJSONLogWriter) WriteEvent(event loopIterationEvent) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if event.TS == "" {
+		event.TS = w.now().UTC().Format(time.RFC3339Nano)
+	}
+	event.Event = "iteration"
+	return w.writeJSON(event)
+}
+
+func (w *loopJSONLogWriter) writeJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.out.Write(append(data, '\n'))
+	return err
+}
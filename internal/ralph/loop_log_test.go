@@ -0,0 +1,65 @@
+package ralph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoopJSONLogWriterEmitsOneObjectPerLine(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	w := newLoopJSONLogWriter(&out)
+	w.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	fmt.Fprint(w, "[ralph-loop] partial")
+	if out.Len() != 0 {
+		t.Fatalf("partial line should be buffered: %q", out.String())
+	}
+	fmt.Fprint(w, " line\n\n[ralph-loop] second\n")
+	if err := w.WriteEvent(loopIterationEvent{Iteration: 1, Role: "developer", IssueID: "I-1", Outcome: "done", CodexRetries: 2, DurationMS: 15}); err != nil {
+		t.Fatalf("write event: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("line count mismatch: got=%d output=%q", len(lines), out.String())
+	}
+	var first loopLogLine
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("decode log line: %v", err)
+	}
+	if first.Event != "log" || first.Msg != "[ralph-loop] partial line" || first.TS != "2026-01-02T03:04:05Z" {
+		t.Fatalf("log line mismatch: %+v", first)
+	}
+	var event map[string]any
+	if err := json.Unmarshal([]byte(lines[2]), &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	for _, key := range []string{"ts", "iteration", "role", "issue_id", "outcome", "codex_retries", "duration_ms"} {
+		if _, ok := event[key]; !ok {
+			t.Fatalf("event missing %s: %v", key, event)
+		}
+	}
+	if event["event"] != "iteration" || event["codex_retries"] != float64(2) {
+		t.Fatalf("event mismatch: %v", event)
+	}
+}
+
+func TestNormalizeLoopLogFormat(t *testing.T) {
+	t.Parallel()
+
+	if got, err := NormalizeLoopLogFormat(""); err != nil || got != LoopLogFormatText {
+		t.Fatalf("default format mismatch: got=%s err=%v", got, err)
+	}
+	if got, err := NormalizeLoopLogFormat("JSON"); err != nil || got != LoopLogFormatJSON {
+		t.Fatalf("json format mismatch: got=%s err=%v", got, err)
+	}
+	if _, err := NormalizeLoopLogFormat("xml"); err == nil {
+		t.Fatalf("expected invalid format error")
+	}
+}
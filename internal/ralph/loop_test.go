@@ -1,6 +1,7 @@
 package ralph
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
@@ -38,6 +39,24 @@ func TestCodexRetryBackoff(t *testing.T) {
 	}
 }
 
+func TestApplyRetryJitter(t *testing.T) {
+	t.Parallel()
+
+	base := 10 * time.Second
+	if got := ApplyRetryJitter(base, 0); got != base {
+		t.Fatalf("zero jitter should keep wait: got=%s", got)
+	}
+	if got := ApplyRetryJitter(0, 20); got != 0 {
+		t.Fatalf("zero wait should stay zero: got=%s", got)
+	}
+	for i := 0; i < 50; i++ {
+		got := ApplyRetryJitter(base, 20)
+		if got < base || got > 12*time.Second {
+			t.Fatalf("jittered wait out of range: got=%s", got)
+		}
+	}
+}
+
 func TestShouldRunWatchdogScan(t *testing.T) {
 	t.Parallel()
 
@@ -315,6 +334,24 @@ func TestPermissionErrorBackoffSec(t *testing.T) {
 	}
 }
 
+func TestPermissionRetryWaitSecCooldown(t *testing.T) {
+	t.Parallel()
+
+	profile := DefaultProfile()
+	profile.PermissionCooldownStreak = 3
+	profile.PermissionCooldownSec = 900
+	if got, cooldown := permissionRetryWaitSec(profile, 2); cooldown || got != permissionErrorBackoffSec(profile.IdleSleepSec, 2) {
+		t.Fatalf("below threshold should back off: got=%d cooldown=%t", got, cooldown)
+	}
+	if got, cooldown := permissionRetryWaitSec(profile, 3); !cooldown || got != 900 {
+		t.Fatalf("threshold should cool down: got=%d cooldown=%t", got, cooldown)
+	}
+	profile.PermissionCooldownSec = 0
+	if _, cooldown := permissionRetryWaitSec(profile, 10); cooldown {
+		t.Fatalf("cooldown_sec=0 should disable cooldown")
+	}
+}
+
 func TestReloadLoopProfileUnchanged(t *testing.T) {
 	paths := newTestPaths(t)
 	resetProfileEnv(t)
@@ -486,3 +523,135 @@ func TestBuildRecentExecutionSummary(t *testing.T) {
 		t.Fatalf("summary should include latest lines: %q", got)
 	}
 }
+
+func TestRunLoopStopsCleanlyAfterMaxRuntime(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	if err := SetEnabled(paths, true); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+
+	profile := DefaultProfile()
+	profile.RequireCodex = false
+	profile.IdleSleepSec = 60
+	profile.ExitOnIdle = false
+	profile.BusyWaitDetectLoops = 0
+
+	var out strings.Builder
+	started := time.Now()
+	err := RunLoop(context.Background(), paths, profile, RunOptions{
+		MaxLoops:   0,
+		Stdout:     &out,
+		MaxRuntime: 200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("run loop: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 10*time.Second {
+		t.Fatalf("loop should stop near max runtime: elapsed=%s", elapsed)
+	}
+	if !strings.Contains(out.String(), "max runtime reached") {
+		t.Fatalf("missing max runtime log: %q", out.String())
+	}
+}
+
+func TestRunLoopUsesIdlePollIntervalWhenQueueEmpty(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	if err := SetEnabled(paths, true); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+
+	t.Setenv("RALPH_IDLE_POLL_INTERVAL_SEC", "90")
+
+	profile := DefaultProfile()
+	profile.RequireCodex = false
+	profile.ExitOnIdle = false
+	profile.BusyWaitDetectLoops = 0
+
+	var out strings.Builder
+	started := time.Now()
+	err := RunLoop(context.Background(), paths, profile, RunOptions{
+		Stdout:     &out,
+		MaxRuntime: 200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("run loop: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 10*time.Second {
+		t.Fatalf("idle poll sleep should be interruptible: elapsed=%s", elapsed)
+	}
+	if !strings.Contains(out.String(), "no ready issues; sleeping 90s") {
+		t.Fatalf("empty queue should sleep idle_poll_interval_sec: %q", out.String())
+	}
+
+	profile.IdlePollIntervalSec = 0
+	if got := idlePollIntervalSec(profile); got != profile.IdleSleepSec {
+		t.Fatalf("unset idle poll interval should fall back to idle_sleep_sec: got=%d", got)
+	}
+}
+
+func TestRunLoopDrainStopsClaimingNewIssues(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	if err := SetEnabled(paths, true); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	readyPath, _, err := CreateIssue(paths, "developer", "pending work")
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+
+	profile := DefaultProfile()
+	profile.RequireCodex = false
+	profile.BusyWaitDetectLoops = 0
+
+	drain := make(chan struct{})
+	close(drain)
+	var out strings.Builder
+	if err := RunLoop(context.Background(), paths, profile, RunOptions{Stdout: &out, Drain: drain}); err != nil {
+		t.Fatalf("run loop: %v", err)
+	}
+	if !strings.Contains(out.String(), "drain requested") {
+		t.Fatalf("missing drain log: %q", out.String())
+	}
+	if _, err := os.Stat(readyPath); err != nil {
+		t.Fatalf("ready issue should not be claimed during drain: %v", err)
+	}
+}
+
+func TestRunLoopReportsScopedOutIssuesWithoutBusyWait(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	if err := SetEnabled(paths, true); err != nil {
+		t.Fatalf("enable: %v", err)
+	}
+	readyPath, _, err := CreateIssue(paths, "developer", "out of scope work")
+	if err != nil {
+		t.Fatalf("create issue: %v", err)
+	}
+
+	profile := DefaultProfile()
+	profile.RequireCodex = false
+	profile.IdleSleepSec = 60
+	profile.BusyWaitDetectLoops = 1
+
+	var out strings.Builder
+	err = RunLoop(context.Background(), paths, profile, RunOptions{
+		Stdout:       &out,
+		AllowedRoles: map[string]struct{}{"qa": {}, "manager": {}},
+		MaxRuntime:   200 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("run loop: %v", err)
+	}
+	if !strings.Contains(out.String(), "1 ready issues skipped: role not in scope") {
+		t.Fatalf("missing scoped-out log: %q", out.String())
+	}
+	if strings.Contains(out.String(), "busy-wait detected") {
+		t.Fatalf("scoped-out queue must not trigger busy-wait: %q", out.String())
+	}
+	if _, err := os.Stat(readyPath); err != nil {
+		t.Fatalf("out-of-scope issue should stay ready: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package ralph
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// StatusMetricsSample is one project's Status, labelled by its fleet project id.
+type StatusMetricsSample struct {
+	ProjectID string
+	Status    Status
+}
+
+type statusMetric struct {
+	name  string
+	kind  string
+	help  string
+	value func(Status) float64
+}
+
+func boolMetric(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+var statusMetrics = []statusMetric{
+	{"ralph_enabled", "gauge", "Whether the project loop is enabled.", func(s Status) float64 { return boolMetric(s.Enabled) }},
+	{"ralph_daemon_running", "gauge", "Whether any ralph daemon or role worker is running.", func(s Status) float64 { return boolMetric(s.Daemon != "stopped") }},
+	{"ralph_queue_ready", "gauge", "Ready issues.", func(s Status) float64 { return float64(s.QueueReady) }},
+	{"ralph_queue_in_progress", "gauge", "In-progress issues.", func(s Status) float64 { return float64(s.InProgress) }},
+	{"ralph_queue_done", "gauge", "Done issues.", func(s Status) float64 { return float64(s.Done) }},
+	{"ralph_queue_blocked", "gauge", "Blocked issues.", func(s Status) float64 { return float64(s.Blocked) }},
+	{"ralph_queue_dead_letter", "gauge", "Dead-lettered issues.", func(s Status) float64 { return float64(s.DeadLetter) }},
+	{"ralph_queue_scoped_out", "gauge", "Ready issues outside every running worker's role scope.", func(s Status) float64 { return float64(s.ScopedOutCount) }},
+	{"ralph_codex_circuit_open", "gauge", "Whether the codex circuit breaker is open.", func(s Status) float64 { return boolMetric(s.CodexCircuitState == "open") }},
+	{"ralph_codex_circuit_failures", "gauge", "Consecutive codex failures counted by the circuit breaker.", func(s Status) float64 { return float64(s.CodexCircuitFailures) }},
+	{"ralph_self_heal_attempts", "gauge", "Busy-wait self-heal attempts.", func(s Status) float64 { return float64(s.SelfHealAttempts) }},
+	{"ralph_throughput_per_hour", "gauge", "Completed issues per hour over the status window.", func(s Status) float64 { return s.ThroughputPerHour }},
+	{"ralph_codex_retries_total", "counter", "Codex retries across all completed issues.", func(s Status) float64 { return float64(s.CodexRetriesTotal) }},
+	{"ralph_profile_reloads_total", "counter", "Profile hot reloads.", func(s Status) float64 { return float64(s.ProfileReloadCount) }},
+	{"ralph_role_restarts_total", "counter", "Supervisor worker restarts across all roles.", func(s Status) float64 {
+		total := 0
+		for _, n := range s.RoleRestartCounts {
+			total += n
+		}
+		return float64(total)
+	}},
+}
+
+// WriteStatusMetrics renders samples in Prometheus text format. The only label is
+// project (the fleet id) to keep series cardinality at one per metric per project.
+func WriteStatusMetrics(w io.Writer, samples []StatusMetricsSample) {
+	for _, m := range statusMetrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, sample := range samples {
+			fmt.Fprintf(w, "%s{project=\"%s\"} %s\n", m.name, escapeMetricLabel(sample.ProjectID), strconv.FormatFloat(m.value(sample.Status), 'f', -1, 64))
+		}
+	}
+}
+
+func escapeMetricLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// StatusMetricsProjectID returns the fleet id registered for paths.ProjectDir,
+// falling back to the project directory name.
+func StatusMetricsProjectID(paths Paths) string {
+	if cfg, err := LoadFleetConfig(paths.ControlDir); err == nil {
+		for _, p := range cfg.Projects {
+			if filepath.Clean(p.ProjectDir) == filepath.Clean(paths.ProjectDir) {
+				return p.ID
+			}
+		}
+	}
+	return filepath.Base(paths.ProjectDir)
+}
@@ -0,0 +1,33 @@
+package ralph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteStatusMetricsAggregatesProjects(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	WriteStatusMetrics(&b, []StatusMetricsSample{
+		{ProjectID: "alpha", Status: Status{QueueReady: 2, Blocked: 1, ProfileReloadCount: 3, CodexRetriesTotal: 4, CodexCircuitFailures: 2, SelfHealAttempts: 1, RoleRestartCounts: map[string]int{"developer": 2, "qa": 1}}},
+		{ProjectID: `be"ta`, Status: Status{QueueReady: 5, Daemon: "stopped"}},
+	})
+	out := b.String()
+	for _, want := range []string{
+		"# TYPE ralph_queue_ready gauge\nralph_queue_ready{project=\"alpha\"} 2\nralph_queue_ready{project=\"be\\\"ta\"} 5\n",
+		"# TYPE ralph_codex_retries_total counter\nralph_codex_retries_total{project=\"alpha\"} 4\n",
+		"ralph_profile_reloads_total{project=\"alpha\"} 3\n",
+		"ralph_role_restarts_total{project=\"alpha\"} 3\n",
+		"# TYPE ralph_codex_circuit_failures gauge\nralph_codex_circuit_failures{project=\"alpha\"} 2\n",
+		"# TYPE ralph_self_heal_attempts gauge\nralph_self_heal_attempts{project=\"alpha\"} 1\n",
+		"ralph_daemon_running{project=\"be\\\"ta\"} 0\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("metrics missing %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "# TYPE ralph_queue_blocked "); n != 1 {
+		t.Fatalf("TYPE line should appear once per metric: %d", n)
+	}
+}
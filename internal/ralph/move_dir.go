@@ -0,0 +1,65 @@
+package ralph
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// renameDir is os.Rename, swappable so tests can simulate a cross-device move.
+var renameDir = os.Rename
+
+// moveDir renames src to dst, falling back to copy+remove when they sit on
+// different filesystems (EXDEV), e.g. a repo moved onto another volume.
+func moveDir(src, dst string) error {
+	err := renameDir(src, dst)
+	if err == nil || !errors.Is(err, errCrossDevice) {
+		return err
+	}
+	if err := copyDirTree(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return fmt.Errorf("copy across filesystems: %w", err)
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return fmt.Errorf("remove %s after copy: %w", src, err)
+	}
+	return nil
+}
+
+// copyDirTree copies src into a new dst, keeping file modes and symlinks.
+func copyDirTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			if err := copyFile(path, target); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode().Perm())
+		default:
+			// Sockets and fifos belong to processes that were stopped before the move.
+			return nil
+		}
+	})
+}
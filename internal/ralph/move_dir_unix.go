@@ -0,0 +1,8 @@
+//go:build !windows
+
+package ralph
+
+import "syscall"
+
+// EXDEV: rename cannot cross filesystems.
+var errCrossDevice error = syscall.EXDEV
@@ -0,0 +1,8 @@
+//go:build windows
+
+package ralph
+
+import "syscall"
+
+// ERROR_NOT_SAME_DEVICE: MoveFile cannot rename across volumes.
+var errCrossDevice error = syscall.Errno(17)
@@ -1,9 +1,12 @@
 package ralph
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type Paths struct {
@@ -15,6 +18,7 @@ type Paths struct {
 	InProgressDir          string
 	DoneDir                string
 	BlockedDir             string
+	DeadLetterDir          string
 	ReportsDir             string
 	HandoffsDir            string
 	LogsDir                string
@@ -33,9 +37,28 @@ type Paths struct {
 	BusyWaitEventsFile     string
 	ProgressJournal        string
 	AgentSetFile           string
+	// OutputDir is where reports, logs and pid files live when output_dir
+	// relocates them out of .ralph; empty means they stay under RalphDir.
+	OutputDir string
 }
 
+// NewPaths resolves the project layout, honoring RALPH_OUTPUT_DIR (the
+// --output-dir global flag) or the project's output_dir profile key.
 func NewPaths(controlDir, projectDir string) (Paths, error) {
+	paths, err := newProjectPaths(controlDir, projectDir)
+	if err != nil {
+		return Paths{}, err
+	}
+	outputDir := strings.TrimSpace(os.Getenv("RALPH_OUTPUT_DIR"))
+	if outputDir == "" {
+		if profile, err := LoadProfile(paths); err == nil {
+			outputDir = profile.OutputDir
+		}
+	}
+	return paths.WithOutputDir(outputDir), nil
+}
+
+func newProjectPaths(controlDir, projectDir string) (Paths, error) {
 	if controlDir == "" {
 		return Paths{}, fmt.Errorf("control-dir is required")
 	}
@@ -64,6 +87,7 @@ func NewPaths(controlDir, projectDir string) (Paths, error) {
 		InProgressDir:          filepath.Join(ralphDir, "in-progress"),
 		DoneDir:                filepath.Join(ralphDir, "done"),
 		BlockedDir:             filepath.Join(ralphDir, "blocked"),
+		DeadLetterDir:          filepath.Join(ralphDir, "dead-letter"),
 		ReportsDir:             reportsDir,
 		HandoffsDir:            filepath.Join(reportsDir, "handoffs"),
 		LogsDir:                filepath.Join(ralphDir, "logs"),
@@ -85,8 +109,41 @@ func NewPaths(controlDir, projectDir string) (Paths, error) {
 	}, nil
 }
 
+// WithOutputDir moves reports, logs and pid files under outputDir while issues,
+// state and profile stay in the project. Each project gets its own subdirectory
+// (<base>-<hash of project dir>) so fleet projects sharing one output dir never
+// clash. A relative outputDir is taken from the project dir; "" is a no-op.
+func (p Paths) WithOutputDir(outputDir string) Paths {
+	outputDir = strings.TrimSpace(outputDir)
+	if outputDir == "" {
+		return p
+	}
+	if !filepath.IsAbs(outputDir) {
+		outputDir = filepath.Join(p.ProjectDir, outputDir)
+	}
+	sum := sha256.Sum256([]byte(p.ProjectDir))
+	root := filepath.Join(filepath.Clean(outputDir), fmt.Sprintf("%s-%s", filepath.Base(p.ProjectDir), hex.EncodeToString(sum[:4])))
+	p.OutputDir = root
+	p.ReportsDir = filepath.Join(root, "reports")
+	p.HandoffsDir = filepath.Join(p.ReportsDir, "handoffs")
+	p.LogsDir = filepath.Join(root, "logs")
+	p.PIDFile = filepath.Join(root, "runner.pid")
+	p.RunnerLogFile = filepath.Join(p.LogsDir, "runner.out")
+	p.BusyWaitEventsFile = filepath.Join(p.ReportsDir, "busywait-events.jsonl")
+	p.ProgressJournal = filepath.Join(p.ReportsDir, "progress-journal.log")
+	return p
+}
+
+// runtimeDir holds pid files: OutputDir when relocated, otherwise RalphDir.
+func (p Paths) runtimeDir() string {
+	if p.OutputDir != "" {
+		return p.OutputDir
+	}
+	return p.RalphDir
+}
+
 func (p Paths) RolePIDFile(role string) string {
-	return filepath.Join(p.RalphDir, fmt.Sprintf("runner.%s.pid", role))
+	return filepath.Join(p.runtimeDir(), fmt.Sprintf("runner.%s.pid", role))
 }
 
 func (p Paths) RoleRunnerLogFile(role string) string {
@@ -94,13 +151,17 @@ func (p Paths) RoleRunnerLogFile(role string) string {
 }
 
 func (p Paths) TelegramPIDFile() string {
-	return filepath.Join(p.RalphDir, "telegram.pid")
+	return filepath.Join(p.runtimeDir(), "telegram.pid")
 }
 
 func (p Paths) TelegramLogFile() string {
 	return filepath.Join(p.LogsDir, "telegram.out")
 }
 
+func (p Paths) CompletedHistoryFile() string {
+	return filepath.Join(p.RalphDir, "state.completed-history.json")
+}
+
 func (p Paths) RoleRulesFile(role string) string {
 	return filepath.Join(p.RulesDir, fmt.Sprintf("%s.md", role))
 }
@@ -108,11 +169,13 @@ func (p Paths) RoleRulesFile(role string) string {
 func EnsureLayout(paths Paths) error {
 	dirs := []string{
 		paths.RalphDir,
+		paths.runtimeDir(),
 		paths.RulesDir,
 		paths.IssuesDir,
 		paths.InProgressDir,
 		paths.DoneDir,
 		paths.BlockedDir,
+		paths.DeadLetterDir,
 		paths.ReportsDir,
 		paths.HandoffsDir,
 		paths.LogsDir,
@@ -124,7 +187,7 @@ func EnsureLayout(paths Paths) error {
 	}
 
 	if _, err := os.Stat(paths.StateFile); os.IsNotExist(err) {
-		if err := os.WriteFile(paths.StateFile, []byte("RALPH_LOCAL_ENABLED=true\n"), 0o644); err != nil {
+		if err := os.WriteFile(paths.StateFile, []byte(stateFileContent(true, StateSchemaVersion)), 0o644); err != nil {
 			return fmt.Errorf("write state file: %w", err)
 		}
 	}
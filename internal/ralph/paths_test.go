@@ -3,6 +3,7 @@ package ralph
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -26,3 +27,56 @@ func TestEnsureLayoutCreatesTelegramLog(t *testing.T) {
 		t.Fatalf("telegram log file should exist: %v", err)
 	}
 }
+
+func TestNewPathsHonorsOutputDirOverride(t *testing.T) {
+	resetProfileEnv(t)
+
+	paths := newTestPaths(t)
+	if err := os.WriteFile(paths.ProfileYAMLFile, []byte("output_dir: ../out\n"), 0o644); err != nil {
+		t.Fatalf("write profile: %v", err)
+	}
+	relocated, err := NewPaths(paths.ControlDir, paths.ProjectDir)
+	if err != nil {
+		t.Fatalf("new paths: %v", err)
+	}
+	outRoot := filepath.Join(filepath.Dir(paths.ProjectDir), "out")
+	if filepath.Dir(relocated.OutputDir) != outRoot {
+		t.Fatalf("output dir should live under %s: got=%q", outRoot, relocated.OutputDir)
+	}
+	for name, got := range map[string]string{
+		"reports":      relocated.ReportsDir,
+		"logs":         relocated.LogsDir,
+		"runner pid":   relocated.PIDFile,
+		"role pid":     relocated.RolePIDFile("developer"),
+		"telegram pid": relocated.TelegramPIDFile(),
+		"telegram log": relocated.TelegramLogFile(),
+	} {
+		if !strings.HasPrefix(got, relocated.OutputDir+string(filepath.Separator)) {
+			t.Fatalf("%s should be relocated under %s: got=%q", name, relocated.OutputDir, got)
+		}
+	}
+	if relocated.IssuesDir != paths.IssuesDir || relocated.StateFile != paths.StateFile {
+		t.Fatalf("issues and state must stay in the project")
+	}
+	if err := EnsureLayout(relocated); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	if _, err := os.Stat(relocated.RunnerLogFile); err != nil {
+		t.Fatalf("runner log should be created in the output dir: %v", err)
+	}
+
+	other := paths.WithOutputDir(outRoot)
+	otherProject := Paths{ProjectDir: filepath.Join(filepath.Dir(paths.ProjectDir), "other", "project")}.WithOutputDir(outRoot)
+	if other.OutputDir == otherProject.OutputDir {
+		t.Fatalf("projects sharing an output dir need separate subdirectories: %q", other.OutputDir)
+	}
+
+	t.Setenv("RALPH_OUTPUT_DIR", filepath.Join(t.TempDir(), "env-out"))
+	fromEnv, err := NewPaths(paths.ControlDir, paths.ProjectDir)
+	if err != nil {
+		t.Fatalf("new paths from env: %v", err)
+	}
+	if !strings.HasPrefix(fromEnv.OutputDir, os.Getenv("RALPH_OUTPUT_DIR")) {
+		t.Fatalf("RALPH_OUTPUT_DIR should win over profile: got=%q", fromEnv.OutputDir)
+	}
+}
@@ -23,6 +23,7 @@ func AutoFixPermissions(paths Paths) (PermissionFixResult, error) {
 		paths.InProgressDir,
 		paths.DoneDir,
 		paths.BlockedDir,
+		paths.DeadLetterDir,
 		paths.ReportsDir,
 		paths.HandoffsDir,
 		paths.LogsDir,
@@ -0,0 +1,150 @@
+package ralph
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PluginCompat is the optional header at the top of a plugin.env:
+//
+//	# min_version: v0.9.0
+//	# conflicts_with: node-default, go-default
+//
+// Only the leading comment block is read, so later comments cannot declare it.
+type PluginCompat struct {
+	MinVersion    string
+	ConflictsWith []string
+}
+
+func ReadPluginCompat(controlDir, pluginName string) (PluginCompat, error) {
+	var compat PluginCompat
+	f, err := os.Open(pluginFilePath(controlDir, pluginName))
+	if err != nil {
+		return compat, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		body, ok := strings.CutPrefix(line, "#")
+		if !ok {
+			break
+		}
+		key, value, ok := strings.Cut(body, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "min_version":
+			compat.MinVersion = value
+		case "conflicts_with":
+			for _, name := range strings.Split(value, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					compat.ConflictsWith = append(compat.ConflictsWith, name)
+				}
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return compat, fmt.Errorf("scan plugin header: %w", err)
+	}
+	return compat, nil
+}
+
+func (c PluginCompat) conflictsWith(pluginName string) bool {
+	for _, name := range c.ConflictsWith {
+		if name == pluginName {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPluginMinVersion fails when binaryVersion is older than the plugin's
+// min_version. Dev builds are assumed current and always pass.
+func checkPluginMinVersion(pluginName string, compat PluginCompat, binaryVersion string) error {
+	if compat.MinVersion == "" || binaryVersion == "dev" {
+		return nil
+	}
+	cmp, ok := compareVersions(binaryVersion, compat.MinVersion)
+	if !ok {
+		return fmt.Errorf("plugin %s min_version %q cannot be compared with ralphctl %s", pluginName, compat.MinVersion, binaryVersion)
+	}
+	if cmp < 0 {
+		return fmt.Errorf("plugin %s requires ralphctl >= %s (running %s)", pluginName, compat.MinVersion, binaryVersion)
+	}
+	return nil
+}
+
+// appliedPluginName is the plugin recorded in the project's profile, or "" when
+// no profile has been written yet.
+func appliedPluginName(paths Paths) string {
+	_, yamlErr := os.Stat(paths.ProfileYAMLFile)
+	_, envErr := os.Stat(paths.ProfileFile)
+	if yamlErr != nil && envErr != nil {
+		return ""
+	}
+	profile, err := LoadProfile(paths)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(profile.PluginName)
+}
+
+// CheckPluginCompat reports why pluginName cannot replace the plugin already
+// applied to the project. A conflict declared by either side counts.
+func CheckPluginCompat(paths Paths, pluginName string) error {
+	compat, err := ReadPluginCompat(paths.ControlDir, pluginName)
+	if err != nil {
+		return fmt.Errorf("read plugin header: %w", err)
+	}
+	if err := checkPluginMinVersion(pluginName, compat, BinaryVersion()); err != nil {
+		return err
+	}
+	applied := appliedPluginName(paths)
+	if applied == "" || applied == pluginName {
+		return nil
+	}
+	if compat.conflictsWith(applied) {
+		return fmt.Errorf("plugin %s conflicts with applied plugin %s", pluginName, applied)
+	}
+	if appliedCompat, err := ReadPluginCompat(paths.ControlDir, applied); err == nil && appliedCompat.conflictsWith(pluginName) {
+		return fmt.Errorf("applied plugin %s conflicts with plugin %s", applied, pluginName)
+	}
+	return nil
+}
+
+func appendPluginCompatChecks(report *DoctorReport, paths Paths, pluginName string) {
+	compat, err := ReadPluginCompat(paths.ControlDir, pluginName)
+	if err != nil {
+		return
+	}
+	binaryVersion := BinaryVersion()
+	if err := checkPluginMinVersion(pluginName, compat, binaryVersion); err != nil {
+		report.add("plugin:compat", doctorStatusFail, err.Error())
+	} else if compat.MinVersion != "" {
+		report.add("plugin:compat", doctorStatusPass, fmt.Sprintf("min_version=%s ralphctl=%s", compat.MinVersion, binaryVersion))
+	}
+	// Install records its plugin in config.env; a later apply-plugin from a build
+	// that did not check conflicts can leave the two at odds.
+	cfg, err := ReadEnvFile(filepath.Join(paths.RalphDir, "config.env"))
+	if err != nil {
+		return
+	}
+	installed := strings.TrimSpace(cfg["PLUGIN"])
+	if installed == "" || installed == pluginName {
+		return
+	}
+	installedCompat, _ := ReadPluginCompat(paths.ControlDir, installed)
+	if compat.conflictsWith(installed) || installedCompat.conflictsWith(pluginName) {
+		report.add("plugin:conflicts", doctorStatusFail, fmt.Sprintf("applied plugin %s conflicts with installed plugin %s", pluginName, installed))
+	}
+}
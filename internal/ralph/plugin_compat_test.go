@@ -0,0 +1,98 @@
+package ralph
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyPluginEnforcesCompatHeader(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	prevVersion := Version
+	Version = "v1.4.0"
+	t.Cleanup(func() { Version = prevVersion })
+	skip := ApplyPluginOptions{SkipVerify: true}
+
+	writeTestPlugin(t, paths.ControlDir, "go-default", "# Go project defaults\n# conflicts_with: node-default\nRALPH_PLUGIN_NAME=go-default\n")
+	writeTestPlugin(t, paths.ControlDir, "node-default", "RALPH_PLUGIN_NAME=node-default\n")
+	writeTestPlugin(t, paths.ControlDir, "future", "# min_version: v1.10.0\nRALPH_PLUGIN_NAME=future\n")
+	writeTestPlugin(t, paths.ControlDir, "current", "# min_version: 1.4\n# conflicts_with: go-default\nRALPH_PLUGIN_NAME=current\n")
+
+	err := ApplyPluginWithOptions(paths, "future", skip)
+	if err == nil || !strings.Contains(err.Error(), "requires ralphctl >= v1.10.0 (running v1.4.0)") {
+		t.Fatalf("too-old binary should be refused: %v", err)
+	}
+
+	if err := ApplyPluginWithOptions(paths, "go-default", skip); err != nil {
+		t.Fatalf("apply go-default: %v", err)
+	}
+	if err := ApplyPluginWithOptions(paths, "go-default", skip); err != nil {
+		t.Fatalf("reapplying the same plugin must not conflict: %v", err)
+	}
+	err = ApplyPluginWithOptions(paths, "node-default", skip)
+	if err == nil || !strings.Contains(err.Error(), "applied plugin go-default conflicts with plugin node-default") {
+		t.Fatalf("conflict declared by the applied plugin should be refused: %v", err)
+	}
+	err = ApplyPluginWithOptions(paths, "current", skip)
+	if err == nil || !strings.Contains(err.Error(), "plugin current conflicts with applied plugin go-default") {
+		t.Fatalf("conflict declared by the new plugin should be refused: %v", err)
+	}
+	if profile, err := LoadProfile(paths); err != nil || profile.PluginName != "go-default" {
+		t.Fatalf("refused apply must keep the profile: plugin=%s err=%v", profile.PluginName, err)
+	}
+
+	Version = "dev"
+	if err := CheckPluginCompat(paths, "future"); err != nil {
+		t.Fatalf("dev builds should skip min_version: %v", err)
+	}
+}
+
+func TestDoctorReportsPluginCompat(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	prevVersion := Version
+	Version = "v1.4.0"
+	t.Cleanup(func() { Version = prevVersion })
+
+	writeTestPlugin(t, paths.ControlDir, "future", "# min_version: v2.0.0\nRALPH_PLUGIN_NAME=future\n")
+	if err := WriteYAMLFlatMap(paths.ProfileYAMLFile, map[string]string{"plugin_name": "future"}); err != nil {
+		t.Fatalf("write profile.yaml: %v", err)
+	}
+	writeFile(t, filepath.Join(paths.RalphDir, "config.env"), "PLUGIN=legacy\n")
+	writeTestPlugin(t, paths.ControlDir, "legacy", "# conflicts_with: future\nRALPH_PLUGIN_NAME=legacy\n")
+
+	var report DoctorReport
+	appendPluginCompatChecks(&report, paths, "future")
+	got := map[string]string{}
+	for _, c := range report.Checks {
+		got[c.Name] = c.Status + " " + c.Detail
+	}
+	if !strings.HasPrefix(got["plugin:compat"], doctorStatusFail+" plugin future requires ralphctl >= v2.0.0") {
+		t.Fatalf("plugin:compat should fail: %q", got["plugin:compat"])
+	}
+	if !strings.HasPrefix(got["plugin:conflicts"], doctorStatusFail+" applied plugin future conflicts with installed plugin legacy") {
+		t.Fatalf("plugin:conflicts should fail: %q", got["plugin:conflicts"])
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "1.2.3", 0},
+		{"v1.10.0", "v1.9.9", 1},
+		{"1.2", "1.2.1", -1},
+		{"v2.0.0-rc.1", "v2.0.0", 0},
+	}
+	for _, c := range cases {
+		got, ok := compareVersions(c.a, c.b)
+		if !ok || got != c.want {
+			t.Fatalf("compareVersions(%q, %q) = %d, %v; want %d", c.a, c.b, got, ok, c.want)
+		}
+	}
+	if _, ok := compareVersions("dev", "v1.0.0"); ok {
+		t.Fatalf("non-numeric versions should not compare")
+	}
+}
@@ -6,10 +6,23 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 )
 
+// PluginDirEnv relocates the plugins dir (and its registry.json) away from
+// <control-dir>/plugins; the --plugin-dir global flag sets it.
+const PluginDirEnv = "RALPH_PLUGIN_DIR"
+
+func PluginsDir(controlDir string) string {
+	if dir := strings.TrimSpace(os.Getenv(PluginDirEnv)); dir != "" {
+		return dir
+	}
+	return filepath.Join(controlDir, "plugins")
+}
+
 func ListPlugins(controlDir string) ([]string, error) {
-	pluginRoot := filepath.Join(controlDir, "plugins")
+	pluginRoot := PluginsDir(controlDir)
 	entries, err := os.ReadDir(pluginRoot)
 	if err != nil {
 		return nil, fmt.Errorf("read plugins dir: %w", err)
@@ -31,16 +44,82 @@ func ListPlugins(controlDir string) ([]string, error) {
 }
 
 func pluginFilePath(controlDir, pluginName string) string {
-	return filepath.Join(controlDir, "plugins", pluginName, "plugin.env")
+	return filepath.Join(PluginsDir(controlDir), pluginName, "plugin.env")
 }
 
-func ApplyPlugin(paths Paths, pluginName string) error {
+type PluginApplyPreview struct {
+	PluginName       string
+	ProfileYAMLFile  string
+	Current          string
+	Proposed         string
+	Diff             string
+	RemovesLegacyEnv bool
+}
+
+func (p PluginApplyPreview) Changed() bool {
+	return p.Diff != "" || p.RemovesLegacyEnv
+}
+
+// ApplyPluginOptions controls plugin integrity checks on install/apply.
+// SkipVerify bypasses the registry SHA256 check for locally edited plugins.
+type ApplyPluginOptions struct {
+	SkipVerify bool
+}
+
+// PreviewApplyPlugin computes what ApplyPlugin would write without touching any file.
+func PreviewApplyPlugin(paths Paths, pluginName string, opts ApplyPluginOptions) (PluginApplyPreview, error) {
+	profile, err := pluginProfile(paths, pluginName, opts)
+	if err != nil {
+		return PluginApplyPreview{}, err
+	}
+	preview := PluginApplyPreview{
+		PluginName:      pluginName,
+		ProfileYAMLFile: paths.ProfileYAMLFile,
+		Proposed:        renderYAMLFlatMap(ProfileToYAMLMap(profile)),
+	}
+	if data, err := os.ReadFile(paths.ProfileYAMLFile); err == nil {
+		preview.Current = string(data)
+	} else if !os.IsNotExist(err) {
+		return PluginApplyPreview{}, fmt.Errorf("read profile.yaml: %w", err)
+	}
+	if _, err := os.Stat(paths.ProfileFile); err == nil {
+		preview.RemovesLegacyEnv = true
+	}
+	preview.Diff = UnifiedDiff("a/"+filepath.Base(paths.ProfileYAMLFile), "b/"+filepath.Base(paths.ProfileYAMLFile), preview.Current, preview.Proposed)
+	return preview, nil
+}
+
+func pluginProfile(paths Paths, pluginName string, opts ApplyPluginOptions) (Profile, error) {
 	src := pluginFilePath(paths.ControlDir, pluginName)
 	if _, err := os.Stat(src); err != nil {
-		return fmt.Errorf("plugin not found: %s", pluginName)
+		return Profile{}, fmt.Errorf("plugin not found: %s", pluginName)
+	}
+	if !opts.SkipVerify {
+		if err := VerifyPluginWithRegistry(paths.ControlDir, pluginName); err != nil {
+			return Profile{}, fmt.Errorf("registry verification failed for plugin %s (use --skip-verify to override): %w", pluginName, err)
+		}
 	}
-	if err := VerifyPluginWithRegistry(paths.ControlDir, pluginName); err != nil {
-		return fmt.Errorf("registry verification failed for plugin %s: %w", pluginName, err)
+	if err := CheckPluginCompat(paths, pluginName); err != nil {
+		return Profile{}, err
+	}
+	pluginEnv, err := ReadEnvFile(src)
+	if err != nil {
+		return Profile{}, fmt.Errorf("read plugin env: %w", err)
+	}
+	profile := DefaultProfile()
+	applyProfileMap(&profile, pluginEnv)
+	profile.PluginName = pluginName
+	return profile, nil
+}
+
+func ApplyPlugin(paths Paths, pluginName string) error {
+	return ApplyPluginWithOptions(paths, pluginName, ApplyPluginOptions{})
+}
+
+func ApplyPluginWithOptions(paths Paths, pluginName string, opts ApplyPluginOptions) error {
+	profile, err := pluginProfile(paths, pluginName, opts)
+	if err != nil {
+		return err
 	}
 
 	if err := EnsureLayout(paths); err != nil {
@@ -56,14 +135,6 @@ func ApplyPlugin(paths Paths, pluginName string) error {
 		return fmt.Errorf("stat profile.yaml: %w", err)
 	}
 
-	pluginEnv, err := ReadEnvFile(src)
-	if err != nil {
-		return fmt.Errorf("read plugin env: %w", err)
-	}
-	profile := DefaultProfile()
-	applyProfileMap(&profile, pluginEnv)
-	profile.PluginName = pluginName
-
 	if err := WriteYAMLFlatMap(paths.ProfileYAMLFile, ProfileToYAMLMap(profile)); err != nil {
 		return fmt.Errorf("write profile.yaml: %w", err)
 	}
@@ -86,10 +157,14 @@ func ApplyPlugin(paths Paths, pluginName string) error {
 }
 
 func Install(paths Paths, pluginName, executablePath string) error {
+	return InstallWithOptions(paths, pluginName, executablePath, ApplyPluginOptions{})
+}
+
+func InstallWithOptions(paths Paths, pluginName, executablePath string, opts ApplyPluginOptions) error {
 	if err := EnsureLayout(paths); err != nil {
 		return err
 	}
-	if err := ApplyPlugin(paths, pluginName); err != nil {
+	if err := ApplyPluginWithOptions(paths, pluginName, opts); err != nil {
 		return err
 	}
 	if err := SetEnabled(paths, true); err != nil {
@@ -114,14 +189,98 @@ func WriteProjectWrapper(paths Paths, executablePath string) error {
 	if executablePath == "" {
 		return fmt.Errorf("executable path is required")
 	}
-	wrapper := fmt.Sprintf("#!/usr/bin/env bash\nset -euo pipefail\nexec %q --control-dir %q --project-dir %q \"$@\"\n", executablePath, paths.ControlDir, paths.ProjectDir)
-	wrapperPath := filepath.Join(paths.ProjectDir, "ralph")
-	if err := os.WriteFile(wrapperPath, []byte(wrapper), 0o755); err != nil {
+	if err := os.WriteFile(ProjectWrapperPath(paths), []byte(projectWrapperContent(paths, executablePath)), 0o755); err != nil {
 		return fmt.Errorf("write wrapper script: %w", err)
 	}
 	return nil
 }
 
+// CurrentExecutablePath is the absolute path of the running ralphctl, as
+// written into ./ralph wrappers. Symlinks are kept (a Homebrew bin/ralphctl
+// link survives upgrades; its versioned Cellar target does not); compare paths
+// with sameExecutable instead.
+func CurrentExecutablePath() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(exe)
+}
+
+// sameExecutable reports whether a and b name the same binary once symlinks
+// are resolved.
+func sameExecutable(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	ra, errA := filepath.EvalSymlinks(a)
+	rb, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && ra == rb
+}
+
+// temporaryExecutableReason explains why exe must not be baked into a wrapper:
+// `go run` and `go test` binaries live under the temp dir or the go-build cache
+// and disappear once the command exits. It returns "" for an installed binary.
+func temporaryExecutableReason(exe string) string {
+	resolved := exe
+	if r, err := filepath.EvalSymlinks(exe); err == nil {
+		resolved = r
+	}
+	for _, part := range strings.Split(filepath.ToSlash(resolved), "/") {
+		if part == "go-build" {
+			return "in the go-build cache"
+		}
+	}
+	tmp := os.TempDir()
+	if r, err := filepath.EvalSymlinks(tmp); err == nil {
+		tmp = r
+	}
+	if rel, err := filepath.Rel(tmp, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "under the temp dir " + tmp
+	}
+	return ""
+}
+
+func ProjectWrapperPath(paths Paths) string {
+	return filepath.Join(paths.ProjectDir, "ralph")
+}
+
+func projectWrapperContent(paths Paths, executablePath string) string {
+	return fmt.Sprintf("#!/usr/bin/env bash\nset -euo pipefail\nexec %q --control-dir %q --project-dir %q \"$@\"\n", executablePath, paths.ControlDir, paths.ProjectDir)
+}
+
+// ProjectWrapperExecutable returns the ralphctl path baked into the project's ./ralph wrapper.
+func ProjectWrapperExecutable(paths Paths) (string, error) {
+	data, err := os.ReadFile(ProjectWrapperPath(paths))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "exec ")
+		if !ok {
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(strings.TrimSpace(rest))
+		if err != nil {
+			return "", fmt.Errorf("parse wrapper exec line: %w", err)
+		}
+		return strconv.Unquote(quoted)
+	}
+	return "", fmt.Errorf("wrapper has no exec line: %s", ProjectWrapperPath(paths))
+}
+
+// ProjectWrapperNeedsUpdate reports whether WriteProjectWrapper would change the wrapper on disk.
+func ProjectWrapperNeedsUpdate(paths Paths, executablePath string) (bool, error) {
+	data, err := os.ReadFile(ProjectWrapperPath(paths))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return string(data) != projectWrapperContent(paths, executablePath), nil
+}
+
 func copyFile(src, dst string) error {
 	in, err := os.Open(src)
 	if err != nil {
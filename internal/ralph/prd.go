@@ -3,22 +3,57 @@ package ralph
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
 type PRDImportResult struct {
 	SourcePath      string
+	SourcePaths     []string
 	StoriesTotal    int
 	Imported        int
 	SkippedPassed   int
 	SkippedExisting int
 	SkippedInvalid  int
+	Updated         int
 	DryRun          bool
+	Merge           bool
 	CreatedPaths    []string
+	Updates         []PRDStoryUpdate
+}
+
+// PRDStoryUpdate records which fields --merge rewrote on an existing issue.
+type PRDStoryUpdate struct {
+	StoryID string
+	Path    string
+	Changed []string
+}
+
+type PRDImportOptions struct {
+	Format      string
+	DefaultRole string
+	DryRun      bool
+	// Merge updates title/description/priority of existing non-done issues with the
+	// same story id instead of skipping them.
+	Merge bool
+}
+
+type PRDExportOptions struct {
+	IncludeDone bool
+	Statuses    []string
+}
+
+type PRDExportResult struct {
+	OutputPath        string
+	Exported          int
+	SkippedFiltered   int
+	SkippedDuplicates int
 }
 
 type prdDocument struct {
@@ -32,12 +67,13 @@ type prdMetadata struct {
 }
 
 type prdContextSummary struct {
-	Problem     string `json:"problem"`
-	Goal        string `json:"goal"`
-	InScope     string `json:"in_scope"`
-	OutOfScope  string `json:"out_of_scope"`
-	Acceptance  string `json:"acceptance"`
-	Constraints string `json:"constraints"`
+	Problem     string   `json:"problem"`
+	Goal        string   `json:"goal"`
+	InScope     string   `json:"in_scope"`
+	OutOfScope  string   `json:"out_of_scope"`
+	Acceptance  string   `json:"acceptance"`
+	Constraints string   `json:"constraints"`
+	Attachments []string `json:"attachments,omitempty"`
 }
 
 type prdStory struct {
@@ -46,55 +82,122 @@ type prdStory struct {
 	Description        string          `json:"description"`
 	Role               string          `json:"role"`
 	Priority           int             `json:"priority"`
+	Assignee           string          `json:"assignee,omitempty"`
 	Passes             bool            `json:"passes"`
 	Passed             bool            `json:"passed"`
 	AcceptanceCriteria json.RawMessage `json:"acceptanceCriteria"`
 }
 
 func ImportPRDStories(paths Paths, prdPath, defaultRole string, dryRun bool) (PRDImportResult, error) {
-	result := PRDImportResult{DryRun: dryRun}
+	return ImportPRDStoriesWithFormat(paths, prdPath, "", defaultRole, dryRun)
+}
+
+func ImportPRDStoriesWithFormat(paths Paths, prdPath, format, defaultRole string, dryRun bool) (PRDImportResult, error) {
+	return ImportPRDFiles(paths, []string{prdPath}, format, defaultRole, dryRun)
+}
+
+func ImportPRDFiles(paths Paths, prdPaths []string, format, defaultRole string, dryRun bool) (PRDImportResult, error) {
+	return ImportPRDFilesWithOptions(paths, prdPaths, PRDImportOptions{Format: format, DefaultRole: defaultRole, DryRun: dryRun})
+}
+
+func ImportPRDFilesWithOptions(paths Paths, prdPaths []string, opts PRDImportOptions) (PRDImportResult, error) {
+	format := opts.Format
+	defaultRole := opts.DefaultRole
+	result := PRDImportResult{DryRun: opts.DryRun, Merge: opts.Merge}
 	if err := EnsureLayout(paths); err != nil {
 		return result, err
 	}
 
-	sourcePath := strings.TrimSpace(prdPath)
-	if sourcePath == "" {
-		sourcePath = "prd.json"
+	sourcePaths, err := resolvePRDSourcePaths(paths, prdPaths)
+	if err != nil {
+		return result, err
 	}
-	if !filepath.IsAbs(sourcePath) {
-		sourcePath = filepath.Join(paths.ProjectDir, sourcePath)
+
+	roleFallback := strings.TrimSpace(defaultRole)
+	if !IsSupportedRole(roleFallback) {
+		roleFallback = "developer"
 	}
-	absSourcePath, err := filepath.Abs(sourcePath)
+
+	existingStoryIDs, err := indexStoryIDs(paths)
 	if err != nil {
-		return result, fmt.Errorf("resolve prd file path: %w", err)
+		return result, err
 	}
-	result.SourcePath = absSourcePath
 
-	data, err := os.ReadFile(absSourcePath)
-	if err != nil {
-		return result, fmt.Errorf("read prd file: %w", err)
+	for _, absSourcePath := range sourcePaths {
+		if result.SourcePath == "" {
+			result.SourcePath = absSourcePath
+		}
+		result.SourcePaths = append(result.SourcePaths, absSourcePath)
+		if err := importPRDFile(paths, absSourcePath, format, roleFallback, existingStoryIDs, &result); err != nil {
+			return result, err
+		}
 	}
+	return result, nil
+}
 
-	doc := prdDocument{}
-	if err := json.Unmarshal(data, &doc); err != nil {
-		return result, fmt.Errorf("parse prd json: %w", err)
+func resolvePRDSourcePaths(paths Paths, prdPaths []string) ([]string, error) {
+	if len(prdPaths) == 0 {
+		prdPaths = []string{""}
 	}
-	if len(doc.UserStories) == 0 {
-		return result, fmt.Errorf("prd json has no userStories")
+	out := []string{}
+	seen := map[string]struct{}{}
+	for _, raw := range prdPaths {
+		sourcePath := strings.TrimSpace(raw)
+		if sourcePath == "" {
+			sourcePath = "prd.json"
+		}
+		if !filepath.IsAbs(sourcePath) {
+			sourcePath = filepath.Join(paths.ProjectDir, sourcePath)
+		}
+		candidates := []string{sourcePath}
+		if strings.ContainsAny(sourcePath, "*?[") {
+			matches, err := filepath.Glob(sourcePath)
+			if err != nil {
+				return nil, fmt.Errorf("expand prd file pattern: %w", err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no prd files match: %s", strings.TrimSpace(raw))
+			}
+			sort.Strings(matches)
+			candidates = matches
+		}
+		for _, candidate := range candidates {
+			absSourcePath, err := filepath.Abs(candidate)
+			if err != nil {
+				return nil, fmt.Errorf("resolve prd file path: %w", err)
+			}
+			if _, exists := seen[absSourcePath]; exists {
+				continue
+			}
+			seen[absSourcePath] = struct{}{}
+			out = append(out, absSourcePath)
+		}
 	}
+	return out, nil
+}
 
-	roleFallback := strings.TrimSpace(defaultRole)
-	if !IsSupportedRole(roleFallback) {
-		roleFallback = "developer"
+func importPRDFile(paths Paths, absSourcePath, format, roleFallback string, existingStoryIDs map[string]string, result *PRDImportResult) error {
+	dryRun := result.DryRun
+	data, err := os.ReadFile(absSourcePath)
+	if err != nil {
+		return fmt.Errorf("read prd file: %w", err)
 	}
 
-	existingStoryIDs, err := indexStoryIDs(paths)
+	resolvedFormat, err := resolvePRDFormat(format, absSourcePath)
 	if err != nil {
-		return result, err
+		return err
+	}
+	doc, err := decodePRDDocument(data, resolvedFormat)
+	if err != nil {
+		return err
+	}
+	if len(doc.UserStories) == 0 {
+		return fmt.Errorf("prd %s has no userStories", resolvedFormat)
 	}
 
 	sourceFileName := filepath.Base(absSourcePath)
 	globalContext := buildPRDGlobalContext(doc.Metadata)
+	attachedContext := renderPRDAttachments(paths, doc.Metadata.Context.Attachments)
 	for _, story := range doc.UserStories {
 		result.StoriesTotal++
 
@@ -109,18 +212,9 @@ func ImportPRDStories(paths Paths, prdPath, defaultRole string, dryRun bool) (PR
 			result.SkippedInvalid++
 			continue
 		}
-		if _, exists := existingStoryIDs[id]; exists {
-			result.SkippedExisting++
-			continue
-		}
-
-		role := strings.TrimSpace(story.Role)
-		if !IsSupportedRole(role) {
-			role = roleFallback
-		}
 
 		priority := story.Priority
-		if priority <= 0 {
+		if priority == 0 {
 			priority = defaultIssuePriority
 		}
 
@@ -129,9 +223,33 @@ func ImportPRDStories(paths Paths, prdPath, defaultRole string, dryRun bool) (PR
 			objective = title
 		}
 
+		if existingPath, exists := existingStoryIDs[id]; exists {
+			if !result.Merge || !mergeablePRDIssuePath(paths, existingPath, result.CreatedPaths) {
+				result.SkippedExisting++
+				continue
+			}
+			changed, err := mergePRDStoryIntoIssue(existingPath, id, title, singleLine(objective), priority, sourceFileName, dryRun)
+			if err != nil {
+				return err
+			}
+			if len(changed) == 0 {
+				result.SkippedExisting++
+				continue
+			}
+			result.Updated++
+			result.Updates = append(result.Updates, PRDStoryUpdate{StoryID: id, Path: existingPath, Changed: changed})
+			continue
+		}
+
+		role := strings.TrimSpace(story.Role)
+		if !IsSupportedRole(role) {
+			role = roleFallback
+		}
+
 		options := IssueCreateOptions{
 			Priority:           priority,
 			StoryID:            id,
+			Assignee:           strings.TrimSpace(story.Assignee),
 			Objective:          objective,
 			AcceptanceCriteria: parseAcceptanceCriteria(story.AcceptanceCriteria),
 			ExtraMeta: map[string]string{
@@ -147,19 +265,335 @@ func ImportPRDStories(paths Paths, prdPath, defaultRole string, dryRun bool) (PR
 
 		issuePath, _, err := CreateIssueWithOptions(paths, role, title, options)
 		if err != nil {
-			return result, err
+			return err
 		}
-		if err := appendPRDContext(issuePath, id, priority, sourceFileName, story.Description, globalContext); err != nil {
-			return result, err
+		if err := appendPRDContext(issuePath, id, priority, sourceFileName, story.Description, globalContext, attachedContext); err != nil {
+			return err
 		}
 
 		existingStoryIDs[id] = issuePath
 		result.CreatedPaths = append(result.CreatedPaths, issuePath)
 	}
 
+	return nil
+}
+
+// mergeablePRDIssuePath excludes done issues, dry-run placeholders and issues
+// created earlier in the same import (duplicate story ids within the PRD).
+func mergeablePRDIssuePath(paths Paths, issuePath string, createdPaths []string) bool {
+	if issuePath == "(dry-run)" {
+		return false
+	}
+	if filepath.Clean(filepath.Dir(issuePath)) == filepath.Clean(paths.DoneDir) {
+		return false
+	}
+	for _, created := range createdPaths {
+		if created == issuePath {
+			return false
+		}
+	}
+	return true
+}
+
+// mergePRDStoryIntoIssue rewrites title/priority meta and the objective line in place
+// and appends a PRD Update section listing the changed fields.
+func mergePRDStoryIntoIssue(issuePath, storyID, title, objective string, priority int, sourceFileName string, dryRun bool) ([]string, error) {
+	meta, err := ReadIssueMeta(issuePath)
+	if err != nil {
+		return nil, err
+	}
+	currentObjective, err := readIssueObjective(issuePath)
+	if err != nil {
+		return nil, err
+	}
+	changed := []string{}
+	if strings.TrimSpace(meta.Title) != title {
+		changed = append(changed, "title")
+	}
+	if currentObjective != objective {
+		changed = append(changed, "description")
+	}
+	if meta.Priority != priority {
+		changed = append(changed, "priority")
+	}
+	if len(changed) == 0 || dryRun {
+		return changed, nil
+	}
+	for _, field := range changed {
+		switch field {
+		case "title":
+			err = setIssueMetaField(issuePath, "title", title)
+		case "description":
+			err = setIssueObjective(issuePath, objective)
+		case "priority":
+			err = setIssueMetaField(issuePath, "priority", strconv.Itoa(priority))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("merge prd story %s: %w", storyID, err)
+		}
+	}
+	f, err := os.OpenFile(issuePath, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(
+		f,
+		"\n## PRD Update\n- story_id: %s\n- source: %s\n- changed: %s\n- updated_at_utc: %s\n",
+		storyID,
+		sourceFileName,
+		strings.Join(changed, ","),
+		time.Now().UTC().Format(time.RFC3339),
+	)
+	return changed, err
+}
+
+// issueObjectiveBlock returns the [start,end) line range of the objective text:
+// the lines after "## Objective" up to the next blank line or section header.
+func issueObjectiveBlock(lines []string) (int, int, bool) {
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "## Objective" {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) {
+			trimmed := strings.TrimSpace(lines[end])
+			if trimmed == "" || strings.HasPrefix(trimmed, "## ") {
+				break
+			}
+			end++
+		}
+		return i + 1, end, true
+	}
+	return 0, 0, false
+}
+
+func readIssueObjective(issuePath string) (string, error) {
+	data, err := os.ReadFile(issuePath)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(string(data), "\n")
+	start, end, ok := issueObjectiveBlock(lines)
+	if !ok {
+		return "", nil
+	}
+	return singleLine(strings.TrimPrefix(strings.TrimSpace(strings.Join(lines[start:end], " ")), "- ")), nil
+}
+
+func setIssueObjective(issuePath, objective string) error {
+	data, err := os.ReadFile(issuePath)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	start, end, ok := issueObjectiveBlock(lines)
+	if !ok {
+		return fmt.Errorf("issue has no objective section: %s", issuePath)
+	}
+	out := append([]string{}, lines[:start]...)
+	out = append(out, "- "+objective)
+	out = append(out, lines[end:]...)
+	return os.WriteFile(issuePath, []byte(strings.Join(out, "\n")), 0o644)
+}
+
+func resolvePRDFormat(format, sourcePath string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "auto":
+		switch strings.ToLower(filepath.Ext(sourcePath)) {
+		case ".yaml", ".yml":
+			return "yaml", nil
+		default:
+			return "json", nil
+		}
+	case "json":
+		return "json", nil
+	case "yaml", "yml":
+		return "yaml", nil
+	default:
+		return "", fmt.Errorf("unsupported prd format: %s (expected json|yaml)", format)
+	}
+}
+
+func decodePRDDocument(data []byte, format string) (prdDocument, error) {
+	doc := prdDocument{}
+	if format == "yaml" {
+		parsed, err := parseYAMLDocument(data)
+		if err != nil {
+			return doc, fmt.Errorf("parse prd yaml: %w", err)
+		}
+		// Round-trip through JSON so both formats share one decoding path.
+		data, err = json.Marshal(coerceYAMLScalars(parsed, reflect.TypeOf(doc)))
+		if err != nil {
+			return doc, fmt.Errorf("parse prd yaml: %w", err)
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return doc, fmt.Errorf("parse prd yaml: %w", err)
+		}
+		return doc, nil
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return doc, fmt.Errorf("parse prd json: %w", err)
+	}
+	return doc, nil
+}
+
+func ExportPRDStories(paths Paths, outPath string, opts PRDExportOptions) (PRDExportResult, error) {
+	result := PRDExportResult{}
+
+	targetPath := strings.TrimSpace(outPath)
+	if targetPath == "" {
+		targetPath = "prd-export.json"
+	}
+	if !filepath.IsAbs(targetPath) {
+		targetPath = filepath.Join(paths.ProjectDir, targetPath)
+	}
+	absTargetPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return result, fmt.Errorf("resolve prd export path: %w", err)
+	}
+	result.OutputPath = absTargetPath
+
+	statusFilter := map[string]struct{}{}
+	for _, raw := range opts.Statuses {
+		status := normalizeExportStatus(raw)
+		if status == "" {
+			continue
+		}
+		switch status {
+		case "ready", "in-progress", "blocked", "done":
+		default:
+			return result, fmt.Errorf("invalid status filter: %s", strings.TrimSpace(raw))
+		}
+		statusFilter[status] = struct{}{}
+	}
+
+	doc := prdDocument{UserStories: []prdStory{}}
+	seen := map[string]struct{}{}
+	for _, scan := range issueStatusDirs(paths) {
+		files, err := filepath.Glob(filepath.Join(scan.dir, "I-*.md"))
+		if err != nil {
+			return result, err
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			meta, err := ReadIssueMeta(file)
+			if err != nil {
+				continue
+			}
+			_, statusAllowed := statusFilter[scan.status]
+			if scan.status == "done" && !opts.IncludeDone && !statusAllowed {
+				result.SkippedFiltered++
+				continue
+			}
+			if len(statusFilter) > 0 && !statusAllowed {
+				result.SkippedFiltered++
+				continue
+			}
+
+			storyID := strings.TrimSpace(meta.StoryID)
+			if storyID == "" {
+				storyID = meta.ID
+			}
+			if _, exists := seen[storyID]; exists {
+				result.SkippedDuplicates++
+				continue
+			}
+			seen[storyID] = struct{}{}
+
+			title := strings.TrimSpace(meta.Title)
+			if title == "" {
+				title = meta.ID
+			}
+			objective, criteria, err := readIssueBodySections(file)
+			if err != nil {
+				return result, err
+			}
+			story := prdStory{
+				ID:          storyID,
+				Title:       title,
+				Description: objective,
+				Role:        meta.Role,
+				Priority:    meta.Priority,
+				Assignee:    meta.Assignee,
+				Passes:      scan.status == "done",
+			}
+			if len(criteria) > 0 {
+				raw, err := json.Marshal(criteria)
+				if err != nil {
+					return result, err
+				}
+				story.AcceptanceCriteria = raw
+			}
+			doc.UserStories = append(doc.UserStories, story)
+			result.Exported++
+		}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return result, fmt.Errorf("encode prd json: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(absTargetPath), 0o755); err != nil {
+		return result, err
+	}
+	if err := os.WriteFile(absTargetPath, append(data, '\n'), 0o644); err != nil {
+		return result, fmt.Errorf("write prd file: %w", err)
+	}
 	return result, nil
 }
 
+func normalizeExportStatus(raw string) string {
+	status := strings.ToLower(strings.TrimSpace(raw))
+	if status == "in_progress" {
+		return "in-progress"
+	}
+	return status
+}
+
+func readIssueBodySections(path string) (string, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	objectiveLines := []string{}
+	criteria := []string{}
+	section := ""
+	inHeader := true
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inHeader {
+			if trimmed == "" {
+				inHeader = false
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "## ") {
+			section = strings.TrimSpace(strings.TrimPrefix(trimmed, "## "))
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+		switch section {
+		case "Objective":
+			objectiveLines = append(objectiveLines, strings.TrimSpace(strings.TrimPrefix(trimmed, "- ")))
+		case "Acceptance Criteria":
+			item := trimmed
+			for _, prefix := range []string{"- [ ]", "- [x]", "- [X]", "- "} {
+				if strings.HasPrefix(item, prefix) {
+					item = strings.TrimSpace(strings.TrimPrefix(item, prefix))
+					break
+				}
+			}
+			if item != "" {
+				criteria = append(criteria, item)
+			}
+		}
+	}
+	return strings.Join(objectiveLines, "\n"), criteria, nil
+}
+
 func parseAcceptanceCriteria(raw json.RawMessage) []string {
 	if len(raw) == 0 || string(raw) == "null" {
 		return nil
@@ -230,6 +664,7 @@ func indexStoryIDs(paths Paths) (map[string]string, error) {
 		paths.InProgressDir,
 		paths.DoneDir,
 		paths.BlockedDir,
+		paths.DeadLetterDir,
 	}
 	for _, dir := range scanDirs {
 		files, err := filepath.Glob(filepath.Join(dir, "I-*.md"))
@@ -254,7 +689,7 @@ func indexStoryIDs(paths Paths) (map[string]string, error) {
 	return out, nil
 }
 
-func appendPRDContext(issuePath, storyID string, priority int, sourceFileName, description, globalContext string) error {
+func appendPRDContext(issuePath, storyID string, priority int, sourceFileName, description, globalContext, attachedContext string) error {
 	f, err := os.OpenFile(issuePath, os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
 		return err
@@ -281,6 +716,12 @@ func appendPRDContext(issuePath, storyID string, priority int, sourceFileName, d
 	}
 	if strings.TrimSpace(globalContext) != "" {
 		_, err = fmt.Fprintf(f, "- global_context: %s\n", globalContext)
+		if err != nil {
+			return err
+		}
+	}
+	if attachedContext != "" {
+		_, err = io.WriteString(f, attachedContext)
 	}
 	return err
 }
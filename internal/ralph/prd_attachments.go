@@ -0,0 +1,88 @@
+package ralph
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PRDAttachmentMaxBytes caps how much of each attached file lands in an issue body.
+const PRDAttachmentMaxBytes = 8 * 1024
+
+// ResolvePRDAttachment resolves raw (relative to the project dir) to a regular file
+// inside the project and returns its slash-separated project-relative path. Symlinks
+// are followed before the check so an attachment cannot point outside the project.
+func ResolvePRDAttachment(paths Paths, raw string) (string, string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", fmt.Errorf("attachment path is required")
+	}
+	root, err := filepath.EvalSymlinks(paths.ProjectDir)
+	if err != nil {
+		return "", "", fmt.Errorf("resolve project dir: %w", err)
+	}
+	target := raw
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(paths.ProjectDir, target)
+	}
+	resolved, err := filepath.EvalSymlinks(target)
+	if err != nil {
+		return "", "", fmt.Errorf("resolve attachment: %w", err)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", "", fmt.Errorf("attachment must be inside the project dir: %s", raw)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", "", fmt.Errorf("stat attachment: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", "", fmt.Errorf("attachment is not a regular file: %s", raw)
+	}
+	return filepath.ToSlash(rel), resolved, nil
+}
+
+func readPRDAttachment(paths Paths, raw string) (string, bool, error) {
+	_, abs, err := ResolvePRDAttachment(paths, raw)
+	if err != nil {
+		return "", false, err
+	}
+	f, err := os.Open(abs)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, PRDAttachmentMaxBytes+1))
+	if err != nil {
+		return "", false, err
+	}
+	if len(data) > PRDAttachmentMaxBytes {
+		return string(data[:PRDAttachmentMaxBytes]), true, nil
+	}
+	return string(data), false, nil
+}
+
+// renderPRDAttachments builds the "## Attached Context" issue section. Files that
+// moved or now resolve outside the project are listed as unavailable, not read.
+func renderPRDAttachments(paths Paths, attachments []string) string {
+	if len(attachments) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n## Attached Context\n")
+	for _, raw := range attachments {
+		content, truncated, err := readPRDAttachment(paths, raw)
+		if err != nil {
+			fmt.Fprintf(&b, "- %s: unavailable (%s)\n", raw, singleLine(err.Error()))
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s\n````text\n%s\n````\n", raw, strings.TrimRight(content, "\n"))
+		if truncated {
+			fmt.Fprintf(&b, "- truncated_at_bytes: %d\n", PRDAttachmentMaxBytes)
+		}
+	}
+	return b.String()
+}
@@ -76,3 +76,338 @@ func TestImportPRDStoriesAppendsGlobalContext(t *testing.T) {
 		t.Fatalf("issue global_context should include product: %s", body)
 	}
 }
+
+func TestImportPRDStoriesIncludesAttachedContext(t *testing.T) {
+	paths := newTestPaths(t)
+
+	if err := os.MkdirAll(filepath.Join(paths.ProjectDir, "docs"), 0o755); err != nil {
+		t.Fatalf("mkdir docs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(paths.ProjectDir, "docs", "design.md"), []byte("retry with backoff\n"), 0o644); err != nil {
+		t.Fatalf("write design doc: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(paths.ProjectDir, "docs", "big.txt"), []byte(strings.Repeat("x", PRDAttachmentMaxBytes+100)), 0o644); err != nil {
+		t.Fatalf("write big doc: %v", err)
+	}
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(outside, []byte("do not leak"), 0o644); err != nil {
+		t.Fatalf("write outside file: %v", err)
+	}
+	if _, _, err := ResolvePRDAttachment(paths, outside); err == nil {
+		t.Fatalf("attachment outside the project dir must be rejected")
+	}
+	if _, _, err := ResolvePRDAttachment(paths, "../secret.txt"); err == nil {
+		t.Fatalf("relative escape must be rejected")
+	}
+	if rel, _, err := ResolvePRDAttachment(paths, "docs/design.md"); err != nil || rel != "docs/design.md" {
+		t.Fatalf("resolve attachment: rel=%q err=%v", rel, err)
+	}
+
+	prdPath := filepath.Join(paths.ProjectDir, "prd.json")
+	writeJSON(t, prdPath, map[string]any{
+		"metadata": map[string]any{
+			"context": map[string]any{
+				"attachments": []string{"docs/design.md", "docs/big.txt", outside},
+			},
+		},
+		"userStories": []map[string]any{
+			{"id": "US-001", "title": "결제 실패 복구", "role": "developer"},
+		},
+	})
+	result, err := ImportPRDStories(paths, prdPath, "developer", false)
+	if err != nil {
+		t.Fatalf("ImportPRDStories failed: %v", err)
+	}
+	content, err := os.ReadFile(result.CreatedPaths[0])
+	if err != nil {
+		t.Fatalf("read imported issue failed: %v", err)
+	}
+	body := string(content)
+	if !strings.Contains(body, "## Attached Context") || !strings.Contains(body, "### docs/design.md") || !strings.Contains(body, "retry with backoff") {
+		t.Fatalf("issue should include attached file content: %s", body)
+	}
+	if strings.Count(body, "x") > PRDAttachmentMaxBytes+10 || !strings.Contains(body, "truncated_at_bytes") {
+		t.Fatalf("large attachment should be capped: %d bytes", len(body))
+	}
+	if strings.Contains(body, "do not leak") || !strings.Contains(body, "unavailable") {
+		t.Fatalf("outside attachment must not be read: %s", body)
+	}
+}
+
+func TestExportPRDStoriesRoundTrip(t *testing.T) {
+	paths := newTestPaths(t)
+
+	if _, _, err := CreateIssueWithOptions(paths, "developer", "결제 재시도", IssueCreateOptions{
+		Priority:           10,
+		StoryID:            "US-001",
+		Objective:          "실패 시 재시도한다",
+		AcceptanceCriteria: []string{"재시도 3회"},
+	}); err != nil {
+		t.Fatalf("create ready issue failed: %v", err)
+	}
+	if _, _, err := CreateIssue(paths, "qa", "회귀 테스트"); err != nil {
+		t.Fatalf("create qa issue failed: %v", err)
+	}
+	donePath, _, err := CreateIssueWithOptions(paths, "planner", "완료된 계획", IssueCreateOptions{StoryID: "US-003"})
+	if err != nil {
+		t.Fatalf("create done issue failed: %v", err)
+	}
+	if err := os.Rename(donePath, filepath.Join(paths.DoneDir, filepath.Base(donePath))); err != nil {
+		t.Fatalf("move done issue failed: %v", err)
+	}
+
+	result, err := ExportPRDStories(paths, "export.json", PRDExportOptions{})
+	if err != nil {
+		t.Fatalf("ExportPRDStories failed: %v", err)
+	}
+	if result.Exported != 2 || result.SkippedFiltered != 1 {
+		t.Fatalf("unexpected export result: %+v", result)
+	}
+
+	target := newTestPaths(t)
+	imported, err := ImportPRDStories(target, result.OutputPath, "developer", false)
+	if err != nil {
+		t.Fatalf("re-import failed: %v", err)
+	}
+	if imported.SkippedInvalid != 0 || imported.Imported != 2 {
+		t.Fatalf("unexpected re-import result: %+v", imported)
+	}
+	for _, path := range imported.CreatedPaths {
+		meta, err := ReadIssueMeta(path)
+		if err != nil {
+			t.Fatalf("read meta failed: %v", err)
+		}
+		if meta.StoryID != "US-001" {
+			continue
+		}
+		if meta.Priority != 10 || meta.Role != "developer" {
+			t.Fatalf("unexpected round-tripped meta: %+v", meta)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read issue failed: %v", err)
+		}
+		if !strings.Contains(string(content), "- [ ] 재시도 3회") {
+			t.Fatalf("acceptance criteria should round-trip: %s", string(content))
+		}
+	}
+
+	withDone, err := ExportPRDStories(paths, "export-done.json", PRDExportOptions{Statuses: []string{"done"}})
+	if err != nil {
+		t.Fatalf("ExportPRDStories with status filter failed: %v", err)
+	}
+	if withDone.Exported != 1 {
+		t.Fatalf("status filter should export only done issue: %+v", withDone)
+	}
+}
+
+func TestImportPRDStoriesYAML(t *testing.T) {
+	paths := newTestPaths(t)
+
+	prdPath := filepath.Join(paths.ProjectDir, "prd.yaml")
+	writeFile(t, prdPath, `metadata:
+  product: Wallet
+  context:
+    goal: "실패율을 낮춘다"
+userStories:
+  - id: US-001
+    title: 결제 실패 복구
+    description: |
+      실패 시 재시도로
+      이탈을 줄인다
+    role: developer
+    priority: 10
+    acceptanceCriteria:
+      - 재시도 3회
+      - text: 알림 발송 # inline comment
+  - id: US-002
+    title: 완료된 스토리
+    passes: true
+  - id: ""
+    title: 잘못된 스토리
+`)
+
+	result, err := ImportPRDStories(paths, prdPath, "developer", true)
+	if err != nil {
+		t.Fatalf("ImportPRDStories yaml failed: %v", err)
+	}
+	if result.StoriesTotal != 3 || result.Imported != 1 || result.SkippedPassed != 1 || result.SkippedInvalid != 1 {
+		t.Fatalf("unexpected yaml dry-run result: %+v", result)
+	}
+
+	result, err = ImportPRDStories(paths, prdPath, "developer", false)
+	if err != nil {
+		t.Fatalf("ImportPRDStories yaml failed: %v", err)
+	}
+	if len(result.CreatedPaths) != 1 {
+		t.Fatalf("unexpected yaml import result: %+v", result)
+	}
+	content, err := os.ReadFile(result.CreatedPaths[0])
+	if err != nil {
+		t.Fatalf("read imported issue failed: %v", err)
+	}
+	body := string(content)
+	for _, want := range []string{"priority: 10", "- [ ] 재시도 3회", "- [ ] 알림 발송", "product=Wallet"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("yaml imported issue missing %q: %s", want, body)
+		}
+	}
+
+	badPath := filepath.Join(paths.ProjectDir, "bad.yml")
+	writeFile(t, badPath, "userStories:\n  - id: US-9\n     title: broken\n")
+	if _, err := ImportPRDStories(paths, badPath, "developer", true); err == nil || !strings.Contains(err.Error(), "parse prd yaml:") {
+		t.Fatalf("expected parse prd yaml error, got=%v", err)
+	}
+
+	if _, err := ImportPRDStoriesWithFormat(paths, prdPath, "json", "developer", true); err == nil || !strings.Contains(err.Error(), "parse prd json:") {
+		t.Fatalf("explicit json format should reject yaml content, got=%v", err)
+	}
+}
+
+func TestImportPRDFilesAggregatesAcrossFiles(t *testing.T) {
+	paths := newTestPaths(t)
+
+	prdDir := filepath.Join(paths.ProjectDir, "prds")
+	if err := os.MkdirAll(prdDir, 0o755); err != nil {
+		t.Fatalf("create prd dir failed: %v", err)
+	}
+	writeJSON(t, filepath.Join(prdDir, "epic-a.json"), map[string]any{
+		"userStories": []map[string]any{
+			{"id": "US-001", "title": "A1", "role": "developer"},
+			{"id": "US-002", "title": "A2", "role": "qa"},
+		},
+	})
+	writeFile(t, filepath.Join(prdDir, "epic-b.yaml"), `userStories:
+  - id: US-002
+    title: B duplicate
+  - id: US-003
+    title: B3
+`)
+
+	for _, dryRun := range []bool{true, false} {
+		result, err := ImportPRDFiles(paths, []string{"prds/*.json", "prds/epic-b.yaml"}, "", "developer", dryRun)
+		if err != nil {
+			t.Fatalf("ImportPRDFiles(dryRun=%t) failed: %v", dryRun, err)
+		}
+		if len(result.SourcePaths) != 2 || result.StoriesTotal != 4 || result.Imported != 3 || result.SkippedExisting != 1 {
+			t.Fatalf("unexpected aggregated result (dryRun=%t): %+v", dryRun, result)
+		}
+		if !dryRun && len(result.CreatedPaths) != 3 {
+			t.Fatalf("created paths should span all files: %+v", result.CreatedPaths)
+		}
+	}
+
+	if _, err := ImportPRDFiles(paths, []string{"prds/*.md"}, "", "developer", true); err == nil {
+		t.Fatalf("expected error for glob without matches")
+	}
+}
+
+func TestImportPRDFilesMergeUpdatesExistingIssues(t *testing.T) {
+	paths := newTestPaths(t)
+
+	prdPath := filepath.Join(paths.ProjectDir, "prd.json")
+	writeJSON(t, prdPath, map[string]any{
+		"userStories": []map[string]any{
+			{"id": "US-001", "title": "Old title", "description": "old\ndescription", "priority": 10},
+			{"id": "US-002", "title": "Shipped", "priority": 10},
+		},
+	})
+	first, err := ImportPRDFiles(paths, []string{prdPath}, "", "developer", false)
+	if err != nil || len(first.CreatedPaths) != 2 {
+		t.Fatalf("initial import failed: result=%+v err=%v", first, err)
+	}
+	donePath := filepath.Join(paths.DoneDir, filepath.Base(first.CreatedPaths[1]))
+	if err := os.Rename(first.CreatedPaths[1], donePath); err != nil {
+		t.Fatalf("move issue to done: %v", err)
+	}
+
+	writeJSON(t, prdPath, map[string]any{
+		"userStories": []map[string]any{
+			{"id": "US-001", "title": "New title", "description": "new description", "priority": 5},
+			{"id": "US-002", "title": "Shipped v2", "priority": 1},
+		},
+	})
+	preview, err := ImportPRDFilesWithOptions(paths, []string{prdPath}, PRDImportOptions{DefaultRole: "developer", DryRun: true, Merge: true})
+	if err != nil || preview.Updated != 1 || preview.SkippedExisting != 1 {
+		t.Fatalf("unexpected merge preview: result=%+v err=%v", preview, err)
+	}
+	if meta, _ := ReadIssueMeta(first.CreatedPaths[0]); meta.Title != "Old title" {
+		t.Fatalf("dry-run merge must not modify issue: %+v", meta)
+	}
+
+	result, err := ImportPRDFilesWithOptions(paths, []string{prdPath}, PRDImportOptions{DefaultRole: "developer", Merge: true})
+	if err != nil {
+		t.Fatalf("merge import failed: %v", err)
+	}
+	if result.Updated != 1 || result.Imported != 0 || result.SkippedExisting != 1 || len(result.Updates) != 1 {
+		t.Fatalf("unexpected merge result: %+v", result)
+	}
+	if got := strings.Join(result.Updates[0].Changed, ","); got != "title,description,priority" {
+		t.Fatalf("changed fields mismatch: %s", got)
+	}
+	meta, err := ReadIssueMeta(first.CreatedPaths[0])
+	if err != nil {
+		t.Fatalf("read merged issue: %v", err)
+	}
+	if meta.Title != "New title" || meta.Priority != 5 {
+		t.Fatalf("merged meta mismatch: %+v", meta)
+	}
+	content, err := os.ReadFile(first.CreatedPaths[0])
+	if err != nil {
+		t.Fatalf("read merged issue: %v", err)
+	}
+	body := string(content)
+	if !strings.Contains(body, "## Objective\n- new description\n\n## Acceptance Criteria") {
+		t.Fatalf("objective should be replaced in place: %s", body)
+	}
+	if !strings.Contains(body, "## PRD Update") || !strings.Contains(body, "- changed: title,description,priority") {
+		t.Fatalf("merge should record changed fields: %s", body)
+	}
+	if doneMeta, _ := ReadIssueMeta(donePath); doneMeta.Title != "Shipped" {
+		t.Fatalf("done issue must not be merged: %+v", doneMeta)
+	}
+
+	again, err := ImportPRDFilesWithOptions(paths, []string{prdPath}, PRDImportOptions{DefaultRole: "developer", Merge: true})
+	if err != nil || again.Updated != 0 || again.SkippedExisting != 2 {
+		t.Fatalf("unchanged stories should not be re-merged: result=%+v err=%v", again, err)
+	}
+}
+
+func TestImportPRDStoriesYAMLKeepsNumericLookingStrings(t *testing.T) {
+	paths := newTestPaths(t)
+
+	prdPath := filepath.Join(paths.ProjectDir, "prd.yml")
+	writeFile(t, prdPath, `userStories:
+  - id: 001
+    title: 2024
+    description: 1.10
+    role: developer
+    priority: 7
+    passes: false
+    acceptanceCriteria: [100, true]
+`)
+
+	result, err := ImportPRDStories(paths, prdPath, "developer", false)
+	if err != nil {
+		t.Fatalf("ImportPRDStories yaml failed: %v", err)
+	}
+	if len(result.CreatedPaths) != 1 {
+		t.Fatalf("unexpected yaml import result: %+v", result)
+	}
+	meta, err := ReadIssueMeta(result.CreatedPaths[0])
+	if err != nil {
+		t.Fatalf("read imported meta failed: %v", err)
+	}
+	if meta.Title != "2024" || meta.StoryID != "001" || meta.Priority != 7 {
+		t.Fatalf("numeric-looking strings should be kept as written: %+v", meta)
+	}
+	content, err := os.ReadFile(result.CreatedPaths[0])
+	if err != nil {
+		t.Fatalf("read imported issue failed: %v", err)
+	}
+	for _, want := range []string{"1.10", "- [ ] 100", "- [ ] true"} {
+		if !strings.Contains(string(content), want) {
+			t.Fatalf("yaml imported issue missing %q: %s", want, content)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package ralph
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
@@ -26,6 +27,10 @@ type Profile struct {
 	CodexExecTimeoutSec            int
 	CodexRetryMaxAttempts          int
 	CodexRetryBackoffSec           int
+	CodexRetryJitterPct            int
+	CodexMaxConcurrent             int
+	CodexDryRun                    bool
+	CodexAuditLog                  bool
 	CodexCircuitBreakerEnabled     bool
 	CodexCircuitBreakerFailures    int
 	CodexCircuitBreakerCooldownSec int
@@ -34,8 +39,17 @@ type Profile struct {
 	HandoffRequired                bool
 	HandoffSchema                  string
 	IdleSleepSec                   int
+	IdlePollIntervalSec            int
+	PermissionCooldownStreak       int
+	PermissionCooldownSec          int
+	OutputDir                      string
+	LogMaxSizeMB                   int
+	LogMaxBackups                  int
+	DoctorMinFreeDiskMB            int
 	ExitOnIdle                     bool
 	NoReadyMaxLoops                int
+	MaxIssueAttempts               int
+	IssueDedupe                    bool
 	ValidateRoles                  map[string]struct{}
 	ValidateCmd                    string
 	BusyWaitDetectLoops            int
@@ -49,6 +63,8 @@ type Profile struct {
 	InProgressWatchdogScanLoops    int
 	SupervisorEnabled              bool
 	SupervisorRestartDelaySec      int
+	SupervisorHeartbeatStaleSec    int
+	SupervisorMaxRestartsPerMin    int
 }
 
 func DefaultProfile() Profile {
@@ -66,6 +82,7 @@ func DefaultProfile() Profile {
 		CodexExecTimeoutSec:            900,
 		CodexRetryMaxAttempts:          3,
 		CodexRetryBackoffSec:           10,
+		CodexRetryJitterPct:            20,
 		CodexCircuitBreakerEnabled:     true,
 		CodexCircuitBreakerFailures:    3,
 		CodexCircuitBreakerCooldownSec: 120,
@@ -74,8 +91,14 @@ func DefaultProfile() Profile {
 		HandoffRequired:                true,
 		HandoffSchema:                  "universal",
 		IdleSleepSec:                   20,
+		PermissionCooldownStreak:       3,
+		PermissionCooldownSec:          600,
+		LogMaxSizeMB:                   50,
+		LogMaxBackups:                  5,
+		DoctorMinFreeDiskMB:            1024,
 		ExitOnIdle:                     false,
 		NoReadyMaxLoops:                0,
+		MaxIssueAttempts:               0,
 		ValidateRoles: map[string]struct{}{
 			"developer": {},
 			"qa":        {},
@@ -92,29 +115,171 @@ func DefaultProfile() Profile {
 		InProgressWatchdogScanLoops: 1,
 		SupervisorEnabled:           true,
 		SupervisorRestartDelaySec:   5,
+		SupervisorHeartbeatStaleSec: 120,
+		SupervisorMaxRestartsPerMin: 5,
 	}
 }
 
+// ProfileEnvVar selects an environment overlay such as profile.prod.yaml.
+const ProfileEnvVar = "RALPH_ENV"
+
+// CodexModelOverrideEnv is set by `run`/`supervise --codex-model` to use one
+// codex model for every role in that process tree. It beats the per-role
+// codex_model_<role> values and is never written to a profile file.
+const CodexModelOverrideEnv = "RALPH_CODEX_MODEL_OVERRIDE"
+
+// CodexSandboxOverrideEnv and CodexApprovalOverrideEnv carry `run --codex-sandbox`
+// and `--codex-approval` the same way, for debugging permission failures
+// without editing the profile.
+const (
+	CodexSandboxOverrideEnv  = "RALPH_CODEX_SANDBOX_OVERRIDE"
+	CodexApprovalOverrideEnv = "RALPH_CODEX_APPROVAL_OVERRIDE"
+)
+
+func NormalizeCodexSandbox(raw string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(raw)); v {
+	case "read-only", "workspace-write", "danger-full-access":
+		return v, nil
+	default:
+		return "", fmt.Errorf("invalid codex sandbox: %s (expected read-only|workspace-write|danger-full-access)", raw)
+	}
+}
+
+func NormalizeCodexApproval(raw string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(raw)); v {
+	case "untrusted", "on-failure", "on-request", "never":
+		return v, nil
+	default:
+		return "", fmt.Errorf("invalid codex approval: %s (expected untrusted|on-failure|on-request|never)", raw)
+	}
+}
+
+func ProfileEnvironment() string {
+	return strings.TrimSpace(os.Getenv(ProfileEnvVar))
+}
+
+// ProfileEnvYAMLFile is the overlay for env, e.g. .ralph/profile.prod.yaml.
+func ProfileEnvYAMLFile(paths Paths, env string) string {
+	return filepath.Join(paths.RalphDir, "profile."+env+".yaml")
+}
+
+func validateProfileEnvironment(env string) error {
+	if env == "" {
+		return nil
+	}
+	if env == "local" {
+		return fmt.Errorf("%s=local is reserved for profile.local.yaml", ProfileEnvVar)
+	}
+	for _, ch := range env {
+		if !(ch == '-' || ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')) {
+			return fmt.Errorf("invalid %s %q: use letters, digits, '-' or '_'", ProfileEnvVar, env)
+		}
+	}
+	return nil
+}
+
+// LoadProfile applies, in order: defaults, profile.yaml, profile.local.yaml,
+// profile.env, profile.local.env, profile.<RALPH_ENV>.yaml, then RALPH_* env vars.
 func LoadProfile(paths Paths) (Profile, error) {
+	return LoadProfileForEnv(paths, ProfileEnvironment())
+}
+
+// LoadProfileForEnv is LoadProfile with an explicit environment overlay ("" for none).
+func LoadProfileForEnv(paths Paths, env string) (Profile, error) {
+	return loadProfileLayers(paths, env, nil)
+}
+
+// LoadProfileWithSources loads the effective profile and reports, per
+// profile key, which layer last changed its value.
+func LoadProfileWithSources(paths Paths) (Profile, map[string]string, error) {
+	return LoadProfileWithSourcesForEnv(paths, ProfileEnvironment())
+}
+
+func LoadProfileWithSourcesForEnv(paths Paths, env string) (Profile, map[string]string, error) {
+	sources := map[string]string{}
+	var prev map[string]string
+	p, err := loadProfileLayers(paths, env, func(source string, p Profile) {
+		cur := ProfileToYAMLMap(p)
+		for key, value := range cur {
+			if old, ok := prev[key]; prev == nil || !ok || old != value {
+				sources[key] = source
+			}
+		}
+		prev = cur
+	})
+	return p, sources, err
+}
+
+func loadProfileLayers(paths Paths, env string, onLayer func(source string, p Profile)) (Profile, error) {
 	p := DefaultProfile()
+	if err := validateProfileEnvironment(env); err != nil {
+		return p, err
+	}
+	layer := func(source string) {
+		if onLayer != nil {
+			onLayer(source, p)
+		}
+	}
+	layer("default")
 
 	if err := loadProfileYAMLFile(paths.ProfileYAMLFile, "profile.yaml", &p); err != nil {
 		return p, err
 	}
+	layer("profile.yaml")
 	if err := loadProfileYAMLFile(paths.ProfileLocalYAMLFile, "profile.local.yaml", &p); err != nil {
 		return p, err
 	}
+	layer("profile.local.yaml")
 	if err := loadProfileEnvFile(paths.ProfileFile, "profile.env", &p); err != nil {
 		return p, err
 	}
+	layer("profile.env")
 	if err := loadProfileEnvFile(paths.ProfileLocalFile, "profile.local.env", &p); err != nil {
 		return p, err
 	}
+	layer("profile.local.env")
+	if env != "" {
+		envFile := ProfileEnvYAMLFile(paths, env)
+		if err := loadProfileYAMLFile(envFile, filepath.Base(envFile), &p); err != nil {
+			return p, err
+		}
+		layer(filepath.Base(envFile))
+	}
 	applyProcessEnvOverrides(&p)
+	if model := strings.TrimSpace(os.Getenv(CodexModelOverrideEnv)); model != "" {
+		p.CodexModel = model
+		p.CodexModelManager, p.CodexModelPlanner, p.CodexModelDeveloper, p.CodexModelQA = "", "", "", ""
+	}
+	if sandbox, err := NormalizeCodexSandbox(os.Getenv(CodexSandboxOverrideEnv)); err == nil {
+		p.CodexSandbox = sandbox
+	}
+	if approval, err := NormalizeCodexApproval(os.Getenv(CodexApprovalOverrideEnv)); err == nil {
+		p.CodexApproval = approval
+	}
+	layer("env")
 
 	if p.IdleSleepSec <= 0 {
 		p.IdleSleepSec = 20
 	}
+	if p.IdlePollIntervalSec < 0 {
+		p.IdlePollIntervalSec = 0
+	}
+	if p.PermissionCooldownStreak <= 0 {
+		p.PermissionCooldownStreak = 3
+	}
+	if p.PermissionCooldownSec < 0 {
+		p.PermissionCooldownSec = 0
+	}
+	p.OutputDir = strings.TrimSpace(p.OutputDir)
+	if p.LogMaxSizeMB < 0 {
+		p.LogMaxSizeMB = 0
+	}
+	if p.LogMaxBackups < 0 {
+		p.LogMaxBackups = 0
+	}
+	if p.DoctorMinFreeDiskMB < 0 {
+		p.DoctorMinFreeDiskMB = 0
+	}
 	if p.CodexModel == "" {
 		p.CodexModel = "auto"
 	}
@@ -139,6 +304,15 @@ func LoadProfile(paths Paths) (Profile, error) {
 	if p.CodexRetryBackoffSec < 0 {
 		p.CodexRetryBackoffSec = 0
 	}
+	if p.CodexRetryJitterPct < 0 {
+		p.CodexRetryJitterPct = 0
+	}
+	if p.CodexRetryJitterPct > 100 {
+		p.CodexRetryJitterPct = 100
+	}
+	if p.CodexMaxConcurrent < 0 {
+		p.CodexMaxConcurrent = 0
+	}
 	if p.CodexCircuitBreakerFailures <= 0 {
 		p.CodexCircuitBreakerFailures = 3
 	}
@@ -146,6 +320,9 @@ func LoadProfile(paths Paths) (Profile, error) {
 		p.CodexCircuitBreakerCooldownSec = 0
 	}
 	p.HandoffSchema = normalizeHandoffSchema(p.HandoffSchema)
+	if p.MaxIssueAttempts < 0 {
+		p.MaxIssueAttempts = 0
+	}
 	if p.ValidateCmd == "" {
 		p.ValidateCmd = "echo \"skip validation\""
 	}
@@ -167,7 +344,14 @@ func LoadProfile(paths Paths) (Profile, error) {
 	if p.SupervisorRestartDelaySec < 0 {
 		p.SupervisorRestartDelaySec = 0
 	}
+	if p.SupervisorHeartbeatStaleSec < 0 {
+		p.SupervisorHeartbeatStaleSec = 0
+	}
+	if p.SupervisorMaxRestartsPerMin < 0 {
+		p.SupervisorMaxRestartsPerMin = 0
+	}
 
+	layer("normalized")
 	return p, nil
 }
 
@@ -273,6 +457,14 @@ func profileConfigEnvKey(rawKey string) string {
 		return "RALPH_CODEX_RETRY_MAX_ATTEMPTS"
 	case "codex_retry_backoff_sec", "codex.retry_backoff_sec":
 		return "RALPH_CODEX_RETRY_BACKOFF_SEC"
+	case "codex_retry_jitter_pct", "codex.retry_jitter_pct":
+		return "RALPH_CODEX_RETRY_JITTER_PCT"
+	case "codex_max_concurrent", "codex.max_concurrent":
+		return "RALPH_CODEX_MAX_CONCURRENT"
+	case "codex_dry_run", "codex.dry_run":
+		return "RALPH_CODEX_DRY_RUN"
+	case "codex_audit_log", "codex.audit_log":
+		return "RALPH_CODEX_AUDIT_LOG"
 	case "codex_circuit_breaker_enabled", "codex.circuit_breaker_enabled":
 		return "RALPH_CODEX_CIRCUIT_BREAKER_ENABLED"
 	case "codex_circuit_breaker_failures", "codex.circuit_breaker_failures":
@@ -289,10 +481,28 @@ func profileConfigEnvKey(rawKey string) string {
 		return "RALPH_HANDOFF_SCHEMA"
 	case "idle_sleep_sec":
 		return "RALPH_IDLE_SLEEP_SEC"
+	case "idle_poll_interval_sec":
+		return "RALPH_IDLE_POLL_INTERVAL_SEC"
+	case "permission_cooldown_streak":
+		return "RALPH_PERMISSION_COOLDOWN_STREAK"
+	case "permission_cooldown_sec":
+		return "RALPH_PERMISSION_COOLDOWN_SEC"
+	case "output_dir":
+		return "RALPH_OUTPUT_DIR"
+	case "log_max_size_mb":
+		return "RALPH_LOG_MAX_SIZE_MB"
+	case "log_max_backups":
+		return "RALPH_LOG_MAX_BACKUPS"
+	case "doctor_min_free_disk_mb":
+		return "RALPH_DOCTOR_MIN_FREE_DISK_MB"
 	case "exit_on_idle":
 		return "RALPH_EXIT_ON_IDLE"
 	case "no_ready_max_loops":
 		return "RALPH_NO_READY_MAX_LOOPS"
+	case "max_issue_attempts":
+		return "RALPH_MAX_ISSUE_ATTEMPTS"
+	case "issue_dedupe":
+		return "RALPH_ISSUE_DEDUPE"
 	case "validate_roles", "validation.roles":
 		return "RALPH_VALIDATE_ROLES"
 	case "validate_cmd", "validation.cmd":
@@ -319,6 +529,10 @@ func profileConfigEnvKey(rawKey string) string {
 		return "RALPH_SUPERVISOR_ENABLED"
 	case "supervisor_restart_delay_sec", "supervisor.restart_delay_sec":
 		return "RALPH_SUPERVISOR_RESTART_DELAY_SEC"
+	case "supervisor_heartbeat_stale_sec", "supervisor.heartbeat_stale_sec":
+		return "RALPH_SUPERVISOR_HEARTBEAT_STALE_SEC"
+	case "supervisor_max_restarts_per_min", "supervisor.max_restarts_per_min":
+		return "RALPH_SUPERVISOR_MAX_RESTARTS_PER_MIN"
 	default:
 		return ""
 	}
@@ -346,6 +560,10 @@ func ProfileToYAMLMap(p Profile) map[string]string {
 		"codex_exec_timeout_sec":             strconv.Itoa(p.CodexExecTimeoutSec),
 		"codex_retry_max_attempts":           strconv.Itoa(p.CodexRetryMaxAttempts),
 		"codex_retry_backoff_sec":            strconv.Itoa(p.CodexRetryBackoffSec),
+		"codex_retry_jitter_pct":             strconv.Itoa(p.CodexRetryJitterPct),
+		"codex_max_concurrent":               strconv.Itoa(p.CodexMaxConcurrent),
+		"codex_dry_run":                      boolToEnv(p.CodexDryRun),
+		"codex_audit_log":                    boolToEnv(p.CodexAuditLog),
 		"codex_circuit_breaker_enabled":      boolToEnv(p.CodexCircuitBreakerEnabled),
 		"codex_circuit_breaker_failures":     strconv.Itoa(p.CodexCircuitBreakerFailures),
 		"codex_circuit_breaker_cooldown_sec": strconv.Itoa(p.CodexCircuitBreakerCooldownSec),
@@ -354,8 +572,17 @@ func ProfileToYAMLMap(p Profile) map[string]string {
 		"handoff_required":                   boolToEnv(p.HandoffRequired),
 		"handoff_schema":                     normalizeHandoffSchema(p.HandoffSchema),
 		"idle_sleep_sec":                     strconv.Itoa(p.IdleSleepSec),
+		"idle_poll_interval_sec":             strconv.Itoa(p.IdlePollIntervalSec),
+		"permission_cooldown_streak":         strconv.Itoa(p.PermissionCooldownStreak),
+		"permission_cooldown_sec":            strconv.Itoa(p.PermissionCooldownSec),
+		"output_dir":                         p.OutputDir,
+		"log_max_size_mb":                    strconv.Itoa(p.LogMaxSizeMB),
+		"log_max_backups":                    strconv.Itoa(p.LogMaxBackups),
+		"doctor_min_free_disk_mb":            strconv.Itoa(p.DoctorMinFreeDiskMB),
 		"exit_on_idle":                       boolToEnv(p.ExitOnIdle),
 		"no_ready_max_loops":                 strconv.Itoa(p.NoReadyMaxLoops),
+		"max_issue_attempts":                 strconv.Itoa(p.MaxIssueAttempts),
+		"issue_dedupe":                       boolToEnv(p.IssueDedupe),
 		"validate_roles":                     RoleSetCSV(p.ValidateRoles),
 		"validate_cmd":                       p.ValidateCmd,
 		"busywait_detect_loops":              strconv.Itoa(p.BusyWaitDetectLoops),
@@ -369,6 +596,8 @@ func ProfileToYAMLMap(p Profile) map[string]string {
 		"inprogress_watchdog_scan_loops":     strconv.Itoa(p.InProgressWatchdogScanLoops),
 		"supervisor_enabled":                 boolToEnv(p.SupervisorEnabled),
 		"supervisor_restart_delay_sec":       strconv.Itoa(p.SupervisorRestartDelaySec),
+		"supervisor_heartbeat_stale_sec":     strconv.Itoa(p.SupervisorHeartbeatStaleSec),
+		"supervisor_max_restarts_per_min":    strconv.Itoa(p.SupervisorMaxRestartsPerMin),
 	}
 	if v := strings.TrimSpace(p.CodexHome); v != "" {
 		out["codex_home"] = v
@@ -443,6 +672,18 @@ func applyProfileMap(p *Profile, m map[string]string) {
 	if v, ok := parseInt(m["RALPH_CODEX_RETRY_BACKOFF_SEC"]); ok {
 		p.CodexRetryBackoffSec = v
 	}
+	if v, ok := parseInt(m["RALPH_CODEX_RETRY_JITTER_PCT"]); ok {
+		p.CodexRetryJitterPct = v
+	}
+	if v, ok := parseInt(m["RALPH_CODEX_MAX_CONCURRENT"]); ok {
+		p.CodexMaxConcurrent = v
+	}
+	if v, ok := parseBool(m["RALPH_CODEX_DRY_RUN"]); ok {
+		p.CodexDryRun = v
+	}
+	if v, ok := parseBool(m["RALPH_CODEX_AUDIT_LOG"]); ok {
+		p.CodexAuditLog = v
+	}
 	if v, ok := parseBool(m["RALPH_CODEX_CIRCUIT_BREAKER_ENABLED"]); ok {
 		p.CodexCircuitBreakerEnabled = v
 	}
@@ -467,12 +708,39 @@ func applyProfileMap(p *Profile, m map[string]string) {
 	if v, ok := parseInt(m["RALPH_IDLE_SLEEP_SEC"]); ok {
 		p.IdleSleepSec = v
 	}
+	if v, ok := parseInt(m["RALPH_IDLE_POLL_INTERVAL_SEC"]); ok {
+		p.IdlePollIntervalSec = v
+	}
+	if v, ok := parseInt(m["RALPH_PERMISSION_COOLDOWN_STREAK"]); ok {
+		p.PermissionCooldownStreak = v
+	}
+	if v, ok := parseInt(m["RALPH_PERMISSION_COOLDOWN_SEC"]); ok {
+		p.PermissionCooldownSec = v
+	}
+	if v := m["RALPH_OUTPUT_DIR"]; v != "" {
+		p.OutputDir = v
+	}
+	if v, ok := parseInt(m["RALPH_LOG_MAX_SIZE_MB"]); ok {
+		p.LogMaxSizeMB = v
+	}
+	if v, ok := parseInt(m["RALPH_LOG_MAX_BACKUPS"]); ok {
+		p.LogMaxBackups = v
+	}
+	if v, ok := parseInt(m["RALPH_DOCTOR_MIN_FREE_DISK_MB"]); ok {
+		p.DoctorMinFreeDiskMB = v
+	}
 	if v, ok := parseBool(m["RALPH_EXIT_ON_IDLE"]); ok {
 		p.ExitOnIdle = v
 	}
 	if v, ok := parseInt(m["RALPH_NO_READY_MAX_LOOPS"]); ok {
 		p.NoReadyMaxLoops = v
 	}
+	if v, ok := parseInt(m["RALPH_MAX_ISSUE_ATTEMPTS"]); ok {
+		p.MaxIssueAttempts = v
+	}
+	if v, ok := parseBool(m["RALPH_ISSUE_DEDUPE"]); ok {
+		p.IssueDedupe = v
+	}
 	if v := m["RALPH_VALIDATE_CMD"]; v != "" {
 		p.ValidateCmd = v
 	}
@@ -512,6 +780,12 @@ func applyProfileMap(p *Profile, m map[string]string) {
 	if v, ok := parseInt(m["RALPH_SUPERVISOR_RESTART_DELAY_SEC"]); ok {
 		p.SupervisorRestartDelaySec = v
 	}
+	if v, ok := parseInt(m["RALPH_SUPERVISOR_HEARTBEAT_STALE_SEC"]); ok {
+		p.SupervisorHeartbeatStaleSec = v
+	}
+	if v, ok := parseInt(m["RALPH_SUPERVISOR_MAX_RESTARTS_PER_MIN"]); ok {
+		p.SupervisorMaxRestartsPerMin = v
+	}
 }
 
 func parseRoleSet(raw string) map[string]struct{} {
@@ -571,25 +845,24 @@ func normalizeHandoffSchema(raw string) string {
 }
 
 func (p Profile) CodexModelForRole(role string) string {
+	if v := p.codexRoleModelOverride(role); v != "" {
+		return normalizeCodexModelForExec(v)
+	}
+	return normalizeCodexModelForExec(p.CodexModel)
+}
+
+func (p Profile) codexRoleModelOverride(role string) string {
 	switch strings.TrimSpace(role) {
 	case "manager":
-		if v := strings.TrimSpace(p.CodexModelManager); v != "" {
-			return normalizeCodexModelForExec(v)
-		}
+		return strings.TrimSpace(p.CodexModelManager)
 	case "planner":
-		if v := strings.TrimSpace(p.CodexModelPlanner); v != "" {
-			return normalizeCodexModelForExec(v)
-		}
+		return strings.TrimSpace(p.CodexModelPlanner)
 	case "developer":
-		if v := strings.TrimSpace(p.CodexModelDeveloper); v != "" {
-			return normalizeCodexModelForExec(v)
-		}
+		return strings.TrimSpace(p.CodexModelDeveloper)
 	case "qa":
-		if v := strings.TrimSpace(p.CodexModelQA); v != "" {
-			return normalizeCodexModelForExec(v)
-		}
+		return strings.TrimSpace(p.CodexModelQA)
 	}
-	return normalizeCodexModelForExec(p.CodexModel)
+	return ""
 }
 
 func normalizeCodexModelForExec(raw string) string {
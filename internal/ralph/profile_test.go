@@ -1,6 +1,10 @@
 package ralph
 
-import "testing"
+import (
+	"os"
+	"strings"
+	"testing"
+)
 
 func TestLoadProfilePrecedence(t *testing.T) {
 	paths := newTestPaths(t)
@@ -158,6 +162,69 @@ RALPH_CODEX_MODEL_DEVELOPER=dev-local-env
 	}
 }
 
+func TestCodexModelOverrideEnvAppliesToEveryRole(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	writeFile(t, paths.ProfileLocalYAMLFile, "codex_model: gpt-global\ncodex_model_planner: planner-local-yaml\n")
+	t.Setenv("RALPH_CODEX_MODEL_QA", "qa-process")
+	t.Setenv(CodexModelOverrideEnv, "gpt-experiment")
+
+	profile, err := LoadProfile(paths)
+	if err != nil {
+		t.Fatalf("load profile: %v", err)
+	}
+	for _, role := range RequiredAgentRoles {
+		if got := profile.CodexModelForRole(role); got != "gpt-experiment" {
+			t.Fatalf("model %s mismatch: got=%q want=%q", role, got, "gpt-experiment")
+		}
+	}
+	data, err := os.ReadFile(paths.ProfileLocalYAMLFile)
+	if err != nil || strings.Contains(string(data), "gpt-experiment") {
+		t.Fatalf("override must not be persisted: %q err=%v", data, err)
+	}
+}
+
+func TestCodexPolicyOverrideEnvBeatsProfile(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	writeFile(t, paths.ProfileLocalYAMLFile, "codex_sandbox: read-only\ncodex_approval: never\n")
+	t.Setenv(CodexSandboxOverrideEnv, "danger-full-access")
+	t.Setenv(CodexApprovalOverrideEnv, "on-request")
+
+	profile, err := LoadProfile(paths)
+	if err != nil {
+		t.Fatalf("load profile: %v", err)
+	}
+	if profile.CodexSandbox != "danger-full-access" || profile.CodexApproval != "on-request" {
+		t.Fatalf("override mismatch: sandbox=%q approval=%q", profile.CodexSandbox, profile.CodexApproval)
+	}
+
+	t.Setenv(CodexSandboxOverrideEnv, "bogus")
+	t.Setenv(CodexApprovalOverrideEnv, "")
+	profile, err = LoadProfile(paths)
+	if err != nil {
+		t.Fatalf("load profile: %v", err)
+	}
+	if profile.CodexSandbox != "read-only" || profile.CodexApproval != "never" {
+		t.Fatalf("invalid/empty override should keep profile: sandbox=%q approval=%q", profile.CodexSandbox, profile.CodexApproval)
+	}
+}
+
+func TestNormalizeCodexSandboxAndApproval(t *testing.T) {
+	if got, err := NormalizeCodexSandbox(" Workspace-Write "); err != nil || got != "workspace-write" {
+		t.Fatalf("sandbox normalize mismatch: got=%q err=%v", got, err)
+	}
+	if _, err := NormalizeCodexSandbox("full"); err == nil {
+		t.Fatalf("expected invalid sandbox error")
+	}
+	if got, err := NormalizeCodexApproval("on-failure"); err != nil || got != "on-failure" {
+		t.Fatalf("approval normalize mismatch: got=%q err=%v", got, err)
+	}
+	if _, err := NormalizeCodexApproval("always"); err == nil {
+		t.Fatalf("expected invalid approval error")
+	}
+}
+
 func TestProfileToYAMLMapRoleModels(t *testing.T) {
 	profile := DefaultProfile()
 	profile.CodexModelManager = "manager-model"
@@ -266,3 +333,132 @@ codex_circuit_breaker_cooldown_sec: 90
 		t.Fatalf("codex_circuit_breaker_cooldown_sec mismatch: got=%d want=90", profile.CodexCircuitBreakerCooldownSec)
 	}
 }
+
+func TestLoadProfileWithSourcesAttributesLayers(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+
+	writeFile(t, paths.ProfileYAMLFile, `
+codex_exec_timeout_sec: 300
+codex_retry_max_attempts: 2
+`)
+	writeFile(t, paths.ProfileLocalFile, `
+RALPH_CODEX_RETRY_MAX_ATTEMPTS=4
+`)
+	t.Setenv("RALPH_IDLE_SLEEP_SEC", "7")
+	t.Setenv("RALPH_CODEX_RETRY_JITTER_PCT", "250")
+
+	profile, sources, err := LoadProfileWithSources(paths)
+	if err != nil {
+		t.Fatalf("load profile with sources: %v", err)
+	}
+	if profile.CodexExecTimeoutSec != 300 {
+		t.Fatalf("timeout mismatch: got=%d", profile.CodexExecTimeoutSec)
+	}
+	want := map[string]string{
+		"codex_exec_timeout_sec":   "profile.yaml",
+		"codex_retry_max_attempts": "profile.local.env",
+		"idle_sleep_sec":           "env",
+		"codex_retry_jitter_pct":   "normalized",
+		"codex_approval":           "default",
+	}
+	for key, source := range want {
+		if sources[key] != source {
+			t.Fatalf("source mismatch for %s: got=%q want=%q", key, sources[key], source)
+		}
+	}
+}
+
+func TestLoadProfileAppliesEnvironmentOverlay(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+
+	writeFile(t, paths.ProfileYAMLFile, `
+codex_model: base-model
+codex_approval: on-request
+idle_sleep_sec: 30
+`)
+	writeFile(t, paths.ProfileLocalYAMLFile, "codex_model: local-model\n")
+	writeFile(t, ProfileEnvYAMLFile(paths, "prod"), `
+codex_model: prod-model
+codex_approval: never
+idle_sleep_sec: 60
+`)
+	t.Setenv("RALPH_IDLE_SLEEP_SEC", "5")
+
+	base, err := LoadProfile(paths)
+	if err != nil {
+		t.Fatalf("load profile: %v", err)
+	}
+	if base.CodexModel != "local-model" || base.CodexApproval != "on-request" {
+		t.Fatalf("overlay must not apply without %s: model=%q approval=%q", ProfileEnvVar, base.CodexModel, base.CodexApproval)
+	}
+
+	t.Setenv(ProfileEnvVar, "prod")
+	profile, sources, err := LoadProfileWithSources(paths)
+	if err != nil {
+		t.Fatalf("load profile with env: %v", err)
+	}
+	if profile.CodexModel != "prod-model" || profile.CodexApproval != "never" {
+		t.Fatalf("overlay should beat profile.local.yaml: model=%q approval=%q", profile.CodexModel, profile.CodexApproval)
+	}
+	if profile.IdleSleepSec != 5 {
+		t.Fatalf("process env should beat overlay: idle_sleep_sec=%d", profile.IdleSleepSec)
+	}
+	if sources["codex_model"] != "profile.prod.yaml" || sources["idle_sleep_sec"] != "env" {
+		t.Fatalf("source attribution mismatch: codex_model=%q idle_sleep_sec=%q", sources["codex_model"], sources["idle_sleep_sec"])
+	}
+
+	staging, err := LoadProfileForEnv(paths, "staging")
+	if err != nil {
+		t.Fatalf("missing overlay file should be ignored: %v", err)
+	}
+	if staging.CodexModel != "local-model" {
+		t.Fatalf("missing overlay should fall back to base: %q", staging.CodexModel)
+	}
+	for _, bad := range []string{"local", "../prod"} {
+		if _, err := LoadProfileForEnv(paths, bad); err == nil {
+			t.Fatalf("expected invalid env error for %q", bad)
+		}
+	}
+}
+
+func TestSetProfileValuesValidatesAndPersists(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+
+	writeFile(t, paths.ProfileLocalYAMLFile, "codex_model: gpt-5\n")
+
+	applied, err := SetProfileValues(paths, map[string]string{
+		"codex-retry-max-attempts": "5",
+		"exit_on_idle":             "yes",
+		"validate_roles":           "qa,developer",
+	})
+	if err != nil {
+		t.Fatalf("set profile values: %v", err)
+	}
+	if applied["exit_on_idle"] != "true" || applied["validate_roles"] != "developer,qa" {
+		t.Fatalf("unexpected applied values: %+v", applied)
+	}
+	profile, err := LoadProfile(paths)
+	if err != nil {
+		t.Fatalf("load profile: %v", err)
+	}
+	if profile.CodexRetryMaxAttempts != 5 || !profile.ExitOnIdle || profile.CodexModel != "gpt-5" {
+		t.Fatalf("profile not updated: retries=%d exit_on_idle=%t model=%q", profile.CodexRetryMaxAttempts, profile.ExitOnIdle, profile.CodexModel)
+	}
+
+	if _, err := SetProfileValues(paths, map[string]string{"no_such_key": "1"}); err == nil || !strings.Contains(err.Error(), "valid keys:") {
+		t.Fatalf("expected unknown key error listing valid keys, got %v", err)
+	}
+	if _, err := SetProfileValues(paths, map[string]string{"idle_sleep_sec": "soon"}); err == nil {
+		t.Fatalf("expected type validation error")
+	}
+	m, err := ReadYAMLFlatMap(paths.ProfileLocalYAMLFile)
+	if err != nil {
+		t.Fatalf("read profile.local.yaml: %v", err)
+	}
+	if _, ok := m["idle_sleep_sec"]; ok {
+		t.Fatalf("invalid value should not be written: %+v", m)
+	}
+}
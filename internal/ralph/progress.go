@@ -17,10 +17,7 @@ func AppendProgressEntry(paths Paths, meta IssueMeta, status, reason, logFile st
 	}
 	defer f.Close()
 
-	priority := meta.Priority
-	if priority <= 0 {
-		priority = defaultIssuePriority
-	}
+	priority := effectiveIssuePriority(meta)
 	storyID := strings.TrimSpace(meta.StoryID)
 	if storyID == "" {
 		storyID = "-"
@@ -18,6 +18,7 @@ type PluginRegistry struct {
 	Version        int                   `json:"version"`
 	GeneratedAtUTC string                `json:"generated_at_utc"`
 	Plugins        []PluginRegistryEntry `json:"plugins"`
+	Signature      string                `json:"signature,omitempty"`
 }
 
 type PluginRegistryEntry struct {
@@ -34,7 +35,7 @@ type RegistryCheck struct {
 }
 
 func PluginRegistryPath(controlDir string) string {
-	return filepath.Join(controlDir, "plugins", "registry.json")
+	return filepath.Join(PluginsDir(controlDir), "registry.json")
 }
 
 func LoadPluginRegistry(controlDir string) (PluginRegistry, error) {
@@ -53,12 +54,16 @@ func LoadPluginRegistry(controlDir string) (PluginRegistry, error) {
 	if reg.Plugins == nil {
 		reg.Plugins = []PluginRegistryEntry{}
 	}
-	sort.Slice(reg.Plugins, func(i, j int) bool {
-		return reg.Plugins[i].Name < reg.Plugins[j].Name
-	})
+	sortPluginRegistryEntries(reg.Plugins)
 	return reg, nil
 }
 
+func sortPluginRegistryEntries(entries []PluginRegistryEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+}
+
 func SavePluginRegistry(controlDir string, reg PluginRegistry) error {
 	reg.Version = pluginRegistryVersion
 	if reg.GeneratedAtUTC == "" {
@@ -67,9 +72,7 @@ func SavePluginRegistry(controlDir string, reg PluginRegistry) error {
 	if reg.Plugins == nil {
 		reg.Plugins = []PluginRegistryEntry{}
 	}
-	sort.Slice(reg.Plugins, func(i, j int) bool {
-		return reg.Plugins[i].Name < reg.Plugins[j].Name
-	})
+	sortPluginRegistryEntries(reg.Plugins)
 
 	path := PluginRegistryPath(controlDir)
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
@@ -116,10 +119,16 @@ func GeneratePluginRegistry(controlDir string) (PluginRegistry, error) {
 func VerifyPluginRegistry(controlDir string) ([]RegistryCheck, error) {
 	reg, err := LoadPluginRegistry(controlDir)
 	if err != nil {
+		if os.IsNotExist(err) && RegistryPublicKeyPath() != "" {
+			return []RegistryCheck{{Name: "registry-signature", Status: "fail", Detail: errSignedRegistryMissing().Error()}}, nil
+		}
 		return nil, err
 	}
 
 	checks := []RegistryCheck{}
+	if check := registrySignatureCheck(reg); check.Name != "" {
+		checks = append(checks, check)
+	}
 	seen := map[string]struct{}{}
 	for _, entry := range reg.Plugins {
 		name := strings.TrimSpace(entry.Name)
@@ -146,7 +155,7 @@ func VerifyPluginRegistry(controlDir string) ([]RegistryCheck, error) {
 			fileRel = filepath.ToSlash(filepath.Join(name, "plugin.env"))
 		}
 		fileRel = strings.TrimPrefix(fileRel, "/")
-		pluginFile := filepath.Join(controlDir, "plugins", filepath.FromSlash(fileRel))
+		pluginFile := filepath.Join(PluginsDir(controlDir), filepath.FromSlash(fileRel))
 		hash, hashErr := sha256FileHex(pluginFile)
 		if hashErr != nil {
 			status := "fail"
@@ -214,10 +223,16 @@ func VerifyPluginWithRegistry(controlDir, pluginName string) error {
 	reg, err := LoadPluginRegistry(controlDir)
 	if err != nil {
 		if os.IsNotExist(err) {
+			if RegistryPublicKeyPath() != "" {
+				return errSignedRegistryMissing()
+			}
 			return nil
 		}
 		return err
 	}
+	if _, err := verifyConfiguredRegistrySignature(reg); err != nil {
+		return err
+	}
 
 	pluginName = strings.TrimSpace(pluginName)
 	var entry *PluginRegistryEntry
@@ -236,7 +251,7 @@ func VerifyPluginWithRegistry(controlDir, pluginName string) error {
 		fileRel = filepath.ToSlash(filepath.Join(pluginName, "plugin.env"))
 	}
 	fileRel = strings.TrimPrefix(fileRel, "/")
-	pluginFile := filepath.Join(controlDir, "plugins", filepath.FromSlash(fileRel))
+	pluginFile := filepath.Join(PluginsDir(controlDir), filepath.FromSlash(fileRel))
 	actual, err := sha256FileHex(pluginFile)
 	if err != nil {
 		return err
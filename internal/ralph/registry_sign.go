@@ -0,0 +1,156 @@
+package ralph
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RegistryPublicKeyEnv points at the PEM ed25519 public key trusted for registry signatures.
+// It is read from the environment (not the control dir) so that whoever can edit
+// registry.json cannot also swap the key it is checked against.
+const RegistryPublicKeyEnv = "RALPH_REGISTRY_PUBLIC_KEY"
+
+func RegistryPublicKeyPath() string {
+	return strings.TrimSpace(os.Getenv(RegistryPublicKeyEnv))
+}
+
+// pluginRegistrySigningPayload is the canonical JSON the signature covers:
+// the registry as saved, with plugins sorted and the signature field cleared.
+func pluginRegistrySigningPayload(reg PluginRegistry) ([]byte, error) {
+	reg.Signature = ""
+	if reg.Plugins == nil {
+		reg.Plugins = []PluginRegistryEntry{}
+	}
+	sortPluginRegistryEntries(reg.Plugins)
+	data, err := json.Marshal(reg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal registry payload: %w", err)
+	}
+	return data, nil
+}
+
+// SignPluginRegistry signs the saved registry with an ed25519 PKCS#8 PEM private key
+// (e.g. `openssl genpkey -algorithm ed25519`) and writes the signature back.
+func SignPluginRegistry(controlDir, keyFile string) (PluginRegistry, error) {
+	key, err := LoadRegistryPrivateKey(keyFile)
+	if err != nil {
+		return PluginRegistry{}, err
+	}
+	reg, err := LoadPluginRegistry(controlDir)
+	if err != nil {
+		return PluginRegistry{}, err
+	}
+	payload, err := pluginRegistrySigningPayload(reg)
+	if err != nil {
+		return PluginRegistry{}, err
+	}
+	reg.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload))
+	if err := SavePluginRegistry(controlDir, reg); err != nil {
+		return PluginRegistry{}, err
+	}
+	return reg, nil
+}
+
+func VerifyPluginRegistrySignature(reg PluginRegistry, pub ed25519.PublicKey) error {
+	encoded := strings.TrimSpace(reg.Signature)
+	if encoded == "" {
+		return fmt.Errorf("registry is not signed")
+	}
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("decode registry signature: %w", err)
+	}
+	payload, err := pluginRegistrySigningPayload(reg)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(pub, payload, sig) {
+		return fmt.Errorf("registry signature does not match public key")
+	}
+	return nil
+}
+
+// errSignedRegistryMissing is returned when a public key is configured but
+// registry.json is gone: deleting the registry must not skip verification.
+func errSignedRegistryMissing() error {
+	return fmt.Errorf("plugin registry not found but %s is set; a signed registry is required", RegistryPublicKeyEnv)
+}
+
+// verifyConfiguredRegistrySignature checks reg against RALPH_REGISTRY_PUBLIC_KEY.
+// It reports configured=false when no key is set, leaving signatures advisory;
+// with a key set, an unsigned registry fails like a bad signature.
+func verifyConfiguredRegistrySignature(reg PluginRegistry) (configured bool, err error) {
+	keyPath := RegistryPublicKeyPath()
+	if keyPath == "" {
+		return false, nil
+	}
+	pub, err := LoadRegistryPublicKey(keyPath)
+	if err != nil {
+		return true, err
+	}
+	return true, VerifyPluginRegistrySignature(reg, pub)
+}
+
+func registrySignatureCheck(reg PluginRegistry) RegistryCheck {
+	configured, err := verifyConfiguredRegistrySignature(reg)
+	switch {
+	case !configured && strings.TrimSpace(reg.Signature) == "":
+		return RegistryCheck{}
+	case !configured:
+		return RegistryCheck{Name: "registry-signature", Status: "warn", Detail: fmt.Sprintf("registry is signed but %s is not set", RegistryPublicKeyEnv)}
+	case err != nil:
+		return RegistryCheck{Name: "registry-signature", Status: "fail", Detail: err.Error()}
+	default:
+		return RegistryCheck{Name: "registry-signature", Status: "pass", Detail: "signature verified"}
+	}
+}
+
+func LoadRegistryPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not ed25519", path)
+	}
+	return key, nil
+}
+
+func LoadRegistryPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not ed25519", path)
+	}
+	return key, nil
+}
+
+func readPEMBlock(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key file %s is not PEM encoded", path)
+	}
+	return block, nil
+}
@@ -0,0 +1,119 @@
+package ralph
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestRegistryKeys(t *testing.T) (string, string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	dir := t.TempDir()
+	privPath := filepath.Join(dir, "registry.key")
+	pubPath := filepath.Join(dir, "registry.pub")
+	writeFile(t, privPath, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})))
+	writeFile(t, pubPath, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})))
+	return privPath, pubPath
+}
+
+func TestSignPluginRegistryDetectsRegistryEdit(t *testing.T) {
+	paths := newTestPaths(t)
+	writeTestPlugin(t, paths.ControlDir, "universal-default", "RALPH_CODEX_MODEL=gpt-5.3-codex\n")
+	privPath, pubPath := writeTestRegistryKeys(t)
+	t.Setenv(RegistryPublicKeyEnv, pubPath)
+
+	reg, err := GeneratePluginRegistry(paths.ControlDir)
+	if err != nil {
+		t.Fatalf("generate registry: %v", err)
+	}
+	if err := SavePluginRegistry(paths.ControlDir, reg); err != nil {
+		t.Fatalf("save registry: %v", err)
+	}
+	if err := VerifyPluginWithRegistry(paths.ControlDir, "universal-default"); err == nil {
+		t.Fatalf("unsigned registry must fail when a public key is configured")
+	}
+
+	if _, err := SignPluginRegistry(paths.ControlDir, privPath); err != nil {
+		t.Fatalf("sign registry: %v", err)
+	}
+	checks, err := VerifyPluginRegistry(paths.ControlDir)
+	if err != nil {
+		t.Fatalf("verify registry: %v", err)
+	}
+	if RegistryFailureCount(checks) != 0 || checks[0].Name != "registry-signature" || checks[0].Status != "pass" {
+		t.Fatalf("signed registry should verify: %+v", checks)
+	}
+	if err := VerifyPluginWithRegistry(paths.ControlDir, "universal-default"); err != nil {
+		t.Fatalf("verify plugin with signed registry: %v", err)
+	}
+
+	// Re-pin the registry to a tampered plugin without re-signing.
+	writeTestPlugin(t, paths.ControlDir, "universal-default", "RALPH_VALIDATE_CMD=echo tampered\n")
+	signed, err := LoadPluginRegistry(paths.ControlDir)
+	if err != nil {
+		t.Fatalf("load registry: %v", err)
+	}
+	edited, err := GeneratePluginRegistry(paths.ControlDir)
+	if err != nil {
+		t.Fatalf("regenerate registry: %v", err)
+	}
+	edited.GeneratedAtUTC = signed.GeneratedAtUTC
+	edited.Signature = signed.Signature
+	if err := SavePluginRegistry(paths.ControlDir, edited); err != nil {
+		t.Fatalf("save edited registry: %v", err)
+	}
+	checks, err = VerifyPluginRegistry(paths.ControlDir)
+	if err != nil {
+		t.Fatalf("verify edited registry: %v", err)
+	}
+	if checks[0].Name != "registry-signature" || checks[0].Status != "fail" {
+		t.Fatalf("edited registry should fail signature check: %+v", checks)
+	}
+	if err := VerifyPluginWithRegistry(paths.ControlDir, "universal-default"); err == nil {
+		t.Fatalf("edited registry must not verify plugins")
+	}
+
+	if err := os.Remove(PluginRegistryPath(paths.ControlDir)); err != nil {
+		t.Fatalf("remove registry: %v", err)
+	}
+	if err := VerifyPluginWithRegistry(paths.ControlDir, "universal-default"); err == nil {
+		t.Fatalf("deleting the registry must not skip signature checks while a key is set")
+	}
+	checks, err = VerifyPluginRegistry(paths.ControlDir)
+	if err != nil || len(checks) != 1 || checks[0].Name != "registry-signature" || checks[0].Status != "fail" {
+		t.Fatalf("missing registry with a key set should be a failing check: checks=%+v err=%v", checks, err)
+	}
+	report := DoctorReport{}
+	appendPluginRegistryChecks(&report, paths.ControlDir)
+	if !report.HasFailures() {
+		t.Fatalf("doctor should fail when the signed registry is missing: %+v", report.Checks)
+	}
+	if err := SavePluginRegistry(paths.ControlDir, edited); err != nil {
+		t.Fatalf("restore registry: %v", err)
+	}
+
+	t.Setenv(RegistryPublicKeyEnv, "")
+	checks, err = VerifyPluginRegistry(paths.ControlDir)
+	if err != nil {
+		t.Fatalf("verify without key: %v", err)
+	}
+	if checks[0].Status != "warn" {
+		t.Fatalf("signature without configured key should warn: %+v", checks)
+	}
+}
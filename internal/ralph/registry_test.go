@@ -1,6 +1,8 @@
 package ralph
 
 import (
+	"bufio"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -35,6 +37,45 @@ func TestGenerateAndVerifyPluginRegistry(t *testing.T) {
 	}
 }
 
+func TestPluginDirOverrideRedirectsPluginsAndRegistry(t *testing.T) {
+	paths := newTestPaths(t)
+	vendored := filepath.Join(paths.ProjectDir, "ralph-plugins")
+	t.Setenv(PluginDirEnv, vendored)
+
+	if err := EnsureDefaultControlAssets(paths.ControlDir); err != nil {
+		t.Fatalf("ensure control assets: %v", err)
+	}
+	if _, err := os.Stat(vendored); !os.IsNotExist(err) {
+		t.Fatalf("builtin plugins must not be seeded into an override dir: %v", err)
+	}
+
+	pluginDir := filepath.Join(vendored, "team-default")
+	if err := os.MkdirAll(pluginDir, 0o755); err != nil {
+		t.Fatalf("create plugin dir: %v", err)
+	}
+	writeFile(t, filepath.Join(pluginDir, "plugin.env"), "RALPH_VALIDATE_CMD=make test\n")
+	writeTestPlugin(t, paths.ControlDir, "universal-default", "RALPH_CODEX_MODEL=gpt-5.3-codex\n")
+
+	plugins, err := ListPlugins(paths.ControlDir)
+	if err != nil || len(plugins) != 1 || plugins[0] != "team-default" {
+		t.Fatalf("plugins should come from the override dir: %v err=%v", plugins, err)
+	}
+	reg, err := GeneratePluginRegistry(paths.ControlDir)
+	if err != nil {
+		t.Fatalf("generate registry: %v", err)
+	}
+	if err := SavePluginRegistry(paths.ControlDir, reg); err != nil {
+		t.Fatalf("save registry: %v", err)
+	}
+	if PluginRegistryPath(paths.ControlDir) != filepath.Join(vendored, "registry.json") {
+		t.Fatalf("registry should live in the override dir: %s", PluginRegistryPath(paths.ControlDir))
+	}
+	checks, err := VerifyPluginRegistry(paths.ControlDir)
+	if err != nil || RegistryFailureCount(checks) != 0 {
+		t.Fatalf("verify override registry: checks=%+v err=%v", checks, err)
+	}
+}
+
 func TestVerifyPluginRegistryDetectsTamper(t *testing.T) {
 	paths := newTestPaths(t)
 	writeTestPlugin(t, paths.ControlDir, "universal-default", "RALPH_CODEX_MODEL=gpt-5.3-codex\n")
@@ -102,3 +143,192 @@ func assertMapValue(t *testing.T, m map[string]string, key, want string) {
 		t.Fatalf("map[%q] mismatch: got=%q want=%q", key, got, want)
 	}
 }
+
+func TestApplySetupSelectionsPersistsRoleModels(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	if err := EnsureDefaultControlAssets(paths.ControlDir); err != nil {
+		t.Fatalf("ensure control assets: %v", err)
+	}
+	writeFile(t, paths.ProfileLocalYAMLFile, "codex_model_manager: old-model\n")
+
+	selections := DefaultSetupSelections("universal-default")
+	selections.RoleModels = map[string]string{
+		"planner": "strong-model",
+		"qa":      "cheap-model",
+		"manager": "",
+	}
+	if err := ApplySetupSelections(paths, "/bin/true", selections); err != nil {
+		t.Fatalf("apply setup selections: %v", err)
+	}
+	profile, err := LoadProfile(paths)
+	if err != nil {
+		t.Fatalf("load profile: %v", err)
+	}
+	if got := profile.CodexModelForRole("planner"); got != "strong-model" {
+		t.Fatalf("planner model mismatch: %q", got)
+	}
+	if got := profile.CodexModelForRole("qa"); got != "cheap-model" {
+		t.Fatalf("qa model mismatch: %q", got)
+	}
+	if profile.CodexModelManager != "" {
+		t.Fatalf("manager override should be cleared: %q", profile.CodexModelManager)
+	}
+}
+
+func TestSetupSelectionsFromProjectSeedsTarget(t *testing.T) {
+	source := newTestPaths(t)
+	resetProfileEnv(t)
+	if err := EnsureDefaultControlAssets(source.ControlDir); err != nil {
+		t.Fatalf("ensure control assets: %v", err)
+	}
+	seed := DefaultSetupSelections("universal-default")
+	seed.HandoffRequired = false
+	seed.ValidationMode = SetupModeCustom
+	seed.ValidateCmd = "make check"
+	seed.RoleModels = map[string]string{"qa": "cheap-model"}
+	if err := ApplySetupSelections(source, "/bin/true", seed); err != nil {
+		t.Fatalf("apply source setup: %v", err)
+	}
+
+	got, err := SetupSelectionsFromProject(source.ControlDir, source.ProjectDir)
+	if err != nil {
+		t.Fatalf("selections from project: %v", err)
+	}
+	if got.Plugin != "universal-default" || got.HandoffRequired || got.ValidationMode != SetupModeCustom || got.ValidateCmd != "make check" {
+		t.Fatalf("seeded selections mismatch: %+v", got)
+	}
+	if got.RoleModels["qa"] != "cheap-model" || got.RoleModels["planner"] != "" {
+		t.Fatalf("seeded role models mismatch: %+v", got.RoleModels)
+	}
+
+	target, err := NewPaths(source.ControlDir, filepath.Join(t.TempDir(), "target"))
+	if err != nil {
+		t.Fatalf("new target paths: %v", err)
+	}
+	if err := ApplySetupSelections(target, "/bin/true", got); err != nil {
+		t.Fatalf("apply seeded setup: %v", err)
+	}
+	profile, err := LoadProfile(target)
+	if err != nil {
+		t.Fatalf("load target profile: %v", err)
+	}
+	if profile.HandoffRequired || profile.ValidateCmd != "make check" || profile.CodexModelForRole("qa") != "cheap-model" {
+		t.Fatalf("target profile should mirror source: handoff=%t validate=%q qa=%q", profile.HandoffRequired, profile.ValidateCmd, profile.CodexModelForRole("qa"))
+	}
+
+	if _, err := SetupSelectionsFromProject(source.ControlDir, filepath.Join(t.TempDir(), "unmanaged")); err == nil || !strings.Contains(err.Error(), "not a managed ralph project") {
+		t.Fatalf("expected unmanaged project error, got %v", err)
+	}
+}
+
+func TestPreviewApplyPluginDiffsWithoutWriting(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	writeTestPlugin(t, paths.ControlDir, "universal-default", "RALPH_IDLE_SLEEP_SEC=20\n")
+
+	if err := ApplyPlugin(paths, "universal-default"); err != nil {
+		t.Fatalf("apply plugin: %v", err)
+	}
+	preview, err := PreviewApplyPlugin(paths, "universal-default", ApplyPluginOptions{})
+	if err != nil {
+		t.Fatalf("preview plugin: %v", err)
+	}
+	if preview.Changed() {
+		t.Fatalf("freshly applied plugin should have no diff:\n%s", preview.Diff)
+	}
+
+	data, err := os.ReadFile(paths.ProfileYAMLFile)
+	if err != nil {
+		t.Fatalf("read profile.yaml: %v", err)
+	}
+	tuned := strings.Replace(string(data), "idle_sleep_sec: 20", "idle_sleep_sec: 45", 1)
+	writeFile(t, paths.ProfileYAMLFile, tuned)
+
+	preview, err = PreviewApplyPlugin(paths, "universal-default", ApplyPluginOptions{})
+	if err != nil {
+		t.Fatalf("preview plugin: %v", err)
+	}
+	if !strings.Contains(preview.Diff, "-idle_sleep_sec: 45\n+idle_sleep_sec: 20\n") || !strings.Contains(preview.Diff, "@@ ") {
+		t.Fatalf("diff should show the hand-tuned value being replaced:\n%s", preview.Diff)
+	}
+	after, err := os.ReadFile(paths.ProfileYAMLFile)
+	if err != nil {
+		t.Fatalf("read profile.yaml: %v", err)
+	}
+	if string(after) != tuned {
+		t.Fatalf("preview must not write profile.yaml")
+	}
+}
+
+func TestApplyPluginRefusesTamperedPluginUnlessSkipVerify(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	writeTestPlugin(t, paths.ControlDir, "universal-default", "RALPH_CODEX_MODEL=gpt-5.3-codex\n")
+
+	reg, err := GeneratePluginRegistry(paths.ControlDir)
+	if err != nil {
+		t.Fatalf("generate registry: %v", err)
+	}
+	if err := SavePluginRegistry(paths.ControlDir, reg); err != nil {
+		t.Fatalf("save registry: %v", err)
+	}
+	pluginPath := filepath.Join(paths.ControlDir, "plugins", "universal-default", "plugin.env")
+	writeFile(t, pluginPath, "RALPH_CODEX_MODEL=gpt-5.3-codex\nRALPH_VALIDATE_CMD=echo tampered\n")
+
+	err = Install(paths, "universal-default", "/bin/true")
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("install should refuse tampered plugin: %v", err)
+	}
+	if _, statErr := os.Stat(paths.ProfileYAMLFile); !os.IsNotExist(statErr) {
+		t.Fatalf("profile.yaml must not be written on verification failure: %v", statErr)
+	}
+
+	if err := InstallWithOptions(paths, "universal-default", "/bin/true", ApplyPluginOptions{SkipVerify: true}); err != nil {
+		t.Fatalf("install with skip-verify: %v", err)
+	}
+	profile, err := LoadProfile(paths)
+	if err != nil {
+		t.Fatalf("load profile: %v", err)
+	}
+	if profile.ValidateCmd != "echo tampered" {
+		t.Fatalf("skip-verify should apply plugin as-is: %q", profile.ValidateCmd)
+	}
+}
+
+func TestRunSetupWizardOffersTelegramStep(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	if err := EnsureDefaultControlAssets(paths.ControlDir); err != nil {
+		t.Fatalf("ensure control assets: %v", err)
+	}
+	// accept every wizard default, confirm, then opt into telegram
+	answers := strings.Repeat("\n", 7) + "y\ny\nbot-answer\n"
+	var got string
+	opts := SetupWizardOptions{Telegram: func(reader *bufio.Reader, out io.Writer) error {
+		line, err := reader.ReadString('\n')
+		got = strings.TrimSpace(line)
+		return err
+	}}
+	var out strings.Builder
+	if err := RunSetupWizardWithOptions(paths, "/bin/true", "universal-default", opts, strings.NewReader(answers), &out); err != nil {
+		t.Fatalf("setup wizard: %v\n%s", err, out.String())
+	}
+	if got != "bot-answer" {
+		t.Fatalf("telegram step should read from the wizard reader, got %q\n%s", got, out.String())
+	}
+
+	// declining (or EOF) skips the step
+	called := false
+	opts.Telegram = func(*bufio.Reader, io.Writer) error {
+		called = true
+		return nil
+	}
+	out.Reset()
+	if err := RunSetupWizardWithOptions(paths, "/bin/true", "universal-default", opts, strings.NewReader(strings.Repeat("\n", 7)+"y\n"), &out); err != nil {
+		t.Fatalf("setup wizard: %v", err)
+	}
+	if called || !strings.Contains(out.String(), "- telegram: skipped") {
+		t.Fatalf("expected telegram step to be skipped, called=%t\n%s", called, out.String())
+	}
+}
@@ -16,6 +16,16 @@ func IsSupportedRole(role string) bool {
 	}
 }
 
+// roleAliases are the group tokens ParseRolesCSV accepts next to plain role names.
+var roleAliases = map[string][]string{
+	"all": RequiredAgentRoles,
+	"dev": {"developer", "qa"},
+}
+
+func validRoleTokens() []string {
+	return append(append([]string(nil), RequiredAgentRoles...), "all", "dev")
+}
+
 func ParseRolesCSV(raw string) (map[string]struct{}, error) {
 	trimmed := strings.TrimSpace(raw)
 	if trimmed == "" {
@@ -27,8 +37,14 @@ func ParseRolesCSV(raw string) (map[string]struct{}, error) {
 		if role == "" {
 			continue
 		}
+		if expanded, ok := roleAliases[strings.ToLower(role)]; ok {
+			for _, r := range expanded {
+				out[r] = struct{}{}
+			}
+			continue
+		}
 		if !IsSupportedRole(role) {
-			return nil, fmt.Errorf("unsupported role: %s", role)
+			return nil, fmt.Errorf("unsupported role: %s (valid: %s)", role, strings.Join(validRoleTokens(), ","))
 		}
 		out[role] = struct{}{}
 	}
@@ -0,0 +1,36 @@
+package ralph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRolesCSVExpandsAliases(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"developer,qa":   "developer,qa",
+		" planner , qa ": "planner,qa",
+		"all":            "manager,planner,developer,qa",
+		"ALL":            "manager,planner,developer,qa",
+		"dev":            "developer,qa",
+		"dev,manager":    "manager,developer,qa",
+	}
+	for raw, want := range cases {
+		got, err := ParseRolesCSV(raw)
+		if err != nil {
+			t.Fatalf("ParseRolesCSV(%q) failed: %v", raw, err)
+		}
+		if RoleSetCSV(got) != want {
+			t.Fatalf("ParseRolesCSV(%q) = %s, want %s", raw, RoleSetCSV(got), want)
+		}
+	}
+	if got, err := ParseRolesCSV(""); err != nil || got != nil {
+		t.Fatalf("empty roles should stay nil: got=%v err=%v", got, err)
+	}
+
+	_, err := ParseRolesCSV("developer,ops")
+	if err == nil || !strings.Contains(err.Error(), "unsupported role: ops") || !strings.Contains(err.Error(), "all,dev") {
+		t.Fatalf("unknown token should list valid tokens: %v", err)
+	}
+}
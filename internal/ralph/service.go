@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -15,6 +16,7 @@ type ServicePlatform string
 const (
 	ServicePlatformSystemd ServicePlatform = "systemd-user"
 	ServicePlatformLaunchd ServicePlatform = "launchd"
+	ServicePlatformWindows ServicePlatform = "windows-sc"
 )
 
 type ServiceInstallResult struct {
@@ -28,6 +30,7 @@ type ServiceInstallResult struct {
 type ServiceStatus struct {
 	Platform    ServicePlatform
 	ServiceName string
+	UnitPath    string
 	Installed   bool
 	Active      bool
 	Detail      string
@@ -39,6 +42,8 @@ func DetectServicePlatform() (ServicePlatform, error) {
 		return ServicePlatformSystemd, nil
 	case "darwin":
 		return ServicePlatformLaunchd, nil
+	case "windows":
+		return ServicePlatformWindows, nil
 	default:
 		return "", fmt.Errorf("unsupported platform: %s", runtime.GOOS)
 	}
@@ -68,6 +73,8 @@ func InstallService(paths Paths, executablePath, serviceName string, activate bo
 		return installSystemdUserService(paths, executablePath, serviceName, activate)
 	case ServicePlatformLaunchd:
 		return installLaunchdService(paths, executablePath, serviceName, activate)
+	case ServicePlatformWindows:
+		return installWindowsService(paths, executablePath, serviceName, activate)
 	default:
 		return ServiceInstallResult{}, fmt.Errorf("unsupported service platform: %s", platform)
 	}
@@ -85,6 +92,8 @@ func UninstallService(paths Paths, serviceName string) (ServiceInstallResult, er
 		return uninstallSystemdUserService(paths, serviceName)
 	case ServicePlatformLaunchd:
 		return uninstallLaunchdService(paths, serviceName)
+	case ServicePlatformWindows:
+		return uninstallWindowsService(serviceName)
 	default:
 		return ServiceInstallResult{}, fmt.Errorf("unsupported service platform: %s", platform)
 	}
@@ -102,11 +111,104 @@ func GetServiceStatus(paths Paths, serviceName string) (ServiceStatus, error) {
 		return getSystemdUserServiceStatus(serviceName)
 	case ServicePlatformLaunchd:
 		return getLaunchdServiceStatus(serviceName)
+	case ServicePlatformWindows:
+		return getWindowsServiceStatus(serviceName)
 	default:
 		return ServiceStatus{}, fmt.Errorf("unsupported service platform: %s", platform)
 	}
 }
 
+// ListServiceStatuses reports every installed ralph service on this host,
+// found by scanning the platform unit directory for the ralph prefix.
+func ListServiceStatuses() ([]ServiceStatus, error) {
+	platform, err := DetectServicePlatform()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		unitPath string
+		pattern  string
+		status   func(name string) (ServiceStatus, error)
+	)
+	switch platform {
+	case ServicePlatformSystemd:
+		unitPath, err = DefaultLinuxServicePath("ralph-")
+		pattern = "ralph-*.service"
+		status = getSystemdUserServiceStatus
+	case ServicePlatformLaunchd:
+		unitPath, err = DefaultDarwinServicePath("io.ralph.")
+		pattern = "io.ralph.*.plist"
+		status = getLaunchdServiceStatus
+	case ServicePlatformWindows:
+		return listWindowsServiceStatuses()
+	default:
+		return nil, fmt.Errorf("unsupported service platform: %s", platform)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(unitPath), pattern))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	out := make([]ServiceStatus, 0, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(file), ".service"), ".plist")
+		st, err := status(name)
+		if err != nil {
+			return out, fmt.Errorf("service %s: %w", name, err)
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// FindProjectService returns the installed ralph service that runs paths'
+// project, whatever name it was installed under. Unit files are matched on
+// their working directory; Windows services only by the default name.
+func FindProjectService(paths Paths) (ServiceStatus, bool, error) {
+	platform, err := DetectServicePlatform()
+	if err != nil {
+		return ServiceStatus{}, false, nil
+	}
+	services, err := ListServiceStatuses()
+	if err != nil {
+		return ServiceStatus{}, false, err
+	}
+	for _, st := range services {
+		if st.UnitPath == "" {
+			if st.ServiceName == DefaultServiceName(paths.ProjectDir) {
+				return st, true, nil
+			}
+			continue
+		}
+		data, err := os.ReadFile(st.UnitPath)
+		if err != nil {
+			continue
+		}
+		content := string(data)
+		switch platform {
+		case ServicePlatformSystemd:
+			if strings.Contains(content, "\nWorkingDirectory="+systemdEscape(paths.ProjectDir)+"\n") {
+				return st, true, nil
+			}
+		case ServicePlatformLaunchd:
+			if strings.Contains(content, "<key>WorkingDirectory</key>\n  <string>"+xmlEscape(paths.ProjectDir)+"</string>") {
+				return st, true, nil
+			}
+		}
+	}
+	return ServiceStatus{}, false, nil
+}
+
+// installedServiceName is the name Install/UninstallService take for st.
+func installedServiceName(st ServiceStatus) string {
+	return strings.TrimPrefix(st.ServiceName, "io.ralph.")
+}
+
 func normalizeServiceName(serviceName, projectDir string) string {
 	name := sanitizeServiceToken(strings.TrimSpace(serviceName))
 	if name == "" {
@@ -228,6 +330,7 @@ func getSystemdUserServiceStatus(serviceName string) (ServiceStatus, error) {
 	st := ServiceStatus{
 		Platform:    ServicePlatformSystemd,
 		ServiceName: serviceName,
+		UnitPath:    unitPath,
 	}
 	if _, err := os.Stat(unitPath); err != nil {
 		if os.IsNotExist(err) {
@@ -350,6 +453,7 @@ func getLaunchdServiceStatus(serviceName string) (ServiceStatus, error) {
 	st := ServiceStatus{
 		Platform:    ServicePlatformLaunchd,
 		ServiceName: label,
+		UnitPath:    plistPath,
 	}
 	if _, err := os.Stat(plistPath); err != nil {
 		if os.IsNotExist(err) {
@@ -368,20 +472,159 @@ func getLaunchdServiceStatus(serviceName string) (ServiceStatus, error) {
 	return st, nil
 }
 
+func installWindowsService(paths Paths, executablePath, serviceName string, activate bool) (ServiceInstallResult, error) {
+	result := ServiceInstallResult{
+		Platform:    ServicePlatformWindows,
+		ServiceName: serviceName,
+	}
+	startMode := "demand"
+	if activate {
+		startMode = "auto"
+	}
+	if err := runCommand("sc.exe", windowsServiceCreateArgs(paths, executablePath, serviceName, startMode)...); err != nil {
+		return result, fmt.Errorf("sc create: %w", err)
+	}
+	if !activate {
+		return result, nil
+	}
+	if err := runCommand("sc.exe", "start", serviceName); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("sc start failed: %v", err))
+		return result, nil
+	}
+	result.Activated = true
+	return result, nil
+}
+
+// sc.exe expects each option as "key=" followed by its value as a separate argument.
+func windowsServiceCreateArgs(paths Paths, executablePath, serviceName, startMode string) []string {
+	binPath := strings.Join([]string{
+		windowsQuoteArg(executablePath),
+		"--control-dir", windowsQuoteArg(paths.ControlDir),
+		"--project-dir", windowsQuoteArg(paths.ProjectDir),
+		"supervise",
+		"--windows-service", windowsQuoteArg(serviceName),
+	}, " ")
+	return []string{
+		"create", serviceName,
+		"binPath=", binPath,
+		"start=", startMode,
+		"DisplayName=", fmt.Sprintf("Ralph Autonomous Loop (%s)", serviceName),
+	}
+}
+
+func uninstallWindowsService(serviceName string) (ServiceInstallResult, error) {
+	result := ServiceInstallResult{
+		Platform:    ServicePlatformWindows,
+		ServiceName: serviceName,
+	}
+	if err := runCommand("sc.exe", "stop", serviceName); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("sc stop failed: %v", err))
+	}
+	if err := runCommand("sc.exe", "delete", serviceName); err != nil {
+		return result, fmt.Errorf("sc delete: %w", err)
+	}
+	return result, nil
+}
+
+func getWindowsServiceStatus(serviceName string) (ServiceStatus, error) {
+	st := ServiceStatus{
+		Platform:    ServicePlatformWindows,
+		ServiceName: serviceName,
+	}
+	output, err := runCommandOutput("sc.exe", "query", serviceName)
+	if err != nil {
+		// 1060: ERROR_SERVICE_DOES_NOT_EXIST
+		if strings.Contains(output, "1060") {
+			st.Detail = "service not installed"
+			return st, nil
+		}
+		return st, fmt.Errorf("sc query: %w", err)
+	}
+	st.Installed = true
+	state := parseWindowsServiceState(output)
+	st.Active = state == "RUNNING"
+	st.Detail = strings.ToLower(state)
+	if st.Detail == "" {
+		st.Detail = "unknown"
+	}
+	return st, nil
+}
+
+func listWindowsServiceStatuses() ([]ServiceStatus, error) {
+	output, err := runCommandOutput("sc.exe", "query", "type=", "service", "state=", "all")
+	if err != nil {
+		return nil, fmt.Errorf("sc query: %w", err)
+	}
+	names := parseWindowsServiceNames(output, "ralph-")
+	out := make([]ServiceStatus, 0, len(names))
+	for _, name := range names {
+		st, err := getWindowsServiceStatus(name)
+		if err != nil {
+			return out, fmt.Errorf("service %s: %w", name, err)
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// parseWindowsServiceState extracts the state name from `sc query` output,
+// e.g. "STATE              : 4  RUNNING" -> "RUNNING".
+func parseWindowsServiceState(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || strings.TrimSpace(key) != "STATE" {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) >= 2 {
+			return strings.ToUpper(fields[1])
+		}
+	}
+	return ""
+}
+
+func parseWindowsServiceNames(output, prefix string) []string {
+	names := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok || strings.TrimSpace(key) != "SERVICE_NAME" {
+			continue
+		}
+		name := strings.TrimSpace(value)
+		if strings.HasPrefix(strings.ToLower(name), prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func windowsQuoteArg(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\"") {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
 func runCommand(name string, args ...string) error {
+	_, err := runCommandOutput(name, args...)
+	return err
+}
+
+func runCommandOutput(name string, args ...string) (string, error) {
 	if _, err := exec.LookPath(name); err != nil {
-		return fmt.Errorf("%s not found", name)
+		return "", fmt.Errorf("%s not found", name)
 	}
 	cmd := exec.Command(name, args...)
 	output, err := cmd.CombinedOutput()
+	text := strings.TrimSpace(string(output))
 	if err == nil {
-		return nil
+		return text, nil
 	}
-	text := strings.TrimSpace(string(output))
 	if text == "" {
-		return err
+		return text, err
 	}
-	return fmt.Errorf("%v: %s", err, text)
+	return text, fmt.Errorf("%v: %s", err, text)
 }
 
 func systemdEscape(value string) string {
@@ -421,6 +664,8 @@ func ServiceInstallHint(platform ServicePlatform) string {
 	case ServicePlatformLaunchd:
 		uid := strconv.Itoa(os.Getuid())
 		return "manage with: launchctl list|kickstart gui/" + uid + "/<label>"
+	case ServicePlatformWindows:
+		return "manage with: sc.exe query|start|stop <service> (run from an elevated prompt)"
 	default:
 		return ""
 	}
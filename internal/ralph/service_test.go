@@ -56,3 +56,75 @@ func TestInstallServiceWithoutActivate(t *testing.T) {
 		t.Fatalf("service file should be removed")
 	}
 }
+
+func TestListServiceStatusesFindsInstalledRalphUnits(t *testing.T) {
+	paths := newTestPaths(t)
+	t.Setenv("HOME", t.TempDir())
+
+	for _, name := range []string{"ralph-alpha", "ralph-beta"} {
+		if _, err := InstallService(paths, "/usr/local/bin/ralphctl", name, false); err != nil {
+			t.Fatalf("InstallService(%s) failed: %v", name, err)
+		}
+	}
+
+	services, err := ListServiceStatuses()
+	if err != nil {
+		t.Fatalf("ListServiceStatuses failed: %v", err)
+	}
+	if len(services) != 2 {
+		t.Fatalf("expected 2 services, got %d: %+v", len(services), services)
+	}
+	for _, st := range services {
+		if !st.Installed {
+			t.Fatalf("listed service should be installed: %+v", st)
+		}
+		if !strings.Contains(st.ServiceName, "alpha") && !strings.Contains(st.ServiceName, "beta") {
+			t.Fatalf("unexpected service name: %s", st.ServiceName)
+		}
+	}
+}
+
+func TestWindowsServiceCreateArgs(t *testing.T) {
+	t.Parallel()
+
+	paths := Paths{ControlDir: `C:\Users\dev\ralph`, ProjectDir: `C:\work\My Project`}
+	args := windowsServiceCreateArgs(paths, `C:\Program Files\ralph\ralphctl.exe`, "ralph-my-project", "auto")
+	if len(args) != 8 || args[0] != "create" || args[1] != "ralph-my-project" {
+		t.Fatalf("unexpected sc args: %q", args)
+	}
+	if args[2] != "binPath=" || args[4] != "start=" || args[5] != "auto" {
+		t.Fatalf("sc options should be split into key= and value: %q", args)
+	}
+	want := `"C:\Program Files\ralph\ralphctl.exe" --control-dir C:\Users\dev\ralph --project-dir "C:\work\My Project" supervise --windows-service ralph-my-project`
+	if args[3] != want {
+		t.Fatalf("binPath mismatch:\ngot=%s\nwant=%s", args[3], want)
+	}
+}
+
+func TestParseWindowsServiceQueryOutput(t *testing.T) {
+	t.Parallel()
+
+	query := `
+SERVICE_NAME: ralph-alpha
+        TYPE               : 10  WIN32_OWN_PROCESS
+        STATE              : 4  RUNNING
+                                (STOPPABLE, NOT_PAUSABLE, ACCEPTS_SHUTDOWN)
+        WIN32_EXIT_CODE    : 0  (0x0)
+`
+	if got := parseWindowsServiceState(query); got != "RUNNING" {
+		t.Fatalf("state mismatch: got=%q", got)
+	}
+
+	list := `
+SERVICE_NAME: Spooler
+DISPLAY_NAME: Print Spooler
+SERVICE_NAME: ralph-beta
+DISPLAY_NAME: Ralph Autonomous Loop (ralph-beta)
+SERVICE_NAME: ralph-alpha
+DISPLAY_NAME: Ralph Autonomous Loop (ralph-alpha)
+`
+	names := parseWindowsServiceNames(list, "ralph-")
+	if strings.Join(names, ",") != "ralph-alpha,ralph-beta" {
+		t.Fatalf("service names mismatch: %v", names)
+	}
+}
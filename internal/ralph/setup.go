@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -29,8 +30,13 @@ type SetupSelections struct {
 	DoctorAutoRepair bool
 	ValidationMode   SetupMode
 	ValidateCmd      string
+	// RoleModels maps a role to its codex model override. Roles not present are
+	// left untouched; an empty value removes the override so the role inherits codex_model.
+	RoleModels map[string]string
 }
 
+const setupRoleModelInherit = "inherit"
+
 func DefaultSetupSelections(preferredPlugin string) SetupSelections {
 	return SetupSelections{
 		Plugin:           strings.TrimSpace(preferredPlugin),
@@ -42,7 +48,87 @@ func DefaultSetupSelections(preferredPlugin string) SetupSelections {
 	}
 }
 
+// SetupSelectionsFromProject reads another managed project's effective profile
+// (same control dir) as setup selections, so a new project can start from it.
+func SetupSelectionsFromProject(controlDir, sourceProjectDir string) (SetupSelections, error) {
+	sourcePaths, err := NewPaths(controlDir, sourceProjectDir)
+	if err != nil {
+		return SetupSelections{}, err
+	}
+	if _, err := os.Stat(sourcePaths.ProfileYAMLFile); err != nil {
+		if os.IsNotExist(err) {
+			return SetupSelections{}, fmt.Errorf("not a managed ralph project (missing %s)", sourcePaths.ProfileYAMLFile)
+		}
+		return SetupSelections{}, err
+	}
+	return setupSelectionsFromPaths(sourcePaths)
+}
+
+func setupSelectionsFromPaths(paths Paths) (SetupSelections, error) {
+	profile, err := LoadProfile(paths)
+	if err != nil {
+		return SetupSelections{}, err
+	}
+	overrides := map[string]string{}
+	if _, err := os.Stat(paths.ProfileLocalYAMLFile); err == nil {
+		overrides, err = ReadYAMLFlatMap(paths.ProfileLocalYAMLFile)
+		if err != nil {
+			return SetupSelections{}, fmt.Errorf("read profile.local.yaml: %w", err)
+		}
+	}
+	return setupSelectionsFromProfile(profile, overrides), nil
+}
+
+// setupSelectionsFromProfile maps an effective profile back onto the setup choices.
+// A validate_cmd set in profile.local.yaml is custom (or skip); otherwise the plugin default.
+func setupSelectionsFromProfile(profile Profile, localOverrides map[string]string) SetupSelections {
+	selections := SetupSelections{
+		Plugin:           strings.TrimSpace(profile.PluginName),
+		RoleRulesEnabled: profile.RoleRulesEnabled,
+		HandoffRequired:  profile.HandoffRequired,
+		HandoffSchema:    normalizeHandoffSchema(profile.HandoffSchema),
+		DoctorAutoRepair: profile.BusyWaitDoctorRepairEnabled,
+		ValidationMode:   SetupModePluginDefault,
+		RoleModels:       map[string]string{},
+	}
+	_, hasLocal := localOverrides["validate_cmd"]
+	_, hasLocalEnv := localOverrides["RALPH_VALIDATE_CMD"]
+	switch {
+	case strings.TrimSpace(profile.ValidateCmd) == validationSkipCommand:
+		selections.ValidationMode = SetupModeSkip
+		selections.ValidateCmd = validationSkipCommand
+	case hasLocal || hasLocalEnv:
+		selections.ValidationMode = SetupModeCustom
+		selections.ValidateCmd = strings.TrimSpace(profile.ValidateCmd)
+	}
+	for _, role := range RequiredAgentRoles {
+		selections.RoleModels[role] = profile.codexRoleModelOverride(role)
+	}
+	return selections
+}
+
 func RunSetupWizard(paths Paths, executablePath, preferredPlugin string, in io.Reader, out io.Writer) error {
+	return RunSetupWizardWithDefaults(paths, executablePath, preferredPlugin, nil, in, out)
+}
+
+// RunSetupWizardWithDefaults runs the wizard with seed as the prompt defaults
+// (nil uses the project's current profile).
+func RunSetupWizardWithDefaults(paths Paths, executablePath, preferredPlugin string, seed *SetupSelections, in io.Reader, out io.Writer) error {
+	return RunSetupWizardWithOptions(paths, executablePath, preferredPlugin, SetupWizardOptions{Seed: seed}, in, out)
+}
+
+// SetupTelegramStep configures the telegram bot from inside the wizard. It reads
+// from the wizard's reader so buffered answers stay in order.
+type SetupTelegramStep func(reader *bufio.Reader, out io.Writer) error
+
+type SetupWizardOptions struct {
+	Seed *SetupSelections
+	// Telegram, when set, is offered as an optional step once the loop settings are applied.
+	Telegram SetupTelegramStep
+}
+
+func RunSetupWizardWithOptions(paths Paths, executablePath, preferredPlugin string, opts SetupWizardOptions, in io.Reader, out io.Writer) error {
+	seed := opts.Seed
 	if err := EnsureLayout(paths); err != nil {
 		return err
 	}
@@ -51,6 +137,14 @@ func RunSetupWizard(paths Paths, executablePath, preferredPlugin string, in io.R
 	if err != nil {
 		return err
 	}
+	current, err := setupSelectionsFromPaths(paths)
+	if err != nil {
+		return err
+	}
+	defaults := current
+	if seed != nil {
+		defaults = *seed
+	}
 	plugins, err := ListPlugins(paths.ControlDir)
 	if err != nil {
 		return err
@@ -65,7 +159,7 @@ func RunSetupWizard(paths Paths, executablePath, preferredPlugin string, in io.R
 	fmt.Fprintf(out, "- control_dir: %s\n\n", paths.ControlDir)
 	fmt.Fprintf(out, "- codex: %s\n\n", codexConnectionSummary())
 
-	pluginDefault := pickDefaultPlugin(plugins, profile.PluginName)
+	pluginDefault := pickDefaultPlugin(plugins, defaults.Plugin)
 	if preferred := strings.TrimSpace(preferredPlugin); preferred != "" && containsString(plugins, preferred) {
 		pluginDefault = preferred
 	}
@@ -74,28 +168,74 @@ func RunSetupWizard(paths Paths, executablePath, preferredPlugin string, in io.R
 		return err
 	}
 
-	roleRulesEnabled, err := promptBool(reader, out, "Enable role rule files?", profile.RoleRulesEnabled)
+	roleRulesEnabled, err := promptBool(reader, out, "Enable role rule files?", defaults.RoleRulesEnabled)
 	if err != nil {
 		return err
 	}
-	handoffRequired, err := promptBool(reader, out, "Require handoff JSON for completion?", profile.HandoffRequired)
+	handoffRequired, err := promptBool(reader, out, "Require handoff JSON for completion?", defaults.HandoffRequired)
 	if err != nil {
 		return err
 	}
-	handoffSchema, err := promptChoice(reader, out, "Handoff schema", []string{"universal", "strict"}, normalizeHandoffSchema(profile.HandoffSchema))
+	handoffSchema, err := promptChoice(reader, out, "Handoff schema", []string{"universal", "strict"}, normalizeHandoffSchema(defaults.HandoffSchema))
 	if err != nil {
 		return err
 	}
-	doctorAutoRepair, err := promptBool(reader, out, "Enable busy-wait auto doctor repair?", profile.BusyWaitDoctorRepairEnabled)
+	doctorAutoRepair, err := promptBool(reader, out, "Enable busy-wait auto doctor repair?", defaults.DoctorAutoRepair)
+	if err != nil {
+		return err
+	}
+
+	var roleModels map[string]string
+	hasRoleModels := false
+	currentHasRoleModels := false
+	for _, role := range RequiredAgentRoles {
+		if defaults.RoleModels[role] != "" {
+			hasRoleModels = true
+		}
+		if current.RoleModels[role] != "" {
+			currentHasRoleModels = true
+		}
+	}
+	distinctModels, err := promptBool(reader, out, "Use distinct codex models per role?", hasRoleModels)
 	if err != nil {
 		return err
 	}
+	if distinctModels {
+		roleModels = map[string]string{}
+		for _, role := range RequiredAgentRoles {
+			def := defaults.RoleModels[role]
+			if def == "" {
+				def = setupRoleModelInherit
+			}
+			model, err := promptInput(reader, out, fmt.Sprintf("Codex model for %s (%s=use codex_model)", role, setupRoleModelInherit), def)
+			if err != nil {
+				return err
+			}
+			model = strings.TrimSpace(model)
+			if strings.EqualFold(model, setupRoleModelInherit) {
+				model = ""
+			}
+			roleModels[role] = model
+		}
+	} else if currentHasRoleModels {
+		roleModels = map[string]string{}
+		for _, role := range RequiredAgentRoles {
+			roleModels[role] = ""
+		}
+	}
 
 	fmt.Fprintln(out, "\nValidation mode")
 	fmt.Fprintln(out, "1) plugin-default")
 	fmt.Fprintln(out, "2) skip (quick setup)")
 	fmt.Fprintln(out, "3) custom command")
-	modeInput, err := promptInput(reader, out, "Choose", "1")
+	modeDefault := "1"
+	switch defaults.ValidationMode {
+	case SetupModeSkip:
+		modeDefault = "2"
+	case SetupModeCustom:
+		modeDefault = "3"
+	}
+	modeInput, err := promptInput(reader, out, "Choose", modeDefault)
 	if err != nil {
 		return err
 	}
@@ -107,7 +247,11 @@ func RunSetupWizard(paths Paths, executablePath, preferredPlugin string, in io.R
 		validateCmd = validationSkipCommand
 	case "3":
 		mode = SetupModeCustom
-		cmd, err := promptInput(reader, out, "Validation command", profile.ValidateCmd)
+		cmdDefault := profile.ValidateCmd
+		if defaults.ValidationMode == SetupModeCustom {
+			cmdDefault = defaults.ValidateCmd
+		}
+		cmd, err := promptInput(reader, out, "Validation command", cmdDefault)
 		if err != nil {
 			return err
 		}
@@ -128,8 +272,48 @@ func RunSetupWizard(paths Paths, executablePath, preferredPlugin string, in io.R
 		DoctorAutoRepair: doctorAutoRepair,
 		ValidationMode:   mode,
 		ValidateCmd:      validateCmd,
+		RoleModels:       roleModels,
 	}
 
+	WriteSetupSummary(out, selections)
+
+	confirm, err := promptBool(reader, out, "Apply these settings?", true)
+	if err != nil {
+		return err
+	}
+	if !confirm {
+		return fmt.Errorf("setup canceled")
+	}
+
+	if err := ApplySetupSelections(paths, executablePath, selections); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "\nsetup complete")
+	fmt.Fprintf(out, "- helper: %s\n", filepath.Join(paths.ProjectDir, "ralph"))
+	fmt.Fprintf(out, "- profile_yaml: %s\n", paths.ProfileYAMLFile)
+	fmt.Fprintf(out, "- profile_local_yaml: %s\n", paths.ProfileLocalYAMLFile)
+	fmt.Fprintf(out, "- profile_env_override: %s\n", paths.ProfileLocalFile)
+
+	if opts.Telegram == nil {
+		return nil
+	}
+	fmt.Fprintln(out)
+	configureTelegram, err := promptBool(reader, out, "Configure Telegram bot now?", false)
+	if err != nil {
+		return err
+	}
+	if !configureTelegram {
+		fmt.Fprintln(out, "- telegram: skipped (later: ralphctl telegram setup)")
+		return nil
+	}
+	// The loop settings are already applied, so a bad answer here should not fail setup.
+	if err := opts.Telegram(reader, out); err != nil {
+		fmt.Fprintf(out, "- telegram: not configured: %v (later: ralphctl telegram setup)\n", err)
+	}
+	return nil
+}
+
+func WriteSetupSummary(out io.Writer, selections SetupSelections) {
 	fmt.Fprintln(out, "\n## Setup Summary")
 	fmt.Fprintf(out, "- plugin: %s\n", selections.Plugin)
 	fmt.Fprintf(out, "- role_rules_enabled: %t\n", selections.RoleRulesEnabled)
@@ -144,23 +328,29 @@ func RunSetupWizard(paths Paths, executablePath, preferredPlugin string, in io.R
 	case SetupModeCustom:
 		fmt.Fprintf(out, "- validate_cmd: %s\n", selections.ValidateCmd)
 	}
+	for _, role := range RequiredAgentRoles {
+		model, ok := selections.RoleModels[role]
+		if !ok {
+			continue
+		}
+		if model == "" {
+			model = "(inherit codex_model)"
+		}
+		fmt.Fprintf(out, "- codex_model_%s: %s\n", role, model)
+	}
+}
 
-	confirm, err := promptBool(reader, out, "Apply these settings?", true)
+// ConfirmSetupSelections prints the summary and asks before applying; a "no"
+// answer returns the same "setup canceled" error as the wizard.
+func ConfirmSetupSelections(selections SetupSelections, in io.Reader, out io.Writer) error {
+	WriteSetupSummary(out, selections)
+	confirm, err := promptBool(bufio.NewReader(in), out, "Apply these settings?", true)
 	if err != nil {
 		return err
 	}
 	if !confirm {
 		return fmt.Errorf("setup canceled")
 	}
-
-	if err := ApplySetupSelections(paths, executablePath, selections); err != nil {
-		return err
-	}
-	fmt.Fprintln(out, "\nsetup complete")
-	fmt.Fprintf(out, "- helper: %s\n", filepath.Join(paths.ProjectDir, "ralph"))
-	fmt.Fprintf(out, "- profile_yaml: %s\n", paths.ProfileYAMLFile)
-	fmt.Fprintf(out, "- profile_local_yaml: %s\n", paths.ProfileLocalYAMLFile)
-	fmt.Fprintf(out, "- profile_env_override: %s\n", paths.ProfileLocalFile)
 	return nil
 }
 
@@ -220,6 +410,20 @@ func ApplySetupSelections(paths Paths, executablePath string, selections SetupSe
 		setProfileConfigValue(existing, "validate_cmd", cmd, "RALPH_VALIDATE_CMD")
 	}
 
+	for role, model := range selections.RoleModels {
+		if !IsSupportedRole(role) {
+			return fmt.Errorf("unsupported role for codex model: %s", role)
+		}
+		key := "codex_model_" + role
+		model = strings.TrimSpace(model)
+		if model == "" {
+			delete(existing, key)
+			delete(existing, profileConfigEnvKey(key))
+			continue
+		}
+		setProfileConfigValue(existing, key, model, profileConfigEnvKey(key))
+	}
+
 	if err := WriteYAMLFlatMap(paths.ProfileLocalYAMLFile, existing); err != nil {
 		return fmt.Errorf("write profile.local.yaml: %w", err)
 	}
@@ -273,6 +477,114 @@ func ApplyStabilityDefaults(paths Paths) error {
 	return pruneLegacySetupEnvOverrides(paths.ProfileLocalFile)
 }
 
+// SetProfileValues validates key/value pairs against the known profile keys and
+// writes them to profile.local.yaml. The file is restored if the result no longer loads.
+func SetProfileValues(paths Paths, values map[string]string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("at least one key=value pair is required")
+	}
+	defaults := profileSettableDefaults()
+	applied := map[string]string{}
+	for rawKey, rawValue := range values {
+		key := normalizeConfigKey(rawKey)
+		def, ok := defaults[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile key: %s (valid keys: %s)", rawKey, strings.Join(ProfileSettableKeys(), ", "))
+		}
+		value, err := normalizeProfileSetValue(key, def, rawValue)
+		if err != nil {
+			return nil, err
+		}
+		applied[key] = value
+	}
+
+	if err := EnsureLayout(paths); err != nil {
+		return nil, err
+	}
+	existing := map[string]string{}
+	previous, err := os.ReadFile(paths.ProfileLocalYAMLFile)
+	hadPrevious := err == nil
+	if hadPrevious {
+		m, readErr := ReadYAMLFlatMap(paths.ProfileLocalYAMLFile)
+		if readErr != nil {
+			return nil, fmt.Errorf("read profile.local.yaml: %w", readErr)
+		}
+		existing = m
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read profile.local.yaml: %w", err)
+	}
+
+	for key, value := range applied {
+		setProfileConfigValue(existing, key, value, profileConfigEnvKey(key))
+	}
+	if err := WriteYAMLFlatMap(paths.ProfileLocalYAMLFile, existing); err != nil {
+		return nil, fmt.Errorf("write profile.local.yaml: %w", err)
+	}
+	if _, err := LoadProfile(paths); err != nil {
+		if hadPrevious {
+			_ = os.WriteFile(paths.ProfileLocalYAMLFile, previous, 0o644)
+		} else {
+			_ = os.Remove(paths.ProfileLocalYAMLFile)
+		}
+		return nil, fmt.Errorf("reload profile after update: %w", err)
+	}
+	return applied, nil
+}
+
+// ProfileSettableKeys returns the sorted yaml keys accepted by SetProfileValues.
+func ProfileSettableKeys() []string {
+	defaults := profileSettableDefaults()
+	keys := make([]string, 0, len(defaults))
+	for key := range defaults {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func profileSettableDefaults() map[string]string {
+	defaults := ProfileToYAMLMap(DefaultProfile())
+	if _, ok := defaults["codex_home"]; !ok {
+		defaults["codex_home"] = ""
+	}
+	for _, role := range RequiredAgentRoles {
+		if _, ok := defaults["codex_model_"+role]; !ok {
+			defaults["codex_model_"+role] = ""
+		}
+	}
+	return defaults
+}
+
+func normalizeProfileSetValue(key, defaultValue, raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	switch {
+	case key == "validate_roles":
+		roles, err := ParseRolesCSV(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		return RoleSetCSV(roles), nil
+	case key == "handoff_schema":
+		lower := strings.ToLower(value)
+		if lower != "strict" && lower != "universal" {
+			return "", fmt.Errorf("invalid value for %s: %q (expected strict|universal)", key, raw)
+		}
+		return lower, nil
+	case defaultValue == "true" || defaultValue == "false":
+		v, ok := parseBool(value)
+		if !ok {
+			return "", fmt.Errorf("invalid value for %s: %q (expected bool)", key, raw)
+		}
+		return boolToEnv(v), nil
+	}
+	if _, err := strconv.Atoi(defaultValue); err == nil {
+		if _, ok := parseInt(value); !ok {
+			return "", fmt.Errorf("invalid value for %s: %q (expected integer)", key, raw)
+		}
+	}
+	return value, nil
+}
+
 // ApplyRemoteProfilePreset is kept for backward compatibility.
 func ApplyRemoteProfilePreset(paths Paths) error {
 	return ApplyStabilityDefaults(paths)
@@ -5,6 +5,14 @@ import (
 	"os"
 )
 
+// StateSchemaVersion is bumped whenever a persisted state file gains keys, so
+// MigrateState knows to rewrite older .ralph/state*.env files with defaults.
+const StateSchemaVersion = 1
+
+func stateFileContent(enabled bool, schemaVersion int) string {
+	return fmt.Sprintf("RALPH_LOCAL_ENABLED=%t\nRALPH_STATE_SCHEMA_VERSION=%d\n", enabled, schemaVersion)
+}
+
 func IsEnabled(paths Paths) (bool, error) {
 	m, err := ReadEnvFile(paths.StateFile)
 	if err != nil {
@@ -20,13 +28,36 @@ func IsEnabled(paths Paths) (bool, error) {
 	return v, nil
 }
 
+// LoadStateSchemaVersion returns the schema version recorded in state.env.
+// A state file written before versioning existed reports 0.
+func LoadStateSchemaVersion(paths Paths) (int, error) {
+	m, err := ReadEnvFile(paths.StateFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return StateSchemaVersion, nil
+		}
+		return 0, fmt.Errorf("read state file: %w", err)
+	}
+	v, ok := parseInt(m["RALPH_STATE_SCHEMA_VERSION"])
+	if !ok || v < 0 {
+		return 0, nil
+	}
+	return v, nil
+}
+
+// SetEnabled toggles RALPH_LOCAL_ENABLED and keeps the recorded schema version,
+// so an old state file still reads as needing MigrateState after `on`/`off`.
 func SetEnabled(paths Paths, enabled bool) error {
 	if err := EnsureLayout(paths); err != nil {
 		return err
 	}
-	value := "false"
-	if enabled {
-		value = "true"
+	version, err := LoadStateSchemaVersion(paths)
+	if err != nil {
+		return err
 	}
-	return os.WriteFile(paths.StateFile, []byte("RALPH_LOCAL_ENABLED="+value+"\n"), 0o644)
+	return writeStateFile(paths, enabled, version)
+}
+
+func writeStateFile(paths Paths, enabled bool, schemaVersion int) error {
+	return os.WriteFile(paths.StateFile, []byte(stateFileContent(enabled, schemaVersion)), 0o644)
 }
@@ -0,0 +1,92 @@
+package ralph
+
+import (
+	"fmt"
+	"os"
+)
+
+type StateMigrationResult struct {
+	FromVersion int      `json:"from_version"`
+	ToVersion   int      `json:"to_version"`
+	Migrated    bool     `json:"migrated"`
+	Files       []string `json:"files,omitempty"`
+}
+
+// MigrateState rewrites state files recorded under an older StateSchemaVersion.
+// Each file is round-tripped through its loader and saver, which fills in any key
+// a newer build added with its default. Newer or current schemas are left alone.
+// It runs from `ralphctl migrate` and loop startup only: read-only commands such as
+// status must not rewrite files that running daemons own.
+func MigrateState(paths Paths) (StateMigrationResult, error) {
+	if err := EnsureLayout(paths); err != nil {
+		return StateMigrationResult{}, err
+	}
+	from, err := LoadStateSchemaVersion(paths)
+	if err != nil {
+		return StateMigrationResult{}, err
+	}
+	res := StateMigrationResult{FromVersion: from, ToVersion: StateSchemaVersion}
+	if from >= StateSchemaVersion {
+		res.ToVersion = from
+		return res, nil
+	}
+
+	type stateRewrite struct {
+		file    string
+		rewrite func() error
+	}
+	rewrites := []stateRewrite{
+		{paths.BusyWaitStateFile, func() error {
+			state, err := LoadBusyWaitState(paths)
+			if err != nil {
+				return err
+			}
+			return SaveBusyWaitState(paths, state)
+		}},
+		{paths.CodexCircuitStateFile, func() error {
+			state, err := LoadCodexCircuitState(paths)
+			if err != nil {
+				return err
+			}
+			return SaveCodexCircuitState(paths, state)
+		}},
+		{paths.ProfileReloadStateFile, func() error {
+			state, err := LoadProfileReloadState(paths)
+			if err != nil {
+				return err
+			}
+			return SaveProfileReloadState(paths, state)
+		}},
+	}
+	for _, scope := range supervisorScopes(paths) {
+		scope := scope
+		rewrites = append(rewrites, stateRewrite{paths.SupervisorStateFile(scope), func() error {
+			state, err := LoadSupervisorState(paths, scope)
+			if err != nil {
+				return err
+			}
+			return SaveSupervisorState(paths, scope, state)
+		}})
+	}
+	for _, rw := range rewrites {
+		if _, err := os.Stat(rw.file); err != nil {
+			continue
+		}
+		if err := rw.rewrite(); err != nil {
+			return res, fmt.Errorf("migrate %s: %w", rw.file, err)
+		}
+		res.Files = append(res.Files, rw.file)
+	}
+
+	// state.env goes last: its version is what marks the migration as done.
+	enabled, err := IsEnabled(paths)
+	if err != nil {
+		return res, err
+	}
+	if err := writeStateFile(paths, enabled, StateSchemaVersion); err != nil {
+		return res, fmt.Errorf("migrate %s: %w", paths.StateFile, err)
+	}
+	res.Files = append(res.Files, paths.StateFile)
+	res.Migrated = true
+	return res, nil
+}
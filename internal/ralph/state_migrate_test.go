@@ -0,0 +1,110 @@
+package ralph
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGetStatusLeavesUnversionedStateToMigrate(t *testing.T) {
+	resetProfileEnv(t)
+
+	paths := newTestPaths(t)
+	if err := os.WriteFile(paths.StateFile, []byte("RALPH_LOCAL_ENABLED=false\n"), 0o644); err != nil {
+		t.Fatalf("write legacy state: %v", err)
+	}
+	if err := os.WriteFile(paths.CodexCircuitStateFile, []byte("CONSECUTIVE_FAILURES=2\n"), 0o644); err != nil {
+		t.Fatalf("write legacy circuit state: %v", err)
+	}
+	roleSetState := paths.SupervisorStateFile("developer,qa")
+	if err := os.WriteFile(roleSetState, []byte("RESTART_COUNT=4\n"), 0o644); err != nil {
+		t.Fatalf("write legacy supervisor state: %v", err)
+	}
+	if v, err := LoadStateSchemaVersion(paths); err != nil || v != 0 {
+		t.Fatalf("legacy schema version: got=%d err=%v", v, err)
+	}
+
+	status, err := GetStatus(paths)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if status.Enabled {
+		t.Fatalf("status must read enabled=false")
+	}
+	if v, err := LoadStateSchemaVersion(paths); err != nil || v != 0 {
+		t.Fatalf("status must not migrate state: got=%d err=%v", v, err)
+	}
+	if circuit, _ := os.ReadFile(paths.CodexCircuitStateFile); string(circuit) != "CONSECUTIVE_FAILURES=2\n" {
+		t.Fatalf("status must not rewrite circuit state:\n%s", circuit)
+	}
+
+	res, err := MigrateState(paths)
+	if err != nil || !res.Migrated {
+		t.Fatalf("migrate: %+v err=%v", res, err)
+	}
+	if v, err := LoadStateSchemaVersion(paths); err != nil || v != StateSchemaVersion {
+		t.Fatalf("schema version after migrate: got=%d err=%v", v, err)
+	}
+	circuit, err := os.ReadFile(paths.CodexCircuitStateFile)
+	if err != nil {
+		t.Fatalf("read circuit state: %v", err)
+	}
+	if !strings.Contains(string(circuit), "CONSECUTIVE_FAILURES=2") || !strings.Contains(string(circuit), "OPEN_UNTIL=") {
+		t.Fatalf("circuit state should keep values and gain defaults:\n%s", circuit)
+	}
+	supervisor, err := os.ReadFile(roleSetState)
+	if err != nil || !strings.Contains(string(supervisor), "RESTART_COUNT=4") || !strings.Contains(string(supervisor), "LAST_CRASH_LOOP_AT=") {
+		t.Fatalf("role set supervisor state should be migrated:\n%s err=%v", supervisor, err)
+	}
+	if _, err := os.Stat(paths.BusyWaitStateFile); !os.IsNotExist(err) {
+		t.Fatalf("migration should not create absent state files: %v", err)
+	}
+
+	res, err = MigrateState(paths)
+	if err != nil {
+		t.Fatalf("migrate again: %v", err)
+	}
+	if res.Migrated || res.FromVersion != StateSchemaVersion {
+		t.Fatalf("second migration should be a no-op: %+v", res)
+	}
+}
+
+func TestSetEnabledKeepsOldSchemaVersionForMigrate(t *testing.T) {
+	resetProfileEnv(t)
+
+	paths := newTestPaths(t)
+	if err := os.WriteFile(paths.StateFile, []byte("RALPH_LOCAL_ENABLED=false\n"), 0o644); err != nil {
+		t.Fatalf("write legacy state: %v", err)
+	}
+	if err := SetEnabled(paths, true); err != nil {
+		t.Fatalf("set enabled: %v", err)
+	}
+	if v, err := LoadStateSchemaVersion(paths); err != nil || v != 0 {
+		t.Fatalf("on must keep the legacy schema version: got=%d err=%v", v, err)
+	}
+
+	status, err := GetStatus(paths)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if !status.Enabled || !status.StateMigrationNeeded || status.StateSchemaVersion != 0 {
+		t.Fatalf("status should report enabled legacy state: %+v", status)
+	}
+	var out bytes.Buffer
+	status.Print(&out)
+	if !strings.Contains(out.String(), "run `ralphctl migrate`") {
+		t.Fatalf("status should show the schema mismatch:\n%s", out.String())
+	}
+
+	if _, err := MigrateState(paths); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	status, err = GetStatus(paths)
+	if err != nil {
+		t.Fatalf("get status after migrate: %v", err)
+	}
+	if !status.Enabled || status.StateMigrationNeeded {
+		t.Fatalf("migrated state should be current and keep enabled: %+v", status)
+	}
+}
@@ -15,35 +15,54 @@ import (
 )
 
 type Status struct {
-	UpdatedUTC             time.Time
-	ProjectDir             string
-	PluginName             string
-	Enabled                bool
-	Daemon                 string
-	DaemonRoles            []string
-	QueueState             string
-	CodexCircuitState      string
-	CodexCircuitOpenUntil  string
-	CodexCircuitFailures   int
-	QueueReady             int
-	InProgress             int
-	Done                   int
-	Blocked                int
-	NextReady              string
-	LastBusyWaitDetectedAt string
-	LastBusyWaitIdleCount  int
-	LastSelfHealAt         string
-	SelfHealAttempts       int
-	LastSelfHealResult     string
-	LastSelfHealError      string
-	LastProfileReloadAt    string
-	ProfileReloadCount     int
-	LastFailureCause       string
-	LastFailureUpdatedAt   string
-	LastCodexRetryCount    int
-	LastPermissionStreak   int
+	UpdatedUTC             time.Time        `json:"updated_utc"`
+	ProjectDir             string           `json:"project_dir"`
+	PluginName             string           `json:"plugin_name"`
+	Enabled                bool             `json:"enabled"`
+	StateSchemaVersion     int              `json:"state_schema_version"`
+	StateMigrationNeeded   bool             `json:"state_migration_needed"`
+	Daemon                 string           `json:"daemon"`
+	DaemonRoles            []string         `json:"daemon_roles"`
+	QueueState             string           `json:"queue_state"`
+	CodexCircuitState      string           `json:"codex_circuit_state"`
+	CodexCircuitOpenUntil  string           `json:"codex_circuit_open_until"`
+	CodexCircuitFailures   int              `json:"codex_circuit_failures"`
+	QueueReady             int              `json:"queue_ready"`
+	InProgress             int              `json:"in_progress"`
+	Done                   int              `json:"done"`
+	Blocked                int              `json:"blocked"`
+	DeadLetter             int              `json:"dead_letter"`
+	ScopedOutCount         int              `json:"scoped_out_count"`
+	BlockedByAssignee      map[string]int   `json:"blocked_by_assignee,omitempty"`
+	NextReady              string           `json:"next_ready"`
+	LastBusyWaitDetectedAt string           `json:"last_busywait_detected_at"`
+	LastBusyWaitIdleCount  int              `json:"last_busywait_idle_count"`
+	LastSelfHealAt         string           `json:"last_self_heal_at"`
+	SelfHealAttempts       int              `json:"self_heal_attempts"`
+	LastSelfHealResult     string           `json:"last_self_heal_result"`
+	LastSelfHealError      string           `json:"last_self_heal_error"`
+	LastProfileReloadAt    string           `json:"last_profile_reload_at"`
+	ProfileReloadCount     int              `json:"profile_reload_count"`
+	LastFailureCause       string           `json:"last_failure_cause"`
+	LastFailureCategory    string           `json:"last_failure_category"`
+	LastFailureUpdatedAt   string           `json:"last_failure_updated_at"`
+	LastCodexRetryCount    int              `json:"last_codex_retry_count"`
+	CodexRetriesTotal      int              `json:"codex_retries_total"`
+	LastPermissionStreak   int              `json:"last_permission_streak"`
+	RoleRestartCounts      map[string]int   `json:"role_restart_counts"`
+	LastCrashLoopAt        string           `json:"last_crash_loop_at,omitempty"`
+	LastCrashLoopRole      string           `json:"last_crash_loop_role,omitempty"`
+	CrashLoopBackoffSec    int              `json:"crash_loop_backoff_sec,omitempty"`
+	RecentCompleted        []CompletedIssue `json:"recent_completed"`
+	ThroughputWindow       string           `json:"throughput_window"`
+	ThroughputPerHour      float64          `json:"throughput_per_hour"`
 }
 
+const (
+	statusRecentCompletedLimit    = 3
+	DefaultStatusThroughputWindow = time.Hour
+)
+
 func IsInputRequiredStatus(s Status) bool {
 	return s.QueueReady == 0 && s.InProgress == 0 && s.Blocked == 0
 }
@@ -51,6 +70,14 @@ func IsInputRequiredStatus(s Status) bool {
 var codexAttemptHeaderPattern = regexp.MustCompile(`codex attempt [0-9]+/[0-9]+`)
 
 func GetStatus(paths Paths) (Status, error) {
+	return GetStatusSince(paths, DefaultStatusThroughputWindow)
+}
+
+// GetStatusSince computes ThroughputPerHour over the given window of done/ issues.
+func GetStatusSince(paths Paths, since time.Duration) (Status, error) {
+	if since <= 0 {
+		since = DefaultStatusThroughputWindow
+	}
 	if err := EnsureLayout(paths); err != nil {
 		return Status{}, err
 	}
@@ -62,6 +89,10 @@ func GetStatus(paths Paths) (Status, error) {
 	if err != nil {
 		return Status{}, err
 	}
+	stateSchemaVersion, err := LoadStateSchemaVersion(paths)
+	if err != nil {
+		return Status{}, err
+	}
 	readyCount, err := CountReadyIssues(paths)
 	if err != nil {
 		return Status{}, err
@@ -78,6 +109,10 @@ func GetStatus(paths Paths) (Status, error) {
 	if err != nil {
 		return Status{}, err
 	}
+	deadLetterCount, err := CountIssueFiles(paths.DeadLetterDir)
+	if err != nil {
+		return Status{}, err
+	}
 	nextIssuePath, nextMeta, err := PickNextReadyIssue(paths)
 	if err != nil {
 		return Status{}, err
@@ -109,6 +144,23 @@ func GetStatus(paths Paths) (Status, error) {
 
 	_ = rolePIDs
 
+	scopedOutCount := 0
+	if !generalRunning && len(roleRunning) > 0 {
+		workerRoles := map[string]struct{}{}
+		for _, role := range roleRunning {
+			workerRoles[role] = struct{}{}
+		}
+		scopedOutCount, err = CountReadyIssuesOutsideRoles(paths, workerRoles)
+		if err != nil {
+			return Status{}, err
+		}
+	}
+
+	blockedByAssignee, err := BlockedIssuesByAssignee(paths)
+	if err != nil {
+		return Status{}, err
+	}
+
 	queueState := deriveQueueState(readyCount, inProgressCount, blockedCount)
 	codexCircuitState, codexCircuitErr := LoadCodexCircuitState(paths)
 	if codexCircuitErr != nil {
@@ -146,11 +198,24 @@ func GetStatus(paths Paths) (Status, error) {
 		lastFailureCause = lastPermissionErr
 	}
 
-	return Status{
+	completedHistory, historyErr := LoadCompletedHistory(paths)
+	if historyErr != nil {
+		completedHistory = nil
+	}
+	throughput := DoneThroughputPerHour(paths, now, since)
+	codexRetriesTotal, _ := CodexRetriesTotal(paths)
+	recentCompleted := completedHistory
+	if len(recentCompleted) > statusRecentCompletedLimit {
+		recentCompleted = recentCompleted[:statusRecentCompletedLimit]
+	}
+
+	st := Status{
 		UpdatedUTC:             time.Now().UTC(),
 		ProjectDir:             paths.ProjectDir,
 		PluginName:             profile.PluginName,
 		Enabled:                enabled,
+		StateSchemaVersion:     stateSchemaVersion,
+		StateMigrationNeeded:   stateSchemaVersion != StateSchemaVersion,
 		Daemon:                 daemon,
 		DaemonRoles:            roleRunning,
 		QueueState:             queueState,
@@ -161,6 +226,9 @@ func GetStatus(paths Paths) (Status, error) {
 		InProgress:             inProgressCount,
 		Done:                   doneCount,
 		Blocked:                blockedCount,
+		DeadLetter:             deadLetterCount,
+		ScopedOutCount:         scopedOutCount,
+		BlockedByAssignee:      blockedByAssignee,
 		NextReady:              nextReady,
 		LastBusyWaitDetectedAt: lastDetected,
 		LastBusyWaitIdleCount:  busyState.LastIdleCount,
@@ -171,10 +239,22 @@ func GetStatus(paths Paths) (Status, error) {
 		LastProfileReloadAt:    lastProfileReload,
 		ProfileReloadCount:     profileReloadState.ReloadCount,
 		LastFailureCause:       lastFailureCause,
+		LastFailureCategory:    ClassifyFailureCategory(lastFailureCause),
 		LastFailureUpdatedAt:   lastFailureUpdatedAt,
 		LastCodexRetryCount:    lastCodexRetryCount,
+		CodexRetriesTotal:      codexRetriesTotal,
 		LastPermissionStreak:   lastPermissionStreak,
-	}, nil
+		RoleRestartCounts:      SupervisorRestartCounts(paths),
+		RecentCompleted:        recentCompleted,
+		ThroughputWindow:       since.String(),
+		ThroughputPerHour:      throughput,
+	}
+	if scope, state, ok := LatestSupervisorCrashLoop(paths); ok {
+		st.LastCrashLoopAt = formatTime(state.LastCrashLoopAt)
+		st.LastCrashLoopRole = scope
+		st.CrashLoopBackoffSec = state.CrashLoopBackoffSec
+	}
+	return st, nil
 }
 
 func (s Status) Print(w io.Writer) {
@@ -186,10 +266,23 @@ func (s Status) Print(w io.Writer) {
 	fmt.Fprintf(w, "Path:    %s\n", s.ProjectDir)
 	fmt.Fprintf(w, "Plugin:  %s\n", s.PluginName)
 	fmt.Fprintf(w, "Enabled: %t\n", s.Enabled)
+	if s.StateMigrationNeeded {
+		if s.StateSchemaVersion > StateSchemaVersion {
+			fmt.Fprintf(w, "Schema:  v%d (newer than this build's v%d)\n", s.StateSchemaVersion, StateSchemaVersion)
+		} else {
+			fmt.Fprintf(w, "Schema:  v%d (current v%d; run `ralphctl migrate`)\n", s.StateSchemaVersion, StateSchemaVersion)
+		}
+	}
 	fmt.Fprintf(w, "Daemon:  %s\n", s.Daemon)
 	if len(s.DaemonRoles) > 0 {
 		fmt.Fprintf(w, "Workers: %s\n", strings.Join(s.DaemonRoles, ","))
 	}
+	if restarts := FormatRoleRestartCounts(s.RoleRestartCounts); restarts != "" {
+		fmt.Fprintf(w, "Restarts: %s\n", restarts)
+	}
+	if s.LastCrashLoopAt != "" {
+		fmt.Fprintf(w, "Crash Loop: %s at %s (backoff=%ds)\n", s.LastCrashLoopRole, s.LastCrashLoopAt, s.CrashLoopBackoffSec)
+	}
 	fmt.Fprintf(w, "State:   %s\n", s.QueueState)
 	fmt.Fprintf(w, "Circuit: %s", s.CodexCircuitState)
 	if s.CodexCircuitOpenUntil != "" {
@@ -206,7 +299,25 @@ func (s Status) Print(w io.Writer) {
 	fmt.Fprintf(w, "In Progress: %d\n", s.InProgress)
 	fmt.Fprintf(w, "Done:        %d\n", s.Done)
 	fmt.Fprintf(w, "Blocked:     %d\n", s.Blocked)
+	if s.DeadLetter > 0 {
+		fmt.Fprintf(w, "Dead Letter: %d\n", s.DeadLetter)
+	}
+	if s.ScopedOutCount > 0 {
+		fmt.Fprintf(w, "Scoped Out:  %d (role not in any running worker scope)\n", s.ScopedOutCount)
+	}
+	if assigned := FormatAssigneeCounts(s.BlockedByAssignee); assigned != "" {
+		fmt.Fprintf(w, "Awaiting:    %s (blocked issues by assignee)\n", assigned)
+	}
 	fmt.Fprintf(w, "Next:        %s\n", s.NextReady)
+	if s.ThroughputWindow != "" {
+		fmt.Fprintf(w, "Throughput:  %.2f/h (window=%s)\n", s.ThroughputPerHour, s.ThroughputWindow)
+	}
+	if len(s.RecentCompleted) > 0 {
+		fmt.Fprintln(w, "Recent Done:")
+		for _, entry := range s.RecentCompleted {
+			fmt.Fprintf(w, "  - %s\n", FormatCompletedIssue(entry))
+		}
+	}
 	if IsInputRequiredStatus(s) {
 		fmt.Fprintln(w)
 		fmt.Fprintln(w, "[Input Required]")
@@ -243,6 +354,9 @@ func (s Status) Print(w io.Writer) {
 	if s.LastFailureCause != "" {
 		fmt.Fprintf(w, "Last Failure Cause:   %s\n", s.LastFailureCause)
 	}
+	if s.LastFailureCategory != "" {
+		fmt.Fprintf(w, "Failure Category:     %s\n", s.LastFailureCategory)
+	}
 	if s.LastFailureUpdatedAt != "" {
 		fmt.Fprintf(w, "Last Failure At:      %s\n", s.LastFailureUpdatedAt)
 	}
@@ -254,6 +368,51 @@ func (s Status) Print(w io.Writer) {
 	}
 }
 
+// BlockedIssuesByAssignee counts blocked issues that are parked for a specific
+// person; unassigned issues are left out.
+func BlockedIssuesByAssignee(paths Paths) (map[string]int, error) {
+	entries, err := ListIssues(paths, IssueListOptions{Statuses: []string{"blocked"}})
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, entry := range entries {
+		if entry.Meta.Assignee == "" {
+			continue
+		}
+		counts[entry.Meta.Assignee]++
+	}
+	if len(counts) == 0 {
+		return nil, nil
+	}
+	return counts, nil
+}
+
+func FormatRoleRestartCounts(counts map[string]int) string {
+	return formatSortedCounts(counts)
+}
+
+// FormatAssigneeCounts renders BlockedByAssignee as "alice=2 bob=1".
+func FormatAssigneeCounts(counts map[string]int) string {
+	return formatSortedCounts(counts)
+}
+
+func formatSortedCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", key, counts[key]))
+	}
+	return strings.Join(parts, " ")
+}
+
 func deriveQueueState(ready, inProgress, blocked int) string {
 	if blocked > 0 {
 		return "blocked"
@@ -1,8 +1,11 @@
 package ralph
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -124,3 +127,121 @@ func TestDeriveQueueState(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusJSONUsesSnakeCaseKeys(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(Status{QueueReady: 2, LastCodexRetryCount: 1, DaemonRoles: []string{"qa"}})
+	if err != nil {
+		t.Fatalf("marshal status: %v", err)
+	}
+	decoded := map[string]any{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal status: %v", err)
+	}
+	for _, key := range []string{"queue_ready", "last_codex_retry_count", "daemon_roles", "codex_circuit_state", "updated_utc"} {
+		if _, ok := decoded[key]; !ok {
+			t.Fatalf("status json missing key %q: %s", key, string(data))
+		}
+	}
+	if got := decoded["queue_ready"]; got != float64(2) {
+		t.Fatalf("queue_ready mismatch: got=%v", got)
+	}
+}
+
+func TestSupervisorRestartCounts(t *testing.T) {
+	t.Parallel()
+
+	paths := newTestPaths(t)
+	if err := SaveSupervisorState(paths, "qa", SupervisorState{RestartCount: 3, LastExitCode: 1}); err != nil {
+		t.Fatalf("save qa supervisor state: %v", err)
+	}
+	if err := SaveSupervisorState(paths, "", SupervisorState{RestartCount: 1}); err != nil {
+		t.Fatalf("save primary supervisor state: %v", err)
+	}
+	if err := SaveSupervisorState(paths, "developer", SupervisorState{}); err != nil {
+		t.Fatalf("save developer supervisor state: %v", err)
+	}
+	if err := SaveSupervisorState(paths, "developer,qa", SupervisorState{RestartCount: 2}); err != nil {
+		t.Fatalf("save role set supervisor state: %v", err)
+	}
+
+	counts := SupervisorRestartCounts(paths)
+	if len(counts) != 3 || counts["qa"] != 3 || counts["all"] != 1 || counts["developer,qa"] != 2 {
+		t.Fatalf("restart counts mismatch: %+v", counts)
+	}
+	if got := FormatRoleRestartCounts(counts); got != "all=1 developer,qa=2 qa=3" {
+		t.Fatalf("formatted restart counts mismatch: %q", got)
+	}
+}
+
+func TestGetStatusCountsScopedOutReadyIssues(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	if _, _, err := CreateIssue(paths, "developer", "dev work"); err != nil {
+		t.Fatalf("create developer issue: %v", err)
+	}
+	if _, _, err := CreateIssue(paths, "qa", "qa work"); err != nil {
+		t.Fatalf("create qa issue: %v", err)
+	}
+
+	status, err := GetStatus(paths)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if status.ScopedOutCount != 0 {
+		t.Fatalf("no workers running: scoped_out should be 0, got %d", status.ScopedOutCount)
+	}
+
+	// A live pid in the qa worker pid file stands in for a running qa-only worker.
+	if err := os.WriteFile(paths.RolePIDFile("qa"), []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644); err != nil {
+		t.Fatalf("write role pid: %v", err)
+	}
+	status, err = GetStatus(paths)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if status.ScopedOutCount != 1 {
+		t.Fatalf("developer issue should be scoped out: got %d", status.ScopedOutCount)
+	}
+}
+
+func TestGetStatusCountsBlockedIssuesByAssignee(t *testing.T) {
+	paths := newTestPaths(t)
+	resetProfileEnv(t)
+	for _, assignee := range []string{"alice", "alice", "bob", ""} {
+		issuePath, _, err := CreateIssueWithOptions(paths, "developer", "parked work", IssueCreateOptions{Assignee: assignee})
+		if err != nil {
+			t.Fatalf("create issue: %v", err)
+		}
+		if err := os.Rename(issuePath, filepath.Join(paths.BlockedDir, filepath.Base(issuePath))); err != nil {
+			t.Fatalf("move to blocked: %v", err)
+		}
+	}
+	if _, _, err := CreateIssueWithOptions(paths, "developer", "ready work", IssueCreateOptions{Assignee: "carol"}); err != nil {
+		t.Fatalf("create ready issue: %v", err)
+	}
+
+	assigned, err := ListIssues(paths, IssueListOptions{Assignee: "alice"})
+	if err != nil {
+		t.Fatalf("list by assignee: %v", err)
+	}
+	if len(assigned) != 2 || assigned[0].Meta.Assignee != "alice" {
+		t.Fatalf("assignee filter mismatch: %+v", assigned)
+	}
+
+	status, err := GetStatus(paths)
+	if err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if got := FormatAssigneeCounts(status.BlockedByAssignee); got != "alice=2 bob=1" {
+		t.Fatalf("assignee counts mismatch: %q", got)
+	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		t.Fatalf("marshal status: %v", err)
+	}
+	if !strings.Contains(string(data), `"blocked_by_assignee":{"alice":2,"bob":1}`) {
+		t.Fatalf("status json should name blocked counts by assignee: %s", data)
+	}
+}
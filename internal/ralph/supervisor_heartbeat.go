@@ -0,0 +1,113 @@
+package ralph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// supervisorHeartbeatInterval is how often RunSupervisor refreshes its heartbeat
+// while a worker runs.
+const supervisorHeartbeatInterval = 15 * time.Second
+
+// SupervisorHeartbeat is rewritten by a live supervisor every tick and removed on a
+// clean exit, so a stale file next to a running pid means the supervisor is hung.
+type SupervisorHeartbeat struct {
+	UpdatedAt     time.Time
+	SupervisorPID int
+	WorkerPID     int
+	Roles         string
+	RestartCount  int
+}
+
+func (p Paths) SupervisorHeartbeatFile(scope string) string {
+	name := strings.ReplaceAll(supervisorScopeName(scope), ",", "-")
+	return filepath.Join(p.RalphDir, fmt.Sprintf("heartbeat.supervisor.%s.env", name))
+}
+
+func WriteSupervisorHeartbeat(paths Paths, scope string, hb SupervisorHeartbeat) error {
+	lines := []string{
+		"UPDATED_AT=" + formatTime(hb.UpdatedAt),
+		"SUPERVISOR_PID=" + strconv.Itoa(hb.SupervisorPID),
+		"WORKER_PID=" + strconv.Itoa(hb.WorkerPID),
+		"ROLES=" + supervisorScopeName(hb.Roles),
+		"RESTART_COUNT=" + strconv.Itoa(hb.RestartCount),
+	}
+	return os.WriteFile(paths.SupervisorHeartbeatFile(scope), []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
+
+// LoadSupervisorHeartbeat returns found=false when the supervisor never wrote one.
+func LoadSupervisorHeartbeat(paths Paths, scope string) (SupervisorHeartbeat, bool, error) {
+	hb := SupervisorHeartbeat{}
+	m, err := ReadEnvFile(paths.SupervisorHeartbeatFile(scope))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hb, false, nil
+		}
+		return hb, false, fmt.Errorf("read supervisor heartbeat: %w", err)
+	}
+	hb.UpdatedAt = parseTime(m["UPDATED_AT"])
+	if v, ok := parseInt(m["SUPERVISOR_PID"]); ok {
+		hb.SupervisorPID = v
+	}
+	if v, ok := parseInt(m["WORKER_PID"]); ok {
+		hb.WorkerPID = v
+	}
+	hb.Roles = m["ROLES"]
+	if v, ok := parseInt(m["RESTART_COUNT"]); ok {
+		hb.RestartCount = v
+	}
+	return hb, true, nil
+}
+
+func supervisorHeartbeatStaleAfter(profile Profile) time.Duration {
+	return time.Duration(profile.SupervisorHeartbeatStaleSec) * time.Second
+}
+
+// checkSupervisorHeartbeat grades one supervisor scope whose daemon pid file is live.
+func checkSupervisorHeartbeat(paths Paths, profile Profile, scope string, pid int, now time.Time) (string, string) {
+	hb, found, err := LoadSupervisorHeartbeat(paths, scope)
+	if err != nil {
+		return doctorStatusWarn, err.Error()
+	}
+	if !found || hb.UpdatedAt.IsZero() {
+		return doctorStatusWarn, fmt.Sprintf("no heartbeat from running supervisor (pid=%d); restart it to pick up heartbeat support", pid)
+	}
+	age := now.Sub(hb.UpdatedAt).Round(time.Second)
+	detail := fmt.Sprintf("pid=%d worker_pid=%d restarts=%d age=%s", pid, hb.WorkerPID, hb.RestartCount, age)
+	if staleAfter := supervisorHeartbeatStaleAfter(profile); profile.SupervisorHeartbeatStaleSec > 0 && age > staleAfter {
+		return doctorStatusFail, fmt.Sprintf("heartbeat stale (%s > %s); supervisor may be hung (run: ralphctl restart)", detail, staleAfter)
+	}
+	return doctorStatusPass, detail
+}
+
+// supervisorScopePID finds the running supervisor for scope: the daemon pid file
+// for all roles or a single role, otherwise the pid its heartbeat recorded
+// (e.g. a role set supervised in the foreground).
+func supervisorScopePID(paths Paths, scope string) (int, bool) {
+	pidFile := paths.PIDFile
+	if scope != "" {
+		pidFile = paths.RolePIDFile(scope)
+	}
+	if pid, running := daemonPIDFromFile(pidFile); running {
+		return pid, true
+	}
+	if hb, found, err := LoadSupervisorHeartbeat(paths, scope); err == nil && found && isPIDRunning(hb.SupervisorPID) {
+		return hb.SupervisorPID, true
+	}
+	return 0, false
+}
+
+func appendSupervisorHeartbeatChecks(report *DoctorReport, paths Paths, profile Profile, now time.Time) {
+	for _, scope := range supervisorScopes(paths) {
+		pid, running := supervisorScopePID(paths, scope)
+		if !running {
+			continue
+		}
+		status, detail := checkSupervisorHeartbeat(paths, profile, scope, pid, now)
+		report.add("supervisor:"+supervisorScopeName(scope), status, detail)
+	}
+}
@@ -0,0 +1,71 @@
+package ralph
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSupervisorHeartbeatDoctorCheckFlagsStaleHeartbeat(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	profile := DefaultProfile()
+	profile.SupervisorHeartbeatStaleSec = 60
+	now := time.Now().UTC()
+
+	report := DoctorReport{}
+	appendSupervisorHeartbeatChecks(&report, paths, profile, now)
+	if len(report.Checks) != 0 {
+		t.Fatalf("stopped daemons should not be checked: %+v", report.Checks)
+	}
+
+	pid := os.Getpid()
+	if err := os.WriteFile(paths.RolePIDFile("developer"), []byte(strconv.Itoa(pid)+"\n"), 0o644); err != nil {
+		t.Fatalf("write pid file: %v", err)
+	}
+	if status, _ := checkSupervisorHeartbeat(paths, profile, "developer", pid, now); status != doctorStatusWarn {
+		t.Fatalf("missing heartbeat should warn, got %s", status)
+	}
+
+	hb := SupervisorHeartbeat{UpdatedAt: now.Add(-30 * time.Second), SupervisorPID: pid, WorkerPID: 4242, Roles: "developer", RestartCount: 2}
+	if err := WriteSupervisorHeartbeat(paths, "developer", hb); err != nil {
+		t.Fatalf("write heartbeat: %v", err)
+	}
+	loaded, found, err := LoadSupervisorHeartbeat(paths, "developer")
+	if err != nil || !found || loaded.WorkerPID != 4242 || loaded.RestartCount != 2 || !loaded.UpdatedAt.Equal(hb.UpdatedAt.Truncate(time.Second)) {
+		t.Fatalf("heartbeat round trip mismatch: %+v found=%t err=%v", loaded, found, err)
+	}
+	report = DoctorReport{}
+	appendSupervisorHeartbeatChecks(&report, paths, profile, now)
+	if len(report.Checks) != 1 || report.Checks[0].Name != "supervisor:developer" || report.Checks[0].Status != doctorStatusPass {
+		t.Fatalf("fresh heartbeat should pass: %+v", report.Checks)
+	}
+
+	if status, detail := checkSupervisorHeartbeat(paths, profile, "developer", pid, now.Add(time.Minute)); status != doctorStatusFail {
+		t.Fatalf("heartbeat older than the stale threshold should fail: %s %s", status, detail)
+	}
+}
+
+func TestSupervisorHeartbeatDoctorCheckCoversRoleSetScope(t *testing.T) {
+	paths := newTestPaths(t)
+	if err := EnsureLayout(paths); err != nil {
+		t.Fatalf("ensure layout: %v", err)
+	}
+	profile := DefaultProfile()
+	profile.SupervisorHeartbeatStaleSec = 60
+	now := time.Now().UTC()
+
+	// A role set has no daemon pid file; its heartbeat carries the supervisor pid.
+	hb := SupervisorHeartbeat{UpdatedAt: now, SupervisorPID: os.Getpid(), Roles: "developer,qa"}
+	if err := WriteSupervisorHeartbeat(paths, "developer,qa", hb); err != nil {
+		t.Fatalf("write heartbeat: %v", err)
+	}
+	report := DoctorReport{}
+	appendSupervisorHeartbeatChecks(&report, paths, profile, now)
+	if len(report.Checks) != 1 || report.Checks[0].Name != "supervisor:developer,qa" || report.Checks[0].Status != doctorStatusPass {
+		t.Fatalf("role set supervisor should be checked: %+v", report.Checks)
+	}
+}
@@ -0,0 +1,179 @@
+package ralph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type SupervisorState struct {
+	StartedAt           time.Time
+	RestartCount        int
+	LastRestartAt       time.Time
+	LastExitCode        int
+	LastCrashLoopAt     time.Time
+	CrashLoopBackoffSec int
+}
+
+func supervisorScopeName(roleScope string) string {
+	if strings.TrimSpace(roleScope) == "" {
+		return "all"
+	}
+	return roleScope
+}
+
+func (p Paths) SupervisorStateFile(scope string) string {
+	name := strings.ReplaceAll(supervisorScopeName(scope), ",", "-")
+	return filepath.Join(p.RalphDir, fmt.Sprintf("state.supervisor.%s.env", name))
+}
+
+// supervisorScopes lists every role scope a supervisor has run with in this
+// project, from the state file each one saves on start or its heartbeat: ""
+// for all roles, a single role, or a comma-separated role set.
+func supervisorScopes(paths Paths) []string {
+	seen := map[string]struct{}{}
+	scopes := []string{}
+	for _, prefix := range []string{"state.supervisor.", "heartbeat.supervisor."} {
+		matches, _ := filepath.Glob(filepath.Join(paths.RalphDir, prefix+"*.env"))
+		for _, match := range matches {
+			name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(match), prefix), ".env")
+			scope := strings.ReplaceAll(name, "-", ",")
+			if name == "all" {
+				scope = ""
+			}
+			if _, ok := seen[scope]; ok {
+				continue
+			}
+			seen[scope] = struct{}{}
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+func LoadSupervisorState(paths Paths, scope string) (SupervisorState, error) {
+	state := SupervisorState{}
+	m, err := ReadEnvFile(paths.SupervisorStateFile(scope))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, fmt.Errorf("read supervisor state: %w", err)
+	}
+
+	state.StartedAt = parseTime(m["STARTED_AT"])
+	if v, ok := parseInt(m["RESTART_COUNT"]); ok {
+		state.RestartCount = v
+	}
+	state.LastRestartAt = parseTime(m["LAST_RESTART_AT"])
+	if v, ok := parseInt(m["LAST_EXIT_CODE"]); ok {
+		state.LastExitCode = v
+	}
+	state.LastCrashLoopAt = parseTime(m["LAST_CRASH_LOOP_AT"])
+	if v, ok := parseInt(m["CRASH_LOOP_BACKOFF_SEC"]); ok {
+		state.CrashLoopBackoffSec = v
+	}
+	return state, nil
+}
+
+func SaveSupervisorState(paths Paths, scope string, state SupervisorState) error {
+	if err := EnsureLayout(paths); err != nil {
+		return err
+	}
+	lines := []string{
+		"STARTED_AT=" + formatTime(state.StartedAt),
+		"RESTART_COUNT=" + strconv.Itoa(state.RestartCount),
+		"LAST_RESTART_AT=" + formatTime(state.LastRestartAt),
+		"LAST_EXIT_CODE=" + strconv.Itoa(state.LastExitCode),
+		"LAST_CRASH_LOOP_AT=" + formatTime(state.LastCrashLoopAt),
+		"CRASH_LOOP_BACKOFF_SEC=" + strconv.Itoa(state.CrashLoopBackoffSec),
+	}
+	content := strings.Join(lines, "\n") + "\n"
+	return os.WriteFile(paths.SupervisorStateFile(scope), []byte(content), 0o644)
+}
+
+func SupervisorRestartCounts(paths Paths) map[string]int {
+	out := map[string]int{}
+	for _, scope := range supervisorScopes(paths) {
+		state, err := LoadSupervisorState(paths, scope)
+		if err != nil || state.RestartCount <= 0 {
+			continue
+		}
+		out[supervisorScopeName(scope)] = state.RestartCount
+	}
+	return out
+}
+
+// recoverFromCrashLoop clears the crash loop marker once the current worker has
+// run a full crash-loop window, the point where supervisorRestartBackoff resets.
+// It reports whether anything changed.
+func (s *SupervisorState) recoverFromCrashLoop(workerRanFor time.Duration) bool {
+	if s.LastCrashLoopAt.IsZero() || workerRanFor < supervisorCrashLoopWindow {
+		return false
+	}
+	s.LastCrashLoopAt = time.Time{}
+	s.CrashLoopBackoffSec = 0
+	return true
+}
+
+// LatestSupervisorCrashLoop returns the scope whose supervisor most recently
+// entered crash-loop backoff, or ok=false when none has.
+func LatestSupervisorCrashLoop(paths Paths) (string, SupervisorState, bool) {
+	latestScope := ""
+	latest := SupervisorState{}
+	for _, scope := range supervisorScopes(paths) {
+		state, err := LoadSupervisorState(paths, scope)
+		if err != nil || state.LastCrashLoopAt.IsZero() || !state.LastCrashLoopAt.After(latest.LastCrashLoopAt) {
+			continue
+		}
+		latestScope, latest = supervisorScopeName(scope), state
+	}
+	return latestScope, latest, !latest.LastCrashLoopAt.IsZero()
+}
+
+const (
+	supervisorCrashLoopWindow = time.Minute
+	supervisorMaxBackoff      = 5 * time.Minute
+)
+
+// supervisorRestartBackoff tracks recent worker restarts for one supervisor. Once
+// the worker crashes more than maxPerMin times a minute, every restart doubles the
+// delay until a worker run lasts a full window.
+type supervisorRestartBackoff struct {
+	maxPerMin int
+	restarts  []time.Time
+	level     int
+}
+
+// next records a restart at now after a worker run of ranFor and returns the delay
+// before it plus whether the worker is crash-looping.
+func (b *supervisorRestartBackoff) next(now time.Time, ranFor, baseDelay time.Duration) (time.Duration, bool) {
+	if ranFor >= supervisorCrashLoopWindow {
+		b.restarts = nil
+		b.level = 0
+	}
+	kept := b.restarts[:0]
+	for _, at := range b.restarts {
+		if now.Sub(at) < supervisorCrashLoopWindow {
+			kept = append(kept, at)
+		}
+	}
+	b.restarts = append(kept, now)
+	if b.maxPerMin <= 0 || (b.level == 0 && len(b.restarts) <= b.maxPerMin) {
+		return baseDelay, false
+	}
+	if baseDelay < time.Second {
+		baseDelay = time.Second
+	}
+	delay := supervisorMaxBackoff
+	if b.level < 16 && baseDelay<<b.level < supervisorMaxBackoff {
+		delay = baseDelay << b.level
+		b.level++
+	}
+	return delay, true
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -20,9 +21,24 @@ import (
 
 const defaultTelegramBaseURL = "https://api.telegram.org"
 
-type TelegramCommandHandler func(ctx context.Context, chatID int64, text string) (string, error)
+// TelegramReply is what a command handler sends back. Pre marks column-aligned
+// output that goes out as a MarkdownV2 code block when CodeBlocks is on.
+type TelegramReply struct {
+	Text string
+	Pre  bool
+}
+
+type TelegramCommandHandler func(ctx context.Context, chatID int64, text string) (TelegramReply, error)
 type TelegramNotifyHandler func(ctx context.Context) ([]string, error)
 
+// TelegramAllowlist is the part of the bot config a reload can swap in place.
+type TelegramAllowlist struct {
+	ChatIDs map[int64]struct{}
+	UserIDs map[int64]struct{}
+}
+
+type TelegramReloadHandler func() (TelegramAllowlist, error)
+
 type TelegramBotOptions struct {
 	Token              string
 	AllowedChatIDs     map[int64]struct{}
@@ -31,16 +47,24 @@ type TelegramBotOptions struct {
 	NotifyIntervalSec  int
 	CommandTimeoutSec  int
 	CommandConcurrency int
-	OffsetFile         string
-	BaseURL            string
-	Client             *http.Client
-	Out                io.Writer
-	OnCommand          TelegramCommandHandler
-	OnNotifyTick       TelegramNotifyHandler
+	CommandRatePerMin  int
+	// CodeBlocks sends replies marked Pre as MarkdownV2 code blocks.
+	CodeBlocks   bool
+	OffsetFile   string
+	BaseURL      string
+	Client       *http.Client
+	Out          io.Writer
+	OnCommand    TelegramCommandHandler
+	OnNotifyTick TelegramNotifyHandler
+	// Reload (typically SIGHUP) triggers OnReload; the new allowlist applies to
+	// the next batch of updates without losing the poll offset.
+	Reload   <-chan os.Signal
+	OnReload TelegramReloadHandler
 }
 
 type telegramGetUpdatesResponse struct {
 	OK          bool             `json:"ok"`
+	ErrorCode   int              `json:"error_code,omitempty"`
 	Description string           `json:"description,omitempty"`
 	Result      []telegramUpdate `json:"result"`
 }
@@ -65,8 +89,9 @@ type telegramUser struct {
 }
 
 type telegramSendMessageRequest struct {
-	ChatID int64  `json:"chat_id"`
-	Text   string `json:"text"`
+	ChatID    int64  `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode,omitempty"`
 }
 
 type telegramSendMessageResponse struct {
@@ -116,22 +141,56 @@ func RunTelegramBot(ctx context.Context, opts TelegramBotOptions) error {
 	if out == nil {
 		out = io.Discard
 	}
+	out = NewTelegramRedactingWriter(out, token)
 
 	offset, err := loadTelegramOffset(opts.OffsetFile)
-	if err != nil {
+	skipPendingUpdates := false
+	if errors.Is(err, errTelegramOffsetCorrupt) {
+		// Resume from the newest update instead of replaying the whole backlog.
+		fmt.Fprintf(out, "[telegram] warning: %v; skipping pending updates\n", err)
+		offset = -1
+		skipPendingUpdates = true
+	} else if err != nil {
 		return err
 	}
 
 	fmt.Fprintf(out, "[telegram] bot started (poll_timeout=%ds, allowed_chats=%d)\n", pollTimeoutSec, len(opts.AllowedChatIDs))
-	backoff := 2 * time.Second
+	backoff := telegramPollBackoffMin
+	pollFailures := 0
 	nextNotifyAt := time.Now().UTC()
-	chatIDs := sortedTelegramChatIDs(opts.AllowedChatIDs)
+	allowedChatIDs := opts.AllowedChatIDs
+	allowedUserIDs := opts.AllowedUserIDs
+	chatIDs := sortedTelegramChatIDs(allowedChatIDs)
+	applyReload := func() {
+		select {
+		case <-opts.Reload:
+		default:
+			return
+		}
+		if opts.OnReload == nil {
+			return
+		}
+		allowlist, err := opts.OnReload()
+		if err == nil && len(allowlist.ChatIDs) == 0 {
+			err = fmt.Errorf("telegram allowed chat IDs are required")
+		}
+		if err != nil {
+			fmt.Fprintf(out, "[telegram] warning: reload failed; keeping current allowlist: %v\n", err)
+			return
+		}
+		allowedChatIDs = allowlist.ChatIDs
+		allowedUserIDs = allowlist.UserIDs
+		chatIDs = sortedTelegramChatIDs(allowedChatIDs)
+		fmt.Fprintf(out, "[telegram] reloaded allowlist (allowed_chats=%d, allowed_users=%d)\n", len(allowedChatIDs), len(allowedUserIDs))
+	}
 	unauthorizedLogCooldown := 60 * time.Second
 	lastUnauthorizedLogAt := map[string]time.Time{}
+	rateLimiter := newTelegramRateLimiter(opts.CommandRatePerMin)
 	dispatcher := newTelegramCommandDispatcher(ctx, telegramCommandDispatcherOptions{
 		CommandTimeout: time.Duration(commandTimeoutSec) * time.Second,
 		Concurrency:    commandConcurrency,
 		OnCommand:      opts.OnCommand,
+		CodeBlocks:     opts.CodeBlocks,
 		Client:         client,
 		BaseURL:        baseURL,
 		Token:          token,
@@ -143,6 +202,7 @@ func RunTelegramBot(ctx context.Context, opts TelegramBotOptions) error {
 			fmt.Fprintln(out, "[telegram] interrupted; stopping")
 			return nil
 		}
+		applyReload()
 
 		if opts.OnNotifyTick != nil && !time.Now().UTC().Before(nextNotifyAt) {
 			nextNotifyAt = time.Now().UTC().Add(time.Duration(notifyIntervalSec) * time.Second)
@@ -156,7 +216,7 @@ func RunTelegramBot(ctx context.Context, opts TelegramBotOptions) error {
 						continue
 					}
 					for _, chatID := range chatIDs {
-						for _, chunk := range splitTelegramMessage(msg, 3500) {
+						for _, chunk := range splitTelegramMessage(msg, telegramMessageChunkRunes) {
 							if sendErr := telegramSendMessage(ctx, client, baseURL, token, chatID, chunk); sendErr != nil {
 								fmt.Fprintf(out, "[telegram] warning: notify send failed chat=%d: %v\n", chatID, sendErr)
 								break
@@ -169,20 +229,36 @@ func RunTelegramBot(ctx context.Context, opts TelegramBotOptions) error {
 
 		updates, nextOffset, err := telegramGetUpdates(ctx, client, baseURL, token, offset, pollTimeoutSec)
 		if err != nil {
-			fmt.Fprintf(out, "[telegram] warning: getUpdates failed: %v\n", err)
+			if ctx.Err() != nil {
+				continue
+			}
+			if fatal := telegramFatalPollError(err); fatal != nil {
+				fmt.Fprintf(out, "[telegram] fatal: %v\n", fatal)
+				return fatal
+			}
+			pollFailures++
+			fmt.Fprintf(out, "[telegram] warning: getUpdates failed (consecutive=%d): %v; retrying in %s\n", pollFailures, err, backoff)
 			if sleepErr := sleepOrCancel(ctx, backoff); sleepErr != nil {
 				return nil
 			}
-			if backoff < 15*time.Second {
-				backoff *= 2
-				if backoff > 15*time.Second {
-					backoff = 15 * time.Second
-				}
-			}
+			backoff = nextTelegramPollBackoff(backoff)
 			continue
 		}
-		backoff = 2 * time.Second
+		if pollFailures > 0 {
+			fmt.Fprintf(out, "[telegram] getUpdates recovered after %d consecutive failure(s)\n", pollFailures)
+			pollFailures = 0
+			backoff = telegramPollBackoffMin
+		}
+		if skipPendingUpdates {
+			skipPendingUpdates = false
+			if len(updates) > 0 {
+				fmt.Fprintf(out, "[telegram] offset recovered: skipped %d pending update(s)\n", len(updates))
+			}
+			updates = nil
+		}
 
+		// A reload that landed during the long poll applies to this batch.
+		applyReload()
 		for _, upd := range updates {
 			if upd.Message == nil {
 				continue
@@ -193,16 +269,24 @@ func RunTelegramBot(ctx context.Context, opts TelegramBotOptions) error {
 				continue
 			}
 
-			if !isTelegramChatAllowed(opts.AllowedChatIDs, chatID) {
+			if !isTelegramChatAllowed(allowedChatIDs, chatID) {
 				telegramLogUnauthorized(out, lastUnauthorizedLogAt, unauthorizedLogCooldown, fmt.Sprintf("chat:%d", chatID), fmt.Sprintf("chat %d is not allowed", chatID))
 				continue
 			}
 			userID := telegramMessageUserID(upd.Message)
-			if !isTelegramUserAllowed(opts.AllowedUserIDs, userID) {
+			if !isTelegramUserAllowed(allowedUserIDs, userID) {
 				telegramLogUnauthorized(out, lastUnauthorizedLogAt, unauthorizedLogCooldown, fmt.Sprintf("user:%d:chat:%d", userID, chatID), fmt.Sprintf("user %d in chat %d is not allowed", userID, chatID))
 				continue
 			}
 
+			if !rateLimiter.Allow(chatID, time.Now()) {
+				fmt.Fprintf(out, "[telegram] rate limited chat=%d\n", chatID)
+				reply := fmt.Sprintf("slow down: max %d commands per minute for this chat. try again shortly.", opts.CommandRatePerMin)
+				if sendErr := telegramSendMessage(ctx, client, baseURL, token, chatID, reply); sendErr != nil {
+					fmt.Fprintf(out, "[telegram] warning: rate limit reply failed chat=%d: %v\n", chatID, sendErr)
+				}
+				continue
+			}
 			dispatcher.Submit(chatID, text)
 		}
 
@@ -215,10 +299,61 @@ func RunTelegramBot(ctx context.Context, opts TelegramBotOptions) error {
 	}
 }
 
+type telegramRateLimiter struct {
+	ratePerMin int
+	buckets    map[int64]*telegramTokenBucket
+}
+
+type telegramTokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newTelegramRateLimiter(ratePerMin int) *telegramRateLimiter {
+	return &telegramRateLimiter{
+		ratePerMin: ratePerMin,
+		buckets:    map[int64]*telegramTokenBucket{},
+	}
+}
+
+func (l *telegramRateLimiter) Allow(chatID int64, now time.Time) bool {
+	if l == nil || l.ratePerMin <= 0 {
+		return true
+	}
+	capacity := float64(l.ratePerMin)
+	refillPerSec := capacity / 60
+
+	// Drop buckets that have fully refilled so idle chats do not accumulate.
+	for id, bucket := range l.buckets {
+		if id != chatID && bucket.tokens+now.Sub(bucket.last).Seconds()*refillPerSec >= capacity {
+			delete(l.buckets, id)
+		}
+	}
+
+	bucket, ok := l.buckets[chatID]
+	if !ok {
+		bucket = &telegramTokenBucket{tokens: capacity, last: now}
+		l.buckets[chatID] = bucket
+	}
+	if elapsed := now.Sub(bucket.last).Seconds(); elapsed > 0 {
+		bucket.tokens += elapsed * refillPerSec
+		if bucket.tokens > capacity {
+			bucket.tokens = capacity
+		}
+	}
+	bucket.last = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
 type telegramCommandDispatcherOptions struct {
 	CommandTimeout time.Duration
 	Concurrency    int
 	OnCommand      TelegramCommandHandler
+	CodeBlocks     bool
 	Client         *http.Client
 	BaseURL        string
 	Token          string
@@ -230,6 +365,7 @@ type telegramCommandDispatcher struct {
 	commandTimeout time.Duration
 	slots          chan struct{}
 	onCommand      TelegramCommandHandler
+	codeBlocks     bool
 	client         *http.Client
 	baseURL        string
 	token          string
@@ -259,6 +395,7 @@ func newTelegramCommandDispatcher(ctx context.Context, opts telegramCommandDispa
 		commandTimeout: timeout,
 		slots:          make(chan struct{}, concurrency),
 		onCommand:      opts.OnCommand,
+		codeBlocks:     opts.CodeBlocks,
 		client:         opts.Client,
 		baseURL:        opts.BaseURL,
 		token:          opts.Token,
@@ -328,19 +465,28 @@ func (d *telegramCommandDispatcher) execute(chatID int64, text string) {
 	cmdCtx, cancel := context.WithTimeout(d.ctx, d.commandTimeout)
 	defer cancel()
 
-	reply, cmdErr := d.onCommand(cmdCtx, chatID, text)
+	res, cmdErr := d.onCommand(cmdCtx, chatID, text)
 	if cmdErr != nil {
-		reply = "error: " + compactTelegramError(cmdErr.Error())
+		res = TelegramReply{Text: "error: " + compactTelegramError(cmdErr.Error())}
 	}
-	reply = strings.TrimSpace(reply)
+	pre := res.Pre && d.codeBlocks
+	reply := strings.TrimSpace(res.Text)
 	if reply == "" {
 		return
 	}
+	chunkRunes := telegramMessageChunkRunes
+	if pre {
+		chunkRunes -= len(formatTelegramPreBlock(""))
+	}
 
 	sendCtx, sendCancel := context.WithTimeout(d.ctx, 20*time.Second)
 	defer sendCancel()
-	for _, chunk := range splitTelegramMessage(reply, 3500) {
-		if sendErr := telegramSendMessage(sendCtx, d.client, d.baseURL, d.token, chatID, chunk); sendErr != nil {
+	for _, chunk := range splitTelegramMessage(reply, chunkRunes) {
+		send := telegramSendMessage
+		if pre {
+			send = sendTelegramPreBlock
+		}
+		if sendErr := send(sendCtx, d.client, d.baseURL, d.token, chatID, chunk); sendErr != nil {
 			fmt.Fprintf(d.out, "[telegram] warning: sendMessage failed chat=%d: %v\n", chatID, sendErr)
 			break
 		}
@@ -466,11 +612,53 @@ func telegramLogUnauthorized(out io.Writer, last map[string]time.Time, cooldown
 	fmt.Fprintf(out, "[telegram] unauthorized access blocked: %s\n", detail)
 }
 
+const (
+	telegramPollBackoffMin = time.Second
+	telegramPollBackoffMax = 30 * time.Second
+)
+
+type telegramAPIError struct {
+	Method      string
+	StatusCode  int
+	Description string
+}
+
+func (e *telegramAPIError) Error() string {
+	return fmt.Sprintf("telegram %s http %d: %s", e.Method, e.StatusCode, e.Description)
+}
+
+func nextTelegramPollBackoff(cur time.Duration) time.Duration {
+	next := cur * 2
+	if next < telegramPollBackoffMin {
+		next = telegramPollBackoffMin
+	}
+	if next > telegramPollBackoffMax {
+		next = telegramPollBackoffMax
+	}
+	return next
+}
+
+// telegramFatalPollError maps getUpdates failures that retrying cannot fix to an
+// operator-facing error; it returns nil for transient failures.
+func telegramFatalPollError(err error) error {
+	var apiErr *telegramAPIError
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("telegram bot token rejected (401 Unauthorized); check RALPH_TELEGRAM_BOT_TOKEN: %w", err)
+	case http.StatusConflict:
+		return fmt.Errorf("telegram getUpdates conflict (409): another bot instance is polling with this token or a webhook is set; stop the other instance: %w", err)
+	}
+	return nil
+}
+
 func telegramGetUpdates(ctx context.Context, client *http.Client, baseURL, token string, offset int64, timeoutSec int) ([]telegramUpdate, int64, error) {
 	endpoint := fmt.Sprintf("%s/bot%s/getUpdates", baseURL, token)
 	values := url.Values{}
 	values.Set("timeout", strconv.Itoa(timeoutSec))
-	if offset > 0 {
+	if offset != 0 {
 		values.Set("offset", strconv.FormatInt(offset, 10))
 	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+values.Encode(), nil)
@@ -484,7 +672,7 @@ func telegramGetUpdates(ctx context.Context, client *http.Client, baseURL, token
 	defer resp.Body.Close()
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
-		return nil, offset, fmt.Errorf("telegram getUpdates http %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		return nil, offset, &telegramAPIError{Method: "getUpdates", StatusCode: resp.StatusCode, Description: strings.TrimSpace(string(body))}
 	}
 
 	var payload telegramGetUpdatesResponse
@@ -492,6 +680,9 @@ func telegramGetUpdates(ctx context.Context, client *http.Client, baseURL, token
 		return nil, offset, err
 	}
 	if !payload.OK {
+		if payload.ErrorCode != 0 {
+			return nil, offset, &telegramAPIError{Method: "getUpdates", StatusCode: payload.ErrorCode, Description: strings.TrimSpace(payload.Description)}
+		}
 		if strings.TrimSpace(payload.Description) == "" {
 			return nil, offset, fmt.Errorf("telegram getUpdates failed")
 		}
@@ -508,10 +699,15 @@ func telegramGetUpdates(ctx context.Context, client *http.Client, baseURL, token
 }
 
 func telegramSendMessage(ctx context.Context, client *http.Client, baseURL, token string, chatID int64, text string) error {
+	return telegramSendMessageMode(ctx, client, baseURL, token, chatID, text, "")
+}
+
+func telegramSendMessageMode(ctx context.Context, client *http.Client, baseURL, token string, chatID int64, text, parseMode string) error {
 	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", baseURL, token)
 	reqBody := telegramSendMessageRequest{
-		ChatID: chatID,
-		Text:   text,
+		ChatID:    chatID,
+		Text:      text,
+		ParseMode: parseMode,
 	}
 	payload, err := json.Marshal(reqBody)
 	if err != nil {
@@ -545,45 +741,187 @@ func telegramSendMessage(ctx context.Context, client *http.Client, baseURL, toke
 	return nil
 }
 
+type TelegramBroadcastOptions struct {
+	Token   string
+	ChatIDs map[int64]struct{}
+	Text    string
+	BaseURL string
+	Client  *http.Client
+}
+
+type TelegramBroadcastResult struct {
+	ChatID int64
+	Err    error
+}
+
+// TelegramBroadcast sends a one-off message to every chat without requiring
+// the bot daemon to be running.
+func TelegramBroadcast(ctx context.Context, opts TelegramBroadcastOptions) ([]TelegramBroadcastResult, error) {
+	token := strings.TrimSpace(opts.Token)
+	if token == "" {
+		return nil, fmt.Errorf("telegram token is required")
+	}
+	if len(opts.ChatIDs) == 0 {
+		return nil, fmt.Errorf("telegram chat IDs are required")
+	}
+	text := strings.TrimSpace(opts.Text)
+	if text == "" {
+		return nil, fmt.Errorf("broadcast message is required")
+	}
+	baseURL := strings.TrimSpace(opts.BaseURL)
+	if baseURL == "" {
+		baseURL = defaultTelegramBaseURL
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	results := []TelegramBroadcastResult{}
+	for _, chatID := range sortedTelegramChatIDs(opts.ChatIDs) {
+		var sendErr error
+		for _, chunk := range splitTelegramMessage(text, telegramMessageChunkRunes) {
+			if sendErr = telegramSendMessage(ctx, client, baseURL, token, chatID, chunk); sendErr != nil {
+				break
+			}
+		}
+		results = append(results, TelegramBroadcastResult{ChatID: chatID, Err: sendErr})
+	}
+	return results, nil
+}
+
+const TelegramTestMessage = "ralph telegram test ok"
+
+type TelegramBotIdentity struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+}
+
+type telegramGetMeResponse struct {
+	OK          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Result      TelegramBotIdentity `json:"result"`
+}
+
+type TelegramTestResult struct {
+	Bot   TelegramBotIdentity
+	Chats []TelegramBroadcastResult
+}
+
+// TelegramTest validates the token with getMe and then sends TelegramTestMessage
+// to every chat. A getMe failure is returned as the error; per-chat delivery
+// failures are reported in Chats.
+func TelegramTest(ctx context.Context, opts TelegramBroadcastOptions) (TelegramTestResult, error) {
+	res := TelegramTestResult{}
+	token := strings.TrimSpace(opts.Token)
+	if token == "" {
+		return res, fmt.Errorf("telegram token is required")
+	}
+	baseURL := strings.TrimSpace(opts.BaseURL)
+	if baseURL == "" {
+		baseURL = defaultTelegramBaseURL
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	bot, err := telegramGetMe(ctx, client, baseURL, token)
+	if err != nil {
+		return res, err
+	}
+	res.Bot = bot
+	opts.BaseURL = baseURL
+	opts.Client = client
+	opts.Text = TelegramTestMessage
+	chats, err := TelegramBroadcast(ctx, opts)
+	if err != nil {
+		return res, err
+	}
+	res.Chats = chats
+	return res, nil
+}
+
+func telegramGetMe(ctx context.Context, client *http.Client, baseURL, token string) (TelegramBotIdentity, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/bot%s/getMe", baseURL, token), nil)
+	if err != nil {
+		return TelegramBotIdentity{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return TelegramBotIdentity{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
+		return TelegramBotIdentity{}, &telegramAPIError{Method: "getMe", StatusCode: resp.StatusCode, Description: strings.TrimSpace(string(body))}
+	}
+	var payload telegramGetMeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return TelegramBotIdentity{}, err
+	}
+	if !payload.OK {
+		return TelegramBotIdentity{}, &telegramAPIError{Method: "getMe", StatusCode: payload.ErrorCode, Description: strings.TrimSpace(payload.Description)}
+	}
+	return payload.Result, nil
+}
+
+// telegramMessageChunkRunes keeps each sendMessage well under Telegram's
+// 4096-char limit (counted in UTF-16 units, so leave headroom).
+const telegramMessageChunkRunes = 3500
+
+// splitTelegramMessage packs whole lines into chunks of at most maxRunes; only a
+// single line longer than maxRunes is cut.
 func splitTelegramMessage(text string, maxRunes int) []string {
 	text = strings.TrimSpace(text)
 	if text == "" {
 		return nil
 	}
 	if maxRunes <= 0 {
-		maxRunes = 3500
+		maxRunes = telegramMessageChunkRunes
 	}
-	runes := []rune(text)
-	if len(runes) <= maxRunes {
+	if utf8.RuneCountInString(text) <= maxRunes {
 		return []string{text}
 	}
 
 	out := []string{}
-	for start := 0; start < len(runes); {
-		end := start + maxRunes
-		if end >= len(runes) {
-			out = append(out, strings.TrimSpace(string(runes[start:])))
-			break
+	var cur strings.Builder
+	curRunes := 0
+	flush := func() {
+		if chunk := strings.TrimRight(cur.String(), " \t\n"); strings.TrimSpace(chunk) != "" {
+			out = append(out, chunk)
 		}
-		split := end
-		for i := end; i > start+(maxRunes/2); i-- {
-			if runes[i-1] == '\n' {
-				split = i
-				break
-			}
+		cur.Reset()
+		curRunes = 0
+	}
+	for _, line := range strings.Split(text, "\n") {
+		runes := []rune(line)
+		for len(runes) > maxRunes {
+			flush()
+			out = append(out, string(runes[:maxRunes]))
+			runes = runes[maxRunes:]
 		}
-		chunk := strings.TrimSpace(string(runes[start:split]))
-		if chunk != "" {
-			out = append(out, chunk)
+		need := len(runes)
+		if curRunes > 0 {
+			need++
 		}
-		start = split
-	}
-	if len(out) == 0 {
-		return []string{text}
+		if curRunes+need > maxRunes {
+			flush()
+			need = len(runes)
+		}
+		if curRunes > 0 {
+			cur.WriteByte('\n')
+		}
+		cur.WriteString(string(runes))
+		curRunes += need
 	}
+	flush()
 	return out
 }
 
+var errTelegramOffsetCorrupt = errors.New("telegram offset file is corrupt")
+
 func loadTelegramOffset(path string) (int64, error) {
 	path = strings.TrimSpace(path)
 	if path == "" {
@@ -596,13 +934,15 @@ func loadTelegramOffset(path string) (int64, error) {
 		}
 		return 0, fmt.Errorf("read telegram offset file: %w", err)
 	}
-	raw := strings.TrimSpace(string(data))
-	if raw == "" {
-		return 0, nil
+	// saveTelegramOffset always writes a newline-terminated value, so an empty
+	// or unterminated file means a torn write.
+	if len(data) == 0 || data[len(data)-1] != '\n' {
+		return 0, fmt.Errorf("%w: %s (partial write)", errTelegramOffsetCorrupt, path)
 	}
+	raw := strings.TrimSpace(string(data))
 	offset, err := strconv.ParseInt(raw, 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("parse telegram offset: %w", err)
+		return 0, fmt.Errorf("%w: %s (%v)", errTelegramOffsetCorrupt, path, err)
 	}
 	if offset < 0 {
 		return 0, nil
@@ -615,11 +955,34 @@ func saveTelegramOffset(path string, offset int64) error {
 	if path == "" {
 		return nil
 	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("create telegram offset dir: %w", err)
 	}
-	content := strconv.FormatInt(offset, 10) + "\n"
-	return os.WriteFile(path, []byte(content), 0o644)
+	tmpFile, err := os.CreateTemp(dir, ".telegram-offset-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	defer func() {
+		_ = os.Remove(tmpPath)
+	}()
+	if _, err := tmpFile.WriteString(strconv.FormatInt(offset, 10) + "\n"); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Chmod(0o644); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
 }
 
 func compactTelegramError(raw string) string {
@@ -0,0 +1,38 @@
+package ralph
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+const telegramPreBlockFence = "```"
+
+// escapeTelegramPreBlock escapes the only characters MarkdownV2 treats as
+// special inside a code block.
+func escapeTelegramPreBlock(text string) string {
+	return strings.NewReplacer("\\", "\\\\", "`", "\\`").Replace(text)
+}
+
+func formatTelegramPreBlock(text string) string {
+	return telegramPreBlockFence + "\n" + escapeTelegramPreBlock(text) + "\n" + telegramPreBlockFence
+}
+
+// sendTelegramPreBlock falls back to plain text when Telegram rejects the
+// MarkdownV2 entities, so a formatting slip never drops the reply. Any other
+// failure (network, auth, rate limit) is returned as is: resending would only
+// fail again or duplicate a message Telegram may already have delivered.
+func sendTelegramPreBlock(ctx context.Context, client *http.Client, baseURL, token string, chatID int64, text string) error {
+	err := telegramSendMessageMode(ctx, client, baseURL, token, chatID, formatTelegramPreBlock(text), "MarkdownV2")
+	if err == nil || !isTelegramParseEntitiesError(err) {
+		return err
+	}
+	return telegramSendMessage(ctx, client, baseURL, token, chatID, text)
+}
+
+// isTelegramParseEntitiesError matches the Bot API's "Bad Request: can't parse
+// entities: ..." description, which telegramSendMessageMode carries in its
+// error for both the HTTP 400 and the ok=false responses.
+func isTelegramParseEntitiesError(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "can't parse entities")
+}
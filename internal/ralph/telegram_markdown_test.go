@@ -0,0 +1,147 @@
+package ralph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTelegramDispatcherSendsPreBlockAsMarkdownV2(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan telegramSendMessageRequest, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := newTelegramCommandDispatcher(ctx, telegramCommandDispatcherOptions{
+		CommandTimeout: 3 * time.Second,
+		Concurrency:    1,
+		OnCommand: func(ctx context.Context, chatID int64, text string) (TelegramReply, error) {
+			if text == "plain" {
+				return TelegramReply{Text: "- state: ok"}, nil
+			}
+			return TelegramReply{Text: "- state:  `ok`\n- path:   C:\\ralph", Pre: true}, nil
+		},
+		CodeBlocks: true,
+		Client:     newTelegramMockClient(requests),
+		BaseURL:    "https://api.telegram.org",
+		Token:      "token",
+		Out:        io.Discard,
+	})
+
+	dispatcher.Submit(7, "pre")
+	dispatcher.Submit(7, "plain")
+	got := make([]telegramSendMessageRequest, 0, 2)
+	deadline := time.After(3 * time.Second)
+	for len(got) < 2 {
+		select {
+		case req := <-requests:
+			got = append(got, req)
+		case <-deadline:
+			t.Fatalf("expected 2 replies, got=%d", len(got))
+		}
+	}
+	want := "```\n- state:  \\`ok\\`\n- path:   C:\\\\ralph\n```"
+	if got[0].ParseMode != "MarkdownV2" || got[0].Text != want {
+		t.Fatalf("pre block mismatch: mode=%q text=%q", got[0].ParseMode, got[0].Text)
+	}
+	if got[1].ParseMode != "" || got[1].Text != "- state: ok" {
+		t.Fatalf("plain reply should stay plain: mode=%q text=%q", got[1].ParseMode, got[1].Text)
+	}
+}
+
+func TestTelegramDispatcherIgnoresPreWithoutCodeBlocks(t *testing.T) {
+	t.Parallel()
+
+	requests := make(chan telegramSendMessageRequest, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dispatcher := newTelegramCommandDispatcher(ctx, telegramCommandDispatcherOptions{
+		CommandTimeout: 3 * time.Second,
+		Concurrency:    1,
+		OnCommand: func(ctx context.Context, chatID int64, text string) (TelegramReply, error) {
+			return TelegramReply{Text: "- state:  ok", Pre: true}, nil
+		},
+		Client:  newTelegramMockClient(requests),
+		BaseURL: "https://api.telegram.org",
+		Token:   "token",
+		Out:     io.Discard,
+	})
+
+	dispatcher.Submit(7, "/status")
+	select {
+	case req := <-requests:
+		if req.ParseMode != "" || req.Text != "- state:  ok" {
+			t.Fatalf("code blocks are opt-in: mode=%q text=%q", req.ParseMode, req.Text)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("expected a reply")
+	}
+}
+
+func TestSendTelegramPreBlockFallsBackToPlainText(t *testing.T) {
+	t.Parallel()
+
+	sent := []telegramSendMessageRequest{}
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			defer req.Body.Close()
+			var payload telegramSendMessageRequest
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			sent = append(sent, payload)
+			body := `{"ok":true}`
+			if payload.ParseMode != "" {
+				body = `{"ok":false,"description":"Bad Request: can't parse entities"}`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	if err := sendTelegramPreBlock(context.Background(), client, "https://example.invalid", "token", 7, "- state: ok"); err != nil {
+		t.Fatalf("fallback send failed: %v", err)
+	}
+	if len(sent) != 2 || sent[1].ParseMode != "" || sent[1].Text != "- state: ok" {
+		t.Fatalf("expected markdown attempt then plain fallback: %+v", sent)
+	}
+}
+
+func TestSendTelegramPreBlockDoesNotResendOnOtherErrors(t *testing.T) {
+	t.Parallel()
+
+	responses := map[string]func() (*http.Response, error){
+		"network": func() (*http.Response, error) {
+			return nil, errors.New("connection reset by peer")
+		},
+		"rate limit": func() (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(`{"ok":false,"description":"Too Many Requests: retry after 5"}`)),
+			}, nil
+		},
+	}
+	for name, respond := range responses {
+		attempts := 0
+		client := &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				req.Body.Close()
+				attempts++
+				return respond()
+			}),
+		}
+		if err := sendTelegramPreBlock(context.Background(), client, "https://example.invalid", "token", 7, "- state: ok"); err == nil {
+			t.Fatalf("%s: expected the send error to be returned", name)
+		}
+		if attempts != 1 {
+			t.Fatalf("%s: only a parse-entities error should trigger the plain resend, attempts=%d", name, attempts)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package ralph
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+const telegramTokenRedacted = "[REDACTED_TELEGRAM_TOKEN]"
+
+// telegramTokenPattern matches bot tokens (<bot id>:<secret>) so a token leaked through
+// a wrapped http error or a pasted command is scrubbed even when it is not ours.
+var telegramTokenPattern = regexp.MustCompile(`\b\d{5,}:[A-Za-z0-9_-]{30,}`)
+
+// RedactTelegramToken masks token and anything shaped like a bot token in s.
+func RedactTelegramToken(s, token string) string {
+	if token = strings.TrimSpace(token); token != "" {
+		s = strings.ReplaceAll(s, token, telegramTokenRedacted)
+	}
+	return telegramTokenPattern.ReplaceAllString(s, telegramTokenRedacted)
+}
+
+type telegramRedactingWriter struct {
+	w     io.Writer
+	token string
+}
+
+// NewTelegramRedactingWriter scrubs bot tokens from every write before passing it on.
+// Log lines are written whole, so redacting per write is enough.
+func NewTelegramRedactingWriter(w io.Writer, token string) io.Writer {
+	return &telegramRedactingWriter{w: w, token: strings.TrimSpace(token)}
+}
+
+func (r *telegramRedactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, RedactTelegramToken(string(p), r.token)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -3,10 +3,16 @@ package ralph
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 	"unicode/utf8"
@@ -82,6 +88,67 @@ func TestSplitTelegramMessage(t *testing.T) {
 	}
 }
 
+func TestTelegramBroadcastChunksLongReplyOnLineBoundaries(t *testing.T) {
+	t.Parallel()
+
+	lines := make([]string, 0, 201)
+	for i := 0; i < 201; i++ {
+		lines = append(lines, fmt.Sprintf("- project-%03d: %s", i, strings.Repeat("x", 34)))
+	}
+	reply := strings.Join(lines, "\n")
+	if len(reply) < 10000 {
+		t.Fatalf("test reply too short: %d", len(reply))
+	}
+
+	requests := make(chan telegramSendMessageRequest, 10)
+	results, err := TelegramBroadcast(context.Background(), TelegramBroadcastOptions{
+		Token:   "test-token",
+		ChatIDs: map[int64]struct{}{111: {}},
+		Text:    reply,
+		BaseURL: "https://example.invalid",
+		Client:  newTelegramMockClient(requests),
+	})
+	if err != nil || len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("broadcast failed: results=%+v err=%v", results, err)
+	}
+	close(requests)
+
+	chunks := []string{}
+	for req := range requests {
+		if n := len([]rune(req.Text)); n >= 4096 || n > telegramMessageChunkRunes {
+			t.Fatalf("chunk too long: %d", n)
+		}
+		for _, line := range strings.Split(req.Text, "\n") {
+			if len(line) != 49 || !strings.HasPrefix(line, "- project-") {
+				t.Fatalf("chunk broke a line: %q", line)
+			}
+		}
+		chunks = append(chunks, req.Text)
+	}
+	// 49-char lines + newline: 70 lines fit in 3500 runes, so 70+70+61.
+	if len(chunks) != 3 {
+		t.Fatalf("chunk count mismatch: got=%d want=3", len(chunks))
+	}
+	if strings.Join(chunks, "\n") != reply {
+		t.Fatalf("chunks should reassemble the original reply in order")
+	}
+}
+
+func TestSplitTelegramMessageCutsOnlyOverlongLines(t *testing.T) {
+	t.Parallel()
+
+	parts := splitTelegramMessage("short\n"+strings.Repeat("a", 20)+"\n  ok", 8)
+	want := []string{"short", "aaaaaaaa", "aaaaaaaa", "aaaa", "  ok"}
+	if len(parts) != len(want) {
+		t.Fatalf("parts mismatch: got=%q want=%q", parts, want)
+	}
+	for i := range want {
+		if parts[i] != want[i] {
+			t.Fatalf("part %d mismatch: got=%q want=%q", i, parts[i], want[i])
+		}
+	}
+}
+
 func TestCompactTelegramErrorUnicodeSafe(t *testing.T) {
 	t.Parallel()
 
@@ -165,10 +232,10 @@ func TestTelegramCommandDispatcherQueuesWithoutDrop(t *testing.T) {
 	dispatcher := newTelegramCommandDispatcher(ctx, telegramCommandDispatcherOptions{
 		CommandTimeout: 3 * time.Second,
 		Concurrency:    1,
-		OnCommand: func(ctx context.Context, chatID int64, text string) (string, error) {
+		OnCommand: func(ctx context.Context, chatID int64, text string) (TelegramReply, error) {
 			// Force queueing under concurrency=1.
 			time.Sleep(80 * time.Millisecond)
-			return "ack:" + text, nil
+			return TelegramReply{Text: "ack:" + text}, nil
 		},
 		Client:  client,
 		BaseURL: "https://api.telegram.org",
@@ -206,9 +273,9 @@ func TestTelegramCommandDispatcherPerChatOrdering(t *testing.T) {
 	dispatcher := newTelegramCommandDispatcher(ctx, telegramCommandDispatcherOptions{
 		CommandTimeout: 3 * time.Second,
 		Concurrency:    2,
-		OnCommand: func(ctx context.Context, chatID int64, text string) (string, error) {
+		OnCommand: func(ctx context.Context, chatID int64, text string) (TelegramReply, error) {
 			time.Sleep(40 * time.Millisecond)
-			return fmt.Sprintf("%d:%s", chatID, text), nil
+			return TelegramReply{Text: fmt.Sprintf("%d:%s", chatID, text)}, nil
 		},
 		Client:  client,
 		BaseURL: "https://api.telegram.org",
@@ -239,12 +306,284 @@ func TestTelegramCommandDispatcherPerChatOrdering(t *testing.T) {
 	}
 }
 
+func TestSaveTelegramOffsetAtomic(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "telegram.offset")
+	if err := saveTelegramOffset(path, 1234); err != nil {
+		t.Fatalf("save offset failed: %v", err)
+	}
+	got, err := loadTelegramOffset(path)
+	if err != nil || got != 1234 {
+		t.Fatalf("load offset mismatch: got=%d err=%v", got, err)
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("read dir failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("temp files should be cleaned up, got=%d entries", len(entries))
+	}
+
+	if err := os.WriteFile(path, []byte("12"), 0o644); err != nil {
+		t.Fatalf("write partial offset failed: %v", err)
+	}
+	if _, err := loadTelegramOffset(path); !errors.Is(err, errTelegramOffsetCorrupt) {
+		t.Fatalf("partial offset should be reported as corrupt, got=%v", err)
+	}
+}
+
+func TestRunTelegramBotRecoversFromCorruptOffset(t *testing.T) {
+	t.Parallel()
+
+	offsetFile := filepath.Join(t.TempDir(), "telegram.offset")
+	if err := os.WriteFile(offsetFile, []byte("4"), 0o644); err != nil {
+		t.Fatalf("write partial offset failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	offsets := make(chan string, 8)
+	polls := 0
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"ok":true}`
+			if strings.HasSuffix(req.URL.Path, "/getUpdates") {
+				polls++
+				offsets <- req.URL.Query().Get("offset")
+				switch polls {
+				case 1:
+					body = `{"ok":true,"result":[{"update_id":41,"message":{"chat":{"id":7},"text":"/start"}}]}`
+				case 2:
+					body = `{"ok":true,"result":[{"update_id":42,"message":{"chat":{"id":7},"text":"/ping"}}]}`
+				default:
+					<-req.Context().Done()
+					return nil, req.Context().Err()
+				}
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	commands := make(chan string, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- RunTelegramBot(ctx, TelegramBotOptions{
+			Token:          "token",
+			AllowedChatIDs: map[int64]struct{}{7: {}},
+			OffsetFile:     offsetFile,
+			Client:         client,
+			Out:            io.Discard,
+			OnCommand: func(ctx context.Context, chatID int64, text string) (TelegramReply, error) {
+				commands <- text
+				return TelegramReply{Text: "ok"}, nil
+			},
+		})
+	}()
+
+	select {
+	case got := <-commands:
+		if got != "/ping" {
+			t.Fatalf("pending update should be skipped after corrupt offset, got command=%q", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for command")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("RunTelegramBot failed: %v", err)
+	}
+
+	if first := <-offsets; first != "-1" {
+		t.Fatalf("first poll should resume from latest update, got offset=%q", first)
+	}
+	if second := <-offsets; second != "42" {
+		t.Fatalf("second poll offset mismatch: got=%q want=42", second)
+	}
+	got, err := loadTelegramOffset(offsetFile)
+	if err != nil || got != 43 {
+		t.Fatalf("saved offset mismatch: got=%d err=%v", got, err)
+	}
+}
+
+func TestRunTelegramBotReloadsAllowlistInPlace(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reload := make(chan os.Signal, 1)
+	offsets := make(chan string, 8)
+	polls := 0
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"ok":true}`
+			if strings.HasSuffix(req.URL.Path, "/getUpdates") {
+				polls++
+				offsets <- req.URL.Query().Get("offset")
+				switch polls {
+				case 1:
+					body = `{"ok":true,"result":[{"update_id":41,"message":{"chat":{"id":8},"text":"/before"}}]}`
+				case 2:
+					// The operator adds chat 8 and sends SIGHUP while the bot is polling.
+					reload <- syscall.SIGHUP
+					body = `{"ok":true,"result":[{"update_id":42,"message":{"chat":{"id":8},"text":"/after"}}]}`
+				default:
+					<-req.Context().Done()
+					return nil, req.Context().Err()
+				}
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	var logMu sync.Mutex
+	var logs strings.Builder
+	commands := make(chan string, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- RunTelegramBot(ctx, TelegramBotOptions{
+			Token:          "token",
+			AllowedChatIDs: map[int64]struct{}{7: {}},
+			Client:         client,
+			Out: writerFunc(func(p []byte) (int, error) {
+				logMu.Lock()
+				defer logMu.Unlock()
+				return logs.Write(p)
+			}),
+			OnCommand: func(ctx context.Context, chatID int64, text string) (TelegramReply, error) {
+				commands <- text
+				return TelegramReply{Text: "ok"}, nil
+			},
+			Reload: reload,
+			OnReload: func() (TelegramAllowlist, error) {
+				return TelegramAllowlist{
+					ChatIDs: map[int64]struct{}{7: {}, 8: {}},
+					UserIDs: map[int64]struct{}{},
+				}, nil
+			},
+		})
+	}()
+
+	select {
+	case got := <-commands:
+		if got != "/after" {
+			t.Fatalf("only the post-reload message should run, got command=%q", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timed out waiting for command")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("RunTelegramBot failed: %v", err)
+	}
+
+	<-offsets
+	if second := <-offsets; second != "42" {
+		t.Fatalf("reload should keep the poll offset: got=%q want=42", second)
+	}
+	logMu.Lock()
+	defer logMu.Unlock()
+	if !strings.Contains(logs.String(), "reloaded allowlist (allowed_chats=2, allowed_users=0)") {
+		t.Fatalf("expected reload log line, got:\n%s", logs.String())
+	}
+}
+
+func TestTelegramRateLimiterPerChat(t *testing.T) {
+	t.Parallel()
+
+	limiter := newTelegramRateLimiter(2)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !limiter.Allow(1, now) || !limiter.Allow(1, now) {
+		t.Fatalf("first two commands should be allowed")
+	}
+	if limiter.Allow(1, now) {
+		t.Fatalf("third command within a minute should be limited")
+	}
+	if !limiter.Allow(2, now) {
+		t.Fatalf("other chat should have its own bucket")
+	}
+	if !limiter.Allow(1, now.Add(30*time.Second)) {
+		t.Fatalf("bucket should refill one token after 30s")
+	}
+	if limiter.Allow(1, now.Add(30*time.Second)) {
+		t.Fatalf("refilled token should be consumed")
+	}
+
+	unlimited := newTelegramRateLimiter(0)
+	for i := 0; i < 100; i++ {
+		if !unlimited.Allow(1, now) {
+			t.Fatalf("rate 0 should be unlimited")
+		}
+	}
+}
+
+func TestTelegramBroadcastReportsPerChatResults(t *testing.T) {
+	t.Parallel()
+
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			defer req.Body.Close()
+			var payload telegramSendMessageRequest
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			body := `{"ok":true}`
+			if payload.ChatID == 222 {
+				body = `{"ok":false,"description":"chat not found"}`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	results, err := TelegramBroadcast(context.Background(), TelegramBroadcastOptions{
+		Token:   "test-token",
+		ChatIDs: map[int64]struct{}{111: {}, 222: {}},
+		Text:    "maintenance at 22:00",
+		BaseURL: "https://example.invalid",
+		Client:  client,
+	})
+	if err != nil {
+		t.Fatalf("broadcast: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("result count mismatch: got=%d want=2", len(results))
+	}
+	if results[0].ChatID != 111 || results[0].Err != nil {
+		t.Fatalf("chat 111 should succeed: %+v", results[0])
+	}
+	if results[1].ChatID != 222 || results[1].Err == nil {
+		t.Fatalf("chat 222 should fail: %+v", results[1])
+	}
+
+	if _, err := TelegramBroadcast(context.Background(), TelegramBroadcastOptions{Token: "t", ChatIDs: map[int64]struct{}{1: {}}}); err == nil {
+		t.Fatalf("expected empty message error")
+	}
+}
+
 type roundTripFunc func(req *http.Request) (*http.Response, error)
 
 func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
 
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
 func newTelegramMockClient(requests chan telegramSendMessageRequest) *http.Client {
 	return &http.Client{
 		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
@@ -260,3 +599,178 @@ func newTelegramMockClient(requests chan telegramSendMessageRequest) *http.Clien
 		}),
 	}
 }
+
+func TestNextTelegramPollBackoffCaps(t *testing.T) {
+	t.Parallel()
+
+	got := []time.Duration{}
+	backoff := telegramPollBackoffMin
+	for i := 0; i < 7; i++ {
+		got = append(got, backoff)
+		backoff = nextTelegramPollBackoff(backoff)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("backoff[%d]=%s want=%s (all=%v)", i, got[i], want[i], got)
+		}
+	}
+}
+
+func TestRunTelegramBotStopsOnFatalPollErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[int]string{
+		http.StatusUnauthorized: "token rejected",
+		http.StatusConflict:     "another bot instance",
+	}
+	for code, want := range cases {
+		client := &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: code,
+					Header:     make(http.Header),
+					Body:       io.NopCloser(strings.NewReader(`{"ok":false,"error_code":` + strconv.Itoa(code) + `,"description":"nope"}`)),
+				}, nil
+			}),
+		}
+		var out strings.Builder
+		done := make(chan error, 1)
+		go func() {
+			done <- RunTelegramBot(context.Background(), TelegramBotOptions{
+				Token:          "token",
+				AllowedChatIDs: map[int64]struct{}{7: {}},
+				OffsetFile:     filepath.Join(t.TempDir(), "offset"),
+				Client:         client,
+				Out:            &out,
+				OnCommand: func(ctx context.Context, chatID int64, text string) (TelegramReply, error) {
+					return TelegramReply{}, nil
+				},
+			})
+		}()
+		select {
+		case err := <-done:
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Fatalf("http %d: expected fatal error containing %q, got %v", code, want, err)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("http %d: bot kept retrying a fatal error", code)
+		}
+	}
+
+	if err := telegramFatalPollError(&telegramAPIError{Method: "getUpdates", StatusCode: http.StatusBadGateway}); err != nil {
+		t.Fatalf("5xx should stay retryable: %v", err)
+	}
+}
+
+func TestTelegramTestValidatesTokenThenSendsToEachChat(t *testing.T) {
+	t.Parallel()
+
+	sent := map[int64]string{}
+	newClient := func(validToken bool) *http.Client {
+		return &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body := `{"ok":true}`
+				status := http.StatusOK
+				switch {
+				case strings.HasSuffix(req.URL.Path, "/getMe") && !validToken:
+					status = http.StatusUnauthorized
+					body = `{"ok":false,"error_code":401,"description":"Unauthorized"}`
+				case strings.HasSuffix(req.URL.Path, "/getMe"):
+					body = `{"ok":true,"result":{"id":42,"username":"ralph_bot"}}`
+				case strings.HasSuffix(req.URL.Path, "/sendMessage"):
+					defer req.Body.Close()
+					var payload telegramSendMessageRequest
+					_ = json.NewDecoder(req.Body).Decode(&payload)
+					sent[payload.ChatID] = payload.Text
+					if payload.ChatID == 222 {
+						status = http.StatusBadRequest
+						body = `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`
+					}
+				}
+				return &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}, nil
+			}),
+		}
+	}
+
+	res, err := TelegramTest(context.Background(), TelegramBroadcastOptions{
+		Token:   "test-token",
+		ChatIDs: map[int64]struct{}{111: {}, 222: {}},
+		BaseURL: "https://example.invalid",
+		Client:  newClient(true),
+	})
+	if err != nil {
+		t.Fatalf("telegram test: %v", err)
+	}
+	if res.Bot.ID != 42 || res.Bot.Username != "ralph_bot" {
+		t.Fatalf("bot identity mismatch: %+v", res.Bot)
+	}
+	if len(res.Chats) != 2 || res.Chats[0].Err != nil || res.Chats[1].Err == nil {
+		t.Fatalf("per-chat results mismatch: %+v", res.Chats)
+	}
+	if !strings.Contains(res.Chats[1].Err.Error(), "chat not found") {
+		t.Fatalf("chat error should include API body: %v", res.Chats[1].Err)
+	}
+	if sent[111] != TelegramTestMessage {
+		t.Fatalf("test message mismatch: %q", sent[111])
+	}
+
+	_, err = TelegramTest(context.Background(), TelegramBroadcastOptions{
+		Token:   "bad-token",
+		ChatIDs: map[int64]struct{}{111: {}},
+		BaseURL: "https://example.invalid",
+		Client:  newClient(false),
+	})
+	if err == nil || !strings.Contains(err.Error(), "getMe") || !strings.Contains(err.Error(), "Unauthorized") {
+		t.Fatalf("expected getMe unauthorized error, got %v", err)
+	}
+}
+
+func TestRunTelegramBotRedactsTokenFromLogOutput(t *testing.T) {
+	t.Parallel()
+
+	token := "123456789:AAH-secretSecretSecretSecretSecret_42"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("dial failed for %s", req.URL.String())
+		}),
+	}
+	var out strings.Builder
+	logOut := writerFunc(func(p []byte) (int, error) {
+		out.Write(p)
+		if strings.Contains(string(p), "getUpdates failed") {
+			cancel()
+		}
+		return len(p), nil
+	})
+	err := RunTelegramBot(ctx, TelegramBotOptions{
+		Token:          token,
+		AllowedChatIDs: map[int64]struct{}{7: {}},
+		OffsetFile:     filepath.Join(t.TempDir(), "offset"),
+		Client:         client,
+		Out:            logOut,
+		OnCommand: func(ctx context.Context, chatID int64, text string) (TelegramReply, error) {
+			return TelegramReply{}, nil
+		},
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatalf("run telegram bot: %v", err)
+	}
+	logged := out.String()
+	if !strings.Contains(logged, "getUpdates failed") {
+		t.Fatalf("expected poll failure to be logged, got:\n%s", logged)
+	}
+	if strings.Contains(logged, token) || strings.Contains(logged, "secretSecret") {
+		t.Fatalf("token leaked into log output:\n%s", logged)
+	}
+	if !strings.Contains(logged, telegramTokenRedacted) {
+		t.Fatalf("expected redaction marker, got:\n%s", logged)
+	}
+
+	other := "987654321:BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB"
+	if got := RedactTelegramToken("pasted "+other, token); strings.Contains(got, other) {
+		t.Fatalf("token-shaped value should be redacted too: %q", got)
+	}
+}
@@ -8,25 +8,40 @@ import (
 )
 
 var profileEnvKeysForTest = []string{
+	"RALPH_ENV",
 	"RALPH_PLUGIN_NAME",
 	"RALPH_CODEX_MODEL",
 	"RALPH_CODEX_MODEL_MANAGER",
 	"RALPH_CODEX_MODEL_PLANNER",
 	"RALPH_CODEX_MODEL_DEVELOPER",
 	"RALPH_CODEX_MODEL_QA",
+	"RALPH_CODEX_MODEL_OVERRIDE",
+	"RALPH_CODEX_SANDBOX_OVERRIDE",
+	"RALPH_CODEX_APPROVAL_OVERRIDE",
 	"RALPH_CODEX_HOME",
 	"RALPH_CODEX_SANDBOX",
 	"RALPH_CODEX_APPROVAL",
 	"RALPH_CODEX_EXEC_TIMEOUT_SEC",
 	"RALPH_CODEX_RETRY_MAX_ATTEMPTS",
 	"RALPH_CODEX_RETRY_BACKOFF_SEC",
+	"RALPH_CODEX_RETRY_JITTER_PCT",
+	"RALPH_CODEX_MAX_CONCURRENT",
+	"RALPH_CODEX_DRY_RUN",
 	"RALPH_REQUIRE_CODEX",
 	"RALPH_ROLE_RULES_ENABLED",
 	"RALPH_HANDOFF_REQUIRED",
 	"RALPH_HANDOFF_SCHEMA",
 	"RALPH_IDLE_SLEEP_SEC",
+	"RALPH_IDLE_POLL_INTERVAL_SEC",
+	"RALPH_PERMISSION_COOLDOWN_STREAK",
+	"RALPH_PERMISSION_COOLDOWN_SEC",
+	"RALPH_OUTPUT_DIR",
+	"RALPH_LOG_MAX_SIZE_MB",
+	"RALPH_LOG_MAX_BACKUPS",
+	"RALPH_DOCTOR_MIN_FREE_DISK_MB",
 	"RALPH_EXIT_ON_IDLE",
 	"RALPH_NO_READY_MAX_LOOPS",
+	"RALPH_MAX_ISSUE_ATTEMPTS",
 	"RALPH_VALIDATE_ROLES",
 	"RALPH_VALIDATE_CMD",
 	"RALPH_BUSYWAIT_DETECT_LOOPS",
@@ -40,6 +55,8 @@ var profileEnvKeysForTest = []string{
 	"RALPH_INPROGRESS_WATCHDOG_SCAN_LOOPS",
 	"RALPH_SUPERVISOR_ENABLED",
 	"RALPH_SUPERVISOR_RESTART_DELAY_SEC",
+	"RALPH_SUPERVISOR_HEARTBEAT_STALE_SEC",
+	"RALPH_SUPERVISOR_MAX_RESTARTS_PER_MIN",
 }
 
 func newTestPaths(t *testing.T) Paths {
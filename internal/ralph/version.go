@@ -0,0 +1,70 @@
+package ralph
+
+import (
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// Version is stamped at release time with
+// -ldflags "-X codex-ralph/internal/ralph.Version=vX.Y.Z".
+var Version = "dev"
+
+// BinaryVersion returns the running ralphctl version, falling back to the module
+// version when installed with go install; "dev" for untagged local builds.
+func BinaryVersion() string {
+	if v := strings.TrimSpace(Version); v != "" && v != "dev" {
+		return v
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v := info.Main.Version; v != "" && v != "(devel)" {
+			return v
+		}
+	}
+	return "dev"
+}
+
+// compareVersions compares dotted numeric versions ("v1.2.3", "1.2"), ignoring
+// any -prerelease/+build suffix. ok is false when either side does not parse.
+func compareVersions(a, b string) (int, bool) {
+	pa, okA := parseVersionParts(a)
+	pb, okB := parseVersionParts(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+func parseVersionParts(v string) ([]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, false
+	}
+	var out []int
+	for _, part := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		out = append(out, n)
+	}
+	return out, true
+}
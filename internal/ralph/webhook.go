@@ -0,0 +1,73 @@
+package ralph
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader carries "sha256=<hex hmac>" of the raw request body.
+const WebhookSignatureHeader = "X-Ralph-Signature"
+
+type WebhookAlert struct {
+	TS         string `json:"ts"`
+	ProjectDir string `json:"project_dir"`
+	Kind       string `json:"kind"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+}
+
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func VerifyWebhookSignature(secret string, body []byte, signature string) bool {
+	expected := SignWebhookPayload(secret, body)
+	return hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signature)))
+}
+
+func PostWebhookAlert(ctx context.Context, client *http.Client, url, secret string, alert WebhookAlert) error {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return fmt.Errorf("webhook url is required")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	if strings.TrimSpace(alert.TS) == "" {
+		alert.TS = time.Now().UTC().Format(time.RFC3339)
+	}
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ralphctl-notify")
+	if secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, body))
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
+		return fmt.Errorf("webhook http %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
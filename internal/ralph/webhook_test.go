@@ -0,0 +1,45 @@
+package ralph
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPostWebhookAlertSignsBody(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan WebhookAlert, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !VerifyWebhookSignature("s3cret", body, r.Header.Get(WebhookSignatureHeader)) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		var alert WebhookAlert
+		_ = json.Unmarshal(body, &alert)
+		received <- alert
+	}))
+	defer srv.Close()
+
+	err := PostWebhookAlert(context.Background(), srv.Client(), srv.URL, "s3cret", WebhookAlert{
+		ProjectDir: "/work/app",
+		Kind:       "blocked",
+		Severity:   "critical",
+		Message:    "[ralph alert][blocked]",
+	})
+	if err != nil {
+		t.Fatalf("post webhook: %v", err)
+	}
+	alert := <-received
+	if alert.Kind != "blocked" || alert.TS == "" {
+		t.Fatalf("alert mismatch: %+v", alert)
+	}
+
+	if err := PostWebhookAlert(context.Background(), srv.Client(), srv.URL, "wrong", WebhookAlert{Kind: "retry"}); err == nil {
+		t.Fatalf("expected receiver to reject bad signature")
+	}
+}
@@ -0,0 +1,57 @@
+package ralph
+
+import "context"
+
+// Service control manager values used by the windows service entrypoint.
+const (
+	winSvcStopped      = 1
+	winSvcStartPending = 2
+	winSvcStopPending  = 3
+	winSvcRunning      = 4
+
+	winSvcControlStop        = 1
+	winSvcControlInterrogate = 4
+	winSvcControlShutdown    = 5
+
+	winSvcAcceptStop     = 1
+	winSvcAcceptShutdown = 4
+
+	// ERROR_SERVICE_SPECIFIC_ERROR: run failed, see the service log.
+	winSvcSpecificError = 1066
+)
+
+// runWindowsServiceLoop drives run between SCM status reports: start pending,
+// running, stop pending once a stop/shutdown control cancels run, then stopped
+// with run's result.
+func runWindowsServiceLoop(controls <-chan uint32, setStatus func(state, accepts, exitCode uint32), run func(ctx context.Context) error) error {
+	setStatus(winSvcStartPending, 0, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- run(ctx) }()
+
+	state, accepts := uint32(winSvcRunning), uint32(winSvcAcceptStop|winSvcAcceptShutdown)
+	setStatus(state, accepts, 0)
+	for {
+		select {
+		case err := <-done:
+			exitCode := uint32(0)
+			if err != nil {
+				exitCode = winSvcSpecificError
+			}
+			setStatus(winSvcStopped, 0, exitCode)
+			return err
+		case c := <-controls:
+			switch c {
+			case winSvcControlInterrogate:
+				setStatus(state, accepts, 0)
+			case winSvcControlStop, winSvcControlShutdown:
+				if state != winSvcStopPending {
+					state, accepts = winSvcStopPending, 0
+					setStatus(state, accepts, 0)
+					cancel()
+				}
+			}
+		}
+	}
+}
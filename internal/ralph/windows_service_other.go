@@ -0,0 +1,12 @@
+//go:build !windows
+
+package ralph
+
+import (
+	"context"
+	"fmt"
+)
+
+func RunWindowsService(name string, run func(ctx context.Context) error) error {
+	return fmt.Errorf("--windows-service is only supported on windows")
+}
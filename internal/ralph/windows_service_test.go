@@ -0,0 +1,62 @@
+package ralph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRunWindowsServiceLoopStopsRunOnStopControl(t *testing.T) {
+	t.Parallel()
+
+	controls := make(chan uint32, 2)
+	var states [][2]uint32
+	setStatus := func(state, accepts, exitCode uint32) {
+		states = append(states, [2]uint32{state, exitCode})
+	}
+	canceled := false
+	controls <- winSvcControlInterrogate
+	controls <- winSvcControlStop
+	done := make(chan error, 1)
+	go func() {
+		done <- runWindowsServiceLoop(controls, setStatus, func(ctx context.Context) error {
+			<-ctx.Done()
+			canceled = true
+			return nil
+		})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("service loop error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("stop control should end the service")
+	}
+	if !canceled {
+		t.Fatalf("run context should be canceled on stop")
+	}
+	want := [][2]uint32{{winSvcStartPending, 0}, {winSvcRunning, 0}, {winSvcRunning, 0}, {winSvcStopPending, 0}, {winSvcStopped, 0}}
+	if !reflect.DeepEqual(states, want) {
+		t.Fatalf("status sequence mismatch:\ngot=%v\nwant=%v", states, want)
+	}
+}
+
+func TestRunWindowsServiceLoopReportsRunFailure(t *testing.T) {
+	t.Parallel()
+
+	var last [2]uint32
+	err := runWindowsServiceLoop(make(chan uint32), func(state, accepts, exitCode uint32) {
+		last = [2]uint32{state, exitCode}
+	}, func(ctx context.Context) error {
+		return errors.New("profile invalid")
+	})
+	if err == nil || err.Error() != "profile invalid" {
+		t.Fatalf("run error should be returned: %v", err)
+	}
+	if last != [2]uint32{winSvcStopped, winSvcSpecificError} {
+		t.Fatalf("failed run should report stopped with a service error: %v", last)
+	}
+}
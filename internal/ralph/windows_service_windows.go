@@ -0,0 +1,89 @@
+//go:build windows
+
+package ralph
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32                          = syscall.NewLazyDLL("advapi32.dll")
+	procStartServiceCtrlDispatcherW   = advapi32.NewProc("StartServiceCtrlDispatcherW")
+	procRegisterServiceCtrlHandlerExW = advapi32.NewProc("RegisterServiceCtrlHandlerExW")
+	procSetServiceStatus              = advapi32.NewProc("SetServiceStatus")
+)
+
+const winSvcWin32OwnProcess = 0x10
+
+type winServiceTableEntry struct {
+	name *uint16
+	proc uintptr
+}
+
+type winServiceStatus struct {
+	serviceType             uint32
+	currentState            uint32
+	controlsAccepted        uint32
+	win32ExitCode           uint32
+	serviceSpecificExitCode uint32
+	checkPoint              uint32
+	waitHint                uint32
+}
+
+// The SCM calls back on its own threads, so the single service this process
+// hosts is kept here.
+var winService struct {
+	name     *uint16
+	run      func(ctx context.Context) error
+	controls chan uint32
+	err      error
+}
+
+// RunWindowsService hands the process to the service control manager, which is
+// what `sc start` waits for; run gets a context canceled on stop or shutdown.
+func RunWindowsService(name string, run func(ctx context.Context) error) error {
+	namePtr, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return fmt.Errorf("service name: %w", err)
+	}
+	winService.name = namePtr
+	winService.run = run
+	winService.controls = make(chan uint32, 4)
+	table := []winServiceTableEntry{
+		{name: namePtr, proc: syscall.NewCallback(winServiceMain)},
+		{},
+	}
+	if r, _, callErr := procStartServiceCtrlDispatcherW.Call(uintptr(unsafe.Pointer(&table[0]))); r == 0 {
+		return fmt.Errorf("start service dispatcher (only the service control manager can start --windows-service): %w", callErr)
+	}
+	return winService.err
+}
+
+func winServiceMain(argc uintptr, argv uintptr) uintptr {
+	handle, _, _ := procRegisterServiceCtrlHandlerExW.Call(uintptr(unsafe.Pointer(winService.name)), syscall.NewCallback(winServiceCtrlHandler), 0)
+	if handle == 0 {
+		winService.err = fmt.Errorf("register service control handler failed")
+		return 0
+	}
+	setStatus := func(state, accepts, exitCode uint32) {
+		status := winServiceStatus{serviceType: winSvcWin32OwnProcess, currentState: state, controlsAccepted: accepts}
+		if exitCode != 0 {
+			status.win32ExitCode = exitCode
+			status.serviceSpecificExitCode = 1
+		}
+		_, _, _ = procSetServiceStatus.Call(handle, uintptr(unsafe.Pointer(&status)))
+	}
+	winService.err = runWindowsServiceLoop(winService.controls, setStatus, winService.run)
+	return 0
+}
+
+func winServiceCtrlHandler(control, eventType, eventData, handlerContext uintptr) uintptr {
+	select {
+	case winService.controls <- uint32(control):
+	default:
+	}
+	return 0
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -154,6 +155,10 @@ func ReadYAMLFlatMap(path string) (map[string]string, error) {
 }
 
 func WriteYAMLFlatMap(path string, values map[string]string) error {
+	return os.WriteFile(path, []byte(renderYAMLFlatMap(values)), 0o644)
+}
+
+func renderYAMLFlatMap(values map[string]string) string {
 	keys := make([]string, 0, len(values))
 	for key := range values {
 		keys = append(keys, key)
@@ -187,8 +192,7 @@ func WriteYAMLFlatMap(path string, values map[string]string) error {
 		b.WriteString(yamlScalar(value))
 		b.WriteString("\n")
 	}
-
-	return os.WriteFile(path, []byte(b.String()), 0o644)
+	return b.String()
 }
 
 func leadingSpaces(line string) (int, error) {
@@ -415,3 +419,425 @@ func isPlainYAMLValue(raw string) bool {
 	}
 	return true
 }
+
+type yamlDocLine struct {
+	no     int
+	indent int
+	text   string
+	raw    string
+}
+
+type yamlDocParser struct {
+	lines []yamlDocLine
+	pos   int
+}
+
+func parseYAMLDocument(data []byte) (any, error) {
+	raw := strings.TrimPrefix(string(data), "\uFEFF")
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	p := &yamlDocParser{}
+	for i, line := range strings.Split(raw, "\n") {
+		line = strings.TrimRight(line, " \t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "---" || trimmed == "..." {
+			continue
+		}
+		indent := 0
+		if trimmed != "" {
+			n, err := leadingSpaces(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			indent = n
+		}
+		p.lines = append(p.lines, yamlDocLine{no: i + 1, indent: indent, text: trimmed, raw: line})
+	}
+
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	root := p.lines[p.pos]
+	value, err := p.parseNode(root.indent)
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("line %d: unexpected content", p.lines[p.pos].no)
+	}
+	return value, nil
+}
+
+func (p *yamlDocParser) skipBlank() {
+	for p.pos < len(p.lines) {
+		text := p.lines[p.pos].text
+		if text != "" && !strings.HasPrefix(text, "#") {
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *yamlDocParser) parseNode(indent int) (any, error) {
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	line := p.lines[p.pos]
+	if line.indent < indent {
+		return nil, nil
+	}
+	if isYAMLSeqItem(line.text) {
+		return p.parseSequence(line.indent)
+	}
+	return p.parseMapping(line.indent)
+}
+
+func (p *yamlDocParser) parseSequence(indent int) ([]any, error) {
+	out := []any{}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.lines) {
+			return out, nil
+		}
+		line := p.lines[p.pos]
+		if line.indent < indent || !isYAMLSeqItem(line.text) {
+			return out, nil
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.no)
+		}
+
+		item := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		if item == "" || strings.HasPrefix(item, "#") {
+			p.pos++
+			value, err := p.parseNode(indent + 1)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, value)
+			continue
+		}
+		if _, _, ok := splitYAMLKeyValue(item); ok {
+			// "- key: value" starts a mapping nested at the item's column.
+			afterDash := line.raw[line.indent+1:]
+			offset := line.indent + 1 + len(afterDash) - len(strings.TrimLeft(afterDash, " "))
+			p.lines[p.pos].indent = offset
+			p.lines[p.pos].text = item
+			value, err := p.parseMapping(offset)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, value)
+			continue
+		}
+		value, err := parseYAMLInlineValue(item)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.no, err)
+		}
+		out = append(out, value)
+		p.pos++
+	}
+}
+
+func (p *yamlDocParser) parseMapping(indent int) (map[string]any, error) {
+	out := map[string]any{}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.lines) {
+			return out, nil
+		}
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			return out, nil
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.no)
+		}
+		if isYAMLSeqItem(line.text) {
+			return out, nil
+		}
+
+		rawKey, rest, ok := splitYAMLKeyValue(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value", line.no)
+		}
+		key, err := parseYAMLScalar(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.no, err)
+		}
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", line.no)
+		}
+		p.pos++
+
+		rest = stripYAMLInlineComment(rest)
+		switch {
+		case rest == "":
+			p.skipBlank()
+			if p.pos >= len(p.lines) {
+				out[key] = nil
+				continue
+			}
+			next := p.lines[p.pos]
+			switch {
+			case next.indent > indent:
+				value, err := p.parseNode(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				out[key] = value
+			case next.indent == indent && isYAMLSeqItem(next.text):
+				value, err := p.parseSequence(indent)
+				if err != nil {
+					return nil, err
+				}
+				out[key] = value
+			default:
+				out[key] = nil
+			}
+		case strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">"):
+			out[key] = p.parseBlockScalar(indent, rest)
+		default:
+			value, err := parseYAMLInlineValue(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.no, err)
+			}
+			out[key] = value
+		}
+	}
+}
+
+func (p *yamlDocParser) parseBlockScalar(parentIndent int, header string) string {
+	folded := strings.HasPrefix(header, ">")
+	chomp := strings.TrimLeft(header, "|>")
+
+	blockIndent := -1
+	lines := []string{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.text == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		if line.indent <= parentIndent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = line.indent
+		}
+		if line.indent < blockIndent {
+			break
+		}
+		lines = append(lines, line.raw[blockIndent:])
+		p.pos++
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	text := ""
+	if folded {
+		var b strings.Builder
+		for i, line := range lines {
+			switch {
+			case line == "":
+				b.WriteString("\n")
+			case i > 0 && lines[i-1] != "":
+				b.WriteString(" ")
+				b.WriteString(line)
+			default:
+				b.WriteString(line)
+			}
+		}
+		text = b.String()
+	} else {
+		text = strings.Join(lines, "\n")
+	}
+	if text != "" && chomp != "-" {
+		text += "\n"
+	}
+	return text
+}
+
+func isYAMLSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func splitYAMLKeyValue(text string) (string, string, bool) {
+	inSingle := false
+	inDouble := false
+	for i := 0; i < len(text); i++ {
+		ch := text[i]
+		switch {
+		case inSingle:
+			if ch == '\'' {
+				inSingle = false
+			}
+		case inDouble:
+			if ch == '\\' {
+				i++
+			} else if ch == '"' {
+				inDouble = false
+			}
+		case ch == '\'' && i == 0:
+			inSingle = true
+		case ch == '"' && i == 0:
+			inDouble = true
+		case ch == '#' && i > 0 && text[i-1] == ' ':
+			return "", "", false
+		case ch == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			key := strings.TrimSpace(text[:i])
+			if key == "" {
+				return "", "", false
+			}
+			return key, strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+func parseYAMLInlineValue(raw string) (any, error) {
+	trimmed := strings.TrimSpace(stripYAMLInlineComment(raw))
+	switch {
+	case trimmed == "":
+		return nil, nil
+	case strings.HasPrefix(trimmed, "["):
+		if !strings.HasSuffix(trimmed, "]") {
+			return nil, fmt.Errorf("invalid inline list")
+		}
+		body := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+		out := []any{}
+		if body == "" {
+			return out, nil
+		}
+		items, err := splitInlineList(body)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			value, err := parseYAMLTypedScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, value)
+		}
+		return out, nil
+	case strings.HasPrefix(trimmed, "{"):
+		if !strings.HasSuffix(trimmed, "}") {
+			return nil, fmt.Errorf("invalid inline map")
+		}
+		body := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+		out := map[string]any{}
+		if body == "" {
+			return out, nil
+		}
+		items, err := splitInlineList(body)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			rawKey, rest, ok := splitYAMLKeyValue(item)
+			if !ok {
+				return nil, fmt.Errorf("invalid inline map entry: %s", item)
+			}
+			key, err := parseYAMLScalar(rawKey)
+			if err != nil {
+				return nil, err
+			}
+			value, err := parseYAMLTypedScalar(rest)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = value
+		}
+		return out, nil
+	}
+	return parseYAMLTypedScalar(trimmed)
+}
+
+// yamlPlainScalar is an unquoted scalar kept as written. Whether "10" or "true"
+// is a number, a bool or a string depends on the field it lands in, which
+// coerceYAMLScalars decides.
+type yamlPlainScalar string
+
+func parseYAMLTypedScalar(raw string) (any, error) {
+	trimmed := strings.TrimSpace(stripYAMLInlineComment(raw))
+	if trimmed == "" {
+		return nil, nil
+	}
+	if trimmed[0] == '"' || trimmed[0] == '\'' {
+		return parseYAMLScalar(trimmed)
+	}
+	switch strings.ToLower(trimmed) {
+	case "null", "~":
+		return nil, nil
+	}
+	return yamlPlainScalar(trimmed), nil
+}
+
+// coerceYAMLScalars converts the plain scalars in a parseYAMLDocument tree that
+// land in bool or numeric fields of t (following json tags) to those types.
+// Everything else, including string, json.RawMessage and interface fields,
+// keeps the scalar text, so a title such as 2024 or 1.10 stays as written.
+func coerceYAMLScalars(value any, t reflect.Type) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := map[string]reflect.Type{}
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+				if name == "-" || !f.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = f.Name
+				}
+				fields[strings.ToLower(name)] = f.Type
+			}
+			for key, item := range v {
+				if ft, ok := fields[strings.ToLower(key)]; ok {
+					v[key] = coerceYAMLScalars(item, ft)
+				}
+			}
+		case reflect.Map:
+			for key, item := range v {
+				v[key] = coerceYAMLScalars(item, t.Elem())
+			}
+		}
+		return v
+	case []any:
+		if (t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8) || t.Kind() == reflect.Array {
+			for i, item := range v {
+				v[i] = coerceYAMLScalars(item, t.Elem())
+			}
+		}
+		return v
+	case yamlPlainScalar:
+		raw := string(v)
+		switch t.Kind() {
+		case reflect.Bool:
+			if b, err := strconv.ParseBool(strings.ToLower(raw)); err == nil {
+				return b
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+				return n
+			}
+		case reflect.Float32, reflect.Float64:
+			if f, err := strconv.ParseFloat(raw, 64); err == nil {
+				return f
+			}
+		}
+		return raw
+	}
+	return value
+}